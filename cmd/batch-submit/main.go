@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// batchItem is one entry of the input file
+type batchItem struct {
+	URL      string
+	Prompt   string
+	Category string
+}
+
+// batchResult is one entry of the results manifest
+type batchResult struct {
+	URL         string     `json:"url"`
+	Prompt      string     `json:"prompt"`
+	Category    string     `json:"category"`
+	RequestID   string     `json:"request_id,omitempty"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	OutputPath  string     `json:"output_path,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run processes the batch and returns the exit code: 1 if it couldn't run or the manifest
+// couldn't be written, 2 if any item failed
+func run() int {
+	configPath := flag.String("config", "config.yaml", "Path to engine config file")
+	inputPath := flag.String("input", "", "File with one URL per line, or a CSV with url,prompt,category columns")
+	manifestPath := flag.String("manifest", "batch-manifest.json", "Path to write the results manifest")
+	parallel := flag.Int("parallel", 2, "Maximum number of videos processed at the same time")
+	defaultPrompt := flag.String("prompt", "general", "Prompt ID or text used when a line has no prompt")
+	defaultCategory := flag.String("category", "general", "Category used when a line has no category")
+	timeout := flag.Duration("timeout", 2*time.Hour, "Maximum time to wait for a single video")
+	flag.Parse()
+
+	if *inputPath == "" {
+		fmt.Println("Please provide --input with a file of URLs")
+		return 1
+	}
+	if *parallel < 1 {
+		*parallel = 1
+	}
+
+	items, err := readBatchFile(*inputPath, *defaultPrompt, *defaultCategory)
+	if err != nil {
+		log.Errorf("Failed to read input file: %v", err)
+		return 1
+	}
+	if len(items) == 0 {
+		log.Errorf("No URLs found in %s", *inputPath)
+		return 1
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Errorf("Failed to load config: %v", err)
+		return 1
+	}
+
+	engine, _, _, err := core.SetupEngine(cfg)
+	if err != nil {
+		log.Errorf("Failed to set up engine: %v", err)
+		return 1
+	}
+	engine.Start()
+	defer engine.Stop()
+	submissionService := services.NewVideoSubmissionService(engine)
+
+	log.Infof("Processing %d URL(s) from %s with parallelism %d", len(items), *inputPath, *parallel)
+
+	results := make([]batchResult, len(items))
+	sem := make(chan struct{}, *parallel)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item batchItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = processItem(submissionService, item, services.DefaultMaxTokens, *timeout)
+			log.Infof("[%d/%d] %s: %s", i+1, len(items), item.URL, results[i].Status)
+		}(i, item)
+	}
+	wg.Wait()
+
+	if err := writeManifest(*manifestPath, results); err != nil {
+		log.Errorf("Failed to write manifest: %v", err)
+		return 1
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != string(interfaces.StatusCompleted) {
+			failed++
+		}
+	}
+	log.Infof("Batch complete: %d succeeded, %d failed. Manifest written to %s", len(results)-failed, failed, *manifestPath)
+	if failed > 0 {
+		return 2
+	}
+	return 0
+}
+
+// processItem submits one URL and waits until it reaches a final state or times out
+func processItem(submissionService *services.VideoSubmissionService, item batchItem, maxTokens int, timeout time.Duration) batchResult {
+	result := batchResult{
+		URL:         item.URL,
+		Prompt:      item.Prompt,
+		Category:    item.Category,
+		SubmittedAt: time.Now(),
+	}
+
+	requestID, err := submissionService.SubmitVideo(item.URL, promptFromInput(item.Prompt), "video", item.Category, maxTokens)
+	if err != nil {
+		result.Status = string(interfaces.StatusFailed)
+		result.Error = err.Error()
+		return result
+	}
+	result.RequestID = requestID
+
+	deadline := time.Now().Add(timeout)
+	for {
+		state, err := submissionService.GetRequestStatus(requestID)
+		if err == nil && isFinal(state) {
+			result.Status = string(state.Status)
+			result.Error = state.Error
+			result.OutputPath = state.OutputPath
+			result.FinishedAt = state.CompletedAt
+			if title, ok := state.VideoInfo["title"].(string); ok {
+				result.Title = title
+			}
+			return result
+		}
+		if time.Now().After(deadline) {
+			result.Status = "timeout"
+			result.Error = fmt.Sprintf("not finished after %s", timeout)
+			return result
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// isFinal reports whether a request has finished, including cleanup for completed requests
+func isFinal(state *interfaces.ProcessingState) bool {
+	switch state.Status {
+	case interfaces.StatusFailed, interfaces.StatusCancelled, interfaces.StatusPartiallyCompleted:
+		return true
+	case interfaces.StatusCompleted:
+		return state.CompletedAt != nil
+	}
+	return false
+}
+
+// promptFromInput treats single words as prompt IDs and anything else as prompt text
+func promptFromInput(input string) interfaces.Prompt {
+	if strings.Contains(input, " ") {
+		return interfaces.Prompt{Type: interfaces.PromptTypeText, Prompt: input}
+	}
+	return interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: input}
+}
+
+// readBatchFile parses either a plain list of URLs or a CSV file with a header row
+func readBatchFile(path, defaultPrompt, defaultCategory string) ([]batchItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		return readBatchCSV(f, defaultPrompt, defaultCategory)
+	}
+
+	var items []batchItem
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		items = append(items, batchItem{URL: line, Prompt: defaultPrompt, Category: defaultCategory})
+	}
+	return items, scanner.Err()
+}
+
+// readBatchCSV reads a CSV with a header naming the url, prompt and category columns
+func readBatchCSV(r io.Reader, defaultPrompt, defaultCategory string) ([]batchItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	urlCol, ok := columns["url"]
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain a url column")
+	}
+	field := func(record []string, name, fallback string) string {
+		if i, ok := columns[name]; ok && i < len(record) && strings.TrimSpace(record[i]) != "" {
+			return strings.TrimSpace(record[i])
+		}
+		return fallback
+	}
+
+	var items []batchItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if urlCol >= len(record) || strings.TrimSpace(record[urlCol]) == "" {
+			continue
+		}
+		items = append(items, batchItem{
+			URL:      strings.TrimSpace(record[urlCol]),
+			Prompt:   field(record, "prompt", defaultPrompt),
+			Category: field(record, "category", defaultCategory),
+		})
+	}
+	return items, nil
+}
+
+// writeManifest writes the batch results as indented JSON
+func writeManifest(path string, results []batchResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
 )
 
 func main() {
@@ -27,7 +28,8 @@ func main() {
 		log.Fatalf("Unable to read credentials file: %v", err)
 	}
 
-	config, err := google.ConfigFromJSON(b, drive.DriveFileScope)
+	// Sheets access lets the same token append to the gsheets output's spreadsheet
+	config, err := google.ConfigFromJSON(b, drive.DriveFileScope, sheets.SpreadsheetsScope)
 	if err != nil {
 		log.Fatalf("Unable to parse client secret file to config: %v", err)
 	}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"video-summarizer-go/pkg/summarizer"
+	"video-summarizer-go/pkg/summarizer/summarizertest"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	url := flag.String("url", "https://www.youtube.com/watch?v=dQw4w9WgXcQ", "URL to run through the fake pipeline")
+	prompt := flag.String("prompt", "general", "Prompt ID")
+	timeout := flag.Duration("timeout", 30*time.Second, "How long to wait for the request")
+	flag.Parse()
+
+	h, err := summarizertest.New()
+	if err != nil {
+		log.Errorf("Failed to start harness: %v", err)
+		os.Exit(1)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	state, err := h.Run(ctx, summarizer.Request{
+		URL:    *url,
+		Prompt: summarizer.Prompt{Type: summarizer.PromptTypeID, Prompt: *prompt},
+	})
+	if err != nil {
+		log.Errorf("Request did not finish: %v", err)
+		os.Exit(1)
+	}
+
+	log.Infof("Request ID: %s", state.RequestID)
+	log.Infof("Status: %s", state.Status)
+	if state.Error != "" {
+		log.Infof("Error: %s", state.Error)
+	}
+	log.Infof("Summary: %s", state.SummaryText)
+	for _, upload := range h.Output.UploadsFor(state.RequestID) {
+		log.Infof("Uploaded %s %q to %s/%s", upload.Kind, upload.Title, upload.User, upload.Category)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/mock"
+	"video-summarizer-go/internal/providers/summarization"
+	"video-summarizer-go/internal/providers/transcription"
+)
+
+// benchmarkSpec lists the provider variants to compare. Fields left empty fall back to config.yaml.
+type benchmarkSpec struct {
+	Transcription []transcriberSpec `yaml:"transcription"`
+	Summarization []summarizerSpec  `yaml:"summarization"`
+}
+
+type transcriberSpec struct {
+	Name        string `yaml:"name"`
+	Provider    string `yaml:"provider"` // whisper (default) or mock
+	WhisperPath string `yaml:"whisper_path"`
+	ModelPath   string `yaml:"model_path"`
+}
+
+type summarizerSpec struct {
+	Name                string  `yaml:"name"`
+	Provider            string  `yaml:"provider"` // openai (default) or mock
+	Model               string  `yaml:"model"`
+	MaxTokens           int     `yaml:"max_tokens"`
+	PromptCostPer1K     float64 `yaml:"prompt_cost_per_1k"`
+	CompletionCostPer1K float64 `yaml:"completion_cost_per_1k"`
+}
+
+// benchmarkResult is one provider run in the report
+type benchmarkResult struct {
+	Kind      string                 `json:"kind"`
+	Name      string                 `json:"name"`
+	LatencyMS int64                  `json:"latency_ms"`
+	Usage     *interfaces.TokenUsage `json:"usage,omitempty"`
+	Output    string                 `json:"output,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to engine config file (defaults for every variant)")
+	specPath := flag.String("spec", "benchmark.yaml", "Path to the file listing the provider variants to compare")
+	audioPath := flag.String("audio", "", "Audio file to run through every transcription variant")
+	textPath := flag.String("text", "", "Text file to run through every summarization variant (default: the first successful transcript)")
+	promptInput := flag.String("prompt", "general", "Prompt ID or text for summarization")
+	reportPath := flag.String("report", "benchmark-report.json", "Path to write the full results, including outputs")
+	flag.Parse()
+
+	if *audioPath == "" && *textPath == "" {
+		fmt.Println("Please provide --audio and/or --text")
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Errorf("Failed to load config: %v", err)
+		os.Exit(1)
+	}
+	spec, err := loadSpec(*specPath)
+	if err != nil {
+		log.Errorf("Failed to load benchmark spec: %v", err)
+		os.Exit(1)
+	}
+
+	var results []benchmarkResult
+	text := ""
+	if *textPath != "" {
+		data, err := os.ReadFile(*textPath)
+		if err != nil {
+			log.Errorf("Failed to read text file: %v", err)
+			os.Exit(1)
+		}
+		text = string(data)
+	}
+
+	if *audioPath != "" {
+		for _, variant := range spec.Transcription {
+			result := runTranscription(cfg, variant, *audioPath)
+			log.Infof("Transcription %s finished in %dms", result.Name, result.LatencyMS)
+			if text == "" && result.Error == "" {
+				text = result.Output
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(spec.Summarization) > 0 {
+		if strings.TrimSpace(text) == "" {
+			log.Errorf("No text to summarize: provide --text or an --audio file at least one transcription variant succeeds on")
+			os.Exit(1)
+		}
+		promptManager := config.NewPromptManager()
+		if err := promptManager.LoadPrompts(cfg.PromptsDir); err != nil {
+			log.Errorf("Failed to load prompts: %v", err)
+			os.Exit(1)
+		}
+		prompt, err := promptManager.ResolvePrompt(*promptInput)
+		if err != nil {
+			log.Errorf("Failed to resolve prompt: %v", err)
+			os.Exit(1)
+		}
+		for _, variant := range spec.Summarization {
+			result := runSummarization(cfg, variant, text, prompt)
+			log.Infof("Summarization %s finished in %dms", result.Name, result.LatencyMS)
+			results = append(results, result)
+		}
+	}
+
+	printResults(results)
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err == nil {
+		err = os.WriteFile(*reportPath, data, 0644)
+	}
+	if err != nil {
+		log.Errorf("Failed to write report: %v", err)
+		os.Exit(1)
+	}
+	log.Infof("Full results written to %s", *reportPath)
+}
+
+// loadSpec reads the benchmark spec and names unnamed variants after their position
+func loadSpec(path string) (*benchmarkSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec benchmarkSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(spec.Transcription) == 0 && len(spec.Summarization) == 0 {
+		return nil, fmt.Errorf("%s lists no transcription or summarization variants", path)
+	}
+	for i := range spec.Transcription {
+		if spec.Transcription[i].Name == "" {
+			spec.Transcription[i].Name = fmt.Sprintf("transcription-%d", i+1)
+		}
+	}
+	for i := range spec.Summarization {
+		if spec.Summarization[i].Name == "" {
+			spec.Summarization[i].Name = fmt.Sprintf("summarization-%d", i+1)
+		}
+	}
+	return &spec, nil
+}
+
+// runTranscription transcribes the audio with one variant and times it
+func runTranscription(cfg *config.AppConfig, variant transcriberSpec, audioPath string) benchmarkResult {
+	result := benchmarkResult{Kind: "transcription", Name: variant.Name}
+
+	var provider interfaces.TranscriptionProvider
+	switch variant.Provider {
+	case "", "whisper":
+		whisperPath, modelPath := cfg.WhisperPath, cfg.WhisperModelPath
+		if variant.WhisperPath != "" {
+			whisperPath = variant.WhisperPath
+		}
+		if variant.ModelPath != "" {
+			modelPath = variant.ModelPath
+		}
+		provider = transcription.NewWhisperCppTranscriptionProvider(whisperPath, modelPath, cfg.TmpDir)
+	case "mock":
+		provider = mock.NewTranscriptionProvider(cfg.TmpDir)
+	default:
+		result.Error = fmt.Sprintf("unsupported transcription provider %q (supported: whisper, mock)", variant.Provider)
+		return result
+	}
+
+	start := time.Now()
+	transcriptPath, err := provider.TranscribeAudio(audioPath)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer os.Remove(transcriptPath)
+	data, err := os.ReadFile(transcriptPath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = string(data)
+	return result
+}
+
+// runSummarization summarizes the text with one variant in a single call, timing it and
+// recording token usage and estimated cost
+func runSummarization(cfg *config.AppConfig, variant summarizerSpec, text, prompt string) benchmarkResult {
+	result := benchmarkResult{Kind: "summarization", Name: variant.Name}
+
+	variantCfg := *cfg
+	if variant.Model != "" {
+		variantCfg.OpenAIModel = variant.Model
+	}
+	if variant.MaxTokens > 0 {
+		variantCfg.OpenAIMaxTokens = variant.MaxTokens
+	}
+	if variant.PromptCostPer1K > 0 || variant.CompletionCostPer1K > 0 {
+		variantCfg.OpenAIPromptCostPer1K = variant.PromptCostPer1K
+		variantCfg.OpenAICompletionCostPer1K = variant.CompletionCostPer1K
+	}
+
+	var provider interfaces.SummarizationProvider
+	switch variant.Provider {
+	case "", "openai":
+		openaiProvider, err := summarization.NewOpenAISummarizationProviderFromConfig(&variantCfg)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		provider = openaiProvider
+	case "mock":
+		provider = mock.NewSummarizationProvider(cfg.TmpDir)
+	default:
+		result.Error = fmt.Sprintf("unsupported summarization provider %q (supported: openai, mock)", variant.Provider)
+		return result
+	}
+
+	ctx, recorder := interfaces.WithUsageRecorder(context.Background())
+	start := time.Now()
+	summaryPath, err := provider.SummarizeText(ctx, text, prompt, variantCfg.OpenAIMaxTokens)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	usage := recorder.Usage()
+	usage.CostUSD = variantCfg.EstimateCost(usage.PromptTokens, usage.CompletionTokens)
+	result.Usage = &usage
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer os.Remove(summaryPath)
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = string(data)
+	return result
+}
+
+// printResults prints one row per variant with the start of its output
+func printResults(results []benchmarkResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tLATENCY\tTOKENS\tCOST (USD)\tCHARS\tOUTPUT")
+	for _, result := range results {
+		tokens, cost := "-", "-"
+		if result.Usage != nil {
+			tokens = fmt.Sprintf("%d", result.Usage.TotalTokens)
+			cost = fmt.Sprintf("%.4f", result.Usage.CostUSD)
+		}
+		output := preview(result.Output, 60)
+		if result.Error != "" {
+			output = "ERROR: " + preview(result.Error, 60)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", result.Kind, result.Name,
+			(time.Duration(result.LatencyMS) * time.Millisecond).String(), tokens, cost, len(result.Output), output)
+	}
+	w.Flush()
+}
+
+// preview returns the first n runes of s on a single line
+func preview(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return s
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,31 +17,78 @@ import (
 	"video-summarizer-go/internal/api"
 	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/digest"
 	"video-summarizer-go/internal/logging"
+	"video-summarizer-go/internal/notifications"
+	"video-summarizer-go/internal/providers/transcription"
+	"video-summarizer-go/internal/schedules"
 	"video-summarizer-go/internal/services"
 	"video-summarizer-go/internal/sources"
 )
 
 func main() {
-	if err := logging.SetupLogging("logging.yaml"); err != nil {
+	requestLogs, err := logging.SetupLogging("logging.yaml")
+	if err != nil {
 		panic(err)
 	}
 
 	serviceConfigPath := flag.String("service-config", "service.yaml", "Path to service configuration file")
+	validateOnly := flag.Bool("validate-config", false, "Validate all configuration files and exit")
+	strict := flag.Bool("strict", false, "Refuse to start if configuration validation fails")
+	env := flag.String("env", "", "Config environment overlay to apply, e.g. prod loads config.prod.yaml over config.yaml (default: $VS_ENV)")
 	flag.Parse()
 
+	if *env != "" {
+		config.SetEnvironment(*env)
+	}
+
 	// Load service configuration
 	serviceCfg, err := config.LoadServiceConfig(*serviceConfigPath)
 	if err != nil {
+		if *validateOnly {
+			fmt.Fprintf(os.Stderr, "Configuration invalid:\n  - %v\n", err)
+			os.Exit(1)
+		}
 		log.Fatalf("Failed to load service config: %v", err)
 	}
 
 	// Load application configuration
 	appCfg, err := config.LoadConfig(serviceCfg.EngineConfigPath)
 	if err != nil {
+		if *validateOnly {
+			fmt.Fprintf(os.Stderr, "Configuration invalid:\n  - %v\n", err)
+			os.Exit(1)
+		}
 		log.Fatalf("Failed to load app config: %v", err)
 	}
 
+	// Install missing whisper models before the dependency checks look for them
+	modelManager := transcription.NewModelManager(appCfg.WhisperModels.RegistryURL, appCfg.WhisperModels.Download, appCfg.WhisperModels.Checksums, appCfg.WhisperModelPaths())
+	if !*validateOnly {
+		if err := modelManager.Sync(context.Background()); err != nil {
+			log.Warnf("Whisper models are not all installed: %v", err)
+		}
+	}
+
+	validationErrors := validateConfig(serviceCfg, appCfg, *validateOnly || *strict)
+	if *validateOnly {
+		if len(validationErrors) > 0 {
+			fmt.Fprintf(os.Stderr, "Configuration invalid (%d problem(s)):\n", len(validationErrors))
+			for _, verr := range validationErrors {
+				fmt.Fprintf(os.Stderr, "  - %v\n", verr)
+			}
+			os.Exit(1)
+		}
+		fmt.Println("Configuration OK")
+		os.Exit(0)
+	}
+	for _, verr := range validationErrors {
+		log.Warnf("Config validation: %v", verr)
+	}
+	if *strict && len(validationErrors) > 0 {
+		log.Fatalf("Refusing to start in strict mode: %d configuration problem(s) found", len(validationErrors))
+	}
+
 	// Initialize core pipeline using SetupEngine
 	engine, _, promptManager, err := core.SetupEngine(appCfg)
 	if err != nil {
@@ -48,6 +97,8 @@ func main() {
 
 	// Initialize video submission service
 	submissionService := services.NewVideoSubmissionService(engine)
+	submissionService.SetUserQuotas(serviceCfg.Users.MaxActiveRequests, serviceCfg.Users.MaxRequestsPerDay)
+	submissionService.SetSourceLimits(serviceCfg.BackgroundSources.SourceLimits())
 
 	// Initialize video source manager
 	sourceManager := sources.NewArtifactSourceManager()
@@ -55,40 +106,89 @@ func main() {
 	// Initialize API handler
 	apiHandler := api.NewAPIHandler(submissionService, promptManager, sourceManager)
 
+	// Notify users when their requests finish
+	notificationPrefs, err := notifications.NewPreferenceStore(serviceCfg.Notifications.PreferencesFile)
+	if err != nil {
+		log.Fatalf("Failed to load notification preferences: %v", err)
+	}
+	smtpCfg := notifications.SMTPConfig{
+		Host:     serviceCfg.Notifications.SMTP.Host,
+		Port:     serviceCfg.Notifications.SMTP.Port,
+		Username: serviceCfg.Notifications.SMTP.Username,
+		Password: serviceCfg.Notifications.SMTP.Password,
+		From:     serviceCfg.Notifications.SMTP.From,
+	}
+	notificationService := notifications.NewService(notificationPrefs, serviceCfg.Notifications.SlackBotToken, smtpCfg)
+	notificationService.Attach(engine.GetEventBus(), engine.GetStore())
+	notificationService.AttachGroups(engine.GetEventBus(), engine.GetGroupStatus)
+	apiHandler.SetNotificationService(notificationService)
+
+	// Roll summaries up into scheduled digests
+	digestScheduler := digest.NewScheduler(serviceCfg.Digests, engine.GetStore(), submissionService, notifications.NewEmailNotifier(smtpCfg), appCfg.TmpDir)
+	digestScheduler.Attach(engine.GetEventBus())
+	apiHandler.SetDigestScheduler(digestScheduler)
+
+	// Create source factory; its channel lists and skip_shorts also apply to scheduled searches
+	sourceFactory := sources.NewSourceFactory(submissionService)
+	sourceFactory.SetChannelFilter(serviceCfg.BackgroundSources.AllowChannels, serviceCfg.BackgroundSources.DenyChannels)
+	sourceFactory.SetSkipShorts(serviceCfg.BackgroundSources.SkipShorts)
+
+	// Re-submit URLs and searches on their recurrence
+	scheduler, err := schedules.NewScheduler(serviceCfg.Schedules.File, submissionService, func(query, channel string, maxVideos int) ([]string, error) {
+		return sources.FilteredSearch("schedule", []sources.SearchFilter{sourceFactory.ChannelFilter(), sources.ShortsFilter(sourceFactory.SkipShorts())}, appCfg.YtDlpPath, query, channel, maxVideos, 50)
+	})
+	if err != nil {
+		log.Fatalf("Failed to load schedules: %v", err)
+	}
+	apiHandler.SetScheduler(scheduler)
+	apiHandler.SetModelManager(modelManager)
+	apiHandler.SetRequestLogs(requestLogs)
+
 	// Set up HTTP routes
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/submit", apiHandler.SubmitVideo)
+	mux.HandleFunc("/api/submit/document", apiHandler.SubmitDocument)
+	mux.HandleFunc("/api/compare", apiHandler.SubmitComparison)
 	mux.HandleFunc("/api/status", apiHandler.GetStatus)
+	mux.HandleFunc("/api/status/batch", apiHandler.GetStatusBatch)
 	mux.HandleFunc("/api/cancel", apiHandler.CancelRequest)
+	mux.HandleFunc("/api/retry", apiHandler.RetryRequest)
+	mux.HandleFunc("/api/requests", apiHandler.ListRequests)
+	mux.HandleFunc("/api/export", apiHandler.ExportRequests)
+	mux.HandleFunc("/api/completed", apiHandler.ListCompleted)
+	mux.HandleFunc("/api/evaluations", apiHandler.EvaluationStats)
+	mux.HandleFunc("/api/requests/search", apiHandler.SearchRequests)
+	mux.HandleFunc("/api/requests/", apiHandler.RequestResource)
+	mux.HandleFunc("/api/groups/", apiHandler.Group)
 	mux.HandleFunc("/api/health", apiHandler.Health)
 	mux.HandleFunc("/api/prompts", apiHandler.ListPrompts)
+	mux.HandleFunc("/api/notifications/preferences", apiHandler.NotificationPreferences)
+	mux.HandleFunc("/api/digests", apiHandler.ListDigests)
+	mux.HandleFunc("/api/digests/run", apiHandler.RunDigest)
+	mux.HandleFunc("/api/schedules", apiHandler.Schedules)
+	mux.HandleFunc("/api/schedules/", apiHandler.Schedule)
+	mux.HandleFunc("/api/feeds/", apiHandler.Feed)
+	mux.HandleFunc("/api/sources/", apiHandler.PushToSource)
+	mux.HandleFunc("/api/models", apiHandler.ListModels)
+	mux.HandleFunc("/api/capabilities", apiHandler.Capabilities)
 
-	// Create source factory
-	sourceFactory := sources.NewSourceFactory(submissionService)
+	mux.HandleFunc("/api/admin/reload", apiHandler.ReloadConfig)
+	mux.HandleFunc("/api/admin/drain", apiHandler.Drain)
+	mux.HandleFunc("/api/admin/pause", apiHandler.Pause)
+	mux.HandleFunc("/api/admin/resume", apiHandler.Resume)
+	mux.HandleFunc("/api/admin/events", apiHandler.RequestEvents)
+	mux.HandleFunc("/api/admin/replay", apiHandler.ReplayRequest)
+	mux.HandleFunc("/api/admin/rebuild", apiHandler.RebuildState)
+	mux.HandleFunc("/api/admin/purge", apiHandler.PurgeData)
+	mux.HandleFunc("/api/admin/models/sync", apiHandler.SyncModels)
+	mux.HandleFunc("/api/admin/logging", apiHandler.LogLevels)
+	mux.HandleFunc("/livez", apiHandler.Livez)
+	mux.HandleFunc("/readyz", apiHandler.Readyz)
+	apiHandler.SetLifecycleConfig(serviceCfg.Lifecycle.MaxQueuedTasks, serviceCfg.GetDrainTimeout())
+	apiHandler.SetFeedConfig(serviceCfg.Feeds.Tokens, serviceCfg.Feeds.MaxItems, serviceCfg.Feeds.BaseURL)
 
 	// Add sources from configuration
-	for _, sourceConfig := range serviceCfg.BackgroundSources.Sources {
-		if !sourceConfig.Enabled {
-			log.Warnf("Skipping disabled source: %s", sourceConfig.Name)
-			continue
-		}
-
-		_, err := sourceConfig.GetIntervalDuration()
-		if err != nil {
-			log.Errorf("Invalid interval for source %s: %v", sourceConfig.Name, err)
-			continue
-		}
-
-		source, err := sourceFactory.CreateSource(&sourceConfig, appCfg)
-		if err != nil {
-			log.Errorf("Failed to create source %s: %v", sourceConfig.Name, err)
-			continue
-		}
-
-		sourceManager.AddSource(sourceConfig.Name, source, &sourceConfig)
-
-		log.Infof("Added source: %s (type: %s, interval: %s)", sourceConfig.Name, sourceConfig.Type, sourceConfig.Interval)
-	}
+	sourceManager.LoadSources(sourceFactory, serviceCfg.BackgroundSources.Sources, appCfg)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -103,6 +203,52 @@ func main() {
 	if err := sourceManager.StartAll(ctx); err != nil {
 		log.Warnf("Failed to start some video sources: %v", err)
 	}
+	digestScheduler.Start()
+	scheduler.Start()
+	submissionService.StartScheduler()
+
+	// Config reload re-reads service.yaml, sources and config.yaml and applies the runtime-safe parts
+	var reloadMu sync.Mutex
+	reloadConfig := func() error {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+
+		if submissionService.IsDraining() {
+			return fmt.Errorf("service is draining")
+		}
+
+		log.Infof("Reloading configuration from %s", *serviceConfigPath)
+		newServiceCfg, err := config.LoadServiceConfig(*serviceConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to load service config: %w", err)
+		}
+		newAppCfg, err := config.LoadConfig(newServiceCfg.EngineConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to load app config: %w", err)
+		}
+		if newServiceCfg.Server != serviceCfg.Server {
+			log.Warnf("Server address changes require a restart and were not applied")
+		}
+		if !reflect.DeepEqual(newServiceCfg.Digests, serviceCfg.Digests) {
+			log.Warnf("Digest changes require a restart and were not applied")
+		}
+		if !reflect.DeepEqual(newServiceCfg.Feeds, serviceCfg.Feeds) {
+			log.Warnf("Feed changes require a restart and were not applied")
+		}
+		if err := engine.ApplyConfig(newAppCfg); err != nil {
+			return err
+		}
+		submissionService.SetUserQuotas(newServiceCfg.Users.MaxActiveRequests, newServiceCfg.Users.MaxRequestsPerDay)
+		submissionService.SetSourceLimits(newServiceCfg.BackgroundSources.SourceLimits())
+		sourceFactory.SetChannelFilter(newServiceCfg.BackgroundSources.AllowChannels, newServiceCfg.BackgroundSources.DenyChannels)
+		sourceFactory.SetSkipShorts(newServiceCfg.BackgroundSources.SkipShorts)
+		if err := sourceManager.ReplaceSources(ctx, sourceFactory, newServiceCfg.BackgroundSources.Sources, newAppCfg); err != nil {
+			return fmt.Errorf("failed to restart sources: %w", err)
+		}
+		log.Infof("Configuration reloaded")
+		return nil
+	}
+	apiHandler.SetReloadFunc(reloadConfig)
 
 	// Start the HTTP server in a goroutine
 	go func() {
@@ -118,6 +264,17 @@ func main() {
 		engine.Start()
 	}()
 
+	// Reload configuration on SIGHUP
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			if err := reloadConfig(); err != nil {
+				log.Errorf("Config reload failed: %v", err)
+			}
+		}
+	}()
+
 	// Wait for shutdown signal
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -125,14 +282,23 @@ func main() {
 
 	log.Println("Shutting down...")
 
-	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	// Stop video sources
+	// Stop video sources, digests, schedules, scheduled requests and new submissions, then let queued work finish
+	digestScheduler.Stop()
+	scheduler.Stop()
+	submissionService.StopScheduler()
+	submissionService.StartDrain()
 	if err := sourceManager.StopAll(); err != nil {
 		log.Errorf("Error stopping video sources: %v", err)
 	}
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), serviceCfg.GetDrainTimeout())
+	if remaining := submissionService.WaitForIdle(drainCtx); remaining > 0 {
+		log.Warnf("Drain timeout reached with %d request(s) still active", remaining)
+	}
+	drainCancel()
+
+	// Graceful shutdown
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
 
 	// Stop HTTP server
 	if err := server.Shutdown(shutdownCtx); err != nil {
@@ -144,3 +310,21 @@ func main() {
 
 	log.Println("Shutdown complete")
 }
+
+// whisperCheckTimeout bounds checking the whisper.cpp binary and loading every model once
+const whisperCheckTimeout = 2 * time.Minute
+
+// validateConfig runs all configuration checks for the service, engine, sources and prompts,
+// and checks the whisper.cpp binary runs; with loadModels it also checks the binary can load
+// the configured models, which is slow enough to be left to --validate-config and --strict
+func validateConfig(serviceCfg *config.ServiceConfig, appCfg *config.AppConfig, loadModels bool) []error {
+	var errs []error
+	errs = append(errs, serviceCfg.Validate()...)
+	errs = append(errs, appCfg.Validate()...)
+	errs = append(errs, config.ValidatePrompts(appCfg.PromptsDir, serviceCfg.BackgroundSources.Sources, appCfg.MaxTokensLimit())...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), whisperCheckTimeout)
+	defer cancel()
+	errs = append(errs, transcription.CheckWhisperSetup(ctx, appCfg.WhisperPath, appCfg.WhisperModelPaths(), appCfg.TmpDir, loadModels)...)
+	return errs
+}
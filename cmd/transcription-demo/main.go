@@ -21,7 +21,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	provider := transcription.NewWhisperCppTranscriptionProvider(*whisperPath, *modelPath)
+	provider := transcription.NewWhisperCppTranscriptionProvider(*whisperPath, *modelPath, "")
 	fmt.Println("Transcribing:", *audioPath)
 	transcript, err := provider.TranscribeAudio(*audioPath)
 	if err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -22,7 +23,7 @@ func main() {
 	provider := video.NewYtDlpVideoProvider("tools/yt-dlp", os.TempDir())
 
 	log.Infof("Getting video info for: %s", *url)
-	info, err := provider.GetVideoInfo(*url)
+	info, err := provider.GetVideoInfo(context.Background(), *url)
 	if err != nil {
 		log.Errorf("Error getting video info: %v", err)
 		os.Exit(1)
@@ -35,7 +36,7 @@ func main() {
 	log.Infof("View Count: %.0f", info["view_count"])
 
 	log.Debugf("Downloading audio...")
-	audioPath, err := provider.DownloadAudio(*url)
+	audioPath, err := provider.DownloadAudio(context.Background(), *url)
 	if err != nil {
 		log.Errorf("Error downloading audio: %v", err)
 		os.Exit(1)
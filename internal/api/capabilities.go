@@ -0,0 +1,30 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"video-summarizer-go/internal/services"
+)
+
+// CapabilitiesResponse describes the deployment's providers, source types and limits
+type CapabilitiesResponse struct {
+	*services.Capabilities
+	MaxBatchStatusIDs int `json:"max_batch_status_ids"`
+}
+
+// Capabilities handles GET /api/capabilities, reporting the configured providers and models,
+// the source types requests can have and the limits submissions are checked against, so
+// clients can adapt to the deployment
+func (h *APIHandler) Capabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CapabilitiesResponse{
+		Capabilities:      h.submissionService.GetCapabilities(),
+		MaxBatchStatusIDs: maxBatchStatusIDs,
+	})
+}
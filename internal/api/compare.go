@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// CompareRequest represents a request for one comparative summary of several videos
+type CompareRequest struct {
+	URLs       []string          `json:"urls,omitempty"`        // New videos to summarize and compare
+	RequestIDs []string          `json:"request_ids,omitempty"` // Past requests to include
+	Prompt     interfaces.Prompt `json:"prompt"`                // Comparison prompt (default: agree/disagree analysis)
+	Category   string            `json:"category,omitempty"`
+	User       string            `json:"user,omitempty"`
+	// Overrides the configured output of the comparison
+	Output *interfaces.OutputTarget `json:"output,omitempty"`
+	// Labels stored with the comparison and the requests it submits
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Priority string            `json:"priority,omitempty"` // high, normal (default) or low
+	// Submit even when the daily or monthly spend budget is exceeded
+	OverrideBudget bool `json:"override_budget,omitempty"`
+	// Group ID collecting the comparison with other requests
+	Group string `json:"group,omitempty"`
+}
+
+// CompareResponse represents the response from submitting a comparison
+type CompareResponse struct {
+	RequestID          string    `json:"request_id"`
+	ComparedRequestIDs []string  `json:"compared_request_ids"`
+	Status             string    `json:"status"`
+	SubmittedAt        time.Time `json:"submitted_at"`
+}
+
+// SubmitComparison handles POST /api/compare. The comparison request completes after all
+// compared requests have finished; its progress is available from /api/status.
+func (h *APIHandler) SubmitComparison(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	category := req.Category
+	if category == "" {
+		category = "general"
+	}
+	maxTokens := services.DefaultMaxTokens
+	opts := services.SubmitOptions{
+		User:           requestUser(r, req.User),
+		Output:         req.Output,
+		Tags:           req.Tags,
+		Metadata:       req.Metadata,
+		Priority:       req.Priority,
+		OverrideBudget: req.OverrideBudget,
+		Group:          req.Group,
+	}
+	requestID, childIDs, err := h.submissionService.SubmitComparison(req.URLs, req.RequestIDs, req.Prompt, category, maxTokens, opts)
+	if errors.Is(err, services.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if errors.Is(err, services.ErrAtCapacity) {
+		writeCapacityError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrBudgetExceeded) {
+		writeBudgetError(w, err)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to submit comparison: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := CompareResponse{
+		RequestID:          requestID,
+		ComparedRequestIDs: childIDs,
+		Status:             "submitted",
+		SubmittedAt:        time.Now(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"video-summarizer-go/internal/services"
+)
+
+// maxCompletedLimit caps how many completed requests one poll returns
+const maxCompletedLimit = 500
+
+// ListCompleted handles GET /api/completed?since_cursor=...&category=...&user=...&source=...&limit=...,
+// the requests completed after the cursor, oldest first, with their summaries. It is meant
+// for polling integrations such as Zapier and Make: each poll passes the next_cursor of
+// the last one, and each item's id is unique to that completion. Without since_cursor it
+// starts from the oldest completion kept. The user defaults to the X-User header when not given.
+func (h *APIHandler) ListCompleted(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := services.CompletedFilter{
+		Category: query.Get("category"),
+		User:     requestUser(r, query.Get("user")),
+		Source:   query.Get("source"),
+	}
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxCompletedLimit {
+			http.Error(w, "limit must be between 1 and 500", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.submissionService.CompletedSince(query.Get("since_cursor"), filter, limit)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list completed requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"video-summarizer-go/internal/digest"
+	"video-summarizer-go/internal/services"
+)
+
+// ListDigests handles GET /api/digests
+func (h *APIHandler) ListDigests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.digests == nil {
+		http.Error(w, "Digests are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	statuses := h.digests.Statuses()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"digests": statuses,
+		"count":   len(statuses),
+	})
+}
+
+// RunDigest handles POST /api/digests/run?name=..., generating a digest over the window ending now
+func (h *APIHandler) RunDigest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.digests == nil {
+		http.Error(w, "Digests are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Digest name is required", http.StatusBadRequest)
+		return
+	}
+
+	requestID, err := h.digests.RunNow(name)
+	if errors.Is(err, digest.ErrNothingToDigest) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, services.ErrAtCapacity) {
+		writeCapacityError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrBudgetExceeded) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to run digest: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"request_id": requestID,
+		"status":     "submitted",
+	})
+}
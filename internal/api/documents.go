@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// maxUploadMemory is how much of a multipart upload is buffered in memory before spilling to disk
+const maxUploadMemory = 10 << 20
+
+// maxUploadFormOverhead allows for the multipart headers and form fields around the file
+const maxUploadFormOverhead = 1 << 20
+
+// SubmitDocument handles POST /api/submit/document, a multipart form upload of a PDF or
+// text file. Form fields: file (required), prompt_type ("id" or "text", default "id"),
+// prompt, category and user.
+func (h *APIHandler) SubmitDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if limit := h.submissionService.MaxDocumentBytes(); limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit+maxUploadFormOverhead)
+	}
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, services.ErrDocumentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Invalid multipart form: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "File is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	prompt := interfaces.Prompt{
+		Type:   interfaces.PromptType(r.FormValue("prompt_type")),
+		Prompt: r.FormValue("prompt"),
+	}
+	if prompt.Type == "" {
+		prompt.Type = interfaces.PromptTypeID
+	}
+	category := r.FormValue("category")
+	if category == "" {
+		category = "general"
+	}
+	maxTokens := services.DefaultMaxTokens
+	if _, err := interfaces.ParsePriority(r.FormValue("priority")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateGroup(r.FormValue("group")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := services.SubmitOptions{
+		User:           requestUser(r, r.FormValue("user")),
+		Priority:       r.FormValue("priority"),
+		OverrideBudget: r.FormValue("override_budget") == "true",
+		Group:          r.FormValue("group"),
+	}
+
+	requestID, err := h.submissionService.SubmitUploadedDocument(header.Filename, file, prompt, category, maxTokens, opts)
+	if errors.Is(err, services.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if errors.Is(err, services.ErrAtCapacity) {
+		writeCapacityError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrBudgetExceeded) {
+		writeBudgetError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrDocumentTooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to submit document: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := SubmitVideoResponse{
+		RequestID:   requestID,
+		Status:      "submitted",
+		SubmittedAt: time.Now(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
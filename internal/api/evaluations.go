@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// EvaluationStats handles GET /api/evaluations?from=...
+// Summary evaluation scores grouped by prompt and summarizer; from accepts a date
+// (2006-01-02) or RFC 3339 timestamp and defaults to all evaluations in the store.
+func (h *APIHandler) EvaluationStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, err := parseExportTime(r.URL.Query().Get("from"), false)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid from: %v", err), http.StatusBadRequest)
+		return
+	}
+	stats, err := h.submissionService.EvaluationStats(from)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load evaluations: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"groups": stats})
+}
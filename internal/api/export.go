@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// exportCSVHeader lists the CSV columns, in order
+var exportCSVHeader = []string{
+	"request_id", "url", "title", "channel", "category", "source_type", "prompt_type", "prompt",
+	"status", "error", "error_code", "created_at", "completed_at", "duration_seconds", "output_path", "summary",
+	"prompt_tokens", "completion_tokens", "total_tokens", "cost_usd",
+	"transcript_score", "transcript_low_confidence", "evaluation_score", "summarizer",
+}
+
+// ExportRequests handles GET /api/export?format=jsonl|csv&from=...&to=...&status=...
+// from and to accept dates (2006-01-02) or RFC 3339 timestamps; status defaults to
+// completed, and "all" exports every status.
+func (h *APIHandler) ExportRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := services.ExportFilter{Status: interfaces.StatusCompleted}
+	if status := query.Get("status"); status == "all" {
+		filter.Status = ""
+	} else if status != "" {
+		filter.Status = interfaces.ProcessingStatus(status)
+	}
+
+	var err error
+	if filter.From, err = parseExportTime(query.Get("from"), false); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid from: %v", err), http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseExportTime(query.Get("to"), true); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid to: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.submissionService.ExportRequests(filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	filename := "requests-" + time.Now().Format("20060102-150405")
+	switch format := query.Get("format"); format {
+	case "", "jsonl":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".jsonl"))
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			encoder.Encode(record)
+		}
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		writer := csv.NewWriter(w)
+		writer.Write(exportCSVHeader)
+		for _, record := range records {
+			writer.Write(exportCSVRow(record))
+		}
+		writer.Flush()
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported format %q (use jsonl or csv)", format), http.StatusBadRequest)
+	}
+}
+
+// parseExportTime parses a date or RFC 3339 timestamp. A bare date used as the end of
+// the range includes that whole day.
+func parseExportTime(value string, endOfRange bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// exportCSVRow returns the CSV columns of a record in exportCSVHeader order
+func exportCSVRow(record services.ExportRecord) []string {
+	completedAt := ""
+	if record.CompletedAt != nil {
+		completedAt = record.CompletedAt.Format(time.RFC3339)
+	}
+	evaluationScore := ""
+	if record.EvaluationScore != nil {
+		evaluationScore = strconv.FormatFloat(*record.EvaluationScore, 'f', 2, 64)
+	}
+	transcriptScore := ""
+	if record.TranscriptScore != nil {
+		transcriptScore = strconv.FormatFloat(*record.TranscriptScore, 'f', 3, 64)
+	}
+	return []string{
+		record.RequestID,
+		record.URL,
+		record.Title,
+		record.Channel,
+		record.Category,
+		record.SourceType,
+		record.PromptType,
+		record.Prompt,
+		record.Status,
+		record.Error,
+		record.ErrorCode,
+		record.CreatedAt.Format(time.RFC3339),
+		completedAt,
+		strconv.FormatFloat(record.DurationSeconds, 'f', -1, 64),
+		record.OutputPath,
+		record.Summary,
+		strconv.Itoa(record.PromptTokens),
+		strconv.Itoa(record.CompletionTokens),
+		strconv.Itoa(record.TotalTokens),
+		strconv.FormatFloat(record.CostUSD, 'f', 6, 64),
+		transcriptScore,
+		strconv.FormatBool(record.TranscriptLowConfidence),
+		evaluationScore,
+		record.Summarizer,
+	}
+}
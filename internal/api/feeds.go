@@ -0,0 +1,230 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// feedAllCategories is the feed category that includes every category
+const feedAllCategories = "all"
+
+// SetFeedConfig enables the summary feeds. Feeds are disabled when no tokens are set.
+func (h *APIHandler) SetFeedConfig(tokens []string, maxItems int, baseURL string) {
+	h.feedTokens = tokens
+	h.feedMaxItems = maxItems
+	h.feedBaseURL = strings.TrimRight(baseURL, "/")
+}
+
+// atomFeed is an Atom 1.0 feed document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Updated  string      `xml:"updated"`
+	Link     atomLink    `xml:"link"`
+	Category *atomCat    `xml:"category,omitempty"`
+	Content  atomContent `xml:"content"`
+}
+
+type atomCat struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// rssFeed is an RSS 2.0 feed document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Feed handles GET /api/feeds/<category>.atom and /api/feeds/<category>.rss, listing the
+// most recent summaries in a category ("all" for every category). The feed token is
+// taken from the token query parameter, since most feed readers cannot set headers, or
+// from an Authorization: Bearer header.
+func (h *APIHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(h.feedTokens) == 0 {
+		http.Error(w, "Feeds are not enabled", http.StatusNotFound)
+		return
+	}
+	if !h.validFeedToken(r) {
+		http.Error(w, "Invalid or missing feed token", http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/feeds/")
+	format := "atom"
+	switch {
+	case strings.HasSuffix(name, ".atom"):
+		name = strings.TrimSuffix(name, ".atom")
+	case strings.HasSuffix(name, ".rss"):
+		name = strings.TrimSuffix(name, ".rss")
+		format = "rss"
+	}
+	category, err := url.PathUnescape(name)
+	if err != nil || category == "" || strings.Contains(category, "/") {
+		http.Error(w, "Feed path must be /api/feeds/<category>.atom or /api/feeds/<category>.rss", http.StatusNotFound)
+		return
+	}
+
+	filter := category
+	if category == feedAllCategories {
+		filter = ""
+	}
+	states, err := h.submissionService.RecentSummaries(filter, h.feedMaxItems)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load summaries: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	baseURL := h.feedBaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		baseURL = scheme + "://" + r.Host
+	}
+	title := "Video summaries: " + category
+	updated := time.Now()
+	if len(states) > 0 {
+		updated = *states[0].CompletedAt
+	}
+
+	var doc interface{}
+	contentType := "application/atom+xml; charset=utf-8"
+	if format == "rss" {
+		contentType = "application/rss+xml; charset=utf-8"
+		channel := rssChannel{
+			Title:         title,
+			Link:          baseURL,
+			Description:   fmt.Sprintf("The most recent summaries in category %s", category),
+			LastBuildDate: updated.Format(time.RFC1123Z),
+			Items:         []rssItem{},
+		}
+		for _, state := range states {
+			channel.Items = append(channel.Items, rssItem{
+				Title:       feedItemTitle(state),
+				Link:        feedItemLink(state, baseURL),
+				Description: state.SummaryText,
+				Category:    state.Category,
+				GUID:        rssGUID{Value: feedItemID(state)},
+				PubDate:     state.CompletedAt.Format(time.RFC1123Z),
+			})
+		}
+		doc = rssFeed{Version: "2.0", Channel: channel}
+	} else {
+		feed := atomFeed{
+			ID:      baseURL + "/api/feeds/" + url.PathEscape(category),
+			Title:   title,
+			Updated: updated.Format(time.RFC3339),
+			Link:    []atomLink{{Href: baseURL}},
+		}
+		for _, state := range states {
+			feed.Entries = append(feed.Entries, atomEntry{
+				ID:       feedItemID(state),
+				Title:    feedItemTitle(state),
+				Updated:  state.CompletedAt.Format(time.RFC3339),
+				Link:     atomLink{Href: feedItemLink(state, baseURL), Rel: "alternate"},
+				Category: &atomCat{Term: state.Category},
+				Content:  atomContent{Type: "text", Body: state.SummaryText},
+			})
+		}
+		doc = feed
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	encoder.Encode(doc)
+}
+
+// validFeedToken reports whether the request carries one of the configured feed tokens
+func (h *APIHandler) validFeedToken(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if token == "" {
+		return false
+	}
+	for _, allowed := range h.feedTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// feedItemTitle names a feed item by its title, falling back to its URL
+func feedItemTitle(state *interfaces.ProcessingState) string {
+	if title := state.Title(); title != "" {
+		return title
+	}
+	return state.URL
+}
+
+// feedItemLink links a feed item to its source, or to its status for generated sources
+// such as digests and comparisons
+func feedItemLink(state *interfaces.ProcessingState, baseURL string) string {
+	if strings.HasPrefix(state.URL, "http://") || strings.HasPrefix(state.URL, "https://") {
+		return state.URL
+	}
+	return baseURL + "/api/status?request_id=" + url.QueryEscape(state.RequestID)
+}
+
+// feedItemID returns a stable, globally unique ID for a feed item
+func feedItemID(state *interfaces.ProcessingState) string {
+	return "urn:video-summarizer:" + state.RequestID
+}
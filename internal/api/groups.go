@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GroupCancelResponse lists the requests a group cancellation cancelled
+type GroupCancelResponse struct {
+	Group     string   `json:"group"`
+	Cancelled []string `json:"cancelled"`
+}
+
+// Group handles the endpoints of a request group, the requests submitted with the same "group":
+//
+//	GET                 returns the group's status counts and each request's status
+//	POST {group}/cancel cancels the group's requests that haven't finished
+func (h *APIHandler) Group(w http.ResponseWriter, r *http.Request) {
+	group, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/groups/"), "/")
+	group, _ = url.PathUnescape(group)
+	if group == "" || (action != "" && action != "cancel") {
+		http.Error(w, "Path must be /api/groups/<group> or /api/groups/<group>/cancel", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "cancel" && r.Method == http.MethodPost:
+		status, err := h.submissionService.GetGroupStatus(group)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get group: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if status == nil {
+			http.Error(w, fmt.Sprintf("Group not found: %s", group), http.StatusNotFound)
+			return
+		}
+		cancelled, err := h.submissionService.CancelGroup(group)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to cancel group: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GroupCancelResponse{Group: group, Cancelled: cancelled})
+	case action == "" && r.Method == http.MethodGet:
+		status, err := h.submissionService.GetGroupStatus(group)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get group: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if status == nil {
+			http.Error(w, fmt.Sprintf("Group not found: %s", group), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -1,13 +1,26 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/digest"
 	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/logging"
+	"video-summarizer-go/internal/notifications"
+	"video-summarizer-go/internal/providers/summarization"
+	"video-summarizer-go/internal/providers/transcription"
+	"video-summarizer-go/internal/providers/video"
+	"video-summarizer-go/internal/schedules"
 	"video-summarizer-go/internal/services"
 	"video-summarizer-go/internal/sources"
 )
@@ -17,6 +30,17 @@ type APIHandler struct {
 	submissionService *services.VideoSubmissionService
 	promptManager     *config.PromptManager
 	sourceManager     *sources.ArtifactSourceManager
+	reloadFunc        func() error
+	notifications     *notifications.Service
+	digests           *digest.Scheduler
+	schedules         *schedules.Scheduler
+	models            *transcription.ModelManager
+	logs              *logging.RequestLogs
+	feedTokens        []string
+	feedMaxItems      int
+	feedBaseURL       string
+	maxQueuedTasks    int
+	drainTimeout      time.Duration
 }
 
 // NewAPIHandler creates a new API handler
@@ -28,42 +52,166 @@ func NewAPIHandler(submissionService *services.VideoSubmissionService, promptMan
 	}
 }
 
+// SetReloadFunc sets the function used by the config reload endpoint
+func (h *APIHandler) SetReloadFunc(reloadFunc func() error) {
+	h.reloadFunc = reloadFunc
+}
+
+// SetNotificationService enables the notification preference endpoints
+func (h *APIHandler) SetNotificationService(service *notifications.Service) {
+	h.notifications = service
+}
+
+// SetDigestScheduler enables the digest endpoints
+func (h *APIHandler) SetDigestScheduler(scheduler *digest.Scheduler) {
+	h.digests = scheduler
+}
+
+// SetScheduler enables the recurring schedule endpoints
+func (h *APIHandler) SetScheduler(scheduler *schedules.Scheduler) {
+	h.schedules = scheduler
+}
+
+// SetModelManager enables the whisper model endpoints
+func (h *APIHandler) SetModelManager(manager *transcription.ModelManager) {
+	h.models = manager
+}
+
+// SetRequestLogs enables the per-request log endpoint
+func (h *APIHandler) SetRequestLogs(logs *logging.RequestLogs) {
+	h.logs = logs
+}
+
+// SetLifecycleConfig sets the readiness backpressure threshold and the drain timeout
+func (h *APIHandler) SetLifecycleConfig(maxQueuedTasks int, drainTimeout time.Duration) {
+	h.maxQueuedTasks = maxQueuedTasks
+	h.drainTimeout = drainTimeout
+}
+
+// requestUser returns the user a request is made for: the X-User header set by an
+// authenticating proxy, else the user given in the request
+func requestUser(r *http.Request, given string) string {
+	if user := strings.TrimSpace(r.Header.Get("X-User")); user != "" {
+		return user
+	}
+	return strings.TrimSpace(given)
+}
+
+// writeBudgetError responds 429 when the spend budget is exceeded
+func writeBudgetError(w http.ResponseWriter, err error) {
+	http.Error(w, fmt.Sprintf("%v; set override_budget to submit anyway", err), http.StatusTooManyRequests)
+}
+
+// writeCapacityError responds 503 with a Retry-After hint when the engine is at capacity
+func writeCapacityError(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", "60")
+	http.Error(w, fmt.Sprintf("%v; try again later", err), http.StatusServiceUnavailable)
+}
+
 // SubmitVideoRequest represents a request to submit a video for processing
 type SubmitVideoRequest struct {
 	URL      string            `json:"url"`
 	Prompt   interfaces.Prompt `json:"prompt"`             // Unified prompt struct
 	Category string            `json:"category,omitempty"` // Category for folder organization (default: "general")
-	// No metadata field
+	User     string            `json:"user,omitempty"`     // Submitting user, for notifications, output folders and quotas
+	// "video" (default), "document" for a PDF or text file at an http(s) URL, or "article" for a web page
+	SourceType string `json:"source_type,omitempty"`
+	// Overrides the configured output, e.g. {"provider": "slack", "channel": "#research"}
+	Output *interfaces.OutputTarget `json:"output,omitempty"`
+	// Labels stored with the request, filterable in /api/requests and written to the output metadata
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// "high", "normal" (default) or "low"; background sources submit at low priority
+	Priority string `json:"priority,omitempty"`
+	// Submit even when the daily or monthly spend budget is exceeded
+	OverrideBudget bool `json:"override_budget,omitempty"`
+	// Whisper model name, or "fast" / "accurate"; by default the model is picked by video duration
+	WhisperQuality string `json:"whisper_quality,omitempty"`
+	// Cite the [HH:MM:SS] moment supporting each bullet of the summary; by default summary_citations applies
+	Citations *bool `json:"citations,omitempty"`
+	// Target summary length: "short", "medium", "detailed" or a word count; by default the prompt decides
+	SummaryLength string `json:"summary_length,omitempty"`
+	// "bullets" or "prose"; by default the prompt decides
+	SummaryStyle string `json:"summary_style,omitempty"`
+	// Start no earlier than this time (RFC 3339), e.g. off-peak; the request is "scheduled" until then
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	// Group ID collecting this request with others, e.g. a playlist, for /api/groups/<group>
+	Group string `json:"group,omitempty"`
 }
 
 // SubmitVideoResponse represents the response from submitting a video
 type SubmitVideoResponse struct {
-	RequestID   string    `json:"request_id"`
-	Status      string    `json:"status"`
-	SubmittedAt time.Time `json:"submitted_at"`
+	RequestID   string     `json:"request_id"`
+	Status      string     `json:"status"`
+	NotBefore   *time.Time `json:"not_before,omitempty"` // when a scheduled request starts
+	SubmittedAt time.Time  `json:"submitted_at"`
 }
 
 // StatusResponse represents the response from checking a request status
 type StatusResponse struct {
-	RequestID   string                 `json:"request_id"`
-	Status      string                 `json:"status"`
-	Progress    float64                `json:"progress"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
-	CompletedAt *time.Time             `json:"completed_at,omitempty"`
-	Error       string                 `json:"error,omitempty"`
-	VideoInfo   map[string]interface{} `json:"video_info,omitempty"`
-	Transcript  string                 `json:"transcript_path,omitempty"`
-	Summary     string                 `json:"summary_path,omitempty"`
-	OutputPath  string                 `json:"output_path,omitempty"`
+	RequestID string `json:"request_id"`
+	Status    string `json:"status"`
+	// Every status the request has had with when it entered it, e.g. to tell queue wait
+	// (pending) from processing time (running)
+	StatusHistory []interfaces.StatusChange `json:"status_history,omitempty"`
+	Progress      float64                   `json:"progress"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+	CompletedAt   *time.Time                `json:"completed_at,omitempty"`
+	NotBefore     *time.Time                `json:"not_before,omitempty"` // when a scheduled request starts
+	Error         string                    `json:"error,omitempty"`
+	// Category of the failure; retrying may help when error_retryable is true
+	ErrorCode      interfaces.ErrorCode   `json:"error_code,omitempty"`
+	ErrorRetryable bool                   `json:"error_retryable,omitempty"`
+	VideoInfo      map[string]interface{} `json:"video_info,omitempty"`
+	InfoPath       string                 `json:"info_path,omitempty"`      // full video metadata, until cleanup
+	ThumbnailPath  string                 `json:"thumbnail_path,omitempty"` // downloaded thumbnail, until cleanup
+	Transcript     string                 `json:"transcript_path,omitempty"`
+	// Confidence in the transcript; low_confidence means the summary may be unreliable
+	TranscriptQuality  *interfaces.TranscriptQuality `json:"transcript_quality,omitempty"`
+	Language           string                        `json:"language,omitempty"`            // spoken language of the transcript
+	WhisperModel       string                        `json:"whisper_model,omitempty"`       // model the transcript was made with
+	TranscriptionRoute string                        `json:"transcription_route,omitempty"` // local or cloud, when routed by video length
+	Summary            string                        `json:"summary_path,omitempty"`
+	OutputPath         string                        `json:"output_path,omitempty"` // link to the uploaded summary
+	// Every uploaded file the output provider verified, with its ID and link
+	Outputs []interfaces.OutputFile `json:"outputs,omitempty"`
+	// Request whose transcript this rerun summarized again, and the model it asked for
+	RerunOf      string `json:"rerun_of,omitempty"`
+	SummaryModel string `json:"summary_model,omitempty"`
+	// Earlier request this one was skipped or linked to as a near-duplicate
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	// Summary text written so far while it streams, or left by a failed call
+	PartialSummary string `json:"partial_summary,omitempty"`
+	// Summary length and style the request asked for
+	SummaryLength string `json:"summary_length,omitempty"`
+	SummaryStyle  string `json:"summary_style,omitempty"`
+	// Quality scores of the summary, when evaluation is enabled
+	Highlights *interfaces.Highlights        `json:"highlights,omitempty"` // key moments, with links into the video
+	Evaluation *interfaces.SummaryEvaluation `json:"evaluation,omitempty"`
+	// Number of redactions made per label before upload
+	Redactions map[string]int `json:"redactions,omitempty"`
+	// Outcome of each post-processing hook run after upload
+	Hooks []interfaces.HookResult `json:"hooks,omitempty"`
 }
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status         string         `json:"status"`
-	Timestamp      time.Time      `json:"timestamp"`
-	RequestCounts  map[string]int `json:"request_counts"`
-	EnabledSources []string       `json:"enabled_sources"`
+	Status         string                           `json:"status"`
+	Paused         bool                             `json:"paused"`
+	Timestamp      time.Time                        `json:"timestamp"`
+	RequestCounts  map[string]int                   `json:"request_counts"`
+	EnabledSources []string                         `json:"enabled_sources"`
+	Store          interfaces.StoreStats            `json:"store"`
+	TmpDir         *core.TmpDirUsage                `json:"tmp_dir,omitempty"`
+	VideoInfoCache *video.VideoInfoCacheStats       `json:"video_info_cache,omitempty"`
+	LLMCache       *summarization.SummaryCacheStats `json:"llm_cache,omitempty"`
+	// Spend against the budget, when one is configured; exceeded pauses background sources
+	Budget *services.BudgetStatus `json:"budget,omitempty"`
+	// Tasks being processed, with when each last showed progress
+	RunningTasks []core.RunningTask `json:"running_tasks"`
+	// Startup self-test results, when preflight is enabled
+	Preflight *core.PreflightStatus `json:"preflight,omitempty"`
 }
 
 // SubmitVideo handles POST /api/submit
@@ -84,18 +232,81 @@ func (h *APIHandler) SubmitVideo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Add API source metadata
-	// In the SubmitVideo handler, set SourceType and URL directly
-	// (Assume all current requests are for videos)
-	sourceType := "video"
+	sourceType := req.SourceType
+	switch sourceType {
+	case "":
+		sourceType = interfaces.SourceTypeVideo
+	case interfaces.SourceTypeVideo, interfaces.SourceTypeAudio:
+	case interfaces.SourceTypeDocument, interfaces.SourceTypeArticle:
+		// Local paths are only accepted through the upload endpoint
+		if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+			http.Error(w, fmt.Sprintf("%s URL must be an http(s) URL; use /api/submit/document to upload a file", sourceType), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported source_type %q (supported: video, audio, document, article)", sourceType), http.StatusBadRequest)
+		return
+	}
 	url := req.URL
 	category := req.Category
 	if category == "" {
 		category = "general"
 	}
 	prompt := req.Prompt
-	maxTokens := 10000 // Default value, can be made configurable
-	requestID, err := h.submissionService.SubmitVideo(url, prompt, sourceType, category, maxTokens)
+	maxTokens := services.DefaultMaxTokens
+	if _, err := interfaces.ParsePriority(req.Priority); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateOutputTarget(req.Output); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid output: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateWhisperQuality(req.WhisperQuality); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateGroup(req.Group); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateSummaryFormat(req.SummaryLength, req.SummaryStyle); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := services.SubmitOptions{
+		User:           requestUser(r, req.User),
+		Output:         req.Output,
+		Tags:           req.Tags,
+		Metadata:       req.Metadata,
+		Priority:       req.Priority,
+		OverrideBudget: req.OverrideBudget,
+		WhisperQuality: req.WhisperQuality,
+		Citations:      req.Citations,
+		SummaryLength:  req.SummaryLength,
+		SummaryStyle:   req.SummaryStyle,
+		Group:          req.Group,
+	}
+	if req.NotBefore != nil {
+		opts.NotBefore = *req.NotBefore
+	}
+	requestID, err := h.submissionService.SubmitVideoWithOptions(url, prompt, sourceType, category, maxTokens, opts)
+	if errors.Is(err, services.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if errors.Is(err, services.ErrAtCapacity) {
+		writeCapacityError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrBudgetExceeded) {
+		writeBudgetError(w, err)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to submit video: %v", err), http.StatusInternalServerError)
 		return
@@ -106,6 +317,10 @@ func (h *APIHandler) SubmitVideo(w http.ResponseWriter, r *http.Request) {
 		Status:      "submitted",
 		SubmittedAt: time.Now(),
 	}
+	if state, err := h.submissionService.GetRequestStatus(requestID); err == nil && state.Status == interfaces.StatusScheduled {
+		response.Status = string(interfaces.StatusScheduled)
+		response.NotBefore = state.NotBefore
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -138,22 +353,46 @@ func (h *APIHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := StatusResponse{
-		RequestID:   state.RequestID,
-		Status:      string(state.Status),
-		Progress:    state.Progress,
-		CreatedAt:   state.CreatedAt,
-		UpdatedAt:   state.UpdatedAt,
-		CompletedAt: state.CompletedAt,
-		Error:       state.Error,
-		VideoInfo:   state.VideoInfo,
-		Transcript:  state.Transcript,
-		Summary:     state.Summary,
-		OutputPath:  state.OutputPath,
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(newStatusResponse(state))
+}
+
+// newStatusResponse is the /api/status view of a request
+func newStatusResponse(state *interfaces.ProcessingState) StatusResponse {
+	return StatusResponse{
+		RequestID:          state.RequestID,
+		Status:             string(state.Status),
+		StatusHistory:      state.StatusHistory,
+		Progress:           state.Progress,
+		CreatedAt:          state.CreatedAt,
+		UpdatedAt:          state.UpdatedAt,
+		CompletedAt:        state.CompletedAt,
+		NotBefore:          state.NotBefore,
+		Error:              state.Error,
+		ErrorCode:          state.ErrorCode,
+		ErrorRetryable:     state.ErrorRetryable,
+		VideoInfo:          state.VideoInfo,
+		InfoPath:           state.InfoPath,
+		ThumbnailPath:      state.ThumbnailPath,
+		Transcript:         state.Transcript,
+		TranscriptQuality:  state.TranscriptQuality,
+		Language:           state.Language,
+		WhisperModel:       state.WhisperModel,
+		TranscriptionRoute: state.TranscriptionRoute,
+		Summary:            state.Summary,
+		OutputPath:         state.OutputPath,
+		Outputs:            state.Outputs,
+		RerunOf:            state.RerunOf,
+		SummaryModel:       state.SummaryModel,
+		DuplicateOf:        state.DuplicateOf,
+		PartialSummary:     state.PartialSummary,
+		SummaryLength:      state.SummaryLength,
+		SummaryStyle:       state.SummaryStyle,
+		Highlights:         state.Highlights,
+		Evaluation:         state.Evaluation,
+		Redactions:         state.Redactions,
+		Hooks:              state.Hooks,
+	}
 }
 
 // CancelRequest handles POST /api/cancel/{requestID}
@@ -179,6 +418,164 @@ func (h *APIHandler) CancelRequest(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "cancelled"})
 }
 
+// RetryRequest handles POST /api/retry?request_id=...
+func (h *APIHandler) RetryRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestID := r.URL.Query().Get("request_id")
+	if requestID == "" {
+		http.Error(w, "Request ID is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.submissionService.RetryRequest(requestID)
+	if errors.Is(err, services.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to retry request: %v", err), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "retrying"})
+}
+
+// RequestResource handles the per-request endpoints under /api/requests/{id}/: rerun, logs and summary
+func (h *APIHandler) RequestResource(w http.ResponseWriter, r *http.Request) {
+	requestID, action, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/requests/"), "/")
+	if ok {
+		requestID, _ = url.PathUnescape(requestID)
+	}
+	if !ok || requestID == "" || (action != "rerun" && action != "logs" && action != "summary") {
+		http.Error(w, "Path must be /api/requests/<request ID>/rerun, /api/requests/<request ID>/logs or /api/requests/<request ID>/summary", http.StatusNotFound)
+		return
+	}
+	if _, err := h.submissionService.GetRequestStatus(requestID); err != nil {
+		http.Error(w, fmt.Sprintf("Request not found: %s", requestID), http.StatusNotFound)
+		return
+	}
+
+	switch action {
+	case "rerun":
+		h.rerunRequest(w, r, requestID)
+	case "logs":
+		h.requestLogs(w, r, requestID)
+	case "summary":
+		h.requestSummary(w, r, requestID)
+	}
+}
+
+// RequestEvents handles GET /api/admin/events?request_id=, listing a request's stored events
+func (h *APIHandler) RequestEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestID := r.URL.Query().Get("request_id")
+	if requestID == "" {
+		http.Error(w, "Request ID is required", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.submissionService.GetRequestEvents(requestID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"request_id": requestID,
+		"events":     events,
+	})
+}
+
+// RebuildState handles /api/admin/rebuild?request_id=, reconstructing a request's state from
+// its stored events. GET reports where the stored state disagrees with the events; POST also
+// writes the rebuilt values back. Without request_id every request is checked and those that
+// disagree are listed.
+func (h *APIHandler) RebuildState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	apply := r.Method == http.MethodPost
+
+	requestID := r.URL.Query().Get("request_id")
+	if requestID == "" {
+		rebuilds, err := h.submissionService.RebuildAllRequestStates(apply)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to rebuild requests: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if rebuilds == nil {
+			rebuilds = []*core.StateRebuild{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"requests": rebuilds,
+			"count":    len(rebuilds),
+		})
+		return
+	}
+
+	rebuild, err := h.submissionService.RebuildRequestState(requestID, apply)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rebuild)
+}
+
+// ReplayRequest handles POST /api/admin/replay?request_id=&from=<event_id>&force=true. It
+// re-publishes a stored event (by default the latest pipeline event) so the request is
+// re-driven from that point, e.g. after a handler fix is deployed.
+func (h *APIHandler) ReplayRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestID := r.URL.Query().Get("request_id")
+	if requestID == "" {
+		http.Error(w, "Request ID is required", http.StatusBadRequest)
+		return
+	}
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "Invalid force value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	event, err := h.submissionService.ReplayRequest(requestID, r.URL.Query().Get("from"), force)
+	if errors.Is(err, services.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to replay request: %v", err), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":     "replaying",
+		"event_id":   event.ID,
+		"event_type": string(event.Type),
+		"replay_of":  event.ReplayOf,
+	})
+}
+
 // Health handles GET /api/health
 func (h *APIHandler) Health(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -194,15 +591,180 @@ func (h *APIHandler) Health(w http.ResponseWriter, r *http.Request) {
 
 	response := HealthResponse{
 		Status:         "healthy",
+		Paused:         h.submissionService.IsProcessingPaused(),
 		Timestamp:      time.Now(),
 		RequestCounts:  requestCounts,
 		EnabledSources: enabledSources,
+		Store:          h.submissionService.GetStoreStats(),
+		TmpDir:         h.submissionService.GetTmpDirUsage(),
+		VideoInfoCache: h.submissionService.GetVideoInfoCacheStats(),
+		LLMCache:       h.submissionService.GetLLMCacheStats(),
+		Budget:         h.submissionService.GetBudgetStatus(),
+		RunningTasks:   h.submissionService.GetRunningTasks(),
+		Preflight:      h.submissionService.GetPreflightStatus(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Ready        bool                        `json:"ready"`
+	Reasons      []string                    `json:"reasons,omitempty"`
+	QueueLengths map[interfaces.TaskType]int `json:"queue_lengths"`
+	Timestamp    time.Time                   `json:"timestamp"`
+}
+
+// Livez handles GET /livez. It only reports that the process is up and serving HTTP,
+// so a slow pipeline never causes the pod to be restarted.
+func (h *APIHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "alive",
+		"timestamp": time.Now(),
+	})
+}
+
+// Readyz handles GET /readyz. The service is not ready while draining, when the task
+// queues are above the configured backpressure threshold, or when a runtime dependency
+// (yt-dlp, whisper, model, tmp dir) is unavailable.
+func (h *APIHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var reasons []string
+	if h.submissionService.IsDraining() {
+		reasons = append(reasons, "draining")
+	}
+
+	queueLengths := h.submissionService.GetQueueLengths()
+	if h.maxQueuedTasks > 0 {
+		total := 0
+		for _, length := range queueLengths {
+			total += length
+		}
+		if total > h.maxQueuedTasks {
+			reasons = append(reasons, fmt.Sprintf("queue backpressure: %d queued tasks (max %d)", total, h.maxQueuedTasks))
+		}
+	}
+
+	for _, err := range h.submissionService.CheckDependencies() {
+		reasons = append(reasons, fmt.Sprintf("dependency unavailable: %v", err))
+	}
+
+	if preflight := h.submissionService.GetPreflightStatus(); preflight != nil && !preflight.Passed {
+		if preflight.Runs == 0 {
+			reasons = append(reasons, "preflight running")
+		}
+		for _, check := range preflight.Checks {
+			if !check.Passed {
+				reasons = append(reasons, fmt.Sprintf("preflight %s failed: %s", check.Name, check.Error))
+			}
+		}
+	}
+
+	response := ReadinessResponse{
+		Ready:        len(reasons) == 0,
+		Reasons:      reasons,
+		QueueLengths: queueLengths,
+		Timestamp:    time.Now(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if !response.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
 	json.NewEncoder(w).Encode(response)
 }
 
+// Drain handles POST /api/admin/drain. It stops background sources and new submissions,
+// then waits until all active requests have finished or the drain timeout expires.
+// It is intended to be called from a Kubernetes preStop hook.
+func (h *APIHandler) Drain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	timeout := h.drainTimeout
+	if val := r.URL.Query().Get("timeout"); val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid timeout: %v", err), http.StatusBadRequest)
+			return
+		}
+		timeout = d
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	h.submissionService.StartDrain()
+	if err := h.sourceManager.StopAll(); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to stop sources: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	remaining := h.submissionService.WaitForIdle(ctx)
+
+	status := "drained"
+	if remaining > 0 {
+		status = "timeout"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":          status,
+		"active_requests": remaining,
+	})
+}
+
+// Pause handles POST /api/admin/pause. Running tasks finish, queued and new tasks are
+// held until /api/admin/resume, e.g. during a provider outage.
+func (h *APIHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.submissionService.PauseProcessing()
+	h.writePauseState(w)
+}
+
+// Resume handles POST /api/admin/resume
+func (h *APIHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.submissionService.ResumeProcessing()
+	h.writePauseState(w)
+}
+
+// writePauseState reports whether processing is paused and how many tasks are queued
+func (h *APIHandler) writePauseState(w http.ResponseWriter) {
+	queued := 0
+	for _, length := range h.submissionService.GetQueueLengths() {
+		queued += length
+	}
+	status := "running"
+	if h.submissionService.IsProcessingPaused() {
+		status = "paused"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":       status,
+		"queued_tasks": queued,
+	})
+}
+
 // ListPrompts handles GET /api/prompts
 func (h *APIHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -217,6 +779,8 @@ func (h *APIHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Category    string `json:"category"`
+		Language    string `json:"language,omitempty"`
+		VariantOf   string `json:"variant_of,omitempty"`
 	}
 
 	promptInfos := make([]PromptInfo, len(prompts))
@@ -226,6 +790,8 @@ func (h *APIHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
 			Name:        prompt.Name,
 			Description: prompt.Description,
 			Category:    prompt.Category,
+			Language:    prompt.Language,
+			VariantOf:   prompt.VariantOf,
 		}
 	}
 
@@ -235,3 +801,27 @@ func (h *APIHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
 		"count":   len(promptInfos),
 	})
 }
+
+// ReloadConfig handles POST /api/admin/reload
+func (h *APIHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.reloadFunc == nil {
+		http.Error(w, "Config reload is not supported", http.StatusNotImplemented)
+		return
+	}
+
+	if err := h.reloadFunc(); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload config: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":      "reloaded",
+		"reloaded_at": time.Now(),
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/logging"
+)
+
+// LogLevels handles /api/admin/logging:
+//
+//	GET          returns the global log level and the per-module levels
+//	PUT / POST   changes them from the JSON body, e.g. {"level": "info", "modules": {"sources": "debug"}};
+//	             an omitted level is left as is and a module set to "" goes back to the global level
+//
+// Changes last until the service restarts; logging.yaml sets the levels at startup.
+func (h *APIHandler) LogLevels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var update logging.LevelSettings
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := logging.UpdateLevels(update); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid log level: %v", err), http.StatusBadRequest)
+			return
+		}
+		log.Infof("Log levels changed to %+v", logging.Levels())
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logging.Levels())
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/providers/transcription"
+)
+
+// modelSyncTimeout bounds a model sync started from the API
+const modelSyncTimeout = 30 * time.Minute
+
+// ModelsResponse lists the configured whisper models
+type ModelsResponse struct {
+	Models []transcription.ModelInfo `json:"models"`
+	Count  int                       `json:"count"`
+	Error  string                    `json:"error,omitempty"` // set when a sync failed for some model
+}
+
+// ListModels handles GET /api/models, listing the configured whisper models and whether
+// they are installed and verified
+func (h *APIHandler) ListModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.models == nil {
+		http.Error(w, "Model management is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	models := h.models.Models()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ModelsResponse{Models: models, Count: len(models)})
+}
+
+// SyncModels handles POST /api/admin/models/sync, re-verifying the whisper models and
+// downloading any that are missing. It responds once the sync is done.
+func (h *APIHandler) SyncModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.models == nil {
+		http.Error(w, "Model management is not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), modelSyncTimeout)
+	defer cancel()
+	response := ModelsResponse{}
+	status := http.StatusOK
+	if err := h.models.Sync(ctx); err != nil {
+		response.Error = err.Error()
+		status = http.StatusBadGateway
+	}
+	response.Models = h.models.Models()
+	response.Count = len(response.Models)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
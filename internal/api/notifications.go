@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"video-summarizer-go/internal/notifications"
+)
+
+// NotificationPreferences handles /api/notifications/preferences for the user named by the
+// X-User header of an authenticating proxy, who can only see and change their own preference:
+//
+//	GET          returns the user's preference
+//	PUT / POST   registers or replaces the user's preference from the JSON body
+//	DELETE       removes the user's preference
+func (h *APIHandler) NotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	if h.notifications == nil {
+		http.Error(w, "Notifications are not enabled", http.StatusNotFound)
+		return
+	}
+	user := strings.TrimSpace(r.Header.Get("X-User"))
+	if user == "" {
+		http.Error(w, "X-User header is required", http.StatusUnauthorized)
+		return
+	}
+	if given := strings.TrimSpace(r.URL.Query().Get("user")); given != "" && given != user {
+		http.Error(w, "Cannot access another user's notification preference", http.StatusForbidden)
+		return
+	}
+	prefs := h.notifications.Preferences()
+
+	switch r.Method {
+	case http.MethodGet:
+		pref, ok := prefs.Get(user)
+		if !ok {
+			http.Error(w, "No notification preference for user", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pref)
+
+	case http.MethodPut, http.MethodPost:
+		var pref notifications.Preference
+		if err := json.NewDecoder(r.Body).Decode(&pref); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if pref.User != "" && pref.User != user {
+			http.Error(w, "Cannot set another user's notification preference", http.StatusForbidden)
+			return
+		}
+		pref.User = user
+		if err := pref.Validate(); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid preference: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := prefs.Set(pref); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to save preference: %v", err), http.StatusInternalServerError)
+			return
+		}
+		saved, _ := prefs.Get(user)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(saved)
+
+	case http.MethodDelete:
+		if err := prefs.Delete(user); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to delete preference: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
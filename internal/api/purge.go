@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/core"
+)
+
+// PurgeRequest names the video or the user whose data is deleted
+type PurgeRequest struct {
+	URL  string `json:"url,omitempty"`
+	User string `json:"user,omitempty"`
+	// Also delete the summaries and other files uploaded by the output provider
+	DeleteOutputs bool `json:"delete_outputs,omitempty"`
+}
+
+// PurgeResponse reports what a purge removed
+type PurgeResponse struct {
+	*core.PurgeReport
+	NotificationPreferencesRemoved bool `json:"notification_preferences_removed,omitempty"`
+}
+
+// PurgeData handles POST /api/admin/purge, deleting every request for a video URL or by a
+// user, for takedown and data deletion requests. A user's notification preferences are
+// removed too.
+func (h *APIHandler) PurgeData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PurgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.URL, req.User = strings.TrimSpace(req.URL), strings.TrimSpace(req.User)
+	if (req.URL == "") == (req.User == "") {
+		http.Error(w, "Exactly one of url or user is required", http.StatusBadRequest)
+		return
+	}
+
+	var response PurgeResponse
+	var err error
+	if req.URL != "" {
+		log.Infof("Purging data for video %s (delete outputs: %t)", req.URL, req.DeleteOutputs)
+		response.PurgeReport, err = h.submissionService.PurgeVideo(req.URL, req.DeleteOutputs)
+	} else {
+		log.Infof("Purging data for user %s (delete outputs: %t)", req.User, req.DeleteOutputs)
+		response.PurgeReport, err = h.submissionService.PurgeUser(req.User, req.DeleteOutputs)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to purge: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if req.User != "" && h.notifications != nil {
+		prefs := h.notifications.Preferences()
+		if _, ok := prefs.Get(req.User); ok {
+			if err := prefs.Delete(req.User); err != nil {
+				response.Errors = append(response.Errors, fmt.Sprintf("notification preferences: %v", err))
+			} else {
+				response.NotificationPreferencesRemoved = true
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/services"
+	"video-summarizer-go/internal/sources"
+)
+
+// maxPushBodyBytes caps the size of a pushed payload
+const maxPushBodyBytes = 1 << 20
+
+// PushRequest is the payload a trusted system pushes to a push source
+type PushRequest struct {
+	URL  string   `json:"url,omitempty"`
+	URLs []string `json:"urls,omitempty"`
+}
+
+// PushResponse lists the requests a pushed payload submitted
+type PushResponse struct {
+	RequestIDs  []string  `json:"request_ids"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"` // videos that failed to submit, if some did
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// PushToSource handles POST /api/sources/{name}/push. The payload must be signed with the
+// source's shared secret (see sources.PushSource); unsigned, mis-signed and stale payloads
+// are refused with 401.
+func (h *APIHandler) PushToSource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/sources/"), "/push")
+	if ok {
+		name, _ = url.PathUnescape(name)
+	}
+	source, exists := h.sourceManager.GetSource(name)
+	push, isPush := source.(*sources.PushSource)
+	if !ok || !exists || !isPush {
+		http.Error(w, "Push path must be /api/sources/<push source name>/push", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPushBodyBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := push.VerifySignature(body, r.Header.Get("X-Signature-Timestamp"), r.Header.Get("X-Signature"), time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req PushRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	urls := req.URLs
+	if req.URL != "" {
+		urls = append([]string{req.URL}, urls...)
+	}
+	if len(urls) == 0 {
+		http.Error(w, "url or urls is required", http.StatusBadRequest)
+		return
+	}
+
+	requestIDs, err := push.Submit(urls)
+	switch {
+	case errors.Is(err, sources.ErrSourceStopped), errors.Is(err, services.ErrDraining):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, services.ErrAtCapacity):
+		writeCapacityError(w, err)
+		return
+	case errors.Is(err, services.ErrBudgetExceeded), errors.Is(err, services.ErrSourceCapReached):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	case err != nil && len(requestIDs) == 0:
+		http.Error(w, fmt.Sprintf("Failed to submit videos: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := PushResponse{
+		RequestIDs:  requestIDs,
+		Status:      "submitted",
+		SubmittedAt: time.Now(),
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/logging"
+)
+
+// logFollowPoll is how often a followed request's status is checked for completion
+const logFollowPoll = time.Second
+
+// requestLogs handles GET /api/requests/{id}/logs, writing the request's recent log lines as
+// text. With follow=true the response stays open and streams new lines until the request
+// finishes or the client disconnects.
+func (h *APIHandler) requestLogs(w http.ResponseWriter, r *http.Request, requestID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.logs == nil {
+		http.Error(w, "Request logs are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if r.URL.Query().Get("follow") != "true" {
+		for _, line := range h.logs.Lines(requestID) {
+			writeLogLine(w, line)
+		}
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	lines, updates, cancel := h.logs.Follow(requestID)
+	defer cancel()
+	for _, line := range lines {
+		writeLogLine(w, line)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(logFollowPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-updates:
+			writeLogLine(w, line)
+			flusher.Flush()
+		case <-ticker.C:
+			state, err := h.submissionService.GetRequestStatus(requestID)
+			if err != nil || (state.Status != interfaces.StatusScheduled && state.Status != interfaces.StatusPending && state.Status != interfaces.StatusRunning) {
+				// Write what was logged before the request finished
+				for {
+					select {
+					case line := <-updates:
+						writeLogLine(w, line)
+					default:
+						flusher.Flush()
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// writeLogLine writes one log line as "time level message"
+func writeLogLine(w io.Writer, line logging.RequestLogLine) {
+	fmt.Fprintf(w, "%s %s %s\n", line.Time.Format(time.RFC3339Nano), line.Level, line.Message)
+}
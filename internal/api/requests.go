@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// RequestListItem is one request in a request listing
+type RequestListItem struct {
+	RequestID   string               `json:"request_id"`
+	URL         string               `json:"url"`
+	Title       string               `json:"title,omitempty"`
+	SourceType  string               `json:"source_type"`
+	Category    string               `json:"category"`
+	User        string               `json:"user,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Metadata    map[string]string    `json:"metadata,omitempty"`
+	Groups      []string             `json:"groups,omitempty"`
+	Priority    string               `json:"priority"`
+	Status      string               `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	ErrorCode   interfaces.ErrorCode `json:"error_code,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	// Links to the uploaded summary and every other verified upload
+	OutputPath string                  `json:"output_path,omitempty"`
+	Outputs    []interfaces.OutputFile `json:"outputs,omitempty"`
+}
+
+// RequestListResponse represents the response of a request listing
+type RequestListResponse struct {
+	Total    int               `json:"total"`
+	Requests []RequestListItem `json:"requests"`
+}
+
+// ListRequests handles GET /api/requests?user=...&status=...&group=...&tag=...&metadata.<key>=...&limit=...,
+// newest first. tag may be repeated; requests must match every tag and metadata value.
+// The user defaults to the X-User header when not given.
+func (h *APIHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := services.RequestFilter{
+		User:   requestUser(r, query.Get("user")),
+		Status: interfaces.ProcessingStatus(query.Get("status")),
+		Tags:   query["tag"],
+		Group:  query.Get("group"),
+	}
+	for key, values := range query {
+		if name := strings.TrimPrefix(key, "metadata."); name != key && name != "" {
+			if filter.Metadata == nil {
+				filter.Metadata = make(map[string]string)
+			}
+			filter.Metadata[name] = values[0]
+		}
+	}
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	states, err := h.submissionService.ListRequests(filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := RequestListResponse{Total: len(states), Requests: []RequestListItem{}}
+	if len(states) > limit {
+		states = states[:limit]
+	}
+	for _, state := range states {
+		response.Requests = append(response.Requests, RequestListItem{
+			RequestID:   state.RequestID,
+			URL:         state.URL,
+			Title:       state.Title(),
+			SourceType:  state.SourceType,
+			Category:    state.Category,
+			User:        state.User,
+			Tags:        state.Tags,
+			Metadata:    state.Metadata,
+			Groups:      state.Groups,
+			Priority:    state.Priority.String(),
+			Status:      string(state.Status),
+			Error:       state.Error,
+			ErrorCode:   state.ErrorCode,
+			CreatedAt:   state.CreatedAt,
+			CompletedAt: state.CompletedAt,
+			OutputPath:  state.OutputPath,
+			Outputs:     state.Outputs,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
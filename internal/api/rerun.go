@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// RerunRequestBody asks for a request's transcript to be summarized again
+type RerunRequestBody struct {
+	Prompt interfaces.Prompt `json:"prompt"`          // empty keeps the original prompt
+	Model  string            `json:"model,omitempty"` // openai_model or one of openai_rerun_models
+	// Summary length and style; empty keeps the original's
+	SummaryLength string `json:"summary_length,omitempty"`
+	SummaryStyle  string `json:"summary_style,omitempty"`
+	// Override the original request's user, output and priority
+	User           string                   `json:"user,omitempty"`
+	Output         *interfaces.OutputTarget `json:"output,omitempty"`
+	Priority       string                   `json:"priority,omitempty"`
+	OverrideBudget bool                     `json:"override_budget,omitempty"`
+}
+
+// RerunResponse names the new request and the one it reran
+type RerunResponse struct {
+	RequestID   string    `json:"request_id"`
+	RerunOf     string    `json:"rerun_of"`
+	Status      string    `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// rerunRequest handles POST /api/requests/{id}/rerun, summarizing the request's transcript
+// again with a new prompt, model, length or style as a new request; only summarization and output run.
+// Returns 409 when the transcript is no longer available.
+func (h *APIHandler) rerunRequest(w http.ResponseWriter, r *http.Request, requestID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RerunRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if _, err := interfaces.ParsePriority(req.Priority); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.submissionService.ValidateSummaryFormat(req.SummaryLength, req.SummaryStyle); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := services.SubmitOptions{
+		User:           requestUser(r, req.User),
+		Output:         req.Output,
+		Priority:       req.Priority,
+		OverrideBudget: req.OverrideBudget,
+		SummaryLength:  req.SummaryLength,
+		SummaryStyle:   req.SummaryStyle,
+	}
+
+	newID, err := h.submissionService.RerunRequest(requestID, req.Prompt, req.Model, opts)
+	switch {
+	case errors.Is(err, services.ErrDraining):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, services.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	case errors.Is(err, services.ErrAtCapacity):
+		writeCapacityError(w, err)
+		return
+	case errors.Is(err, services.ErrBudgetExceeded):
+		writeBudgetError(w, err)
+		return
+	case errors.Is(err, core.ErrTranscriptUnavailable):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("Failed to rerun request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(RerunResponse{
+		RequestID:   newID,
+		RerunOf:     requestID,
+		Status:      "submitted",
+		SubmittedAt: time.Now(),
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"video-summarizer-go/internal/schedules"
+)
+
+// Schedules handles /api/schedules:
+//
+//	GET          lists the schedules, soonest next run first
+//	POST         creates a schedule from the JSON body (see schedules.Schedule)
+func (h *APIHandler) Schedules(w http.ResponseWriter, r *http.Request) {
+	if h.schedules == nil {
+		http.Error(w, "Schedules are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list := h.schedules.List()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"schedules": list,
+			"count":     len(list),
+		})
+
+	case http.MethodPost:
+		var schedule schedules.Schedule
+		if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		schedule.User = requestUser(r, schedule.User)
+		created, err := h.schedules.Create(schedule)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Schedule handles /api/schedules/{id}:
+//
+//	GET              returns the schedule and its last run
+//	PUT              replaces its definition, e.g. to pause it with "paused": true
+//	DELETE           removes it
+//	POST {id}/run    runs it now, without moving its next run
+func (h *APIHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	if h.schedules == nil {
+		http.Error(w, "Schedules are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/schedules/"), "/")
+	id, _ = url.PathUnescape(id)
+	if id == "" || (action != "" && action != "run") {
+		http.Error(w, "Path must be /api/schedules/<schedule ID> or /api/schedules/<schedule ID>/run", http.StatusNotFound)
+		return
+	}
+
+	var schedule schedules.Schedule
+	var err error
+	switch {
+	case action == "run" && r.Method == http.MethodPost:
+		schedule, err = h.schedules.RunNow(id)
+	case action == "run":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	case r.Method == http.MethodGet:
+		schedule, err = h.schedules.Get(id)
+	case r.Method == http.MethodPut:
+		var update schedules.Schedule
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		update.User = requestUser(r, update.User)
+		schedule, err = h.schedules.Update(id, update)
+		if err != nil && !errors.Is(err, schedules.ErrNotFound) {
+			http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+			return
+		}
+	case r.Method == http.MethodDelete:
+		if err := h.schedules.Delete(id); err != nil {
+			if errors.Is(err, schedules.ErrNotFound) {
+				http.Error(w, fmt.Sprintf("Schedule not found: %s", id), http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to delete schedule: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+		return
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if errors.Is(err, schedules.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("Schedule not found: %s", id), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// SearchResult is one request in a search response
+type SearchResult struct {
+	RequestID string    `json:"request_id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title,omitempty"`
+	Channel   string    `json:"channel,omitempty"`
+	Category  string    `json:"category"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Snippet   string    `json:"snippet,omitempty"`
+	// Link to the uploaded summary
+	OutputPath string `json:"output_path,omitempty"`
+}
+
+// SearchResponse represents the response of a request search
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchRequests handles GET /api/requests/search?q=...&category=...&limit=...
+func (h *APIHandler) SearchRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "Query parameter q is required", http.StatusBadRequest)
+		return
+	}
+	category := r.URL.Query().Get("category")
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	response := SearchResponse{Query: query, Results: []SearchResult{}}
+	for _, state := range h.submissionService.SearchRequests(query) {
+		if category != "" && state.Category != category {
+			continue
+		}
+		response.Total++
+		if len(response.Results) < limit {
+			response.Results = append(response.Results, newSearchResult(state, query))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// newSearchResult builds a search result with a snippet of the summary around the first match
+func newSearchResult(state *interfaces.ProcessingState, query string) SearchResult {
+	result := SearchResult{
+		RequestID:  state.RequestID,
+		URL:        state.URL,
+		Title:      state.Title(),
+		Category:   state.Category,
+		Status:     string(state.Status),
+		CreatedAt:  state.CreatedAt,
+		Snippet:    snippet(state.SummaryText, query, 200),
+		OutputPath: state.OutputPath,
+	}
+	if channel, ok := state.VideoInfo["channel"].(string); ok {
+		result.Channel = channel
+	} else if uploader, ok := state.VideoInfo["uploader"].(string); ok {
+		result.Channel = uploader
+	}
+	return result
+}
+
+// snippet returns up to length characters of text around the first query word found in it
+func snippet(text, query string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return strings.TrimSpace(text)
+	}
+	lower := []rune(strings.ToLower(text))
+	start := 0
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if i := strings.Index(string(lower), word); i >= 0 {
+			start = len([]rune(string(lower)[:i])) - length/4
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+	if end > len(runes) {
+		end = len(runes)
+		start = end - length
+	}
+	out := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// maxBatchStatusIDs is how many requests one /api/status/batch call may ask for
+const maxBatchStatusIDs = 500
+
+// BatchStatusRequest lists the requests whose status to return
+type BatchStatusRequest struct {
+	RequestIDs []string `json:"request_ids"`
+}
+
+// BatchStatusResponse holds the requests' statuses, in the order asked for, and the IDs of
+// those that don't exist
+type BatchStatusResponse struct {
+	Requests []StatusResponse `json:"requests"`
+	NotFound []string         `json:"not_found"`
+}
+
+// GetStatusBatch handles POST /api/status/batch: the /api/status of several requests in one
+// call, all read at the same moment. Repeated IDs are returned once.
+func (h *APIHandler) GetStatusBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatchStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	var requestIDs []string
+	seen := make(map[string]bool, len(req.RequestIDs))
+	for _, requestID := range req.RequestIDs {
+		if requestID == "" || seen[requestID] {
+			continue
+		}
+		seen[requestID] = true
+		requestIDs = append(requestIDs, requestID)
+	}
+	if len(requestIDs) == 0 {
+		http.Error(w, "request_ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(requestIDs) > maxBatchStatusIDs {
+		http.Error(w, fmt.Sprintf("At most %d request IDs per call, got %d", maxBatchStatusIDs, len(requestIDs)), http.StatusBadRequest)
+		return
+	}
+
+	states := h.submissionService.GetRequestStatuses(requestIDs)
+	response := BatchStatusResponse{
+		Requests: make([]StatusResponse, 0, len(states)),
+		NotFound: []string{},
+	}
+	for _, requestID := range requestIDs {
+		if state, ok := states[requestID]; ok {
+			response.Requests = append(response.Requests, newStatusResponse(state))
+		} else {
+			response.NotFound = append(response.NotFound, requestID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
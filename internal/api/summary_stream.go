@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// summaryStreamPoll is how often a watched request is checked for new summary text
+const summaryStreamPoll = time.Second
+
+// summaryStreamEvent is the data of an event on the summary stream
+type summaryStreamEvent struct {
+	Text   string `json:"text,omitempty"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// requestSummary handles GET /api/requests/{id}/summary, a server-sent event stream of the
+// request's summary as it forms. A "partial" event carries the text written so far each time
+// it grows (with summary_streaming), and a "summary" event the final text, after which the
+// stream ends. A request that finishes without a summary ends the stream with an "end" event
+// carrying its status and error.
+func (h *APIHandler) requestSummary(w http.ResponseWriter, r *http.Request, requestID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	ticker := time.NewTicker(summaryStreamPoll)
+	defer ticker.Stop()
+	sent := ""
+	for {
+		state, err := h.submissionService.GetRequestStatus(requestID)
+		if err != nil {
+			writeSummaryEvent(w, "end", summaryStreamEvent{Error: err.Error()})
+			flusher.Flush()
+			return
+		}
+		switch {
+		case state.SummaryText != "":
+			writeSummaryEvent(w, "summary", summaryStreamEvent{Text: state.SummaryText})
+			flusher.Flush()
+			return
+		case state.Status != interfaces.StatusScheduled && state.Status != interfaces.StatusPending && state.Status != interfaces.StatusRunning:
+			writeSummaryEvent(w, "end", summaryStreamEvent{Status: string(state.Status), Error: state.Error})
+			flusher.Flush()
+			return
+		case state.PartialSummary != sent:
+			sent = state.PartialSummary
+			writeSummaryEvent(w, "partial", summaryStreamEvent{Text: sent})
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeSummaryEvent writes one server-sent event with JSON data
+func writeSummaryEvent(w io.Writer, event string, data summaryStreamEvent) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
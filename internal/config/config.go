@@ -1,9 +1,12 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,24 +16,128 @@ type AppConfig struct {
 	// Summarizer Provider
 	SummarizerProvider string `yaml:"summarizer_provider"`
 
+	// Dry run: stub summaries and local output, for smoke tests that spend no tokens.
+	// Overrides summarizer_provider and output_provider.
+	DryRun bool `yaml:"dry_run"`
+
+	// Stub summarizer: summaries are the first this many sentences of the text
+	StubSummarySentences int `yaml:"stub_summary_sentences"`
+
+	// Text summarizer: summaries are the text's most representative sentences, picked offline
+	TextSummarySentences int `yaml:"text_summary_sentences"`
+
 	// OpenAI Settings
 	OpenAIKey       string `yaml:"openai_api_key"`
 	OpenAIModel     string `yaml:"openai_model"`
 	OpenAIMaxTokens int    `yaml:"openai_max_tokens"`
+	// Response token limits per category, overriding openai_max_tokens; a prompt's own
+	// max_tokens overrides both
+	MaxTokensByCategory map[string]int `yaml:"max_tokens_by_category"`
+	// Other models a rerun may ask for instead of openai_model
+	OpenAIRerunModels []string `yaml:"openai_rerun_models"`
+
+	// Token prices used to estimate request cost (USD per 1K tokens, 0 = unknown)
+	OpenAIPromptCostPer1K     float64 `yaml:"openai_prompt_cost_per_1k"`
+	OpenAICompletionCostPer1K float64 `yaml:"openai_completion_cost_per_1k"`
+
+	// Summarization responses are cached by model, prompt and input text
+	LLMCacheTTL  string `yaml:"llm_cache_ttl"`  // "0" disables the cache
+	LLMCacheSize int    `yaml:"llm_cache_size"` // max cached responses
+
+	// How prompts adapt to transcripts that aren't in the prompt's language (see PromptLanguageModes)
+	PromptLanguageMode string `yaml:"prompt_language_mode"`
+
+	// Transcripts longer than this many bytes are summarized chunk by chunk
+	SummarizationChunkSize int `yaml:"summarization_chunk_size"`
+	// How many chunks of one transcript are summarized at a time. This multiplies the
+	// summarization workers' calls, so keep it within the provider's rate limits.
+	SummarizationChunkConcurrency int `yaml:"summarization_chunk_concurrency"`
+
+	// Summaries cite the [HH:MM:SS] moment of the video supporting each bullet, linked where
+	// the site supports it. Needs a transcription provider that reports segment times.
+	SummaryCitations bool `yaml:"summary_citations"`
+
+	// Stream the final summary from the model, saving the text written so far to the request
+	// every flush interval, so long generations keep the watchdog fed and can be watched as
+	// they form
+	SummaryStreaming           bool   `yaml:"summary_streaming"`
+	SummaryStreamFlushInterval string `yaml:"summary_stream_flush_interval"` // e.g. "2s"
+
+	// Video metadata given to the model alongside the transcript
+	VideoContext VideoContextConfig `yaml:"video_context"`
+
+	// Optional OCR of the text shown in videos, such as slides, added to the summarization prompt
+	Slides SlidesConfig `yaml:"slides"`
+
+	// Optional key moments of videos, picked from the timed transcript after summarization
+	Highlights HighlightsConfig `yaml:"highlights"`
+
+	// Optional LLM-as-judge scoring of summaries before they are uploaded
+	Evaluation EvaluationConfig `yaml:"evaluation"`
+
+	// Optional scrubbing of personal data and profanity from transcripts and summaries before upload
+	Redaction RedactionConfig `yaml:"redaction"`
+
+	// Optional audio rendition of summaries, uploaded alongside the text
+	Speech SpeechConfig `yaml:"speech"`
+
+	// Post-processing steps run after a request's outputs are uploaded, e.g. indexing or TTS
+	Hooks []HookConfig `yaml:"hooks"`
 
 	// Video Provider
 	YtDlpPath string `yaml:"yt_dlp_path"`
+	// Anti-bot settings for yt-dlp: the user agent it presents, --extractor-args values
+	// (e.g. "youtube:player_client=web"), and any other arguments added to every call
+	YtDlpUserAgent     string   `yaml:"yt_dlp_user_agent"`
+	YtDlpExtractorArgs []string `yaml:"yt_dlp_extractor_args"`
+	YtDlpExtraArgs     []string `yaml:"yt_dlp_extra_args"`
+	VideoInfoCacheTTL  string   `yaml:"video_info_cache_ttl"`  // "0" disables the cache
+	VideoInfoCacheSize int      `yaml:"video_info_cache_size"` // max cached videos
+	// Bandwidth caps for audio downloads in bytes per second, with an optional K/M/G suffix
+	// like yt-dlp's --limit-rate ("" = unlimited). The total is split evenly across the
+	// audio_download workers.
+	DownloadRateLimit      string `yaml:"download_rate_limit"`
+	DownloadTotalRateLimit string `yaml:"download_total_rate_limit"`
+	// Limits on yt-dlp calls (video info and audio downloads) per host
+	HostLimits HostLimitsConfig `yaml:"host_limits"`
+
+	// Document Provider
+	PdfToTextPath     string `yaml:"pdftotext_path"`
+	DocumentMaxSizeMB int    `yaml:"document_max_size_mb"`
 
 	// Transcription Provider
 	WhisperPath      string `yaml:"whisper_path"`
 	WhisperModelPath string `yaml:"whisper_model_path"`
+	// Spoken language passed to whisper, e.g. "de", or "auto" to detect it ("" = English)
+	WhisperLanguage string `yaml:"whisper_language"`
+	// Downloading missing whisper models at startup instead of baking them into the image
+	WhisperModels WhisperModelsConfig `yaml:"whisper_models"`
+	// Sends videos to local whisper.cpp or the OpenAI transcription API depending on their length
+	TranscriptionRouting TranscriptionRoutingConfig `yaml:"transcription_routing"`
+	// Transcripts whose confidence score (0-1) is below this are flagged as low confidence
+	TranscriptLowConfidenceThreshold float64 `yaml:"transcript_low_confidence_threshold"`
 
 	// Directories
 	TmpDir     string `yaml:"tmp_dir"`
 	PromptsDir string `yaml:"prompts_dir"`
 
+	// Temp directory housekeeping
+	TmpDirQuotaMB    int    `yaml:"tmp_dir_quota_mb"`   // audio downloads pause above this (0 = no quota)
+	TmpSweepInterval string `yaml:"tmp_sweep_interval"` // how often orphaned files are removed
+	TmpOrphanMinAge  string `yaml:"tmp_orphan_min_age"` // unreferenced files younger than this are kept
+
+	// Stage checkpoints for retry and resume ("" disables checkpointing)
+	ArtifactsDir       string `yaml:"artifacts_dir"`
+	ArtifactsRetention string `yaml:"artifacts_retention"` // checkpoints not updated for this long are removed
+	// AES-256 key (base64) that checkpointed transcripts and summaries are encrypted with,
+	// or a file holding it, e.g. a mounted secret ("" for both stores them unencrypted)
+	ArtifactsEncryptionKey     string `yaml:"artifacts_encryption_key"`
+	ArtifactsEncryptionKeyFile string `yaml:"artifacts_encryption_key_file"`
+
 	// Output Provider
 	OutputProvider string `yaml:"output_provider"`
+	// Output providers per category, replacing output_provider for that category's requests
+	OutputRoutes map[string][]OutputRoute `yaml:"output_routes"`
 
 	// Google Drive Settings
 	GDriveAuthMethod      string `yaml:"gdrive_auth_method"`
@@ -39,19 +146,660 @@ type AppConfig struct {
 	GDriveFolderID        string `yaml:"gdrive_folder_id"`
 	UploadSummary         bool   `yaml:"upload_summary"`
 	UploadTranscript      bool   `yaml:"upload_transcript"`
+	UploadInfoJSON        bool   `yaml:"upload_info_json"` // full video metadata, as <name>_info.json
+	UploadThumbnail       bool   `yaml:"upload_thumbnail"` // video thumbnail, as <name>_thumbnail.<ext>
+
+	// Local Output Settings
+	LocalOutputDir string `yaml:"local_output_dir"`
+
+	// Output folder and file names (gdrive and local)
+	OutputNaming OutputNamingConfig `yaml:"output_naming"`
+
+	// Slack Output Settings
+	SlackBotToken string `yaml:"slack_bot_token"`
+	SlackChannel  string `yaml:"slack_channel"` // default channel when output_provider is slack
+
+	// Discord Output Settings, also used by discord sources to read their channels
+	DiscordBotToken string `yaml:"discord_bot_token"`
+	DiscordChannel  string `yaml:"discord_channel"` // default channel ID when output_provider is discord
+
+	// Google Sheets index of summaries, authenticated with the gdrive_* settings
+	GSheets GSheetsConfig `yaml:"gsheets"`
+
+	// Readwise and Raindrop.io, for reading workflows that live there
+	Readwise ReadwiseConfig `yaml:"readwise"`
+	Raindrop RaindropConfig `yaml:"raindrop"`
+
+	// Confluence pages, for knowledge bases that collect meeting and webinar summaries
+	Confluence ConfluenceConfig `yaml:"confluence"`
 
 	// Concurrency
 	Concurrency map[string]int `yaml:"concurrency"`
+
+	// Estimated LLM spend limits; background sources pause and API submissions need an override once reached
+	Budget BudgetConfig `yaml:"budget"`
+
+	// New requests are refused while this many are pending or running (0 = no limit)
+	MaxActiveRequests int `yaml:"max_active_requests"`
+
+	// Background sources and scheduled searches don't submit a video already summarized with
+	// any prompt, rather than only with the same prompt
+	SourceDedupAcrossPrompts bool `yaml:"source_dedup_across_prompts"`
+
+	// Skips or links background-source videos that look like re-uploads of recent ones
+	NearDuplicates NearDuplicateConfig `yaml:"near_duplicates"`
+
+	// State store limits
+	Store StoreConfig `yaml:"store"`
+
+	// How long each class of data is kept before the background sweep removes it
+	Retention RetentionConfig `yaml:"retention"`
+
+	// Where tasks wait between pipeline stages: in memory, or on a Kafka-compatible broker
+	TaskQueue TaskQueueConfig `yaml:"task_queue"`
+
+	// Stopping tasks that hang, such as a stalled yt-dlp download or whisper run
+	Watchdog WatchdogConfig `yaml:"watchdog"`
+
+	// Deliberate provider failures and latency, for staging only
+	FaultInjection FaultInjectionConfig `yaml:"fault_injection"`
+
+	// Self-test of the providers at startup; the service isn't ready until it passes
+	Preflight PreflightConfig `yaml:"preflight"`
+}
+
+// Preflight checks
+const (
+	PreflightTranscription = "transcription" // a second of silence through the transcription provider
+	PreflightSummarization = "summarization" // a one-token summarization call
+	PreflightOutput        = "output"        // the output destinations are reachable, without uploading
+)
+
+// PreflightConfig runs a short self-test of the providers at startup, so misconfiguration
+// (a missing whisper model, a bad API key, an unreachable Drive folder) shows up before real
+// requests fail. /readyz reports not ready until every check passes; failed checks are run
+// again every retry_interval.
+type PreflightConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Checks        []string `yaml:"checks"`         // checks to run (default all)
+	Timeout       string   `yaml:"timeout"`        // per check (default 2m)
+	RetryInterval string   `yaml:"retry_interval"` // wait before running failed checks again (default 1m)
+}
+
+// GetTimeout returns how long each preflight check may take, falling back to 2 minutes if invalid
+func (p PreflightConfig) GetTimeout() time.Duration {
+	d, err := time.ParseDuration(p.Timeout)
+	if err != nil || d <= 0 {
+		return 2 * time.Minute
+	}
+	return d
+}
+
+// GetRetryInterval returns the wait before failed preflight checks run again, falling back to
+// 1 minute if invalid
+func (p PreflightConfig) GetRetryInterval() time.Duration {
+	d, err := time.ParseDuration(p.RetryInterval)
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// State store backends
+const (
+	StoreMemory = "memory"
+	StoreBolt   = "bolt"
+)
+
+// Task queue backends
+const (
+	TaskQueueMemory = "memory"
+	TaskQueueKafka  = "kafka"
+)
+
+// TaskQueueConfig selects where queued tasks are kept. The memory queue orders tasks by
+// priority and shares workers out fairly between tenants; the kafka queue keeps tasks on a
+// Kafka-compatible broker (Kafka, Redpanda, MSK), a topic per priority read highest first,
+// in first-in, first-out order per partition.
+type TaskQueueConfig struct {
+	Backend string      `yaml:"backend"` // memory (default) or kafka
+	Kafka   KafkaConfig `yaml:"kafka"`
+}
+
+// KafkaConfig connects the kafka task queue to its broker. Each task type has its own topic,
+// read as one consumer group. Request state and events are kept in the process, so only one
+// process may consume a group; startup fails if another already does.
+type KafkaConfig struct {
+	Brokers     []string `yaml:"brokers"`      // host:port of the bootstrap brokers
+	TopicPrefix string   `yaml:"topic_prefix"` // topics are named <prefix><task type> (default "video-summarizer.tasks.")
+	GroupID     string   `yaml:"group_id"`     // consumer group of the workers (default "video-summarizer")
+	TLS         bool     `yaml:"tls"`
+	// SASL authentication: "" (none), plain, scram-sha-256 or scram-sha-512
+	SASLMechanism string `yaml:"sasl_mechanism"`
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+}
+
+// WatchdogConfig stops tasks that go longer than their task type's timeout without showing
+// progress. yt-dlp and whisper show progress as they print output, chunked summarization and
+// slide OCR after each chunk or frame, other tasks only when they start. A stopped task is
+// killed and run again until it has had max_attempts runs, then its request fails with a
+// timeout error.
+type WatchdogConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	CheckInterval string `yaml:"check_interval"` // how often running tasks are checked (default 30s)
+	MaxAttempts   int    `yaml:"max_attempts"`   // runs of a task before its request fails (default 2)
+	// Timeout per task type, e.g. transcription: "45m"; "default" covers the other types
+	Timeouts map[string]string `yaml:"timeouts"`
+}
+
+// DefaultWatchdogTimeouts are the timeouts of task types not set in watchdog.timeouts
+var DefaultWatchdogTimeouts = map[string]string{
+	"video_info":     "5m",
+	"audio_download": "30m",
+	"transcription":  "30m",
+	"summarization":  "15m",
+	"default":        "15m",
+}
+
+// GetCheckInterval returns how often running tasks are checked, falling back to 30 seconds if invalid
+func (w WatchdogConfig) GetCheckInterval() time.Duration {
+	d, err := time.ParseDuration(w.CheckInterval)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// GetTimeout returns how long a task of the given type may go without showing progress, or 0
+// if the watchdog is off or the timeout is invalid
+func (w WatchdogConfig) GetTimeout(taskType string) time.Duration {
+	if !w.Enabled {
+		return 0
+	}
+	timeout, ok := w.Timeouts[taskType]
+	if !ok {
+		timeout = w.Timeouts["default"]
+	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// FaultInjectionProviders are the provider keys fault injection can be configured for
+var FaultInjectionProviders = []string{"video", "transcription", "summarization", "document", "article", "output"}
+
+// SlidesConfig samples frames of each video after its info is fetched and reads their text
+// with tesseract, so slides, code and other on-screen text reach the summarization prompt.
+// The video is downloaded at a low resolution with yt-dlp and sampled with ffmpeg. Failures
+// never fail a request.
+type SlidesConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Interval      string `yaml:"interval"`       // time between sampled frames (default 30s), widened so long videos stay under max_frames
+	MaxFrames     int    `yaml:"max_frames"`     // most frames read per video (default 120)
+	MaxHeight     int    `yaml:"max_height"`     // highest video resolution downloaded (default 720)
+	MaxChars      int    `yaml:"max_chars"`      // on-screen text added to the prompt is cut at this (default 8000)
+	Language      string `yaml:"language"`       // tesseract language(s), e.g. "eng+deu" (default eng)
+	FfmpegPath    string `yaml:"ffmpeg_path"`    // default ffmpeg
+	TesseractPath string `yaml:"tesseract_path"` // default tesseract
+}
+
+// GSheetsConfig configures the gsheets output provider, which appends a row per summary to a
+// sheet (tab) of a Google spreadsheet
+type GSheetsConfig struct {
+	SpreadsheetID string `yaml:"spreadsheet_id"` // ID from the spreadsheet's URL; also registers gsheets for output overrides
+	Sheet         string `yaml:"sheet"`          // tab rows are appended to (default Summaries)
+	ExcerptChars  int    `yaml:"excerpt_chars"`  // summary excerpt length (default 500, at most 50000, a cell's limit)
+}
+
+// ReadwiseConfig configures the readwise output provider
+type ReadwiseConfig struct {
+	Token string `yaml:"token"` // access token from readwise.io/access_token; also registers readwise for output overrides
+	Mode  string `yaml:"mode"`  // "highlights" (default), one highlight per passage, or "reader" to save a Reader document
+}
+
+// RaindropConfig configures the raindrop output provider, which bookmarks each video with its
+// summary as the note
+type RaindropConfig struct {
+	Token        string `yaml:"token"`         // test token of a Raindrop app; also registers raindrop for output overrides
+	CollectionID int    `yaml:"collection_id"` // collection bookmarks are saved in (default Unsorted)
+}
+
+// OutputRoute is one destination of a category's outputs. Channel and FolderID override the
+// provider's default destination as in a request's output override.
+type OutputRoute struct {
+	Provider string `yaml:"provider"`
+	Channel  string `yaml:"channel"`   // Slack channel, or Discord channel ID
+	FolderID string `yaml:"folder_id"` // Drive folder, Raindrop collection or Confluence parent page ID
+}
+
+// DefaultConfluenceTitleTemplate names summary pages; titles are unique within a space, so it
+// includes the request ID
+const DefaultConfluenceTitleTemplate = "{title} ({request_id})"
+
+// ConfluenceConfig configures the confluence output provider, which writes a page per summary
+// in a space
+type ConfluenceConfig struct {
+	BaseURL       string `yaml:"base_url"`       // e.g. https://example.atlassian.net/wiki
+	Username      string `yaml:"username"`       // account email for Confluence Cloud API tokens; empty for Data Center personal access tokens
+	Token         string `yaml:"token"`          // API or personal access token; also registers confluence for output overrides
+	SpaceKey      string `yaml:"space_key"`      // space pages are created in
+	ParentID      string `yaml:"parent_id"`      // page new pages are created under (default the space's top level)
+	TitleTemplate string `yaml:"title_template"` // output_naming placeholders except {suffix} (default "{title} ({request_id})")
+}
+
+// GetInterval returns the time between sampled frames, falling back to 30 seconds if invalid
+func (s SlidesConfig) GetInterval() time.Duration {
+	d, err := time.ParseDuration(s.Interval)
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// HighlightsConfig has the summarization provider pick the key moments of each video from
+// its timed transcript. They are uploaded as <name>_highlights.txt with links to each moment.
+// Failures never fail a request.
+type HighlightsConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	MaxMoments int  `yaml:"max_moments"` // most moments kept per video (default 8)
+}
+
+// HostLimitsConfig spaces out and caps concurrent yt-dlp calls to the same host (YouTube's
+// domains count as one), to avoid 429s and bot detection during bursts
+type HostLimitsConfig struct {
+	MaxConcurrent int    `yaml:"max_concurrent"` // calls per host at once (0 = unlimited)
+	MinInterval   string `yaml:"min_interval"`   // delay between the starts of calls to a host ("0" = none)
+}
+
+// GetMinInterval returns the delay between calls to a host; 0 if unset or invalid
+func (h HostLimitsConfig) GetMinInterval() time.Duration {
+	d, err := time.ParseDuration(h.MinInterval)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// EvaluationConfig scores summaries with the summarization provider acting as a judge, on a
+// 1-5 rubric of coverage, faithfulness and length. Evaluation failures never fail a request.
+type EvaluationConfig struct {
+	Enabled    bool    `yaml:"enabled"`
+	SampleRate float64 `yaml:"sample_rate"` // fraction (0-1] of summaries evaluated
+	MinScore   float64 `yaml:"min_score"`   // summaries scoring below this are logged as regressions
+}
+
+// Speech providers
+const (
+	SpeechProviderOpenAI = "openai" // the OpenAI speech API, writing MP3
+	SpeechProviderLocal  = "local"  // a local TTS command such as piper or espeak-ng
+)
+
+// SpeechConfig reads each summary aloud after any redaction and uploads the audio next to
+// it as <name>_summary.<format>. Speech failures never fail a request.
+type SpeechConfig struct {
+	Enabled  bool    `yaml:"enabled"`
+	Provider string  `yaml:"provider"`  // openai or local (default openai)
+	Model    string  `yaml:"model"`     // OpenAI speech model (default gpt-4o-mini-tts)
+	Voice    string  `yaml:"voice"`     // OpenAI voice (default alloy)
+	Speed    float64 `yaml:"speed"`     // 0.25 to 4 (default 1)
+	MaxChars int     `yaml:"max_chars"` // longer summaries are cut before they are read (default 20000)
+	// Local TTS command and its arguments; it reads the text on stdin and writes the audio
+	// to the argument "{output}"
+	Command []string `yaml:"command"`
+	Format  string   `yaml:"format"` // extension of the audio the command writes (default wav)
+}
+
+// BudgetConfig caps the estimated LLM spend per calendar day and month (local time), as
+// computed from token usage and the openai_*_cost_per_1k prices. 0 disables a limit.
+type BudgetConfig struct {
+	DailyUSD   float64 `yaml:"daily_usd"`
+	MonthlyUSD float64 `yaml:"monthly_usd"`
+}
+
+// NearDuplicateConfig compares an embedding of each video's title and description with those
+// of the videos seen within the window. A background-source video at least threshold
+// similar (cosine) to one of them is a near-duplicate, e.g. a re-upload or the same news
+// clip on another channel: "skip" cancels it, "link" completes it pointing at the original.
+type NearDuplicateConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	Provider  string  `yaml:"provider"`  // openai, or local (hashed words, no API calls)
+	Model     string  `yaml:"model"`     // openai embedding model
+	Threshold float64 `yaml:"threshold"` // 0-1
+	Action    string  `yaml:"action"`    // skip or link
+	Window    string  `yaml:"window"`    // how far back to compare, e.g. "168h"
+}
+
+// DefaultNearDuplicateModel is the OpenAI embedding model used when none is configured
+const DefaultNearDuplicateModel = "text-embedding-3-small"
+
+// GetWindow returns how far back videos are compared, 7 days if unset or invalid
+func (n NearDuplicateConfig) GetWindow() time.Duration {
+	d, err := time.ParseDuration(n.Window)
+	if err != nil || d <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return d
+}
+
+// DefaultWhisperModelRegistryURL is where whisper.cpp publishes its ggml models
+const DefaultWhisperModelRegistryURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main"
+
+// WhisperModelsConfig lists extra whisper models to choose from per request, and downloads
+// configured models that are missing from disk. A model's file name (e.g. ggml-base.en.bin)
+// is appended to the registry URL.
+type WhisperModelsConfig struct {
+	Download    bool   `yaml:"download"`
+	RegistryURL string `yaml:"registry_url"`
+	// Expected SHA-256 per model file name; downloads and installed models that don't
+	// match are rejected. Models without a checksum are not verified.
+	Checksums map[string]string `yaml:"checksums"`
+	// Models to select from by video duration, listed from most accurate to fastest. A
+	// request uses the first model whose max_duration covers the video; requests of unknown
+	// duration, or longer than every limit, use whisper_model_path.
+	Models []WhisperModelConfig `yaml:"models"`
+}
+
+// WhisperModelConfig is a whisper model requests can be transcribed with
+type WhisperModelConfig struct {
+	Name        string `yaml:"name"` // e.g. "small"; requests can ask for it as their whisper_quality
+	Path        string `yaml:"path"`
+	MaxDuration string `yaml:"max_duration"` // longest video it is picked for, e.g. "20m" ("" = any length)
+}
+
+// GetMaxDuration returns the longest video the model is picked for, or 0 for any length
+func (m WhisperModelConfig) GetMaxDuration() time.Duration {
+	d, err := time.ParseDuration(m.MaxDuration)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Whisper quality hints a request can give instead of a model name
+const (
+	// WhisperQualityAuto picks the model by video duration (same as no hint)
+	WhisperQualityAuto = "auto"
+	// WhisperQualityFast uses the last, fastest listed model
+	WhisperQualityFast = "fast"
+	// WhisperQualityAccurate uses the first, most accurate listed model
+	WhisperQualityAccurate = "accurate"
+)
+
+// CheckWhisperQuality returns an error unless hint is empty, a quality hint or the name of
+// a configured whisper model
+func (c *AppConfig) CheckWhisperQuality(hint string) error {
+	switch hint {
+	case "", WhisperQualityAuto, WhisperQualityFast, WhisperQualityAccurate:
+		return nil
+	}
+	names := []string{WhisperQualityAuto, WhisperQualityFast, WhisperQualityAccurate}
+	for _, model := range c.WhisperModels.Models {
+		if model.Name == hint {
+			return nil
+		}
+		names = append(names, model.Name)
+	}
+	return fmt.Errorf("unsupported whisper_quality %q (supported: %s)", hint, strings.Join(names, ", "))
+}
+
+// CheckRerunModel returns an error unless model is empty, openai_model or one of
+// openai_rerun_models
+func (c *AppConfig) CheckRerunModel(model string) error {
+	if model == "" || model == c.OpenAIModel {
+		return nil
+	}
+	if c.SummarizerProvider != "openai" {
+		return fmt.Errorf("a summary model can only be chosen with the openai summarizer")
+	}
+	names := []string{c.OpenAIModel}
+	for _, name := range c.OpenAIRerunModels {
+		if name == model {
+			return nil
+		}
+		names = append(names, name)
+	}
+	return fmt.Errorf("unsupported model %q (supported: %s; add it to openai_rerun_models)", model, strings.Join(names, ", "))
+}
+
+// WhisperModelPaths returns the paths of every configured whisper model
+func (c *AppConfig) WhisperModelPaths() []string {
+	paths := []string{c.WhisperModelPath}
+	seen := map[string]bool{c.WhisperModelPath: true}
+	for _, model := range c.WhisperModels.Models {
+		if !seen[model.Path] {
+			seen[model.Path] = true
+			paths = append(paths, model.Path)
+		}
+	}
+	return paths
+}
+
+// Transcription routes
+const (
+	TranscriptionRouteLocal = "local" // whisper.cpp
+	TranscriptionRouteCloud = "cloud" // the OpenAI transcription API, billed per minute of audio
+)
+
+// TranscriptionRoutingConfig picks local or cloud transcription per request by comparing
+// the video's duration with a threshold, to balance cost against turnaround time. The cloud
+// route uses openai_api_key.
+type TranscriptionRoutingConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Threshold       string `yaml:"threshold"`        // e.g. "20m"
+	ShortVideos     string `yaml:"short_videos"`     // route for videos up to the threshold (default local)
+	LongVideos      string `yaml:"long_videos"`      // route for longer videos (default cloud)
+	UnknownDuration string `yaml:"unknown_duration"` // route when the duration isn't known (default local)
+	CloudModel      string `yaml:"cloud_model"`      // OpenAI transcription model (default whisper-1)
+}
+
+// GetThreshold returns the duration separating short from long videos, or 0 if unset or invalid
+func (c TranscriptionRoutingConfig) GetThreshold() time.Duration {
+	d, err := time.ParseDuration(c.Threshold)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// RedactionConfig scrubs transcripts and summaries before they are uploaded. Categories
+// listed under categories use their own rules; every other category uses default.
+type RedactionConfig struct {
+	Default    RedactionRules            `yaml:"default"`
+	Categories map[string]RedactionRules `yaml:"categories"`
+}
+
+// RedactionRules selects what is redacted. The zero value redacts nothing.
+type RedactionRules struct {
+	Emails       bool `yaml:"emails"`
+	PhoneNumbers bool `yaml:"phone_numbers"`
+	// Person names found by the summarization provider acting as a named-entity recognizer
+	Names     bool `yaml:"names"`
+	Profanity bool `yaml:"profanity"`
+	// Extra words treated as profanity, matched case-insensitively as whole words
+	ProfanityWords []string `yaml:"profanity_words"`
+	// Extra regular expressions, keyed by the label their matches are replaced with
+	Patterns map[string]string `yaml:"patterns"`
+}
+
+// Enabled reports whether the rules redact anything
+func (r RedactionRules) Enabled() bool {
+	return r.Emails || r.PhoneNumbers || r.Names || r.Profanity || len(r.ProfanityWords) > 0 || len(r.Patterns) > 0
+}
+
+// RulesFor returns the redaction rules for a category
+func (c RedactionConfig) RulesFor(category string) RedactionRules {
+	if rules, ok := c.Categories[category]; ok {
+		return rules
+	}
+	return c.Default
+}
+
+// Prompt language modes
+const (
+	// PromptLanguageOff uses the request's prompt as is, whatever the transcript language
+	PromptLanguageOff = "off"
+	// PromptLanguageVariant uses the prompt's variant for the transcript language, and
+	// falls back to PromptLanguageInstruct when there is none
+	PromptLanguageVariant = "variant"
+	// PromptLanguageInstruct tells the model to write the summary in the transcript language
+	PromptLanguageInstruct = "instruct"
+)
+
+// PromptLanguageModes are the supported prompt_language_mode values
+var PromptLanguageModes = []string{PromptLanguageOff, PromptLanguageVariant, PromptLanguageInstruct}
+
+// HookConfig is a post-processing step run once a request's outputs are uploaded. Exactly one
+// of command and url is set. Hook failures are logged and never fail the request.
+type HookConfig struct {
+	Name string `yaml:"name"`
+	// Program and arguments, run with the request details in VS_HOOK_* environment
+	// variables and as JSON on stdin
+	Command []string `yaml:"command"`
+	// Webhook the request details are POSTed to as JSON
+	URL        string   `yaml:"url"`
+	Timeout    string   `yaml:"timeout"`    // e.g. "30s"; defaults to 1 minute
+	Categories []string `yaml:"categories"` // empty runs the hook for every category
+}
+
+// GetTimeout returns how long the hook may run, falling back to 1 minute if unset or invalid
+func (h HookConfig) GetTimeout() time.Duration {
+	d, err := time.ParseDuration(h.Timeout)
+	if err != nil || d <= 0 {
+		return time.Minute
+	}
+	return d
+}
+
+// HooksFor returns the hooks that run for requests in a category
+func (c *AppConfig) HooksFor(category string) []HookConfig {
+	var hooks []HookConfig
+	for _, hook := range c.Hooks {
+		if len(hook.Categories) == 0 {
+			hooks = append(hooks, hook)
+			continue
+		}
+		for _, hookCategory := range hook.Categories {
+			if hookCategory == category {
+				hooks = append(hooks, hook)
+				break
+			}
+		}
+	}
+	return hooks
+}
+
+// FaultInjectionConfig makes providers fail or slow down on purpose, to exercise retry,
+// timeout and failure handling in staging. It must never be enabled in production.
+type FaultInjectionConfig struct {
+	Enabled   bool                   `yaml:"enabled"`
+	Providers map[string]FaultConfig `yaml:"providers"` // keyed by FaultInjectionProviders
+}
+
+// FaultConfig is the fault injected into every call of one provider
+type FaultConfig struct {
+	FailureRate float64 `yaml:"failure_rate"` // probability (0-1) that a call fails
+	Latency     string  `yaml:"latency"`      // delay added before every call, e.g. "2s"
+}
+
+// GetLatency returns the injected delay, or 0 if unset or invalid
+func (f FaultConfig) GetLatency() time.Duration {
+	if f.Latency == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(f.Latency)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Output naming defaults, matching the <title>_<request> layout used before names were configurable
+const (
+	DefaultOutputFolderTemplate = "{title}_{request_id}"
+	DefaultOutputFileTemplate   = "{title}_{request_id}_{suffix}"
+	DefaultOutputNameMaxLength  = 150
+)
+
+// OutputNamePlaceholders are the {placeholders} output naming templates can use.
+// {date} is the upload day, {upload_date} the video's publish day (both YYYY-MM-DD),
+// and {suffix} the kind of file, e.g. "summary.txt".
+var OutputNamePlaceholders = []string{"title", "channel", "request_id", "category", "user", "date", "upload_date", "suffix"}
+
+// OutputNamingConfig sets how output folders and files are named. Placeholders that are
+// empty for a request are dropped along with their separator. Names longer than MaxLength
+// bytes are shortened by cutting the title, then the channel.
+type OutputNamingConfig struct {
+	FolderTemplate string `yaml:"folder_template"`
+	FileTemplate   string `yaml:"file_template"` // must contain {suffix}
+	MaxLength      int    `yaml:"max_length"`
+}
+
+// VideoContextConfig controls which parts of the uploader's metadata are added to the
+// summarization prompt. Both are on unless disabled in the file.
+type VideoContextConfig struct {
+	Description         bool `yaml:"description"`
+	Chapters            bool `yaml:"chapters"`
+	MaxDescriptionChars int  `yaml:"max_description_chars"` // longer descriptions are cut (default 4000)
+}
+
+// StoreConfig caps how much request history the in-memory state store keeps.
+// Once MaxRequests is reached, the least recently used finished requests are evicted.
+// A negative value disables the cap.
+type StoreConfig struct {
+	// memory (default), or bolt to keep requests, events, dedup keys and spend in a single
+	// file at path, so they survive restarts without a database server
+	Backend             string `yaml:"backend"`
+	Path                string `yaml:"path"`
+	MaxRequests         int    `yaml:"max_requests"`
+	MaxEventsPerRequest int    `yaml:"max_events_per_request"`
+}
+
+// RetentionConfig sets how long each class of data is kept, as durations like "720h".
+// "0" keeps it until it is evicted or deleted some other way. There is no audit class: the
+// service keeps no audit log apart from the request event log, covered by Events.
+type RetentionConfig struct {
+	RequestStates string `yaml:"request_states"` // finished requests, with their events and dedup keys
+	Events        string `yaml:"events"`         // event log entries of finished requests
+	Transcripts   string `yaml:"transcripts"`    // transcript files written by the local output provider
+	Summaries     string `yaml:"summaries"`      // summary files written by the local output provider
+}
+
+// GetRequestStates returns how long finished requests are kept; 0 if forever or invalid
+func (r RetentionConfig) GetRequestStates() time.Duration { return parseRetention(r.RequestStates) }
+
+// GetEvents returns how long event log entries are kept; 0 if forever or invalid
+func (r RetentionConfig) GetEvents() time.Duration { return parseRetention(r.Events) }
+
+// GetTranscripts returns how long local transcript files are kept; 0 if forever or invalid
+func (r RetentionConfig) GetTranscripts() time.Duration { return parseRetention(r.Transcripts) }
+
+// GetSummaries returns how long local summary files are kept; 0 if forever or invalid
+func (r RetentionConfig) GetSummaries() time.Duration { return parseRetention(r.Summaries) }
+
+func parseRetention(value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
 }
 
 func LoadConfig(path string) (*AppConfig, error) {
-	// Read YAML file
-	data, err := os.ReadFile(path)
+	// Read YAML file, applying the environment overlay if there is one
+	data, err := readLayeredYAML(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
 
-	var cfg AppConfig
+	// Upload and video context toggles default to enabled when omitted from the file
+	cfg := AppConfig{
+		UploadSummary:    true,
+		UploadTranscript: true,
+		VideoContext:     VideoContextConfig{Description: true, Chapters: true},
+	}
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
@@ -93,23 +841,133 @@ func (c *AppConfig) applyEnvOverrides() {
 		return fallback
 	}
 
+	getEnvFloat := func(key string, fallback float64) float64 {
+		if val := os.Getenv(key); val != "" {
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return f
+			}
+		}
+		return fallback
+	}
+
 	// Apply overrides
 	c.SummarizerProvider = getEnv("VS_SUMMARIZER_PROVIDER", c.SummarizerProvider)
+	c.DryRun = getEnvBool("VS_DRY_RUN", c.DryRun)
+	c.StubSummarySentences = getEnvInt("VS_STUB_SUMMARY_SENTENCES", c.StubSummarySentences)
+	c.TextSummarySentences = getEnvInt("VS_TEXT_SUMMARY_SENTENCES", c.TextSummarySentences)
 	c.OpenAIKey = getEnv("VS_OPENAI_API_KEY", c.OpenAIKey)
 	c.OpenAIModel = getEnv("VS_OPENAI_MODEL", c.OpenAIModel)
+	if models := os.Getenv("VS_OPENAI_RERUN_MODELS"); models != "" {
+		c.OpenAIRerunModels = strings.Fields(models)
+	}
 	c.OpenAIMaxTokens = getEnvInt("VS_OPENAI_MAX_TOKENS", c.OpenAIMaxTokens)
+	c.OpenAIPromptCostPer1K = getEnvFloat("VS_OPENAI_PROMPT_COST_PER_1K", c.OpenAIPromptCostPer1K)
+	c.OpenAICompletionCostPer1K = getEnvFloat("VS_OPENAI_COMPLETION_COST_PER_1K", c.OpenAICompletionCostPer1K)
+	c.SummarizationChunkSize = getEnvInt("VS_SUMMARIZATION_CHUNK_SIZE", c.SummarizationChunkSize)
+	c.SummarizationChunkConcurrency = getEnvInt("VS_SUMMARIZATION_CHUNK_CONCURRENCY", c.SummarizationChunkConcurrency)
+	c.SummaryCitations = getEnvBool("VS_SUMMARY_CITATIONS", c.SummaryCitations)
+	c.SummaryStreaming = getEnvBool("VS_SUMMARY_STREAMING", c.SummaryStreaming)
+	c.SummaryStreamFlushInterval = getEnv("VS_SUMMARY_STREAM_FLUSH_INTERVAL", c.SummaryStreamFlushInterval)
+	c.VideoContext.Description = getEnvBool("VS_VIDEO_CONTEXT_DESCRIPTION", c.VideoContext.Description)
+	c.VideoContext.Chapters = getEnvBool("VS_VIDEO_CONTEXT_CHAPTERS", c.VideoContext.Chapters)
+	c.VideoContext.MaxDescriptionChars = getEnvInt("VS_VIDEO_CONTEXT_MAX_DESCRIPTION_CHARS", c.VideoContext.MaxDescriptionChars)
 	c.YtDlpPath = getEnv("VS_YT_DLP_PATH", c.YtDlpPath)
+	c.YtDlpUserAgent = getEnv("VS_YT_DLP_USER_AGENT", c.YtDlpUserAgent)
+	if args := os.Getenv("VS_YT_DLP_EXTRACTOR_ARGS"); args != "" {
+		c.YtDlpExtractorArgs = strings.Fields(args)
+	}
+	if args := os.Getenv("VS_YT_DLP_EXTRA_ARGS"); args != "" {
+		c.YtDlpExtraArgs = strings.Fields(args)
+	}
+	c.DownloadRateLimit = getEnv("VS_DOWNLOAD_RATE_LIMIT", c.DownloadRateLimit)
+	c.DownloadTotalRateLimit = getEnv("VS_DOWNLOAD_TOTAL_RATE_LIMIT", c.DownloadTotalRateLimit)
+	c.HostLimits.MaxConcurrent = getEnvInt("VS_HOST_LIMITS_MAX_CONCURRENT", c.HostLimits.MaxConcurrent)
+	c.HostLimits.MinInterval = getEnv("VS_HOST_LIMITS_MIN_INTERVAL", c.HostLimits.MinInterval)
+	c.LLMCacheTTL = getEnv("VS_LLM_CACHE_TTL", c.LLMCacheTTL)
+	c.LLMCacheSize = getEnvInt("VS_LLM_CACHE_SIZE", c.LLMCacheSize)
+	c.VideoInfoCacheTTL = getEnv("VS_VIDEO_INFO_CACHE_TTL", c.VideoInfoCacheTTL)
+	c.VideoInfoCacheSize = getEnvInt("VS_VIDEO_INFO_CACHE_SIZE", c.VideoInfoCacheSize)
+	c.PdfToTextPath = getEnv("VS_PDFTOTEXT_PATH", c.PdfToTextPath)
+	c.DocumentMaxSizeMB = getEnvInt("VS_DOCUMENT_MAX_SIZE_MB", c.DocumentMaxSizeMB)
 	c.WhisperPath = getEnv("VS_WHISPER_PATH", c.WhisperPath)
 	c.WhisperModelPath = getEnv("VS_WHISPER_MODEL_PATH", c.WhisperModelPath)
+	c.WhisperLanguage = getEnv("VS_WHISPER_LANGUAGE", c.WhisperLanguage)
+	c.WhisperModels.Download = getEnvBool("VS_WHISPER_MODELS_DOWNLOAD", c.WhisperModels.Download)
+	c.WhisperModels.RegistryURL = getEnv("VS_WHISPER_MODELS_REGISTRY_URL", c.WhisperModels.RegistryURL)
+	c.TranscriptionRouting.Enabled = getEnvBool("VS_TRANSCRIPTION_ROUTING_ENABLED", c.TranscriptionRouting.Enabled)
+	c.TranscriptionRouting.Threshold = getEnv("VS_TRANSCRIPTION_ROUTING_THRESHOLD", c.TranscriptionRouting.Threshold)
+	c.PromptLanguageMode = getEnv("VS_PROMPT_LANGUAGE_MODE", c.PromptLanguageMode)
+	c.TranscriptLowConfidenceThreshold = getEnvFloat("VS_TRANSCRIPT_LOW_CONFIDENCE_THRESHOLD", c.TranscriptLowConfidenceThreshold)
 	c.TmpDir = getEnv("VS_TMP_DIR", c.TmpDir)
 	c.PromptsDir = getEnv("VS_PROMPTS_DIR", c.PromptsDir)
+	c.TmpDirQuotaMB = getEnvInt("VS_TMP_DIR_QUOTA_MB", c.TmpDirQuotaMB)
+	c.TmpSweepInterval = getEnv("VS_TMP_SWEEP_INTERVAL", c.TmpSweepInterval)
+	c.TmpOrphanMinAge = getEnv("VS_TMP_ORPHAN_MIN_AGE", c.TmpOrphanMinAge)
+	c.ArtifactsDir = getEnv("VS_ARTIFACTS_DIR", c.ArtifactsDir)
+	c.ArtifactsRetention = getEnv("VS_ARTIFACTS_RETENTION", c.ArtifactsRetention)
+	c.ArtifactsEncryptionKey = getEnv("VS_ARTIFACTS_ENCRYPTION_KEY", c.ArtifactsEncryptionKey)
+	c.ArtifactsEncryptionKeyFile = getEnv("VS_ARTIFACTS_ENCRYPTION_KEY_FILE", c.ArtifactsEncryptionKeyFile)
 	c.OutputProvider = getEnv("VS_OUTPUT_PROVIDER", c.OutputProvider)
+	c.LocalOutputDir = getEnv("VS_LOCAL_OUTPUT_DIR", c.LocalOutputDir)
+	c.OutputNaming.FolderTemplate = getEnv("VS_OUTPUT_FOLDER_TEMPLATE", c.OutputNaming.FolderTemplate)
+	c.OutputNaming.FileTemplate = getEnv("VS_OUTPUT_FILE_TEMPLATE", c.OutputNaming.FileTemplate)
+	c.OutputNaming.MaxLength = getEnvInt("VS_OUTPUT_NAME_MAX_LENGTH", c.OutputNaming.MaxLength)
 	c.GDriveAuthMethod = getEnv("VS_GDRIVE_AUTH_METHOD", c.GDriveAuthMethod)
 	c.GDriveCredentialsFile = getEnv("VS_GDRIVE_CREDENTIALS_FILE", c.GDriveCredentialsFile)
 	c.GDriveTokenFile = getEnv("VS_GDRIVE_TOKEN_FILE", c.GDriveTokenFile)
 	c.GDriveFolderID = getEnv("VS_GDRIVE_FOLDER_ID", c.GDriveFolderID)
+	c.SlackBotToken = getEnv("VS_SLACK_BOT_TOKEN", c.SlackBotToken)
+	c.SlackChannel = getEnv("VS_SLACK_CHANNEL", c.SlackChannel)
+	c.DiscordBotToken = getEnv("VS_DISCORD_BOT_TOKEN", c.DiscordBotToken)
+	c.DiscordChannel = getEnv("VS_DISCORD_CHANNEL", c.DiscordChannel)
+	c.GSheets.SpreadsheetID = getEnv("VS_GSHEETS_SPREADSHEET_ID", c.GSheets.SpreadsheetID)
+	c.Readwise.Token = getEnv("VS_READWISE_TOKEN", c.Readwise.Token)
+	c.Raindrop.Token = getEnv("VS_RAINDROP_TOKEN", c.Raindrop.Token)
+	c.Confluence.BaseURL = getEnv("VS_CONFLUENCE_BASE_URL", c.Confluence.BaseURL)
+	c.Confluence.Username = getEnv("VS_CONFLUENCE_USERNAME", c.Confluence.Username)
+	c.Confluence.Token = getEnv("VS_CONFLUENCE_TOKEN", c.Confluence.Token)
 	c.UploadSummary = getEnvBool("VS_UPLOAD_SUMMARY", c.UploadSummary)
 	c.UploadTranscript = getEnvBool("VS_UPLOAD_TRANSCRIPT", c.UploadTranscript)
+	c.UploadInfoJSON = getEnvBool("VS_UPLOAD_INFO_JSON", c.UploadInfoJSON)
+	c.UploadThumbnail = getEnvBool("VS_UPLOAD_THUMBNAIL", c.UploadThumbnail)
+	c.MaxActiveRequests = getEnvInt("VS_MAX_ACTIVE_REQUESTS", c.MaxActiveRequests)
+	c.SourceDedupAcrossPrompts = getEnvBool("VS_SOURCE_DEDUP_ACROSS_PROMPTS", c.SourceDedupAcrossPrompts)
+	c.NearDuplicates.Enabled = getEnvBool("VS_NEAR_DUPLICATES_ENABLED", c.NearDuplicates.Enabled)
+	c.Store.Backend = getEnv("VS_STORE_BACKEND", c.Store.Backend)
+	c.Store.Path = getEnv("VS_STORE_PATH", c.Store.Path)
+	c.Store.MaxRequests = getEnvInt("VS_STORE_MAX_REQUESTS", c.Store.MaxRequests)
+	c.Store.MaxEventsPerRequest = getEnvInt("VS_STORE_MAX_EVENTS_PER_REQUEST", c.Store.MaxEventsPerRequest)
+	c.Retention.RequestStates = getEnv("VS_RETENTION_REQUEST_STATES", c.Retention.RequestStates)
+	c.Retention.Events = getEnv("VS_RETENTION_EVENTS", c.Retention.Events)
+	c.Retention.Transcripts = getEnv("VS_RETENTION_TRANSCRIPTS", c.Retention.Transcripts)
+	c.Retention.Summaries = getEnv("VS_RETENTION_SUMMARIES", c.Retention.Summaries)
+	c.FaultInjection.Enabled = getEnvBool("VS_FAULT_INJECTION_ENABLED", c.FaultInjection.Enabled)
+	c.TaskQueue.Backend = getEnv("VS_TASK_QUEUE_BACKEND", c.TaskQueue.Backend)
+	if brokers := os.Getenv("VS_KAFKA_BROKERS"); brokers != "" {
+		c.TaskQueue.Kafka.Brokers = strings.Split(brokers, ",")
+	}
+	c.TaskQueue.Kafka.GroupID = getEnv("VS_KAFKA_GROUP_ID", c.TaskQueue.Kafka.GroupID)
+	c.TaskQueue.Kafka.Username = getEnv("VS_KAFKA_USERNAME", c.TaskQueue.Kafka.Username)
+	c.TaskQueue.Kafka.Password = getEnv("VS_KAFKA_PASSWORD", c.TaskQueue.Kafka.Password)
+	c.Preflight.Enabled = getEnvBool("VS_PREFLIGHT_ENABLED", c.Preflight.Enabled)
+	c.Watchdog.Enabled = getEnvBool("VS_WATCHDOG_ENABLED", c.Watchdog.Enabled)
+	c.Watchdog.MaxAttempts = getEnvInt("VS_WATCHDOG_MAX_ATTEMPTS", c.Watchdog.MaxAttempts)
+	c.Slides.Enabled = getEnvBool("VS_SLIDES_ENABLED", c.Slides.Enabled)
+	c.Slides.Interval = getEnv("VS_SLIDES_INTERVAL", c.Slides.Interval)
+	c.Slides.MaxFrames = getEnvInt("VS_SLIDES_MAX_FRAMES", c.Slides.MaxFrames)
+	c.Slides.Language = getEnv("VS_SLIDES_LANGUAGE", c.Slides.Language)
+	c.Slides.FfmpegPath = getEnv("VS_FFMPEG_PATH", c.Slides.FfmpegPath)
+	c.Slides.TesseractPath = getEnv("VS_TESSERACT_PATH", c.Slides.TesseractPath)
+	c.Highlights.Enabled = getEnvBool("VS_HIGHLIGHTS_ENABLED", c.Highlights.Enabled)
+	c.Highlights.MaxMoments = getEnvInt("VS_HIGHLIGHTS_MAX_MOMENTS", c.Highlights.MaxMoments)
+	c.Speech.Enabled = getEnvBool("VS_SPEECH_ENABLED", c.Speech.Enabled)
+	c.Speech.Provider = getEnv("VS_SPEECH_PROVIDER", c.Speech.Provider)
+	c.Speech.Voice = getEnv("VS_SPEECH_VOICE", c.Speech.Voice)
+	c.Evaluation.Enabled = getEnvBool("VS_EVALUATION_ENABLED", c.Evaluation.Enabled)
+	c.Evaluation.SampleRate = getEnvFloat("VS_EVALUATION_SAMPLE_RATE", c.Evaluation.SampleRate)
+	c.Evaluation.MinScore = getEnvFloat("VS_EVALUATION_MIN_SCORE", c.Evaluation.MinScore)
+	c.Budget.DailyUSD = getEnvFloat("VS_BUDGET_DAILY_USD", c.Budget.DailyUSD)
+	c.Budget.MonthlyUSD = getEnvFloat("VS_BUDGET_MONTHLY_USD", c.Budget.MonthlyUSD)
 
 	// Handle concurrency overrides
 	c.applyConcurrencyOverrides()
@@ -124,12 +982,18 @@ func (c *AppConfig) applyConcurrencyOverrides() {
 
 	// Define concurrency types and their environment variable names
 	concurrencyTypes := map[string]string{
-		"transcription":  "VS_CONCURRENCY_TRANSCRIPTION",
-		"summarization":  "VS_CONCURRENCY_SUMMARIZATION",
-		"video_info":     "VS_CONCURRENCY_VIDEO_INFO",
-		"output":         "VS_CONCURRENCY_OUTPUT",
-		"cleanup":        "VS_CONCURRENCY_CLEANUP",
-		"audio_download": "VS_CONCURRENCY_AUDIO_DOWNLOAD",
+		"transcription":   "VS_CONCURRENCY_TRANSCRIPTION",
+		"summarization":   "VS_CONCURRENCY_SUMMARIZATION",
+		"video_info":      "VS_CONCURRENCY_VIDEO_INFO",
+		"output":          "VS_CONCURRENCY_OUTPUT",
+		"cleanup":         "VS_CONCURRENCY_CLEANUP",
+		"audio_download":  "VS_CONCURRENCY_AUDIO_DOWNLOAD",
+		"text_extraction": "VS_CONCURRENCY_TEXT_EXTRACTION",
+		"highlights":      "VS_CONCURRENCY_HIGHLIGHTS",
+		"evaluation":      "VS_CONCURRENCY_EVALUATION",
+		"redaction":       "VS_CONCURRENCY_REDACTION",
+		"speech":          "VS_CONCURRENCY_SPEECH",
+		"hooks":           "VS_CONCURRENCY_HOOKS",
 	}
 
 	// Apply overrides for each concurrency type
@@ -144,6 +1008,28 @@ func (c *AppConfig) applyConcurrencyOverrides() {
 
 // setDefaults sets default values for missing configuration
 func (c *AppConfig) setDefaults() {
+	if c.DryRun {
+		c.SummarizerProvider = "stub"
+		c.OutputProvider = "local"
+	}
+	if c.StubSummarySentences == 0 {
+		c.StubSummarySentences = 3
+	}
+	if c.TextSummarySentences == 0 {
+		c.TextSummarySentences = 8
+	}
+	if c.LocalOutputDir == "" {
+		c.LocalOutputDir = "output"
+	}
+	if c.OutputNaming.FolderTemplate == "" {
+		c.OutputNaming.FolderTemplate = DefaultOutputFolderTemplate
+	}
+	if c.OutputNaming.FileTemplate == "" {
+		c.OutputNaming.FileTemplate = DefaultOutputFileTemplate
+	}
+	if c.OutputNaming.MaxLength == 0 {
+		c.OutputNaming.MaxLength = DefaultOutputNameMaxLength
+	}
 	if c.SummarizerProvider == "" {
 		c.SummarizerProvider = "openai"
 	}
@@ -153,18 +1039,81 @@ func (c *AppConfig) setDefaults() {
 	if c.OpenAIMaxTokens == 0 {
 		c.OpenAIMaxTokens = 10000
 	}
+	if c.SummarizationChunkSize == 0 {
+		c.SummarizationChunkSize = 60000
+	}
+	if c.SummarizationChunkConcurrency == 0 {
+		c.SummarizationChunkConcurrency = 1
+	}
+	if c.SummaryStreamFlushInterval == "" {
+		c.SummaryStreamFlushInterval = "2s"
+	}
+	if c.VideoContext.MaxDescriptionChars == 0 {
+		c.VideoContext.MaxDescriptionChars = 4000
+	}
 	if c.YtDlpPath == "" {
 		c.YtDlpPath = "/app/tools/yt-dlp"
 	}
+	if c.HostLimits.MinInterval == "" {
+		c.HostLimits.MinInterval = "0"
+	}
+	if c.LLMCacheTTL == "" {
+		c.LLMCacheTTL = "24h"
+	}
+	if c.LLMCacheSize == 0 {
+		c.LLMCacheSize = 500
+	}
+	if c.VideoInfoCacheTTL == "" {
+		c.VideoInfoCacheTTL = "6h"
+	}
+	if c.VideoInfoCacheSize == 0 {
+		c.VideoInfoCacheSize = 1000
+	}
+	if c.PdfToTextPath == "" {
+		c.PdfToTextPath = "pdftotext"
+	}
+	if c.DocumentMaxSizeMB == 0 {
+		c.DocumentMaxSizeMB = 50
+	}
 	if c.WhisperPath == "" {
 		c.WhisperPath = "/app/tools/whisper"
 	}
 	if c.WhisperModelPath == "" {
 		c.WhisperModelPath = "/app/models/ggml-tiny.en.bin"
 	}
+	if c.WhisperModels.RegistryURL == "" {
+		c.WhisperModels.RegistryURL = DefaultWhisperModelRegistryURL
+	}
+	if c.TranscriptionRouting.ShortVideos == "" {
+		c.TranscriptionRouting.ShortVideos = TranscriptionRouteLocal
+	}
+	if c.TranscriptionRouting.LongVideos == "" {
+		c.TranscriptionRouting.LongVideos = TranscriptionRouteCloud
+	}
+	if c.TranscriptionRouting.UnknownDuration == "" {
+		c.TranscriptionRouting.UnknownDuration = TranscriptionRouteLocal
+	}
+	if c.TranscriptionRouting.CloudModel == "" {
+		c.TranscriptionRouting.CloudModel = "whisper-1"
+	}
+	if c.PromptLanguageMode == "" {
+		c.PromptLanguageMode = PromptLanguageVariant
+	}
+	if c.TranscriptLowConfidenceThreshold == 0 {
+		c.TranscriptLowConfidenceThreshold = 0.6
+	}
 	if c.TmpDir == "" {
 		c.TmpDir = "/tmp"
 	}
+	if c.TmpSweepInterval == "" {
+		c.TmpSweepInterval = "10m"
+	}
+	if c.TmpOrphanMinAge == "" {
+		c.TmpOrphanMinAge = "1h"
+	}
+	if c.ArtifactsRetention == "" {
+		c.ArtifactsRetention = "72h"
+	}
 	if c.PromptsDir == "" {
 		c.PromptsDir = "/app/prompts"
 	}
@@ -180,14 +1129,279 @@ func (c *AppConfig) setDefaults() {
 	if c.GDriveTokenFile == "" {
 		c.GDriveTokenFile = "/app/secrets/gdrive_token.json"
 	}
+	if c.GSheets.Sheet == "" {
+		c.GSheets.Sheet = "Summaries"
+	}
+	if c.GSheets.ExcerptChars == 0 {
+		c.GSheets.ExcerptChars = 500
+	}
+	if c.NearDuplicates.Provider == "" {
+		c.NearDuplicates.Provider = "openai"
+	}
+	if c.NearDuplicates.Model == "" {
+		c.NearDuplicates.Model = DefaultNearDuplicateModel
+	}
+	if c.NearDuplicates.Threshold == 0 {
+		c.NearDuplicates.Threshold = 0.92
+	}
+	if c.NearDuplicates.Action == "" {
+		c.NearDuplicates.Action = "link"
+	}
+	if c.NearDuplicates.Window == "" {
+		c.NearDuplicates.Window = "168h"
+	}
+	if c.Readwise.Mode == "" {
+		c.Readwise.Mode = "highlights"
+	}
+	if c.Confluence.TitleTemplate == "" {
+		c.Confluence.TitleTemplate = DefaultConfluenceTitleTemplate
+	}
+	if c.Store.Backend == "" {
+		c.Store.Backend = StoreMemory
+	}
+	if c.Store.Path == "" {
+		c.Store.Path = "data/state.db"
+	}
+	if c.Store.MaxRequests == 0 {
+		c.Store.MaxRequests = 10000
+	}
+	if c.Store.MaxEventsPerRequest == 0 {
+		c.Store.MaxEventsPerRequest = 100
+	}
+	for _, value := range []*string{&c.Retention.RequestStates, &c.Retention.Events, &c.Retention.Transcripts, &c.Retention.Summaries} {
+		if *value == "" {
+			*value = "0"
+		}
+	}
+	if c.TaskQueue.Backend == "" {
+		c.TaskQueue.Backend = TaskQueueMemory
+	}
+	if c.TaskQueue.Kafka.TopicPrefix == "" {
+		c.TaskQueue.Kafka.TopicPrefix = "video-summarizer.tasks."
+	}
+	if c.TaskQueue.Kafka.GroupID == "" {
+		c.TaskQueue.Kafka.GroupID = "video-summarizer"
+	}
+	if len(c.Preflight.Checks) == 0 {
+		c.Preflight.Checks = []string{PreflightTranscription, PreflightSummarization, PreflightOutput}
+	}
+	if c.Preflight.Timeout == "" {
+		c.Preflight.Timeout = "2m"
+	}
+	if c.Preflight.RetryInterval == "" {
+		c.Preflight.RetryInterval = "1m"
+	}
+	if c.Watchdog.CheckInterval == "" {
+		c.Watchdog.CheckInterval = "30s"
+	}
+	if c.Watchdog.MaxAttempts == 0 {
+		c.Watchdog.MaxAttempts = 2
+	}
+	if c.Watchdog.Timeouts == nil {
+		c.Watchdog.Timeouts = make(map[string]string)
+	}
+	for taskType, timeout := range DefaultWatchdogTimeouts {
+		if _, ok := c.Watchdog.Timeouts[taskType]; !ok {
+			c.Watchdog.Timeouts[taskType] = timeout
+		}
+	}
+	if c.Slides.Interval == "" {
+		c.Slides.Interval = "30s"
+	}
+	if c.Slides.MaxFrames == 0 {
+		c.Slides.MaxFrames = 120
+	}
+	if c.Slides.MaxHeight == 0 {
+		c.Slides.MaxHeight = 720
+	}
+	if c.Slides.MaxChars == 0 {
+		c.Slides.MaxChars = 8000
+	}
+	if c.Slides.Language == "" {
+		c.Slides.Language = "eng"
+	}
+	if c.Slides.FfmpegPath == "" {
+		c.Slides.FfmpegPath = "ffmpeg"
+	}
+	if c.Slides.TesseractPath == "" {
+		c.Slides.TesseractPath = "tesseract"
+	}
+	if c.Highlights.MaxMoments == 0 {
+		c.Highlights.MaxMoments = 8
+	}
+	if c.Evaluation.SampleRate == 0 {
+		c.Evaluation.SampleRate = 1
+	}
+	if c.Evaluation.MinScore == 0 {
+		c.Evaluation.MinScore = 3
+	}
+	if c.Speech.Provider == "" {
+		c.Speech.Provider = SpeechProviderOpenAI
+	}
+	if c.Speech.Model == "" {
+		c.Speech.Model = "gpt-4o-mini-tts"
+	}
+	if c.Speech.Voice == "" {
+		c.Speech.Voice = "alloy"
+	}
+	if c.Speech.Speed == 0 {
+		c.Speech.Speed = 1
+	}
+	if c.Speech.MaxChars == 0 {
+		c.Speech.MaxChars = 20000
+	}
+	if c.Speech.Format == "" {
+		c.Speech.Format = "wav"
+	}
 	if c.Concurrency == nil {
 		c.Concurrency = map[string]int{
-			"transcription":  2,
-			"summarization":  3,
-			"video_info":     1,
-			"output":         1,
-			"cleanup":        1,
-			"audio_download": 1,
+			"transcription":   2,
+			"summarization":   3,
+			"video_info":      1,
+			"output":          1,
+			"cleanup":         1,
+			"audio_download":  1,
+			"text_extraction": 1,
+			"slides":          1,
+			"highlights":      1,
+			"evaluation":      1,
+			"redaction":       1,
+			"speech":          1,
+			"hooks":           1,
+		}
+	}
+}
+
+// GetTmpSweepInterval returns the orphan sweep interval, falling back to 10 minutes if invalid
+func (c *AppConfig) GetTmpSweepInterval() time.Duration {
+	d, err := time.ParseDuration(c.TmpSweepInterval)
+	if err != nil {
+		return 10 * time.Minute
+	}
+	return d
+}
+
+// GetTmpOrphanMinAge returns the minimum age of a file before it can be swept, falling back to 1 hour if invalid
+func (c *AppConfig) GetTmpOrphanMinAge() time.Duration {
+	d, err := time.ParseDuration(c.TmpOrphanMinAge)
+	if err != nil {
+		return time.Hour
+	}
+	return d
+}
+
+// GetArtifactsRetention returns how long checkpoints are kept, falling back to 72 hours if invalid
+func (c *AppConfig) GetArtifactsRetention() time.Duration {
+	d, err := time.ParseDuration(c.ArtifactsRetention)
+	if err != nil {
+		return 72 * time.Hour
+	}
+	return d
+}
+
+// GetArtifactsEncryptionKey returns the 32-byte checkpoint encryption key, read from
+// artifacts_encryption_key_file when set, or nil if encryption is off
+func (c *AppConfig) GetArtifactsEncryptionKey() ([]byte, error) {
+	encoded := c.ArtifactsEncryptionKey
+	if c.ArtifactsEncryptionKeyFile != "" {
+		data, err := os.ReadFile(c.ArtifactsEncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifacts encryption key: %w", err)
 		}
+		encoded = string(data)
+	}
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("artifacts encryption key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("artifacts encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// GetVideoInfoCacheTTL returns how long video info is cached; 0 means caching is disabled
+func (c *AppConfig) GetVideoInfoCacheTTL() time.Duration {
+	d, err := time.ParseDuration(c.VideoInfoCacheTTL)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// GetYtDlpArgs returns the configured arguments added to every yt-dlp call
+func (c *AppConfig) GetYtDlpArgs() []string {
+	var args []string
+	for _, extractorArgs := range c.YtDlpExtractorArgs {
+		args = append(args, "--extractor-args", extractorArgs)
 	}
+	return append(args, c.YtDlpExtraArgs...)
+}
+
+// GetDownloadRateLimit returns the bandwidth each audio download may use, in bytes per second:
+// the per-download limit, or the worker's share of the total limit if that is lower. 0 means
+// unlimited.
+func (c *AppConfig) GetDownloadRateLimit() int64 {
+	limit, _ := ParseByteRate(c.DownloadRateLimit)
+	if total, _ := ParseByteRate(c.DownloadTotalRateLimit); total > 0 {
+		share := total / int64(max(c.Concurrency["audio_download"], 1))
+		if limit == 0 || share < limit {
+			limit = max(share, 1)
+		}
+	}
+	return limit
+}
+
+// ParseByteRate reads a rate like "500K" or "2.5M" as bytes per second, using 1024-based
+// suffixes as yt-dlp does. An empty rate is 0.
+func ParseByteRate(rate string) (int64, error) {
+	rate = strings.TrimSpace(rate)
+	if rate == "" {
+		return 0, nil
+	}
+	number, multiplier := rate, 1.0
+	switch strings.ToUpper(rate[len(rate)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		number = rate[:len(rate)-1]
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid rate %q", rate)
+	}
+	return int64(value * multiplier), nil
+}
+
+// GetLLMCacheTTL returns how long summarization responses are cached; 0 means caching is disabled
+func (c *AppConfig) GetLLMCacheTTL() time.Duration {
+	d, err := time.ParseDuration(c.LLMCacheTTL)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// GetSummaryStreamFlushInterval returns how often a streamed summary is saved, falling back
+// to 2 seconds if invalid
+func (c *AppConfig) GetSummaryStreamFlushInterval() time.Duration {
+	d, err := time.ParseDuration(c.SummaryStreamFlushInterval)
+	if err != nil || d <= 0 {
+		return 2 * time.Second
+	}
+	return d
+}
+
+// EstimateCost returns the estimated USD cost of the given token counts, or 0 if prices are not configured
+func (c *AppConfig) EstimateCost(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens)/1000*c.OpenAIPromptCostPer1K + float64(completionTokens)/1000*c.OpenAICompletionCostPer1K
 }
@@ -0,0 +1,65 @@
+package config
+
+import "strings"
+
+// modelMaxOutputTokens is the most tokens each OpenAI model family can write in one response
+var modelMaxOutputTokens = map[string]int{
+	"gpt-3.5-turbo": 4096,
+	"gpt-4":         8192,
+	"gpt-4-turbo":   4096,
+	"gpt-4o":        16384,
+	"gpt-4o-mini":   16384,
+	"gpt-4.1":       32768,
+	"gpt-4.1-mini":  32768,
+	"gpt-4.1-nano":  32768,
+	"o1":            100000,
+	"o1-mini":       65536,
+	"o3":            100000,
+	"o3-mini":       100000,
+	"o4-mini":       100000,
+}
+
+// ModelMaxOutputTokens returns the output token limit of a model, matched by its longest
+// known family prefix (so "gpt-4o-2024-08-06" is "gpt-4o"), or 0 if the model is unknown
+func ModelMaxOutputTokens(model string) int {
+	model = strings.ToLower(model)
+	best, limit := "", 0
+	for family, familyLimit := range modelMaxOutputTokens {
+		if (model == family || strings.HasPrefix(model, family+"-")) && len(family) > len(best) {
+			best, limit = family, familyLimit
+		}
+	}
+	return limit
+}
+
+// SummaryModel returns the configured OpenAI summarization model
+func (c *AppConfig) SummaryModel() string {
+	if c.OpenAIModel == "" {
+		return "gpt-4o"
+	}
+	return c.OpenAIModel
+}
+
+// MaxTokensLimit returns the output token limit of the summarization model, or 0 if it is
+// unknown or summaries don't come from OpenAI
+func (c *AppConfig) MaxTokensLimit() int {
+	if c.SummarizerProvider != "openai" || c.DryRun {
+		return 0
+	}
+	return ModelMaxOutputTokens(c.SummaryModel())
+}
+
+// MaxTokensFor returns the response token limit of a summary: the prompt's max_tokens if
+// set, else its category's under max_tokens_by_category, else openai_max_tokens
+func (c *AppConfig) MaxTokensFor(category string, promptMaxTokens int) int {
+	if promptMaxTokens > 0 {
+		return promptMaxTokens
+	}
+	if maxTokens := c.MaxTokensByCategory[category]; maxTokens > 0 {
+		return maxTokens
+	}
+	if c.OpenAIMaxTokens > 0 {
+		return c.OpenAIMaxTokens
+	}
+	return 10000
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// environment is the active config profile (e.g. "dev", "staging", "prod").
+// It is set with SetEnvironment or the VS_ENV environment variable.
+var environment string
+
+// SetEnvironment sets the config profile used to select overlay files
+func SetEnvironment(env string) {
+	environment = env
+}
+
+// Environment returns the active config profile, or "" when no overlay should be applied
+func Environment() string {
+	if environment != "" {
+		return environment
+	}
+	return os.Getenv("VS_ENV")
+}
+
+// OverlayPath returns the environment overlay path for a base config file,
+// e.g. config.yaml + "prod" -> config.prod.yaml
+func OverlayPath(path, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
+
+// readLayeredYAML reads a base YAML file and, when an environment is active and an overlay
+// file exists next to it, deep-merges the overlay on top. Maps are merged key by key;
+// scalars and lists in the overlay replace the base value.
+func readLayeredYAML(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	env := Environment()
+	if env == "" {
+		return data, nil
+	}
+
+	overlayPath := OverlayPath(path, env)
+	overlayData, err := os.ReadFile(overlayPath)
+	if os.IsNotExist(err) {
+		log.Debugf("No %s overlay for %s (looked for %s)", env, path, overlayPath)
+		return data, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay file %s: %w", overlayPath, err)
+	}
+
+	var base, overlay map[string]interface{}
+	if err := yaml.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(overlayData, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay file %s: %w", overlayPath, err)
+	}
+
+	merged, err := yaml.Marshal(deepMerge(base, overlay))
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge overlay file %s: %w", overlayPath, err)
+	}
+	log.Infof("Applied %s config overlay %s to %s", env, overlayPath, path)
+	return merged, nil
+}
+
+// deepMerge merges overlay into base, recursing into nested maps
+func deepMerge(base, overlay map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{})
+	}
+	for key, overlayVal := range overlay {
+		overlayMap, overlayIsMap := overlayVal.(map[string]interface{})
+		baseMap, baseIsMap := base[key].(map[string]interface{})
+		if overlayIsMap && baseIsMap {
+			base[key] = deepMerge(baseMap, overlayMap)
+			continue
+		}
+		base[key] = overlayVal
+	}
+	return base
+}
@@ -6,14 +6,17 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
 
 // PromptManager manages loading and accessing prompts from files
 type PromptManager struct {
-	prompts map[string]*Prompt
-	loaded  bool
+	prompts    map[string]*Prompt
+	loaded     bool
+	promptsDir string
+	mu         sync.RWMutex
 }
 
 // NewPromptManager creates a new prompt manager
@@ -26,66 +29,103 @@ func NewPromptManager() *PromptManager {
 
 // LoadPrompts loads all prompt files from the specified directory
 func (pm *PromptManager) LoadPrompts(promptsDir string) error {
-	if pm.loaded {
+	pm.mu.RLock()
+	loaded := pm.loaded
+	pm.mu.RUnlock()
+	if loaded {
 		return nil
 	}
+	return pm.Reload(promptsDir)
+}
 
+// Reload re-reads all prompt files from the specified directory and atomically
+// replaces the loaded prompts. On error the previously loaded prompts are kept.
+func (pm *PromptManager) Reload(promptsDir string) error {
+	prompts, err := pm.readPrompts(promptsDir)
+	if err != nil {
+		return err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.prompts = prompts
+	pm.promptsDir = promptsDir
+	pm.loaded = true
+	return nil
+}
+
+// GetPromptsDir returns the directory the prompts were last loaded from
+func (pm *PromptManager) GetPromptsDir() string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.promptsDir
+}
+
+// readPrompts loads all prompt files from promptsDir into a new map
+func (pm *PromptManager) readPrompts(promptsDir string) (map[string]*Prompt, error) {
 	// Create prompts directory if it doesn't exist
 	if err := os.MkdirAll(promptsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create prompts directory: %w", err)
+		return nil, fmt.Errorf("failed to create prompts directory: %w", err)
 	}
 
 	// Load all .yaml files in the prompts directory
 	files, err := filepath.Glob(filepath.Join(promptsDir, "*.yaml"))
 	if err != nil {
-		return fmt.Errorf("failed to glob prompt files: %w", err)
+		return nil, fmt.Errorf("failed to glob prompt files: %w", err)
 	}
 
 	// If no files exist, create default prompts
 	if len(files) == 0 {
 		if err := pm.createDefaultPrompts(promptsDir); err != nil {
-			return fmt.Errorf("failed to create default prompts: %w", err)
+			return nil, fmt.Errorf("failed to create default prompts: %w", err)
 		}
 		// Reload after creating defaults
 		files, err = filepath.Glob(filepath.Join(promptsDir, "*.yaml"))
 		if err != nil {
-			return fmt.Errorf("failed to glob prompt files after creating defaults: %w", err)
+			return nil, fmt.Errorf("failed to glob prompt files after creating defaults: %w", err)
 		}
 	}
 
 	// Load each prompt file
+	prompts := make(map[string]*Prompt)
 	for _, file := range files {
-		if err := pm.loadPromptFile(file); err != nil {
-			return fmt.Errorf("failed to load prompt file %s: %w", file, err)
+		prompt, err := pm.loadPromptFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load prompt file %s: %w", file, err)
 		}
+		prompts[prompt.ID] = prompt
 	}
 
-	pm.loaded = true
-	return nil
+	return prompts, nil
 }
 
 // loadPromptFile loads a single prompt file
-func (pm *PromptManager) loadPromptFile(filepath string) error {
+func (pm *PromptManager) loadPromptFile(filepath string) (*Prompt, error) {
 	data, err := ioutil.ReadFile(filepath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var prompt Prompt
 	if err := yaml.Unmarshal(data, &prompt); err != nil {
-		return fmt.Errorf("failed to unmarshal prompt file: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal prompt file: %w", err)
 	}
 
 	// Validate prompt
 	if prompt.ID == "" {
-		return fmt.Errorf("prompt in %s has no ID", filepath)
+		return nil, fmt.Errorf("prompt in %s has no ID", filepath)
 	}
 	if prompt.Content == "" {
-		return fmt.Errorf("prompt %s has no content", prompt.ID)
+		return nil, fmt.Errorf("prompt %s has no content", prompt.ID)
+	}
+	if prompt.VariantOf != "" && prompt.Language == "" {
+		return nil, fmt.Errorf("prompt %s is a variant of %s but has no language", prompt.ID, prompt.VariantOf)
+	}
+	if prompt.MaxTokens < 0 {
+		return nil, fmt.Errorf("prompt %s has a negative max_tokens", prompt.ID)
 	}
 
-	pm.prompts[prompt.ID] = &prompt
-	return nil
+	return &prompt, nil
 }
 
 // createDefaultPrompts creates default prompt files
@@ -157,6 +197,8 @@ func (pm *PromptManager) savePromptToFile(promptsDir string, prompt Prompt) erro
 
 // GetPrompt retrieves a prompt by ID
 func (pm *PromptManager) GetPrompt(id string) (*Prompt, error) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	if !pm.loaded {
 		return nil, fmt.Errorf("prompts not loaded")
 	}
@@ -180,6 +222,8 @@ func (pm *PromptManager) GetPromptContent(id string) (string, error) {
 
 // GetAllPrompts returns all loaded prompts
 func (pm *PromptManager) GetAllPrompts() []*Prompt {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	if !pm.loaded {
 		return nil
 	}
@@ -193,6 +237,8 @@ func (pm *PromptManager) GetAllPrompts() []*Prompt {
 
 // GetPromptsByCategory returns prompts filtered by category
 func (pm *PromptManager) GetPromptsByCategory(category string) []*Prompt {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 	if !pm.loaded {
 		return nil
 	}
@@ -206,9 +252,28 @@ func (pm *PromptManager) GetPromptsByCategory(category string) []*Prompt {
 	return prompts
 }
 
+// GetVariant returns the variant of a prompt written for a language, if there is one. A
+// prompt is its own variant when it is already in that language.
+func (pm *PromptManager) GetVariant(id, language string) (*Prompt, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if prompt, exists := pm.prompts[id]; exists && strings.EqualFold(prompt.PromptLanguage(), language) {
+		return prompt, true
+	}
+	for _, prompt := range pm.prompts {
+		if prompt.VariantOf == id && strings.EqualFold(prompt.PromptLanguage(), language) {
+			return prompt, true
+		}
+	}
+	return nil, false
+}
+
 // ResolvePrompt resolves a prompt input (either ID or direct content)
 func (pm *PromptManager) ResolvePrompt(input string) (string, error) {
-	if !pm.loaded {
+	pm.mu.RLock()
+	loaded := pm.loaded
+	pm.mu.RUnlock()
+	if !loaded {
 		return "", fmt.Errorf("prompts not loaded")
 	}
 
@@ -7,4 +7,18 @@ type Prompt struct {
 	Description string `yaml:"description"`
 	Content     string `yaml:"content"`
 	Category    string `yaml:"category"`
+	// Language the prompt is written for and asks for ("" = English)
+	Language string `yaml:"language,omitempty"`
+	// ID of the prompt this is a translation of, for transcripts in Language
+	VariantOf string `yaml:"variant_of,omitempty"`
+	// Response token limit of summaries with this prompt (0 = by category or openai_max_tokens)
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+}
+
+// PromptLanguage returns the language of the prompt, defaulting to English
+func (p *Prompt) PromptLanguage() string {
+	if p.Language == "" {
+		return "en"
+	}
+	return p.Language
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -16,6 +17,47 @@ type ServiceConfig struct {
 		Host string `yaml:"host"`
 	} `yaml:"server"`
 
+	// Lifecycle controls readiness and draining for orchestrated deployments
+	Lifecycle struct {
+		MaxQueuedTasks int    `yaml:"max_queued_tasks"` // readiness fails above this many queued tasks (0 = no limit)
+		DrainTimeout   string `yaml:"drain_timeout"`    // how long a drain waits for active requests
+	} `yaml:"lifecycle"`
+
+	// Users limits the requests each submitting user can make
+	Users struct {
+		MaxActiveRequests int `yaml:"max_active_requests"`  // pending or running requests per user (0 = no limit)
+		MaxRequestsPerDay int `yaml:"max_requests_per_day"` // requests per user in the last 24 hours (0 = no limit)
+	} `yaml:"users"`
+
+	// Notifications tell users when their requests complete or fail
+	Notifications struct {
+		PreferencesFile string `yaml:"preferences_file"` // where user preferences are stored ("" = memory only)
+		SlackBotToken   string `yaml:"slack_bot_token"`  // bot token for Slack DMs
+		SMTP            struct {
+			Host     string `yaml:"host"`
+			Port     int    `yaml:"port"`
+			Username string `yaml:"username"`
+			Password string `yaml:"password"`
+			From     string `yaml:"from"`
+		} `yaml:"smtp"`
+	} `yaml:"notifications"`
+
+	// Feeds publish completed summaries per category as RSS/Atom
+	Feeds struct {
+		Tokens   []string `yaml:"tokens"`    // accepted feed tokens (empty = feeds disabled)
+		MaxItems int      `yaml:"max_items"` // most recent summaries per feed
+		BaseURL  string   `yaml:"base_url"`  // public URL of the service for feed links ("" = from the request)
+	} `yaml:"feeds"`
+
+	// Digests roll the summaries of a day or week up into one document
+	Digests []DigestConfig `yaml:"digests"`
+
+	// Schedules re-submit a URL or re-run a search on a recurrence; they are managed through
+	// /api/schedules
+	Schedules struct {
+		File string `yaml:"file"` // where schedules are stored ("" = memory only)
+	} `yaml:"schedules"`
+
 	EngineConfigPath  string `yaml:"engine_config_path"`
 	PromptsDir        string `yaml:"prompts_dir"`
 	SourcesConfigPath string `yaml:"sources_config_path"`
@@ -26,23 +68,87 @@ type ServiceConfig struct {
 
 // BackgroundSourcesConfig represents background sources configuration
 type BackgroundSourcesConfig struct {
-	Sources []SourceConfig `yaml:"sources"`
+	// Channels (IDs, @handles or names) search sources and scheduled searches may only, or
+	// may never, submit videos from; sources can add their own lists
+	AllowChannels []string `yaml:"allow_channels"`
+	DenyChannels  []string `yaml:"deny_channels"`
+	// SkipShorts skips YouTube Shorts and sub-minute clips in search and discord sources and
+	// scheduled searches; sources can override it
+	SkipShorts bool           `yaml:"skip_shorts"`
+	Sources    []SourceConfig `yaml:"sources"`
 }
 
 // SourceConfig represents a background source configuration
 type SourceConfig struct {
-	Name     string                 `yaml:"name"`
-	Type     string                 `yaml:"type"`
-	Enabled  bool                   `yaml:"enabled"`
-	Interval string                 `yaml:"interval"`
-	PromptID string                 `yaml:"prompt_id"`
-	Category string                 `yaml:"category"`
-	Config   map[string]interface{} `yaml:"config"`
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Enabled  bool   `yaml:"enabled"`
+	Interval string `yaml:"interval"`
+	PromptID string `yaml:"prompt_id"`
+	Category string `yaml:"category"`
+	// Overrides prompt_language_mode for the source's requests ("" = use the global mode)
+	LanguageMode string `yaml:"language_mode"`
+	// Daily spans of local time the source submits in, like "06:00-09:00" (empty = any time)
+	ActiveWindows []string `yaml:"active_windows"`
+	// Shared secret that push sources verify payload signatures with; SecretEnv names an
+	// environment variable holding it instead, to keep it out of the file
+	Secret    string                 `yaml:"secret"`
+	SecretEnv string                 `yaml:"secret_env"`
+	Config    map[string]interface{} `yaml:"config"`
+}
+
+// DigestConfig schedules a roll-up of the summaries completed in the last day or week
+type DigestConfig struct {
+	Name     string   `yaml:"name"`
+	Schedule string   `yaml:"schedule"`  // daily or weekly
+	Time     string   `yaml:"time"`      // local time of day to run, "HH:MM" (default "07:00")
+	Weekday  string   `yaml:"weekday"`   // day of week for weekly digests (default monday)
+	Category string   `yaml:"category"`  // only summaries in this category ("" = all)
+	Source   string   `yaml:"source"`    // only summaries from this background source ("" = all)
+	PromptID string   `yaml:"prompt_id"` // roll-up prompt ("" = built-in digest prompt)
+	Email    []string `yaml:"email"`     // recipients of the finished digest
+}
+
+// digestWeekdays maps weekday names to time.Weekday
+var digestWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// GetWindow returns the period a digest covers
+func (c *DigestConfig) GetWindow() time.Duration {
+	if c.Schedule == "weekly" {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// GetTimeOfDay returns the hour and minute the digest runs at
+func (c *DigestConfig) GetTimeOfDay() (int, int, error) {
+	t, err := time.Parse("15:04", c.Time)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// GetWeekday returns the day a weekly digest runs on
+func (c *DigestConfig) GetWeekday() (time.Weekday, error) {
+	return ParseWeekday(c.Weekday)
+}
+
+// ParseWeekday parses a weekday name such as "monday"
+func ParseWeekday(name string) (time.Weekday, error) {
+	day, ok := digestWeekdays[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q", name)
+	}
+	return day, nil
 }
 
 func LoadServiceConfig(path string) (*ServiceConfig, error) {
-	// Read YAML file
-	data, err := os.ReadFile(path)
+	// Read YAML file, applying the environment overlay if there is one
+	data, err := readLayeredYAML(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read service config file %s: %w", path, err)
 	}
@@ -93,6 +199,23 @@ func (c *ServiceConfig) applyEnvOverrides() {
 	c.EngineConfigPath = getEnv("VS_ENGINE_CONFIG_PATH", c.EngineConfigPath)
 	c.PromptsDir = getEnv("VS_PROMPTS_DIR", c.PromptsDir)
 	c.SourcesConfigPath = getEnv("VS_SOURCES_CONFIG_PATH", c.SourcesConfigPath)
+	c.Lifecycle.MaxQueuedTasks = getEnvInt("VS_LIFECYCLE_MAX_QUEUED_TASKS", c.Lifecycle.MaxQueuedTasks)
+	c.Lifecycle.DrainTimeout = getEnv("VS_LIFECYCLE_DRAIN_TIMEOUT", c.Lifecycle.DrainTimeout)
+	c.Users.MaxActiveRequests = getEnvInt("VS_USERS_MAX_ACTIVE_REQUESTS", c.Users.MaxActiveRequests)
+	c.Users.MaxRequestsPerDay = getEnvInt("VS_USERS_MAX_REQUESTS_PER_DAY", c.Users.MaxRequestsPerDay)
+	c.Notifications.PreferencesFile = getEnv("VS_NOTIFICATIONS_PREFERENCES_FILE", c.Notifications.PreferencesFile)
+	c.Schedules.File = getEnv("VS_SCHEDULES_FILE", c.Schedules.File)
+	c.Notifications.SlackBotToken = getEnv("VS_SLACK_BOT_TOKEN", c.Notifications.SlackBotToken)
+	c.Notifications.SMTP.Host = getEnv("VS_SMTP_HOST", c.Notifications.SMTP.Host)
+	c.Notifications.SMTP.Port = getEnvInt("VS_SMTP_PORT", c.Notifications.SMTP.Port)
+	c.Notifications.SMTP.Username = getEnv("VS_SMTP_USERNAME", c.Notifications.SMTP.Username)
+	c.Notifications.SMTP.Password = getEnv("VS_SMTP_PASSWORD", c.Notifications.SMTP.Password)
+	c.Notifications.SMTP.From = getEnv("VS_SMTP_FROM", c.Notifications.SMTP.From)
+	if tokens := getEnv("VS_FEED_TOKENS", ""); tokens != "" {
+		c.Feeds.Tokens = strings.Split(tokens, ",")
+	}
+	c.Feeds.MaxItems = getEnvInt("VS_FEED_MAX_ITEMS", c.Feeds.MaxItems)
+	c.Feeds.BaseURL = getEnv("VS_FEED_BASE_URL", c.Feeds.BaseURL)
 
 	// Note: Background sources are configured via YAML config files
 	// For runtime configuration, mount different service.yaml files or use ConfigMaps in Kubernetes
@@ -115,6 +238,35 @@ func (c *ServiceConfig) setDefaults() {
 	if c.SourcesConfigPath == "" {
 		c.SourcesConfigPath = "sources.yaml"
 	}
+	if c.Notifications.SMTP.Port == 0 {
+		c.Notifications.SMTP.Port = 587
+	}
+	if c.Lifecycle.DrainTimeout == "" {
+		c.Lifecycle.DrainTimeout = "5m"
+	}
+	if c.Feeds.MaxItems == 0 {
+		c.Feeds.MaxItems = 50
+	}
+	for i := range c.Digests {
+		if c.Digests[i].Schedule == "" {
+			c.Digests[i].Schedule = "daily"
+		}
+		if c.Digests[i].Time == "" {
+			c.Digests[i].Time = "07:00"
+		}
+		if c.Digests[i].Weekday == "" {
+			c.Digests[i].Weekday = "monday"
+		}
+	}
+}
+
+// GetDrainTimeout returns the parsed drain timeout, falling back to 5 minutes
+func (c *ServiceConfig) GetDrainTimeout() time.Duration {
+	d, err := time.ParseDuration(c.Lifecycle.DrainTimeout)
+	if err != nil || d <= 0 {
+		return 5 * time.Minute
+	}
+	return d
 }
 
 // loadBackgroundSources loads background sources from a separate YAML file
@@ -123,7 +275,7 @@ func (c *ServiceConfig) loadBackgroundSources() error {
 		return nil // No sources config path specified, no sources to load
 	}
 
-	data, err := os.ReadFile(c.SourcesConfigPath)
+	data, err := readLayeredYAML(c.SourcesConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to read sources config file %s: %w", c.SourcesConfigPath, err)
 	}
@@ -142,16 +294,203 @@ func (c *SourceConfig) GetIntervalDuration() (time.Duration, error) {
 	return time.ParseDuration(c.Interval)
 }
 
+// GetSecret returns the source's shared secret, from the environment if secret_env is set
+func (c *SourceConfig) GetSecret() string {
+	if c.SecretEnv != "" {
+		return os.Getenv(c.SecretEnv)
+	}
+	return c.Secret
+}
+
+// TimeWindow is a daily span of local time, from Start up to End minutes after midnight. A
+// window that ends before it starts runs past midnight.
+type TimeWindow struct {
+	Start int
+	End   int
+}
+
+// ParseTimeWindow parses a window like "06:00-09:00" or "22:00-02:00"
+func ParseTimeWindow(value string) (TimeWindow, error) {
+	startText, endText, ok := strings.Cut(value, "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("invalid window %q (use \"HH:MM-HH:MM\")", value)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startText))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid window %q (use \"HH:MM-HH:MM\")", value)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endText))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid window %q (use \"HH:MM-HH:MM\")", value)
+	}
+	window := TimeWindow{Start: start.Hour()*60 + start.Minute(), End: end.Hour()*60 + end.Minute()}
+	if window.Start == window.End {
+		return TimeWindow{}, fmt.Errorf("window %q is empty", value)
+	}
+	return window, nil
+}
+
+// Contains reports whether t's local time of day is within the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// GetActiveWindows returns the parsed active_windows of the source
+func (c *SourceConfig) GetActiveWindows() ([]TimeWindow, error) {
+	windows := make([]TimeWindow, 0, len(c.ActiveWindows))
+	for _, value := range c.ActiveWindows {
+		window, err := ParseTimeWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// GetSignatureTolerance returns how far a push payload's signed timestamp may be from now,
+// from the signature_tolerance config value (default 5m)
+func (c *SourceConfig) GetSignatureTolerance() (time.Duration, error) {
+	value, ok := c.Config["signature_tolerance"].(string)
+	if !ok || value == "" {
+		return 5 * time.Minute, nil
+	}
+	return time.ParseDuration(value)
+}
+
 // GetMaxVideosPerRun returns the max_videos_per_run value from config
 func (c *SourceConfig) GetMaxVideosPerRun() int {
 	return c.getConfigInt("max_videos_per_run", 1)
 }
 
+// GetMaxSubmissionsPerDay returns the max_submissions_per_day value from config (0 = no limit)
+func (c *SourceConfig) GetMaxSubmissionsPerDay() int {
+	return c.getConfigInt("max_submissions_per_day", 0)
+}
+
+// GetMaxActiveRequests returns how many of the source's requests may be pending or running at
+// once (0 = no limit)
+func (c *SourceConfig) GetMaxActiveRequests() int {
+	return c.getConfigInt("max_active_requests", 0)
+}
+
+// SourceLimits returns the max_active_requests of the enabled sources that set one
+func (c *BackgroundSourcesConfig) SourceLimits() map[string]int {
+	limits := make(map[string]int)
+	for i := range c.Sources {
+		if limit := c.Sources[i].GetMaxActiveRequests(); c.Sources[i].Enabled && limit > 0 {
+			limits[c.Sources[i].Name] = limit
+		}
+	}
+	return limits
+}
+
 // GetChannelVideosLookback returns the channel_videos_lookback value from config
 func (c *SourceConfig) GetChannelVideosLookback() int {
 	return c.getConfigInt("channel_videos_lookback", 50)
 }
 
+// GetChannels returns the Discord channel IDs a discord source watches
+func (c *SourceConfig) GetChannels() ([]string, error) {
+	return c.getConfigStrings("channels")
+}
+
+// GetLinkHosts returns the hosts whose links a discord source submits (nil = the default hosts)
+func (c *SourceConfig) GetLinkHosts() ([]string, error) {
+	return c.getConfigStrings("link_hosts")
+}
+
+// GetAllowChannels returns the channels a search source may only submit videos from, replacing
+// the sources file's allow_channels (nil = that list applies)
+func (c *SourceConfig) GetAllowChannels() ([]string, error) {
+	return c.getConfigStrings("allow_channels")
+}
+
+// GetDenyChannels returns the channels a search source never submits videos from, on top of
+// the sources file's deny_channels
+func (c *SourceConfig) GetDenyChannels() ([]string, error) {
+	return c.getConfigStrings("deny_channels")
+}
+
+// GetSkipShorts returns whether a source skips YouTube Shorts and sub-minute clips, from its
+// skip_shorts config value or else the sources file's skip_shorts
+func (c *SourceConfig) GetSkipShorts(defaultValue bool) (bool, error) {
+	val, ok := c.Config["skip_shorts"]
+	if !ok || val == nil {
+		return defaultValue, nil
+	}
+	skip, ok := val.(bool)
+	if !ok {
+		return defaultValue, fmt.Errorf("skip_shorts must be true or false for source: %s", c.Name)
+	}
+	return skip, nil
+}
+
+// GetMinViews returns the view count a search source's videos need to be submitted (0 = any)
+func (c *SourceConfig) GetMinViews() int {
+	return c.getConfigInt("min_views", 0)
+}
+
+// GetMaxAge returns how long ago a search source's videos may have been uploaded (0 = any age)
+func (c *SourceConfig) GetMaxAge() (time.Duration, error) {
+	return c.getConfigDuration("max_age")
+}
+
+// GetMinDuration returns the shortest video a search source submits (0 = no minimum)
+func (c *SourceConfig) GetMinDuration() (time.Duration, error) {
+	return c.getConfigDuration("min_duration")
+}
+
+// GetMaxDuration returns the longest video a search source submits (0 = no maximum)
+func (c *SourceConfig) GetMaxDuration() (time.Duration, error) {
+	return c.getConfigDuration("max_duration")
+}
+
+// getConfigDuration extracts an optional duration like "72h" from the config map (0 = unset)
+func (c *SourceConfig) getConfigDuration(key string) (time.Duration, error) {
+	val, ok := c.Config[key]
+	if !ok || val == nil {
+		return 0, nil
+	}
+	str, ok := val.(string)
+	if !ok {
+		return 0, fmt.Errorf("%s must be a duration like \"72h\" for source: %s", key, c.Name)
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q for source %s (use values like \"72h\" or \"90m\")", key, str, c.Name)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative for source: %s", key, c.Name)
+	}
+	return d, nil
+}
+
+// getConfigStrings extracts an optional list of strings from the config map
+func (c *SourceConfig) getConfigStrings(key string) ([]string, error) {
+	val, ok := c.Config[key]
+	if !ok || val == nil {
+		return nil, nil
+	}
+	list, ok := val.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list for source: %s", key, c.Name)
+	}
+	result := make([]string, len(list))
+	for i, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must hold strings for source: %s", key, c.Name)
+		}
+		result[i] = str
+	}
+	return result, nil
+}
+
 // getConfigInt is a reusable helper method to extract integer values from config map
 func (c *SourceConfig) getConfigInt(key string, defaultValue int) int {
 	if val, ok := c.Config[key]; ok {
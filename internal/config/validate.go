@@ -0,0 +1,916 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError describes a single configuration problem and how to fix it
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func newValidationError(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// supportedOutputProviders are the output providers output_provider and output_routes can name
+var supportedOutputProviders = map[string]bool{
+	"gdrive":     true,
+	"slack":      true,
+	"discord":    true,
+	"local":      true,
+	"gsheets":    true,
+	"readwise":   true,
+	"raindrop":   true,
+	"confluence": true,
+}
+
+// supportedSourceTypes lists the background source types the source factory can create
+var supportedSourceTypes = map[string]bool{
+	"youtube_search": true,
+	"push":           true,
+	"discord":        true,
+}
+
+// Validate checks the application config for missing binaries, models, credentials and invalid values
+func (c *AppConfig) Validate() []error {
+	errs := c.CheckRuntimeDependencies()
+
+	switch c.SummarizerProvider {
+	case "openai":
+		if c.OpenAIKey == "" {
+			errs = append(errs, newValidationError("openai_api_key", "required when summarizer_provider is openai (set VS_OPENAI_API_KEY)"))
+		}
+	case "stub":
+		if c.StubSummarySentences < 1 {
+			errs = append(errs, newValidationError("stub_summary_sentences", "must be at least 1, got %d", c.StubSummarySentences))
+		}
+	case "text":
+		if c.TextSummarySentences < 1 {
+			errs = append(errs, newValidationError("text_summary_sentences", "must be at least 1, got %d", c.TextSummarySentences))
+		}
+	default:
+		errs = append(errs, newValidationError("summarizer_provider", "unsupported provider %q (supported: openai, text, stub)", c.SummarizerProvider))
+	}
+	if c.OpenAIMaxTokens < 0 {
+		errs = append(errs, newValidationError("openai_max_tokens", "must be positive, got %d", c.OpenAIMaxTokens))
+	}
+	maxTokensLimit := c.MaxTokensLimit()
+	if maxTokensLimit > 0 && c.OpenAIMaxTokens > maxTokensLimit {
+		errs = append(errs, newValidationError("openai_max_tokens", "%d is above the %d output tokens %s can write", c.OpenAIMaxTokens, maxTokensLimit, c.SummaryModel()))
+	}
+	tokenCategories := make([]string, 0, len(c.MaxTokensByCategory))
+	for category := range c.MaxTokensByCategory {
+		tokenCategories = append(tokenCategories, category)
+	}
+	sort.Strings(tokenCategories)
+	for _, category := range tokenCategories {
+		maxTokens := c.MaxTokensByCategory[category]
+		field := "max_tokens_by_category." + category
+		if maxTokens <= 0 {
+			errs = append(errs, newValidationError(field, "must be positive, got %d", maxTokens))
+		} else if maxTokensLimit > 0 && maxTokens > maxTokensLimit {
+			errs = append(errs, newValidationError(field, "%d is above the %d output tokens %s can write", maxTokens, maxTokensLimit, c.SummaryModel()))
+		}
+	}
+	if c.SummarizationChunkSize < 1000 {
+		errs = append(errs, newValidationError("summarization_chunk_size", "must be at least 1000 bytes, got %d", c.SummarizationChunkSize))
+	}
+	if c.SummarizationChunkConcurrency < 1 || c.SummarizationChunkConcurrency > 16 {
+		errs = append(errs, newValidationError("summarization_chunk_concurrency", "must be between 1 and 16, got %d", c.SummarizationChunkConcurrency))
+	}
+	if c.VideoContext.MaxDescriptionChars < 0 {
+		errs = append(errs, newValidationError("video_context.max_description_chars", "must not be negative, got %d", c.VideoContext.MaxDescriptionChars))
+	}
+
+	switch c.OutputProvider {
+	case "gdrive":
+		errs = append(errs, c.validateGoogleAuth()...)
+		if c.GDriveFolderID == "" || c.GDriveFolderID == "your-folder-id" {
+			errs = append(errs, newValidationError("gdrive_folder_id", "must be set to the ID of the Drive folder to upload into (set VS_GDRIVE_FOLDER_ID)"))
+		}
+	case "slack":
+		if c.SlackBotToken == "" {
+			errs = append(errs, newValidationError("slack_bot_token", "required when output_provider is slack (set VS_SLACK_BOT_TOKEN)"))
+		}
+		if c.SlackChannel == "" {
+			errs = append(errs, newValidationError("slack_channel", "required when output_provider is slack (set VS_SLACK_CHANNEL)"))
+		}
+	case "discord":
+		if c.DiscordBotToken == "" {
+			errs = append(errs, newValidationError("discord_bot_token", "required when output_provider is discord (set VS_DISCORD_BOT_TOKEN)"))
+		}
+		if c.DiscordChannel == "" {
+			errs = append(errs, newValidationError("discord_channel", "required when output_provider is discord (set VS_DISCORD_CHANNEL)"))
+		}
+	case "local":
+		if c.LocalOutputDir == "" {
+			errs = append(errs, newValidationError("local_output_dir", "required when output_provider is local (set VS_LOCAL_OUTPUT_DIR)"))
+		}
+	case "gsheets":
+		if c.GSheets.SpreadsheetID == "" {
+			errs = append(errs, newValidationError("gsheets.spreadsheet_id", "required when output_provider is gsheets (set VS_GSHEETS_SPREADSHEET_ID)"))
+		}
+	case "readwise":
+		if c.Readwise.Token == "" {
+			errs = append(errs, newValidationError("readwise.token", "required when output_provider is readwise (set VS_READWISE_TOKEN)"))
+		}
+	case "raindrop":
+		if c.Raindrop.Token == "" {
+			errs = append(errs, newValidationError("raindrop.token", "required when output_provider is raindrop (set VS_RAINDROP_TOKEN)"))
+		}
+	case "confluence":
+		if c.Confluence.Token == "" {
+			errs = append(errs, newValidationError("confluence.token", "required when output_provider is confluence (set VS_CONFLUENCE_TOKEN)"))
+		}
+	default:
+		errs = append(errs, newValidationError("output_provider", "unsupported provider %q (supported: %s)", c.OutputProvider, strings.Join(slices.Sorted(maps.Keys(supportedOutputProviders)), ", ")))
+	}
+	if c.Confluence.Token != "" {
+		errs = append(errs, c.Confluence.validate()...)
+	}
+	categories := make([]string, 0, len(c.OutputRoutes))
+	for category := range c.OutputRoutes {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		for i, route := range c.OutputRoutes[category] {
+			field := fmt.Sprintf("output_routes.%s[%d]", category, i)
+			if !supportedOutputProviders[route.Provider] {
+				errs = append(errs, newValidationError(field+".provider", "unsupported provider %q", route.Provider))
+			}
+			if route.Channel != "" && route.FolderID != "" {
+				errs = append(errs, newValidationError(field, "set channel or folder_id, not both"))
+			}
+		}
+	}
+	if c.Readwise.Mode != "highlights" && c.Readwise.Mode != "reader" {
+		errs = append(errs, newValidationError("readwise.mode", "must be highlights or reader, got %q", c.Readwise.Mode))
+	}
+	if c.GSheets.SpreadsheetID != "" {
+		if c.OutputProvider != "gdrive" {
+			errs = append(errs, c.validateGoogleAuth()...)
+		}
+		if c.GSheets.ExcerptChars < 1 || c.GSheets.ExcerptChars > 50000 {
+			errs = append(errs, newValidationError("gsheets.excerpt_chars", "must be between 1 and 50000, got %d", c.GSheets.ExcerptChars))
+		}
+	}
+
+	errs = append(errs, c.OutputNaming.validate()...)
+
+	if c.Slides.Enabled {
+		if d, err := time.ParseDuration(c.Slides.Interval); err != nil || d < time.Second {
+			errs = append(errs, newValidationError("slides.interval", "invalid duration %q (use values like \"30s\", at least 1s)", c.Slides.Interval))
+		}
+		if c.Slides.MaxFrames < 1 {
+			errs = append(errs, newValidationError("slides.max_frames", "must be at least 1, got %d", c.Slides.MaxFrames))
+		}
+		if c.Slides.MaxHeight < 144 {
+			errs = append(errs, newValidationError("slides.max_height", "must be at least 144, got %d", c.Slides.MaxHeight))
+		}
+		if c.Slides.MaxChars < 1 {
+			errs = append(errs, newValidationError("slides.max_chars", "must be at least 1, got %d", c.Slides.MaxChars))
+		}
+	}
+
+	switch c.Store.Backend {
+	case StoreMemory:
+	case StoreBolt:
+		if c.Store.Path == "" {
+			errs = append(errs, newValidationError("store.path", "required with backend bolt (set VS_STORE_PATH)"))
+		}
+	default:
+		errs = append(errs, newValidationError("store.backend", "unsupported backend %q (supported: memory, bolt)", c.Store.Backend))
+	}
+
+	switch c.TaskQueue.Backend {
+	case TaskQueueMemory:
+	case TaskQueueKafka:
+		if len(c.TaskQueue.Kafka.Brokers) == 0 {
+			errs = append(errs, newValidationError("task_queue.kafka.brokers", "required when task_queue.backend is kafka (set VS_KAFKA_BROKERS)"))
+		}
+		// Queued tasks outlive a restart, so the requests they belong to must too
+		if c.Store.Backend != StoreBolt {
+			errs = append(errs, newValidationError("task_queue.backend", "kafka needs store.backend bolt, so the requests of queued tasks survive a restart"))
+		}
+		switch c.TaskQueue.Kafka.SASLMechanism {
+		case "":
+		case "plain", "scram-sha-256", "scram-sha-512":
+			if c.TaskQueue.Kafka.Username == "" {
+				errs = append(errs, newValidationError("task_queue.kafka.username", "required with sasl_mechanism %s (set VS_KAFKA_USERNAME)", c.TaskQueue.Kafka.SASLMechanism))
+			}
+		default:
+			errs = append(errs, newValidationError("task_queue.kafka.sasl_mechanism", "unsupported mechanism %q (supported: plain, scram-sha-256, scram-sha-512)", c.TaskQueue.Kafka.SASLMechanism))
+		}
+	default:
+		errs = append(errs, newValidationError("task_queue.backend", "unsupported backend %q (supported: memory, kafka)", c.TaskQueue.Backend))
+	}
+
+	if c.Watchdog.Enabled {
+		if d, err := time.ParseDuration(c.Watchdog.CheckInterval); err != nil || d < time.Second {
+			errs = append(errs, newValidationError("watchdog.check_interval", "invalid duration %q (use values like \"30s\", at least 1s)", c.Watchdog.CheckInterval))
+		}
+		if c.Watchdog.MaxAttempts < 1 {
+			errs = append(errs, newValidationError("watchdog.max_attempts", "must be at least 1, got %d", c.Watchdog.MaxAttempts))
+		}
+		taskTypes := make([]string, 0, len(c.Watchdog.Timeouts))
+		for taskType := range c.Watchdog.Timeouts {
+			taskTypes = append(taskTypes, taskType)
+		}
+		sort.Strings(taskTypes)
+		for _, taskType := range taskTypes {
+			if d, err := time.ParseDuration(c.Watchdog.Timeouts[taskType]); err != nil || d < 0 {
+				errs = append(errs, newValidationError("watchdog.timeouts."+taskType, "invalid duration %q (use values like \"30m\", or \"0\" for no timeout)", c.Watchdog.Timeouts[taskType]))
+			}
+		}
+	}
+
+	if c.Preflight.Enabled {
+		for _, check := range c.Preflight.Checks {
+			switch check {
+			case PreflightTranscription, PreflightSummarization, PreflightOutput:
+			default:
+				errs = append(errs, newValidationError("preflight.checks", "unknown check %q (supported: %s, %s, %s)", check, PreflightTranscription, PreflightSummarization, PreflightOutput))
+			}
+		}
+		if d, err := time.ParseDuration(c.Preflight.Timeout); err != nil || d <= 0 {
+			errs = append(errs, newValidationError("preflight.timeout", "invalid duration %q (use values like \"2m\")", c.Preflight.Timeout))
+		}
+		if d, err := time.ParseDuration(c.Preflight.RetryInterval); err != nil || d < time.Second {
+			errs = append(errs, newValidationError("preflight.retry_interval", "invalid duration %q (use values like \"1m\", at least 1s)", c.Preflight.RetryInterval))
+		}
+	}
+
+	if c.Highlights.Enabled && (c.Highlights.MaxMoments < 1 || c.Highlights.MaxMoments > 50) {
+		errs = append(errs, newValidationError("highlights.max_moments", "must be between 1 and 50, got %d", c.Highlights.MaxMoments))
+	}
+
+	if c.Evaluation.Enabled {
+		if c.Evaluation.SampleRate <= 0 || c.Evaluation.SampleRate > 1 {
+			errs = append(errs, newValidationError("evaluation.sample_rate", "must be greater than 0 and at most 1, got %g", c.Evaluation.SampleRate))
+		}
+		if c.Evaluation.MinScore < 1 || c.Evaluation.MinScore > 5 {
+			errs = append(errs, newValidationError("evaluation.min_score", "must be between 1 and 5, got %g", c.Evaluation.MinScore))
+		}
+	}
+
+	errs = append(errs, c.Redaction.validate()...)
+
+	if c.Speech.Enabled {
+		switch c.Speech.Provider {
+		case SpeechProviderOpenAI:
+			if c.OpenAIKey == "" {
+				errs = append(errs, newValidationError("speech.provider", "openai needs openai_api_key (set VS_OPENAI_API_KEY)"))
+			}
+		case SpeechProviderLocal:
+			if len(c.Speech.Command) == 0 {
+				errs = append(errs, newValidationError("speech.command", "required when speech.provider is local"))
+			} else if !slices.Contains(c.Speech.Command[1:], "{output}") {
+				errs = append(errs, newValidationError("speech.command", "must pass \"{output}\" as an argument, the file the audio is written to"))
+			}
+			if !speechFormatPattern.MatchString(c.Speech.Format) {
+				errs = append(errs, newValidationError("speech.format", "must be a file extension like \"wav\", got %q", c.Speech.Format))
+			}
+		default:
+			errs = append(errs, newValidationError("speech.provider", "unsupported provider %q (supported: openai, local)", c.Speech.Provider))
+		}
+		if c.Speech.Speed < 0.25 || c.Speech.Speed > 4 {
+			errs = append(errs, newValidationError("speech.speed", "must be between 0.25 and 4, got %g", c.Speech.Speed))
+		}
+		if c.Speech.MaxChars < 1 {
+			errs = append(errs, newValidationError("speech.max_chars", "must be at least 1, got %d", c.Speech.MaxChars))
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, hook := range c.Hooks {
+		field := fmt.Sprintf("hooks[%d]", i)
+		if hook.Name == "" {
+			errs = append(errs, newValidationError(field+".name", "is required"))
+		} else if names[hook.Name] {
+			errs = append(errs, newValidationError(field+".name", "duplicate hook name %q", hook.Name))
+		}
+		names[hook.Name] = true
+		if (len(hook.Command) == 0) == (hook.URL == "") {
+			errs = append(errs, newValidationError(field, "set exactly one of command and url"))
+		}
+		if hook.URL != "" && !strings.HasPrefix(hook.URL, "http://") && !strings.HasPrefix(hook.URL, "https://") {
+			errs = append(errs, newValidationError(field+".url", "must be an http or https URL, got %q", hook.URL))
+		}
+		if hook.Timeout != "" {
+			if d, err := time.ParseDuration(hook.Timeout); err != nil || d <= 0 {
+				errs = append(errs, newValidationError(field+".timeout", "invalid duration %q (use values like \"30s\")", hook.Timeout))
+			}
+		}
+	}
+
+	if c.WhisperModels.Download && !strings.HasPrefix(c.WhisperModels.RegistryURL, "http://") && !strings.HasPrefix(c.WhisperModels.RegistryURL, "https://") {
+		errs = append(errs, newValidationError("whisper_models.registry_url", "must be an http or https URL, got %q", c.WhisperModels.RegistryURL))
+	}
+	modelNames := make(map[string]bool)
+	for i, model := range c.WhisperModels.Models {
+		field := fmt.Sprintf("whisper_models.models[%d]", i)
+		switch {
+		case model.Name == "":
+			errs = append(errs, newValidationError(field+".name", "is required"))
+		case model.Name == WhisperQualityAuto || model.Name == WhisperQualityFast || model.Name == WhisperQualityAccurate:
+			errs = append(errs, newValidationError(field+".name", "%q is reserved for a quality hint", model.Name))
+		case modelNames[model.Name]:
+			errs = append(errs, newValidationError(field+".name", "duplicate model name %q", model.Name))
+		}
+		modelNames[model.Name] = true
+		if model.Path == "" {
+			errs = append(errs, newValidationError(field+".path", "is required"))
+		}
+		if model.MaxDuration != "" {
+			if d, err := time.ParseDuration(model.MaxDuration); err != nil || d <= 0 {
+				errs = append(errs, newValidationError(field+".max_duration", "invalid duration %q (use values like \"20m\")", model.MaxDuration))
+			}
+		}
+	}
+	for name, sum := range c.WhisperModels.Checksums {
+		if !sha256Pattern.MatchString(sum) {
+			errs = append(errs, newValidationError("whisper_models.checksums."+name, "must be a hex SHA-256 (64 characters), got %q", sum))
+		}
+	}
+
+	if routing := c.TranscriptionRouting; routing.Enabled {
+		if d, err := time.ParseDuration(routing.Threshold); err != nil || d <= 0 {
+			errs = append(errs, newValidationError("transcription_routing.threshold", "invalid duration %q (use values like \"20m\")", routing.Threshold))
+		}
+		for _, route := range []struct{ field, value string }{
+			{"transcription_routing.short_videos", routing.ShortVideos},
+			{"transcription_routing.long_videos", routing.LongVideos},
+			{"transcription_routing.unknown_duration", routing.UnknownDuration},
+		} {
+			if route.value != TranscriptionRouteLocal && route.value != TranscriptionRouteCloud {
+				errs = append(errs, newValidationError(route.field, "unsupported route %q (supported: local, cloud)", route.value))
+			}
+		}
+		if c.OpenAIKey == "" {
+			errs = append(errs, newValidationError("transcription_routing", "the cloud route needs openai_api_key (set VS_OPENAI_API_KEY)"))
+		}
+	}
+
+	if !isPromptLanguageMode(c.PromptLanguageMode) {
+		errs = append(errs, newValidationError("prompt_language_mode", "unsupported mode %q (supported: %s)", c.PromptLanguageMode, strings.Join(PromptLanguageModes, ", ")))
+	}
+
+	if c.TranscriptLowConfidenceThreshold < 0 || c.TranscriptLowConfidenceThreshold > 1 {
+		errs = append(errs, newValidationError("transcript_low_confidence_threshold", "must be between 0 and 1, got %g", c.TranscriptLowConfidenceThreshold))
+	}
+
+	if c.Budget.DailyUSD < 0 {
+		errs = append(errs, newValidationError("budget.daily_usd", "must not be negative, got %g (use 0 for no limit)", c.Budget.DailyUSD))
+	}
+	if c.Budget.MonthlyUSD < 0 {
+		errs = append(errs, newValidationError("budget.monthly_usd", "must not be negative, got %g (use 0 for no limit)", c.Budget.MonthlyUSD))
+	}
+	if (c.Budget.DailyUSD > 0 || c.Budget.MonthlyUSD > 0) && c.OpenAIPromptCostPer1K == 0 && c.OpenAICompletionCostPer1K == 0 {
+		errs = append(errs, newValidationError("budget", "needs openai_prompt_cost_per_1k and openai_completion_cost_per_1k to estimate spend"))
+	}
+
+	if c.NearDuplicates.Enabled {
+		switch c.NearDuplicates.Provider {
+		case "openai":
+			if c.OpenAIKey == "" {
+				errs = append(errs, newValidationError("near_duplicates.provider", "openai embeddings need openai_api_key (set VS_OPENAI_API_KEY), or use provider local"))
+			}
+		case "local":
+		default:
+			errs = append(errs, newValidationError("near_duplicates.provider", "unsupported provider %q (supported: openai, local)", c.NearDuplicates.Provider))
+		}
+		if c.NearDuplicates.Threshold <= 0 || c.NearDuplicates.Threshold > 1 {
+			errs = append(errs, newValidationError("near_duplicates.threshold", "must be above 0 and at most 1, got %g", c.NearDuplicates.Threshold))
+		}
+		if c.NearDuplicates.Action != "skip" && c.NearDuplicates.Action != "link" {
+			errs = append(errs, newValidationError("near_duplicates.action", "must be skip or link, got %q", c.NearDuplicates.Action))
+		}
+		if d, err := time.ParseDuration(c.NearDuplicates.Window); err != nil || d <= 0 {
+			errs = append(errs, newValidationError("near_duplicates.window", "invalid duration %q (use values like \"168h\")", c.NearDuplicates.Window))
+		}
+	}
+
+	if c.MaxActiveRequests < 0 {
+		errs = append(errs, newValidationError("max_active_requests", "must not be negative, got %d (use 0 for no limit)", c.MaxActiveRequests))
+	}
+	if c.TmpDirQuotaMB < 0 {
+		errs = append(errs, newValidationError("tmp_dir_quota_mb", "must not be negative, got %d (use 0 for no quota)", c.TmpDirQuotaMB))
+	}
+	if _, err := time.ParseDuration(c.TmpSweepInterval); err != nil {
+		errs = append(errs, newValidationError("tmp_sweep_interval", "invalid duration %q (use values like \"10m\")", c.TmpSweepInterval))
+	}
+	if _, err := time.ParseDuration(c.TmpOrphanMinAge); err != nil {
+		errs = append(errs, newValidationError("tmp_orphan_min_age", "invalid duration %q (use values like \"1h\")", c.TmpOrphanMinAge))
+	}
+
+	if _, err := time.ParseDuration(c.VideoInfoCacheTTL); err != nil {
+		errs = append(errs, newValidationError("video_info_cache_ttl", "invalid duration %q (use values like \"6h\", or \"0\" to disable)", c.VideoInfoCacheTTL))
+	}
+
+	for _, arg := range c.YtDlpExtraArgs {
+		flag, _, _ := strings.Cut(arg, "=")
+		if reservedYtDlpFlags[flag] {
+			errs = append(errs, newValidationError("yt_dlp_extra_args", "%s is set by the summarizer and can't be overridden", flag))
+		}
+	}
+
+	if _, err := ParseByteRate(c.DownloadRateLimit); err != nil {
+		errs = append(errs, newValidationError("download_rate_limit", "%v (use values like \"500K\" or \"2M\")", err))
+	}
+	if _, err := ParseByteRate(c.DownloadTotalRateLimit); err != nil {
+		errs = append(errs, newValidationError("download_total_rate_limit", "%v (use values like \"500K\" or \"2M\")", err))
+	}
+
+	if c.HostLimits.MaxConcurrent < 0 {
+		errs = append(errs, newValidationError("host_limits.max_concurrent", "must not be negative, got %d", c.HostLimits.MaxConcurrent))
+	}
+	if d, err := time.ParseDuration(c.HostLimits.MinInterval); err != nil || d < 0 {
+		errs = append(errs, newValidationError("host_limits.min_interval", "invalid duration %q (use values like \"2s\", or \"0\" for none)", c.HostLimits.MinInterval))
+	}
+
+	if d, err := time.ParseDuration(c.SummaryStreamFlushInterval); err != nil || d <= 0 {
+		errs = append(errs, newValidationError("summary_stream_flush_interval", "invalid duration %q (use values like \"2s\")", c.SummaryStreamFlushInterval))
+	}
+
+	if _, err := time.ParseDuration(c.LLMCacheTTL); err != nil {
+		errs = append(errs, newValidationError("llm_cache_ttl", "invalid duration %q (use values like \"24h\", or \"0\" to disable)", c.LLMCacheTTL))
+	}
+
+	for _, retention := range []struct{ field, value string }{
+		{"retention.request_states", c.Retention.RequestStates},
+		{"retention.events", c.Retention.Events},
+		{"retention.transcripts", c.Retention.Transcripts},
+		{"retention.summaries", c.Retention.Summaries},
+	} {
+		if d, err := time.ParseDuration(retention.value); retention.value != "" && (err != nil || d < 0) {
+			errs = append(errs, newValidationError(retention.field, "invalid duration %q (use values like \"720h\", or \"0\" to keep forever)", retention.value))
+		}
+	}
+
+	if c.ArtifactsDir != "" {
+		if _, err := time.ParseDuration(c.ArtifactsRetention); err != nil {
+			errs = append(errs, newValidationError("artifacts_retention", "invalid duration %q (use values like \"72h\")", c.ArtifactsRetention))
+		}
+		if c.ArtifactsEncryptionKey != "" && c.ArtifactsEncryptionKeyFile != "" {
+			errs = append(errs, newValidationError("artifacts_encryption_key", "set either artifacts_encryption_key or artifacts_encryption_key_file, not both"))
+		} else if _, err := c.GetArtifactsEncryptionKey(); err != nil {
+			errs = append(errs, newValidationError("artifacts_encryption_key", "%v (generate one with: openssl rand -base64 32)", err))
+		}
+	}
+
+	for taskType, limit := range c.Concurrency {
+		if limit <= 0 {
+			errs = append(errs, newValidationError("concurrency."+taskType, "must be at least 1, got %d (tasks of this type would never run)", limit))
+		}
+	}
+
+	for name, fault := range c.FaultInjection.Providers {
+		field := "fault_injection.providers." + name
+		known := false
+		for _, provider := range FaultInjectionProviders {
+			known = known || provider == name
+		}
+		if !known {
+			errs = append(errs, newValidationError(field, "unknown provider (supported: %s)", strings.Join(FaultInjectionProviders, ", ")))
+		}
+		if fault.FailureRate < 0 || fault.FailureRate > 1 {
+			errs = append(errs, newValidationError(field+".failure_rate", "must be between 0 and 1, got %g", fault.FailureRate))
+		}
+		if fault.Latency != "" {
+			if d, err := time.ParseDuration(fault.Latency); err != nil || d < 0 {
+				errs = append(errs, newValidationError(field+".latency", "invalid duration %q (use values like \"2s\")", fault.Latency))
+			}
+		}
+	}
+
+	return errs
+}
+
+// isPromptLanguageMode reports whether mode is a supported prompt language mode
+func isPromptLanguageMode(mode string) bool {
+	for _, m := range PromptLanguageModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that the extra redaction patterns compile
+func (c RedactionConfig) validate() []error {
+	var errs []error
+	check := func(field string, rules RedactionRules) {
+		for label, pattern := range rules.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = append(errs, newValidationError(field+".patterns."+label, "invalid regular expression: %v", err))
+			}
+		}
+	}
+	check("redaction.default", c.Default)
+	for category, rules := range c.Categories {
+		check("redaction.categories."+category, rules)
+	}
+	return errs
+}
+
+// sha256Pattern matches a hex-encoded SHA-256 checksum
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// speechFormatPattern matches the file extension of a local TTS command's audio
+var speechFormatPattern = regexp.MustCompile(`^[a-z0-9]{1,8}$`)
+
+// outputNamePlaceholder matches a {placeholder} in an output naming template
+var outputNamePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// validate checks the naming templates only use known placeholders
+func (c OutputNamingConfig) validate() []error {
+	var errs []error
+	templates := []struct{ field, template string }{
+		{"output_naming.folder_template", c.FolderTemplate},
+		{"output_naming.file_template", c.FileTemplate},
+	}
+	for _, t := range templates {
+		for _, match := range outputNamePlaceholder.FindAllStringSubmatch(t.template, -1) {
+			known := false
+			for _, name := range OutputNamePlaceholders {
+				known = known || name == match[1]
+			}
+			if !known {
+				errs = append(errs, newValidationError(t.field, "unknown placeholder {%s} (supported: %s)", match[1], strings.Join(OutputNamePlaceholders, ", ")))
+			}
+		}
+	}
+	if !strings.Contains(c.FileTemplate, "{suffix}") {
+		errs = append(errs, newValidationError("output_naming.file_template", "must contain {suffix}, or a request's files would overwrite each other"))
+	}
+	if !strings.Contains(c.FolderTemplate, "{request_id}") && !strings.Contains(c.FileTemplate, "{request_id}") {
+		errs = append(errs, newValidationError("output_naming", "folder_template or file_template must contain {request_id}, or requests for videos with the same title would share files"))
+	}
+	if c.MaxLength < 40 {
+		errs = append(errs, newValidationError("output_naming.max_length", "must be at least 40 bytes, got %d", c.MaxLength))
+	}
+	return errs
+}
+
+func (c ConfluenceConfig) validate() []error {
+	var errs []error
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, newValidationError("confluence.base_url", "must be the http(s) URL of the Confluence site (set VS_CONFLUENCE_BASE_URL), got %q", c.BaseURL))
+	}
+	if c.SpaceKey == "" {
+		errs = append(errs, newValidationError("confluence.space_key", "required when confluence.token is set"))
+	}
+	for _, match := range outputNamePlaceholder.FindAllStringSubmatch(c.TitleTemplate, -1) {
+		if match[1] == "suffix" || !slices.Contains(OutputNamePlaceholders, match[1]) {
+			errs = append(errs, newValidationError("confluence.title_template", "unknown placeholder {%s} (supported: %s)", match[1], strings.Join(OutputNamePlaceholders[:len(OutputNamePlaceholders)-1], ", ")))
+		}
+	}
+	return errs
+}
+
+// reservedYtDlpFlags are the yt-dlp options the video provider relies on, which extra
+// arguments must not change
+var reservedYtDlpFlags = map[string]bool{
+	"-o": true, "--output": true, "-x": true, "--extract-audio": true, "--audio-format": true,
+	"-j": true, "--dump-json": true, "-s": true, "--simulate": true, "--skip-download": true,
+	"--limit-rate": true, "-r": true, "--user-agent": true,
+}
+
+// CheckRuntimeDependencies verifies the external tools and directories the pipeline needs at runtime.
+// It is cheap enough to run from a readiness probe.
+func (c *AppConfig) CheckRuntimeDependencies() []error {
+	var errs []error
+	if err := checkExecutable("yt_dlp_path", c.YtDlpPath, "run ./setup_tools.sh or set VS_YT_DLP_PATH"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkExecutable("whisper_path", c.WhisperPath, "run ./setup_tools.sh or set VS_WHISPER_PATH"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkFile("whisper_model_path", c.WhisperModelPath, "download a ggml model, enable whisper_models.download or set VS_WHISPER_MODEL_PATH"); err != nil {
+		errs = append(errs, err)
+	}
+	for i, model := range c.WhisperModels.Models {
+		if model.Path == "" {
+			continue
+		}
+		if err := checkFile(fmt.Sprintf("whisper_models.models[%d].path", i), model.Path, "download the model or enable whisper_models.download"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := checkWritableDir("tmp_dir", c.TmpDir); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Validate checks the service config and its background source definitions
+func (c *ServiceConfig) Validate() []error {
+	var errs []error
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, newValidationError("server.port", "must be between 1 and 65535, got %d", c.Server.Port))
+	}
+
+	seen := make(map[string]bool)
+	for i, source := range c.BackgroundSources.Sources {
+		field := fmt.Sprintf("sources[%d]", i)
+		if source.Name == "" {
+			errs = append(errs, newValidationError(field+".name", "is required"))
+		} else {
+			field = fmt.Sprintf("sources[%s]", source.Name)
+			if seen[source.Name] {
+				errs = append(errs, newValidationError(field+".name", "duplicate source name"))
+			}
+			seen[source.Name] = true
+		}
+		errs = append(errs, source.validate(field)...)
+	}
+
+	if c.Users.MaxActiveRequests < 0 {
+		errs = append(errs, newValidationError("users.max_active_requests", "must not be negative, got %d (use 0 for no limit)", c.Users.MaxActiveRequests))
+	}
+	if c.Users.MaxRequestsPerDay < 0 {
+		errs = append(errs, newValidationError("users.max_requests_per_day", "must not be negative, got %d (use 0 for no limit)", c.Users.MaxRequestsPerDay))
+	}
+
+	for i, token := range c.Feeds.Tokens {
+		if strings.TrimSpace(token) == "" {
+			errs = append(errs, newValidationError(fmt.Sprintf("feeds.tokens[%d]", i), "must not be empty"))
+		}
+	}
+	if c.Feeds.MaxItems < 0 {
+		errs = append(errs, newValidationError("feeds.max_items", "must not be negative, got %d", c.Feeds.MaxItems))
+	}
+	if c.Feeds.BaseURL != "" && !strings.HasPrefix(c.Feeds.BaseURL, "http://") && !strings.HasPrefix(c.Feeds.BaseURL, "https://") {
+		errs = append(errs, newValidationError("feeds.base_url", "must be an http(s) URL, got %q", c.Feeds.BaseURL))
+	}
+
+	seenDigests := make(map[string]bool)
+	for i, digest := range c.Digests {
+		field := fmt.Sprintf("digests[%d]", i)
+		if digest.Name == "" {
+			errs = append(errs, newValidationError(field+".name", "is required"))
+		} else {
+			field = fmt.Sprintf("digests[%s]", digest.Name)
+			if seenDigests[digest.Name] {
+				errs = append(errs, newValidationError(field+".name", "duplicate digest name"))
+			}
+			seenDigests[digest.Name] = true
+		}
+		if digest.Schedule != "daily" && digest.Schedule != "weekly" {
+			errs = append(errs, newValidationError(field+".schedule", "unsupported schedule %q (supported: daily, weekly)", digest.Schedule))
+		}
+		if _, _, err := digest.GetTimeOfDay(); err != nil {
+			errs = append(errs, newValidationError(field+".time", "invalid time %q (use 24-hour \"HH:MM\")", digest.Time))
+		}
+		if _, err := digest.GetWeekday(); err != nil {
+			errs = append(errs, newValidationError(field+".weekday", "%v", err))
+		}
+		if len(digest.Email) > 0 && (c.Notifications.SMTP.Host == "" || c.Notifications.SMTP.From == "") {
+			errs = append(errs, newValidationError(field+".email", "requires notifications.smtp.host and notifications.smtp.from"))
+		}
+	}
+
+	return errs
+}
+
+// validate checks a single source definition
+func (c *SourceConfig) validate(field string) []error {
+	var errs []error
+
+	if !supportedSourceTypes[c.Type] {
+		errs = append(errs, newValidationError(field+".type", "unsupported source type %q", c.Type))
+	}
+
+	// Push sources are fed by callers rather than polled
+	if c.Type != "push" {
+		interval, err := c.GetIntervalDuration()
+		if err != nil {
+			errs = append(errs, newValidationError(field+".interval", "invalid duration %q (use values like \"30m\" or \"1h\")", c.Interval))
+		} else if interval <= 0 {
+			errs = append(errs, newValidationError(field+".interval", "must be positive, got %q", c.Interval))
+		}
+	}
+
+	if c.GetMaxActiveRequests() < 0 {
+		errs = append(errs, newValidationError(field+".config.max_active_requests", "must not be negative"))
+	}
+
+	if _, err := c.GetActiveWindows(); err != nil {
+		errs = append(errs, newValidationError(field+".active_windows", "%v", err))
+	}
+
+	if c.LanguageMode != "" && !isPromptLanguageMode(c.LanguageMode) {
+		errs = append(errs, newValidationError(field+".language_mode", "unsupported mode %q (supported: %s)", c.LanguageMode, strings.Join(PromptLanguageModes, ", ")))
+	}
+
+	if c.Type == "youtube_search" {
+		queries, err := c.GetQueries()
+		if err != nil {
+			errs = append(errs, newValidationError(field+".config.queries", "%v", err))
+		} else if len(queries) == 0 {
+			errs = append(errs, newValidationError(field+".config.queries", "at least one query is required"))
+		}
+		if c.GetMaxVideosPerRun() <= 0 {
+			errs = append(errs, newValidationError(field+".config.max_videos_per_run", "must be at least 1"))
+		}
+		if _, err := c.GetAllowChannels(); err != nil {
+			errs = append(errs, newValidationError(field+".config.allow_channels", "%v", err))
+		}
+		if _, err := c.GetDenyChannels(); err != nil {
+			errs = append(errs, newValidationError(field+".config.deny_channels", "%v", err))
+		}
+		if _, err := c.GetSkipShorts(false); err != nil {
+			errs = append(errs, newValidationError(field+".config.skip_shorts", "%v", err))
+		}
+		if c.GetMinViews() < 0 {
+			errs = append(errs, newValidationError(field+".config.min_views", "must not be negative"))
+		}
+		if _, err := c.GetMaxAge(); err != nil {
+			errs = append(errs, newValidationError(field+".config.max_age", "%v", err))
+		}
+		minDuration, minErr := c.GetMinDuration()
+		if minErr != nil {
+			errs = append(errs, newValidationError(field+".config.min_duration", "%v", minErr))
+		}
+		maxDuration, maxErr := c.GetMaxDuration()
+		if maxErr != nil {
+			errs = append(errs, newValidationError(field+".config.max_duration", "%v", maxErr))
+		}
+		if minErr == nil && maxErr == nil && maxDuration > 0 && minDuration > maxDuration {
+			errs = append(errs, newValidationError(field+".config.max_duration", "must not be shorter than min_duration"))
+		}
+	}
+
+	if c.Type == "discord" {
+		channels, err := c.GetChannels()
+		if err != nil {
+			errs = append(errs, newValidationError(field+".config.channels", "%v", err))
+		} else if len(channels) == 0 {
+			errs = append(errs, newValidationError(field+".config.channels", "at least one channel ID is required"))
+		}
+		if _, err := c.GetLinkHosts(); err != nil {
+			errs = append(errs, newValidationError(field+".config.link_hosts", "%v", err))
+		}
+		if _, err := c.GetSkipShorts(false); err != nil {
+			errs = append(errs, newValidationError(field+".config.skip_shorts", "%v", err))
+		}
+	}
+
+	if c.Type == "push" {
+		if c.GetSecret() == "" {
+			if c.SecretEnv != "" {
+				errs = append(errs, newValidationError(field+".secret_env", "environment variable %s is not set", c.SecretEnv))
+			} else {
+				errs = append(errs, newValidationError(field+".secret", "is required to verify pushed payloads"))
+			}
+		}
+		if tolerance, err := c.GetSignatureTolerance(); err != nil || tolerance <= 0 {
+			errs = append(errs, newValidationError(field+".config.signature_tolerance", "must be a positive duration like \"5m\""))
+		}
+	}
+
+	return errs
+}
+
+// ValidatePrompts checks every prompt file in promptsDir and that the prompt IDs referenced
+// by background sources exist. Unlike PromptManager.LoadPrompts it never writes default prompts.
+func ValidatePrompts(promptsDir string, sources []SourceConfig, maxTokensLimit int) []error {
+	var errs []error
+
+	files, err := filepath.Glob(filepath.Join(promptsDir, "*.yaml"))
+	if err != nil {
+		return []error{newValidationError("prompts_dir", "failed to list prompt files: %v", err)}
+	}
+
+	ids := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, newValidationError(file, "failed to read prompt file: %v", err))
+			continue
+		}
+		var prompt Prompt
+		if err := yaml.Unmarshal(data, &prompt); err != nil {
+			errs = append(errs, newValidationError(file, "invalid YAML: %v", err))
+			continue
+		}
+		if prompt.ID == "" {
+			errs = append(errs, newValidationError(file, "prompt has no id"))
+			continue
+		}
+		if prompt.Content == "" {
+			errs = append(errs, newValidationError(file, "prompt %s has no content", prompt.ID))
+		}
+		if prompt.MaxTokens < 0 {
+			errs = append(errs, newValidationError(file, "prompt %s max_tokens must not be negative, got %d", prompt.ID, prompt.MaxTokens))
+		} else if maxTokensLimit > 0 && prompt.MaxTokens > maxTokensLimit {
+			errs = append(errs, newValidationError(file, "prompt %s max_tokens %d is above the model's %d output tokens", prompt.ID, prompt.MaxTokens, maxTokensLimit))
+		}
+		if other, ok := ids[prompt.ID]; ok {
+			errs = append(errs, newValidationError(file, "prompt id %s is also defined in %s", prompt.ID, other))
+		}
+		ids[prompt.ID] = file
+	}
+
+	// An empty prompts directory is populated with the default prompts on startup
+	if len(files) == 0 {
+		return errs
+	}
+
+	for _, source := range sources {
+		if !source.Enabled {
+			continue
+		}
+		promptID := source.PromptID
+		if promptID == "" {
+			promptID = "general"
+		}
+		if _, ok := ids[promptID]; !ok {
+			errs = append(errs, newValidationError(fmt.Sprintf("sources[%s].prompt_id", source.Name), "prompt %q not found in %s", promptID, promptsDir))
+		}
+	}
+
+	return errs
+}
+
+// validateGoogleAuth checks the gdrive_* credentials Drive and Sheets outputs authenticate with
+func (c *AppConfig) validateGoogleAuth() []error {
+	var errs []error
+	if err := checkFile("gdrive_credentials_file", c.GDriveCredentialsFile, "see docs/google_drive_setup.md"); err != nil {
+		errs = append(errs, err)
+	}
+	switch c.GDriveAuthMethod {
+	case "oauth":
+		if err := checkFile("gdrive_token_file", c.GDriveTokenFile, "run ./bin/gdrive-auth to create a token"); err != nil {
+			errs = append(errs, err)
+		}
+	case "service_account":
+	default:
+		errs = append(errs, newValidationError("gdrive_auth_method", "unsupported auth method %q (supported: oauth, service_account)", c.GDriveAuthMethod))
+	}
+	return errs
+}
+
+// checkFile verifies that path points to an existing regular file
+func checkFile(field, path, hint string) error {
+	if path == "" {
+		return newValidationError(field, "is not set (%s)", hint)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return newValidationError(field, "%s does not exist (%s)", path, hint)
+	}
+	if info.IsDir() {
+		return newValidationError(field, "%s is a directory, expected a file", path)
+	}
+	return nil
+}
+
+// checkExecutable verifies that path points to an existing executable file
+func checkExecutable(field, path, hint string) error {
+	if err := checkFile(field, path, hint); err != nil {
+		return err
+	}
+	info, _ := os.Stat(path)
+	if info.Mode()&0111 == 0 {
+		return newValidationError(field, "%s is not executable (chmod +x %s)", path, path)
+	}
+	return nil
+}
+
+// checkWritableDir verifies that path is an existing directory the process can write to
+func checkWritableDir(field, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return newValidationError(field, "%s does not exist (create it or set VS_TMP_DIR)", path)
+	}
+	if !info.IsDir() {
+		return newValidationError(field, "%s is not a directory", path)
+	}
+	f, err := os.CreateTemp(path, ".validate-*")
+	if err != nil {
+		return newValidationError(field, "%s is not writable: %v", path, err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
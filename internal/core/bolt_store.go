@@ -0,0 +1,383 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Buckets of the bolt state store file
+var (
+	boltRequestsBucket = []byte("requests") // request ID -> state JSON
+	boltEventsBucket   = []byte("events")   // request ID -> events JSON
+	boltDedupBucket    = []byte("dedup")    // dedup key -> request ID
+	boltSpendBucket    = []byte("spend")    // local day -> spend in USD
+)
+
+// BoltStateStore is an InMemoryStateStore whose contents are written through to a bbolt file
+// and loaded back when it is opened, so a single binary keeps its requests, events, dedup keys
+// and spend across restarts without a database server. Reads are served from memory.
+type BoltStateStore struct {
+	*InMemoryStateStore
+	db *bolt.DB
+
+	// writeMu keeps writes to the file in the order of the changes they record
+	writeMu sync.Mutex
+	// removed holds the requests removed from memory by the change being written
+	removed []string
+}
+
+// boltChange names what a change to the in-memory store touched, to write to the file
+type boltChange struct {
+	requests  []string // requests whose state to write
+	events    []string // requests whose events to write
+	allEvents bool     // rewrite every request's events
+	dedupKeys []string
+	spend     bool
+}
+
+// NewBoltStateStore opens or creates the store file at path and loads what it holds. The
+// limits are those of NewInMemoryStoreWithLimits.
+func NewBoltStateStore(path string, maxRequests, maxEventsPerRequest int) (*BoltStateStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state store directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store %s: %w", path, err)
+	}
+	s := &BoltStateStore{
+		InMemoryStateStore: NewInMemoryStoreWithLimits(maxRequests, maxEventsPerRequest),
+		db:                 db,
+	}
+	s.onRemove = func(requestID string) { s.removed = append(s.removed, requestID) }
+	if err := s.load(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load state store %s: %w", path, err)
+	}
+	log.Infof("Loaded %d requests from state store %s", len(s.requests), path)
+	return s, nil
+}
+
+// Close closes the store file
+func (s *BoltStateStore) Close() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.db.Close()
+}
+
+// load creates the buckets and reads them into memory. Records that can't be read, such as
+// events written by a newer build, are skipped with a warning.
+func (s *BoltStateStore) load() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltRequestsBucket, boltEventsBucket, boltDedupBucket, boltSpendBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		var states []*interfaces.ProcessingState
+		if err := tx.Bucket(boltRequestsBucket).ForEach(func(k, v []byte) error {
+			var state interfaces.ProcessingState
+			if err := json.Unmarshal(v, &state); err != nil {
+				log.Warnf("Skipping unreadable request %s in state store: %v", k, err)
+				return nil
+			}
+			states = append(states, &state)
+			return nil
+		}); err != nil {
+			return err
+		}
+		// The most recently updated requests are the last to be evicted
+		sort.Slice(states, func(i, j int) bool { return states[i].UpdatedAt.Before(states[j].UpdatedAt) })
+		for _, state := range states {
+			s.requests[state.RequestID] = state
+			s.touch(state.RequestID)
+		}
+
+		if err := tx.Bucket(boltEventsBucket).ForEach(func(k, v []byte) error {
+			var events []interfaces.Event
+			if err := json.Unmarshal(v, &events); err != nil {
+				log.Warnf("Skipping unreadable events of request %s in state store: %v", k, err)
+				return nil
+			}
+			s.events[string(k)] = events
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltDedupBucket).ForEach(func(k, v []byte) error {
+			s.dedup[string(k)] = string(v)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.Bucket(boltSpendBucket).ForEach(func(k, v []byte) error {
+			if cost, err := strconv.ParseFloat(string(v), 64); err == nil {
+				s.spend[string(k)] = cost
+			}
+			return nil
+		})
+	})
+}
+
+// write copies what a change touched from memory to the file, and deletes the requests it
+// removed, in one transaction
+func (s *BoltStateStore) write(change boltChange) error {
+	// Snapshot under the memory lock, so the file gets a consistent view
+	s.mu.RLock()
+	states := make(map[string][]byte, len(change.requests))
+	for _, requestID := range change.requests {
+		if state, ok := s.requests[requestID]; ok {
+			data, err := json.Marshal(state)
+			if err != nil {
+				s.mu.RUnlock()
+				return fmt.Errorf("failed to encode request %s: %w", requestID, err)
+			}
+			states[requestID] = data
+		}
+	}
+	eventIDs := change.events
+	if change.allEvents {
+		eventIDs = make([]string, 0, len(s.events))
+		for requestID := range s.events {
+			eventIDs = append(eventIDs, requestID)
+		}
+	}
+	events := make(map[string][]byte, len(eventIDs))
+	for _, requestID := range eventIDs {
+		if requestEvents, ok := s.events[requestID]; ok {
+			data, err := json.Marshal(requestEvents)
+			if err != nil {
+				s.mu.RUnlock()
+				return fmt.Errorf("failed to encode events of request %s: %w", requestID, err)
+			}
+			events[requestID] = data
+		}
+	}
+	dedup := make(map[string]string, len(change.dedupKeys))
+	for _, key := range change.dedupKeys {
+		dedup[key] = s.dedup[key]
+	}
+	var spend map[string]float64
+	if change.spend {
+		spend = make(map[string]float64, len(s.spend))
+		for day, cost := range s.spend {
+			spend[day] = cost
+		}
+	}
+	s.mu.RUnlock()
+
+	removed := s.removed
+	s.removed = nil
+	return s.db.Update(func(tx *bolt.Tx) error {
+		requestsBucket := tx.Bucket(boltRequestsBucket)
+		for requestID, data := range states {
+			if err := requestsBucket.Put([]byte(requestID), data); err != nil {
+				return err
+			}
+		}
+
+		if change.allEvents {
+			if err := tx.DeleteBucket(boltEventsBucket); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(boltEventsBucket); err != nil {
+				return err
+			}
+		}
+		eventsBucket := tx.Bucket(boltEventsBucket)
+		for _, requestID := range eventIDs {
+			var err error
+			if data, ok := events[requestID]; ok {
+				err = eventsBucket.Put([]byte(requestID), data)
+			} else {
+				err = eventsBucket.Delete([]byte(requestID))
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		dedupBucket := tx.Bucket(boltDedupBucket)
+		for key, requestID := range dedup {
+			var err error
+			if requestID != "" {
+				err = dedupBucket.Put([]byte(key), []byte(requestID))
+			} else {
+				err = dedupBucket.Delete([]byte(key))
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if spend != nil {
+			spendBucket := tx.Bucket(boltSpendBucket)
+			c := spendBucket.Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				if _, ok := spend[string(k)]; !ok {
+					if err := c.Delete(); err != nil {
+						return err
+					}
+				}
+			}
+			for day, cost := range spend {
+				if err := spendBucket.Put([]byte(day), []byte(strconv.FormatFloat(cost, 'f', -1, 64))); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(removed) == 0 {
+			return nil
+		}
+		gone := make(map[string]bool, len(removed))
+		for _, requestID := range removed {
+			gone[requestID] = true
+			if err := requestsBucket.Delete([]byte(requestID)); err != nil {
+				return err
+			}
+			if err := eventsBucket.Delete([]byte(requestID)); err != nil {
+				return err
+			}
+		}
+		c := dedupBucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if gone[string(v)] {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// SaveRequestState stores a request and writes it to the file
+func (s *BoltStateStore) SaveRequestState(requestID string, state *interfaces.ProcessingState) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.InMemoryStateStore.SaveRequestState(requestID, state); err != nil {
+		return err
+	}
+	return s.write(boltChange{requests: []string{requestID}})
+}
+
+// UpdateRequestState updates a request and writes it, and the spend its token usage adds, to the file
+func (s *BoltStateStore) UpdateRequestState(requestID string, updates map[string]interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.InMemoryStateStore.UpdateRequestState(requestID, updates); err != nil {
+		return err
+	}
+	change := boltChange{requests: []string{requestID}}
+	for _, key := range []string{"token_usage", "highlights", "evaluation"} {
+		if _, ok := updates[key]; ok {
+			change.spend = true
+		}
+	}
+	return s.write(change)
+}
+
+// DeleteRequestState deletes a request from memory and the file
+func (s *BoltStateStore) DeleteRequestState(requestID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.InMemoryStateStore.DeleteRequestState(requestID); err != nil {
+		return err
+	}
+	return s.write(boltChange{})
+}
+
+// LogEvent records an event and writes the request's events to the file
+func (s *BoltStateStore) LogEvent(event interfaces.Event) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.InMemoryStateStore.LogEvent(event); err != nil {
+		return err
+	}
+	return s.write(boltChange{events: []string{event.RequestID}})
+}
+
+// CleanupOldRequests removes old finished requests from memory and the file
+func (s *BoltStateStore) CleanupOldRequests(olderThan time.Time) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	removed, err := s.InMemoryStateStore.CleanupOldRequests(olderThan)
+	if err != nil {
+		return removed, err
+	}
+	return removed, s.write(boltChange{})
+}
+
+// PruneEvents drops old events of finished requests from memory and the file
+func (s *BoltStateStore) PruneEvents(olderThan time.Time) int {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	dropped := s.InMemoryStateStore.PruneEvents(olderThan)
+	if dropped > 0 {
+		if err := s.write(boltChange{allEvents: true}); err != nil {
+			log.Warnf("Failed to write pruned events to state store: %v", err)
+		}
+	}
+	return dropped
+}
+
+// AddDedupKey stores a dedup key -> requestID mapping and writes it to the file
+func (s *BoltStateStore) AddDedupKey(dedupKey, requestID string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.InMemoryStateStore.AddDedupKey(dedupKey, requestID)
+	if err := s.write(boltChange{dedupKeys: []string{dedupKey}}); err != nil {
+		log.Warnf("Failed to write dedup key to state store: %v", err)
+	}
+}
+
+// CreateOrGetDedupRequest is InMemoryStateStore.CreateOrGetDedupRequest, writing a new
+// request and its dedup key to the file
+func (s *BoltStateStore) CreateOrGetDedupRequest(dedupKey string, state *interfaces.ProcessingState) (string, bool, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	requestID, exists, err := s.InMemoryStateStore.CreateOrGetDedupRequest(dedupKey, state)
+	if err != nil || exists {
+		return requestID, exists, err
+	}
+	return requestID, false, s.write(boltChange{requests: []string{requestID}, dedupKeys: []string{dedupKey}})
+}
+
+// FailInterruptedRequests fails the requests that were pending or running when the store was
+// last closed, whose queued tasks were lost with an in-memory task queue, so they can be
+// retried. Scheduled requests are left to start as usual. Returns how many were failed.
+func (s *BoltStateStore) FailInterruptedRequests() int {
+	active, err := s.GetAllActiveRequests()
+	if err != nil {
+		return 0
+	}
+	failed := 0
+	for _, state := range active {
+		if state.Status == interfaces.StatusScheduled {
+			continue
+		}
+		if err := s.UpdateRequestState(state.RequestID, map[string]interface{}{
+			"status":     interfaces.StatusFailed,
+			"error":      "interrupted by a service restart",
+			"error_code": interfaces.ErrorCodeInterrupted,
+		}); err != nil {
+			log.Warnf("Failed to mark interrupted request %s as failed: %v", state.RequestID, err)
+			continue
+		}
+		failed++
+	}
+	return failed
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Checkpoint records the artifacts a request has produced so far
+type Checkpoint struct {
+	URL            string                 `json:"url"`
+	VideoInfo      map[string]interface{} `json:"video_info,omitempty"`
+	AudioPath      string                 `json:"audio_path,omitempty"`
+	TranscriptPath string                 `json:"transcript_path,omitempty"`
+	SummaryPath    string                 `json:"summary_path,omitempty"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// CheckpointStore persists per-request checkpoints and their artifact files under a directory,
+// so a retried or resubmitted request can resume from the last completed stage.
+// Checkpoints are keyed by URL and prompt, and removed once a request completes.
+// Transcripts and summaries can be sealed with AES-GCM so they are not stored in plaintext.
+type CheckpointStore struct {
+	dir  string
+	aead cipher.AEAD // encrypts sealed artifacts; nil when encryption is off
+	mu   sync.Mutex
+}
+
+// sealedSuffix marks artifact files stored encrypted
+const sealedSuffix = ".enc"
+
+// NewCheckpointStore creates a checkpoint store rooted at dir
+func NewCheckpointStore(dir string) (*CheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts dir %s: %w", dir, err)
+	}
+	return &CheckpointStore{dir: dir}, nil
+}
+
+// EnableEncryption has Seal store artifacts encrypted with AES-256-GCM under key
+func (s *CheckpointStore) EnableEncryption(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("invalid artifacts encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	s.aead = aead
+	return nil
+}
+
+// Encrypted reports whether sealed artifacts are encrypted
+func (s *CheckpointStore) Encrypted() bool {
+	return s.aead != nil
+}
+
+// CheckpointKey identifies the work a request does, independent of its request ID
+func CheckpointKey(state *interfaces.ProcessingState) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", state.URL, state.Prompt.Type, state.Prompt.Prompt)))
+	return hex.EncodeToString(sum[:12])
+}
+
+// TranscriptKey identifies the transcript kept for a URL after its request finishes, so
+// reruns with another prompt can summarize it without transcribing again
+func TranscriptKey(url string) string {
+	sum := sha256.Sum256([]byte("transcript|" + url))
+	return hex.EncodeToString(sum[:12])
+}
+
+func (s *CheckpointStore) keyDir(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+// Load returns the checkpoint for key. Artifacts whose files have disappeared are dropped.
+func (s *CheckpointStore) Load(key string) (*Checkpoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(filepath.Join(s.keyDir(key), "checkpoint.json"))
+	if err != nil {
+		return nil, false
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		log.Warnf("Ignoring unreadable checkpoint %s: %v", key, err)
+		return nil, false
+	}
+	for _, path := range []*string{&cp.AudioPath, &cp.TranscriptPath, &cp.SummaryPath} {
+		if *path != "" {
+			if _, err := os.Stat(*path); err != nil {
+				*path = ""
+			}
+		}
+	}
+	return &cp, true
+}
+
+// Update applies fn to the checkpoint for key and saves it
+func (s *CheckpointStore) Update(key string, fn func(cp *Checkpoint)) error {
+	cp, ok := s.Load(key)
+	if !ok {
+		cp = &Checkpoint{}
+	}
+	fn(cp)
+	cp.UpdatedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.keyDir(key), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(s.keyDir(key), "checkpoint.json.tmp")
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(s.keyDir(key), "checkpoint.json"))
+}
+
+// Adopt moves an artifact file into the checkpoint directory for key and returns its new path.
+// Files already inside the directory are left where they are.
+func (s *CheckpointStore) Adopt(key, name, path string) (string, error) {
+	dir := s.keyDir(key)
+	if filepath.Dir(filepath.Clean(path)) == dir {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name+filepath.Ext(path))
+	if err := os.Rename(path, dest); err != nil {
+		// Rename fails across filesystems; fall back to copying
+		if err := copyFile(path, dest); err != nil {
+			return "", err
+		}
+		os.Remove(path)
+	}
+	return dest, nil
+}
+
+// Keep stores a copy of an artifact file for key, encrypted when encryption is on, and
+// returns its path. The original is left in place.
+func (s *CheckpointStore) Keep(key, name, path string) (string, error) {
+	if s.aead != nil {
+		return s.Seal(key, name, path)
+	}
+	dir := s.keyDir(key)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name+filepath.Ext(path))
+	if filepath.Clean(path) == dest {
+		return dest, nil
+	}
+	return dest, copyFile(path, dest)
+}
+
+// Seal stores an encrypted copy of an artifact file for key and returns its path. The
+// original is left in place for the request to keep using, and its plaintext is removed
+// with the request's other temp files.
+func (s *CheckpointStore) Seal(key, name, path string) (string, error) {
+	if s.aead == nil {
+		return "", errors.New("artifacts encryption is not enabled")
+	}
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	dir := s.keyDir(key)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name+filepath.Ext(path)+sealedSuffix)
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// Binding the ciphertext to its location stops it being swapped into another checkpoint
+	sealed := s.aead.Seal(nonce, nonce, plaintext, []byte(s.sealedName(dest)))
+	tmpPath := dest + ".tmp"
+	if err := os.WriteFile(tmpPath, sealed, 0600); err != nil {
+		return "", err
+	}
+	return dest, os.Rename(tmpPath, dest)
+}
+
+// Unseal decrypts a sealed artifact into a new file in tmpDir, which the caller owns.
+// Artifacts that were stored unencrypted are returned as they are.
+func (s *CheckpointStore) Unseal(path, tmpDir string) (string, error) {
+	if !strings.HasSuffix(path, sealedSuffix) {
+		return path, nil
+	}
+	if s.aead == nil {
+		return "", fmt.Errorf("%s is encrypted but no artifacts encryption key is configured", path)
+	}
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("%s is truncated", path)
+	}
+	plaintext, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(s.sealedName(path)))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s (was the key changed?): %w", path, err)
+	}
+
+	pattern := strings.TrimSuffix(filepath.Base(path), sealedSuffix)
+	ext := filepath.Ext(pattern)
+	f, err := os.CreateTemp(tmpDir, strings.TrimSuffix(pattern, ext)+"-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(plaintext)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// sealedName names a sealed file by its checkpoint and file name, independent of dir
+func (s *CheckpointStore) sealedName(path string) string {
+	return filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path)
+}
+
+// Delete removes the checkpoint and all of its artifacts
+func (s *CheckpointStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.RemoveAll(s.keyDir(key))
+}
+
+// Prune removes checkpoints that have not been updated since olderThan
+func (s *CheckpointStore) Prune(olderThan time.Time) int {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0
+	}
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		cp, ok := s.Load(entry.Name())
+		if ok && cp.UpdatedAt.After(olderThan) {
+			continue
+		}
+		if !ok {
+			// Directories without a readable checkpoint are kept until they are old enough
+			info, err := entry.Info()
+			if err != nil || info.ModTime().After(olderThan) {
+				continue
+			}
+		}
+		if err := s.Delete(entry.Name()); err == nil {
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Infof("Pruned %d stale checkpoint(s) from %s", removed, s.dir)
+	}
+	return removed
+}
+
+// copyFile copies src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
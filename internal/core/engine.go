@@ -2,7 +2,11 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,8 +15,13 @@ import (
 	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/core/tasks"
 	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/summarization"
+	"video-summarizer-go/internal/providers/video"
 )
 
+// ErrAtCapacity is returned when max_active_requests requests are already pending or running
+var ErrAtCapacity = errors.New("service is at capacity")
+
 type ProcessingEngine struct {
 	store      interfaces.StateStore
 	eventBus   interfaces.EventBus
@@ -24,10 +33,32 @@ type ProcessingEngine struct {
 	transcriptionProvider interfaces.TranscriptionProvider
 	summarizationProvider interfaces.SummarizationProvider
 	outputProvider        interfaces.OutputProvider
+	outputProviders       map[string]interfaces.OutputProvider
+	documentProvider      interfaces.DocumentProvider
+	articleProvider       interfaces.DocumentProvider
+	slideProvider         interfaces.SlideProvider
+	speechProvider        interfaces.SpeechProvider
+	embeddingProvider     interfaces.EmbeddingProvider
 	promptManager         *config.PromptManager
 	taskProcessorRegistry *tasks.TaskProcessorRegistry
-
-	mu sync.Mutex
+	pipelines             *PipelineRegistry
+	config                *config.AppConfig
+	tmpDirManager         *TmpDirManager
+	preflight             *Preflight
+	checkpoints           *CheckpointStore
+	videoInfoCache        *video.CachingVideoProvider
+	llmCache              *summarization.CachingSummarizationProvider
+	ytDlp                 *video.YtDlpVideoProvider // nil when the video provider is overridden
+	hostLimits            *video.HostLimitedVideoProvider
+	searchIndex           *SearchIndex
+	nearDuplicates        *NearDuplicateIndex
+
+	// Comparisons waiting for the requests they compare to finish
+	pendingComparisons map[string]bool
+	comparisonsMu      sync.Mutex
+
+	mu       sync.Mutex
+	configMu sync.RWMutex
 }
 
 func NewProcessingEngine(
@@ -44,7 +75,7 @@ func NewProcessingEngine(
 ) *ProcessingEngine {
 	engine := &ProcessingEngine{
 		store:                 store,
-		eventBus:              eventBus,
+		eventBus:              &eventLog{EventBus: eventBus, store: store},
 		taskQueue:             taskQueue,
 		workerPool:            workerPool,
 		videoProvider:         videoProvider,
@@ -52,20 +83,36 @@ func NewProcessingEngine(
 		transcriptionProvider: transcriptionProvider,
 		summarizationProvider: summarizationProvider,
 		outputProvider:        outputProvider,
+		outputProviders:       make(map[string]interfaces.OutputProvider),
 		promptManager:         promptManager,
 		taskProcessorRegistry: tasks.NewTaskProcessorRegistry(),
+		pipelines:             NewPipelineRegistry(),
+		searchIndex:           NewSearchIndex(),
+		nearDuplicates:        NewNearDuplicateIndex(),
+		pendingComparisons:    make(map[string]bool),
 	}
 	engine.registerEventHandlers()
 	return engine
 }
 
 func (e *ProcessingEngine) registerEventHandlers() {
-	e.eventBus.Subscribe("VideoProcessingRequested", e.onVideoProcessingRequested)
-	e.eventBus.Subscribe("VideoInfoFetched", e.onVideoInfoFetched)
-	e.eventBus.Subscribe("AudioDownloaded", e.onAudioDownloaded)
+	e.eventBus.Subscribe(interfaces.EventTypeVideoProcessingRequested, e.onVideoProcessingRequested)
+	e.eventBus.Subscribe(interfaces.EventTypeVideoInfoFetched, e.onVideoInfoFetched)
+	e.eventBus.Subscribe(interfaces.EventTypeSlidesCompleted, e.onSlidesCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeAudioDownloaded, e.onAudioDownloaded)
+	e.eventBus.Subscribe(interfaces.EventTypeTextExtracted, e.onTextExtracted)
 	e.eventBus.Subscribe(interfaces.EventTypeTranscriptionCompleted, e.onTranscriptionCompleted)
 	e.eventBus.Subscribe(interfaces.EventTypeSummarizationCompleted, e.onSummarizationCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeHighlightsCompleted, e.onHighlightsCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeEvaluationCompleted, e.onEvaluationCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeRedactionCompleted, e.onRedactionCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeSpeechCompleted, e.onSpeechCompleted)
 	e.eventBus.Subscribe(interfaces.EventTypeOutputCompleted, e.onOutputCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeHooksCompleted, e.onHooksCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeProcessingCompleted, e.onProcessingCompleted)
+	e.eventBus.Subscribe(interfaces.EventTypeProcessingCompleted, e.onComparedRequestFinished)
+	e.eventBus.Subscribe(interfaces.EventTypeProcessingFailed, e.onComparedRequestFinished)
+	e.eventBus.Subscribe(interfaces.EventTypeRequestCancelled, e.onComparedRequestFinished)
 }
 
 // Entry point: create a new request and emit VideoProcessingRequested
@@ -80,25 +127,41 @@ func (e *ProcessingEngine) StartRequest(requestID, url string, prompt interfaces
 		URL:        url,
 		Prompt:     prompt,
 		MaxTokens:  maxTokens,
-		// Add category as a top-level field if you want, or handle it elsewhere
+		Category:   category,
 	}
-	e.store.SaveRequestState(requestID, state)
-	log.Debugf("Publishing VideoProcessingRequested event for requestID: %s", requestID)
+	return e.StartPreparedRequest(state)
+}
+
+// StartPreparedRequest stores a fully populated request state and emits VideoProcessingRequested
+func (e *ProcessingEngine) StartPreparedRequest(state *interfaces.ProcessingState) error {
+	e.store.SaveRequestState(state.RequestID, state)
+	log.Debugf("Publishing VideoProcessingRequested event for requestID: %s", state.RequestID)
 	e.eventBus.Publish(interfaces.Event{
-		ID:        fmt.Sprintf("evt-%s-%d", requestID, time.Now().UnixNano()),
-		RequestID: requestID,
-		Type:      "VideoProcessingRequested",
-		Data:      map[string]interface{}{"url": url},
+		ID:        fmt.Sprintf("evt-%s-%d", state.RequestID, time.Now().UnixNano()),
+		RequestID: state.RequestID,
+		Type:      interfaces.EventTypeVideoProcessingRequested,
+		Data:      interfaces.VideoProcessingRequestedPayload{URL: state.URL},
 		Timestamp: time.Now(),
 	})
 	return nil
 }
 
-// GetRequestState gets the current state of a processing request
+// GetRequestState gets a copy of the current state of a processing request
 func (e *ProcessingEngine) GetRequestState(requestID string) (*interfaces.ProcessingState, error) {
 	return e.store.GetRequestState(requestID)
 }
 
+// ViewRequestState calls view with the current state of a request without copying it; see
+// StateStore.ViewRequestState
+func (e *ProcessingEngine) ViewRequestState(requestID string, view func(state *interfaces.ProcessingState)) error {
+	return e.store.ViewRequestState(requestID, view)
+}
+
+// GetRequestStates returns the requests among requestIDs that exist, as of one moment
+func (e *ProcessingEngine) GetRequestStates(requestIDs []string) map[string]*interfaces.ProcessingState {
+	return e.store.GetRequestStates(requestIDs)
+}
+
 // CancelRequest cancels a processing request
 func (e *ProcessingEngine) CancelRequest(requestID string) error {
 	e.mu.Lock()
@@ -109,7 +172,7 @@ func (e *ProcessingEngine) CancelRequest(requestID string) error {
 		return fmt.Errorf("request not found: %s", requestID)
 	}
 
-	if state.Status == interfaces.StatusCompleted || state.Status == interfaces.StatusFailed || state.Status == interfaces.StatusCancelled {
+	if isTerminalStatus(state.Status) {
 		return fmt.Errorf("request %s is already in final state: %s", requestID, state.Status)
 	}
 
@@ -126,8 +189,8 @@ func (e *ProcessingEngine) CancelRequest(requestID string) error {
 	e.eventBus.Publish(interfaces.Event{
 		ID:        fmt.Sprintf("evt-%s-cancelled-%d", requestID, time.Now().UnixNano()),
 		RequestID: requestID,
-		Type:      "RequestCancelled",
-		Data:      map[string]interface{}{"cancelled_at": time.Now()},
+		Type:      interfaces.EventTypeRequestCancelled,
+		Data:      interfaces.RequestCancelledPayload{CancelledAt: time.Now()},
 		Timestamp: time.Now(),
 	})
 
@@ -139,15 +202,177 @@ func (e *ProcessingEngine) GetRequestCountsByStatus() map[string]int {
 	return e.store.GetRequestCountsByStatus()
 }
 
-// Start starts the processing engine (workers are already started when limits are set)
+// Pause holds all queued tasks until Resume is called; tasks already running finish.
+// Submissions are still accepted and queue up while paused.
+func (e *ProcessingEngine) Pause() {
+	if e.workerPool != nil && !e.workerPool.IsPaused() {
+		e.workerPool.Pause()
+		log.Warn("[Engine] Processing paused")
+	}
+}
+
+// Resume restarts processing of queued tasks after Pause
+func (e *ProcessingEngine) Resume() {
+	if e.workerPool != nil && e.workerPool.IsPaused() {
+		e.workerPool.Resume()
+		log.Info("[Engine] Processing resumed")
+	}
+}
+
+// IsPaused reports whether processing is paused
+func (e *ProcessingEngine) IsPaused() bool {
+	return e.workerPool != nil && e.workerPool.IsPaused()
+}
+
+// CheckCapacity returns ErrAtCapacity if starting n more requests would exceed
+// max_active_requests
+func (e *ProcessingEngine) CheckCapacity(n int) error {
+	cfg := e.GetConfig()
+	if cfg == nil || cfg.MaxActiveRequests <= 0 {
+		return nil
+	}
+	counts := e.store.GetRequestCountsByStatus()
+	active := counts[string(interfaces.StatusPending)] + counts[string(interfaces.StatusRunning)]
+	if active+n > cfg.MaxActiveRequests {
+		return fmt.Errorf("%w: %d active requests (limit %d)", ErrAtCapacity, active, cfg.MaxActiveRequests)
+	}
+	return nil
+}
+
+// GetStoreStats reports the size of the state store
+func (e *ProcessingEngine) GetStoreStats() interfaces.StoreStats {
+	return e.store.GetStoreStats()
+}
+
+// indexRequest adds a request's title, channel and summary to the search index
+func (e *ProcessingEngine) indexRequest(state *interfaces.ProcessingState) {
+	doc := SearchDocument{Title: state.Title(), Summary: state.SummaryText}
+	if channel, ok := state.VideoInfo["channel"].(string); ok {
+		doc.Channel = channel
+	} else if uploader, ok := state.VideoInfo["uploader"].(string); ok {
+		doc.Channel = uploader
+	} else if siteName, ok := state.DocumentInfo["site_name"].(string); ok {
+		doc.Channel = siteName
+	}
+	e.searchIndex.Index(state.RequestID, doc)
+}
+
+// indexStoredRequests adds the requests a persistent store held at startup to the search and
+// near-duplicate indexes, which are otherwise only filled as events arrive. The near-duplicate
+// embeddings are made in the background.
+func (e *ProcessingEngine) indexStoredRequests() {
+	states, err := e.store.ListRequests()
+	if err != nil || len(states) == 0 {
+		return
+	}
+	indexed := 0
+	for _, state := range states {
+		if state.Title() == "" && state.SummaryText == "" {
+			continue
+		}
+		e.indexRequest(state)
+		indexed++
+	}
+	log.Infof("[Engine] Indexed %d stored request(s) for search", indexed)
+	go e.restoreNearDuplicates(states)
+}
+
+// SearchRequests returns the requests whose title, channel or summary contain every word of
+// the query, best matches first. Requests no longer in the store are dropped from the index.
+func (e *ProcessingEngine) SearchRequests(query string) []*interfaces.ProcessingState {
+	var results []*interfaces.ProcessingState
+	for _, hit := range e.searchIndex.Search(query) {
+		state, err := e.store.GetRequestState(hit.RequestID)
+		if err != nil {
+			e.searchIndex.Remove(hit.RequestID)
+			continue
+		}
+		results = append(results, state)
+	}
+	return results
+}
+
+// GetQueueLengths returns the number of queued tasks per task type
+func (e *ProcessingEngine) GetQueueLengths() map[interfaces.TaskType]int {
+	lengths := make(map[interfaces.TaskType]int)
+	for taskType := range e.taskProcessorRegistry.GetAllProcessors() {
+		lengths[taskType] = e.taskQueue.QueueLength(taskType)
+	}
+	return lengths
+}
+
+// GetVideoInfoCacheStats reports video info cache hits and misses, or nil if caching is disabled
+func (e *ProcessingEngine) GetVideoInfoCacheStats() *video.VideoInfoCacheStats {
+	if e.videoInfoCache == nil {
+		return nil
+	}
+	stats := e.videoInfoCache.Stats()
+	return &stats
+}
+
+// GetLLMCacheStats reports summarization cache hits and the spend they saved, or nil if
+// caching is disabled
+func (e *ProcessingEngine) GetLLMCacheStats() *summarization.SummaryCacheStats {
+	if e.llmCache == nil {
+		return nil
+	}
+	stats := e.llmCache.Stats()
+	stats.SavedUSD = e.GetConfig().EstimateCost(stats.Saved.PromptTokens, stats.Saved.CompletionTokens)
+	return &stats
+}
+
+// GetTmpDirUsage reports disk usage of the temp directory, or nil if it is not tracked
+func (e *ProcessingEngine) GetTmpDirUsage() *TmpDirUsage {
+	if e.tmpDirManager == nil {
+		return nil
+	}
+	usage := e.tmpDirManager.Usage()
+	return &usage
+}
+
+// Start starts background maintenance (workers are already started when limits are set)
 func (e *ProcessingEngine) Start() {
-	// Workers are already running when concurrency limits are set
-	// This method is kept for API consistency
+	if e.tmpDirManager != nil {
+		e.tmpDirManager.Start()
+	}
+	if cfg := e.GetConfig(); cfg != nil && e.workerPool != nil {
+		e.workerPool.StartWatchdog(cfg.Watchdog.GetCheckInterval(), e.watchdogTimeout)
+	}
+	if e.preflight != nil {
+		e.preflight.Start()
+	}
 }
 
 // Stop stops the processing engine
 func (e *ProcessingEngine) Stop() {
+	if e.tmpDirManager != nil {
+		e.tmpDirManager.Stop()
+	}
+	if e.preflight != nil {
+		e.preflight.Stop()
+	}
 	e.workerPool.Stop()
+	// Queues on a broker leave their consumer group and flush pending writes
+	if closer, ok := e.taskQueue.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("[Engine] Failed to close task queue: %v", err)
+		}
+	}
+	// File-backed stores release their file
+	if closer, ok := e.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("[Engine] Failed to close state store: %v", err)
+		}
+	}
+}
+
+// GetPreflightStatus returns the results of the startup self-test, or nil if it is disabled
+func (e *ProcessingEngine) GetPreflightStatus() *PreflightStatus {
+	if e.preflight == nil {
+		return nil
+	}
+	status := e.preflight.Status()
+	return &status
 }
 
 // GetVideoProvider returns the video provider
@@ -170,11 +395,171 @@ func (e *ProcessingEngine) GetOutputProvider() interfaces.OutputProvider {
 	return e.outputProvider
 }
 
+// GetNamedOutputProvider returns a registered output provider by name, or nil if that
+// provider is not configured
+func (e *ProcessingEngine) GetNamedOutputProvider(name string) interfaces.OutputProvider {
+	return e.outputProviders[name]
+}
+
+// GetOutputProviderNames returns the names of the registered output providers, sorted
+func (e *ProcessingEngine) GetOutputProviderNames() []string {
+	names := make([]string, 0, len(e.outputProviders))
+	for name := range e.outputProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetDocumentProvider returns the document provider
+func (e *ProcessingEngine) GetDocumentProvider() interfaces.DocumentProvider {
+	return e.documentProvider
+}
+
+// GetArticleProvider returns the web article provider
+func (e *ProcessingEngine) GetArticleProvider() interfaces.DocumentProvider {
+	return e.articleProvider
+}
+
+// GetSlideProvider returns the provider that reads on-screen text, or nil if there is none
+func (e *ProcessingEngine) GetSlideProvider() interfaces.SlideProvider {
+	return e.slideProvider
+}
+
+// GetSpeechProvider returns the provider that reads summaries aloud, or nil if there is none
+func (e *ProcessingEngine) GetSpeechProvider() interfaces.SpeechProvider {
+	return e.speechProvider
+}
+
 // GetPromptManager returns the prompt manager
 func (e *ProcessingEngine) GetPromptManager() *config.PromptManager {
 	return e.promptManager
 }
 
+// GetConfig returns the engine configuration currently in effect
+func (e *ProcessingEngine) GetConfig() *config.AppConfig {
+	e.configMu.RLock()
+	defer e.configMu.RUnlock()
+	return e.config
+}
+
+// ApplyConfig applies the runtime-safe parts of a reloaded configuration:
+// concurrency limits, download bandwidth and host limits, the prompts directory and output toggles. Settings that
+// require new providers (binary paths, API keys, output provider) are logged
+// and only take effect after a restart. In-flight tasks are not interrupted.
+func (e *ProcessingEngine) ApplyConfig(newCfg *config.AppConfig) error {
+	oldCfg := e.GetConfig()
+
+	if err := e.promptManager.Reload(newCfg.PromptsDir); err != nil {
+		return fmt.Errorf("failed to reload prompts: %w", err)
+	}
+
+	for taskType, limit := range concurrencyLimitsFromConfig(newCfg) {
+		if e.workerPool.GetConcurrencyLimit(taskType) != limit {
+			log.Infof("[Engine] Concurrency for %s changed to %d", taskType, limit)
+			e.workerPool.SetConcurrencyLimit(taskType, limit)
+		}
+	}
+	if e.ytDlp != nil {
+		e.ytDlp.SetLimitRate(newCfg.GetDownloadRateLimit())
+	}
+	if e.hostLimits != nil {
+		e.hostLimits.SetLimits(newCfg.HostLimits.MaxConcurrent, newCfg.HostLimits.GetMinInterval())
+	}
+
+	if oldCfg != nil {
+		for _, setting := range restartRequiredChanges(oldCfg, newCfg) {
+			log.Warnf("[Engine] Config change to %s requires a restart and was not applied", setting)
+		}
+	}
+
+	e.configMu.Lock()
+	e.config = newCfg
+	e.configMu.Unlock()
+
+	log.Infof("[Engine] Configuration reloaded (upload_summary: %v, upload_transcript: %v)", newCfg.UploadSummary, newCfg.UploadTranscript)
+	return nil
+}
+
+// restartRequiredChanges lists settings that differ between configs but cannot be applied at runtime
+func restartRequiredChanges(oldCfg, newCfg *config.AppConfig) []string {
+	var changed []string
+	check := func(name string, oldVal, newVal interface{}) {
+		if oldVal != newVal {
+			changed = append(changed, name)
+		}
+	}
+	check("summarizer_provider", oldCfg.SummarizerProvider, newCfg.SummarizerProvider)
+	check("dry_run", oldCfg.DryRun, newCfg.DryRun)
+	check("stub_summary_sentences", oldCfg.StubSummarySentences, newCfg.StubSummarySentences)
+	check("text_summary_sentences", oldCfg.TextSummarySentences, newCfg.TextSummarySentences)
+	check("openai_api_key", oldCfg.OpenAIKey, newCfg.OpenAIKey)
+	check("openai_model", oldCfg.OpenAIModel, newCfg.OpenAIModel)
+	check("openai_max_tokens", oldCfg.OpenAIMaxTokens, newCfg.OpenAIMaxTokens)
+	check("summary_streaming", oldCfg.SummaryStreaming, newCfg.SummaryStreaming)
+	check("summary_stream_flush_interval", oldCfg.SummaryStreamFlushInterval, newCfg.SummaryStreamFlushInterval)
+	check("yt_dlp_path", oldCfg.YtDlpPath, newCfg.YtDlpPath)
+	check("yt_dlp_user_agent", oldCfg.YtDlpUserAgent, newCfg.YtDlpUserAgent)
+	if !reflect.DeepEqual(oldCfg.YtDlpExtractorArgs, newCfg.YtDlpExtractorArgs) {
+		changed = append(changed, "yt_dlp_extractor_args")
+	}
+	if !reflect.DeepEqual(oldCfg.YtDlpExtraArgs, newCfg.YtDlpExtraArgs) {
+		changed = append(changed, "yt_dlp_extra_args")
+	}
+	check("llm_cache_ttl", oldCfg.LLMCacheTTL, newCfg.LLMCacheTTL)
+	check("llm_cache_size", oldCfg.LLMCacheSize, newCfg.LLMCacheSize)
+	check("video_info_cache_ttl", oldCfg.VideoInfoCacheTTL, newCfg.VideoInfoCacheTTL)
+	check("video_info_cache_size", oldCfg.VideoInfoCacheSize, newCfg.VideoInfoCacheSize)
+	check("pdftotext_path", oldCfg.PdfToTextPath, newCfg.PdfToTextPath)
+	check("slides.ffmpeg_path", oldCfg.Slides.FfmpegPath, newCfg.Slides.FfmpegPath)
+	check("slides.tesseract_path", oldCfg.Slides.TesseractPath, newCfg.Slides.TesseractPath)
+	check("document_max_size_mb", oldCfg.DocumentMaxSizeMB, newCfg.DocumentMaxSizeMB)
+	check("speech.provider", oldCfg.Speech.Provider, newCfg.Speech.Provider)
+	check("speech.model", oldCfg.Speech.Model, newCfg.Speech.Model)
+	check("speech.format", oldCfg.Speech.Format, newCfg.Speech.Format)
+	check("near_duplicates.provider", oldCfg.NearDuplicates.Provider, newCfg.NearDuplicates.Provider)
+	check("near_duplicates.model", oldCfg.NearDuplicates.Model, newCfg.NearDuplicates.Model)
+	check("store.backend", oldCfg.Store.Backend, newCfg.Store.Backend)
+	check("store.path", oldCfg.Store.Path, newCfg.Store.Path)
+	if !reflect.DeepEqual(oldCfg.Speech.Command, newCfg.Speech.Command) {
+		changed = append(changed, "speech.command")
+	}
+	check("whisper_path", oldCfg.WhisperPath, newCfg.WhisperPath)
+	check("whisper_model_path", oldCfg.WhisperModelPath, newCfg.WhisperModelPath)
+	check("whisper_language", oldCfg.WhisperLanguage, newCfg.WhisperLanguage)
+	check("transcription_routing", oldCfg.TranscriptionRouting, newCfg.TranscriptionRouting)
+	if !reflect.DeepEqual(oldCfg.WhisperModels, newCfg.WhisperModels) {
+		changed = append(changed, "whisper_models")
+	}
+	check("tmp_dir", oldCfg.TmpDir, newCfg.TmpDir)
+	check("tmp_dir_quota_mb", oldCfg.TmpDirQuotaMB, newCfg.TmpDirQuotaMB)
+	check("tmp_sweep_interval", oldCfg.TmpSweepInterval, newCfg.TmpSweepInterval)
+	check("tmp_orphan_min_age", oldCfg.TmpOrphanMinAge, newCfg.TmpOrphanMinAge)
+	check("artifacts_dir", oldCfg.ArtifactsDir, newCfg.ArtifactsDir)
+	check("artifacts_retention", oldCfg.ArtifactsRetention, newCfg.ArtifactsRetention)
+	check("artifacts_encryption_key", oldCfg.ArtifactsEncryptionKey, newCfg.ArtifactsEncryptionKey)
+	check("artifacts_encryption_key_file", oldCfg.ArtifactsEncryptionKeyFile, newCfg.ArtifactsEncryptionKeyFile)
+	check("output_provider", oldCfg.OutputProvider, newCfg.OutputProvider)
+	check("gdrive_auth_method", oldCfg.GDriveAuthMethod, newCfg.GDriveAuthMethod)
+	check("gdrive_credentials_file", oldCfg.GDriveCredentialsFile, newCfg.GDriveCredentialsFile)
+	check("gdrive_token_file", oldCfg.GDriveTokenFile, newCfg.GDriveTokenFile)
+	check("gdrive_folder_id", oldCfg.GDriveFolderID, newCfg.GDriveFolderID)
+	check("local_output_dir", oldCfg.LocalOutputDir, newCfg.LocalOutputDir)
+	check("output_naming", oldCfg.OutputNaming, newCfg.OutputNaming)
+	check("slack_bot_token", oldCfg.SlackBotToken, newCfg.SlackBotToken)
+	check("slack_channel", oldCfg.SlackChannel, newCfg.SlackChannel)
+	check("readwise", oldCfg.Readwise, newCfg.Readwise)
+	check("raindrop", oldCfg.Raindrop, newCfg.Raindrop)
+	check("confluence", oldCfg.Confluence, newCfg.Confluence)
+	if !reflect.DeepEqual(oldCfg.FaultInjection, newCfg.FaultInjection) {
+		changed = append(changed, "fault_injection")
+	}
+	if !reflect.DeepEqual(oldCfg.Preflight, newCfg.Preflight) {
+		changed = append(changed, "preflight")
+	}
+	return changed
+}
+
 // GetStore returns the state store
 func (e *ProcessingEngine) GetStore() interfaces.StateStore {
 	return e.store
@@ -190,6 +575,29 @@ func (e *ProcessingEngine) GetTaskQueue() interfaces.TaskQueue {
 	return e.taskQueue
 }
 
+// enqueue queues a task at the priority of its request
+func (e *ProcessingEngine) enqueue(task *interfaces.Task) {
+	e.store.ViewRequestState(task.RequestID, func(state *interfaces.ProcessingState) {
+		task.Priority = state.Priority
+		task.Tenant = requestTenant(state)
+	})
+	if err := e.taskQueue.Enqueue(task); err != nil {
+		log.Errorf("[Engine] Failed to enqueue %s task for request %s: %v", task.Type, task.RequestID, err)
+	}
+}
+
+// requestTenant identifies who a request belongs to for fair scheduling: its user, else the
+// background source that submitted it. Anonymous API requests share one tenant.
+func requestTenant(state *interfaces.ProcessingState) string {
+	if state.User != "" {
+		return "user:" + state.User
+	}
+	if state.Source != "" {
+		return "source:" + state.Source
+	}
+	return ""
+}
+
 func (e *ProcessingEngine) onVideoProcessingRequested(event interfaces.Event) {
 	log.Debugf("[Engine] Received VideoProcessingRequested event for request: %s", event.RequestID)
 	state, err := e.store.GetRequestState(event.RequestID)
@@ -197,18 +605,36 @@ func (e *ProcessingEngine) onVideoProcessingRequested(event interfaces.Event) {
 		log.Errorf("Could not get state for request: %s", event.RequestID)
 		return
 	}
-	url := state.URL
-	log.Debugf("[Engine] Enqueueing video info task for request: %s, URL: %s", event.RequestID, url)
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-video-%d", event.RequestID, time.Now().UnixNano()),
-		Type:      interfaces.TaskVideoInfo,
-		RequestID: event.RequestID,
-		Data:      map[string]interface{}{"url": url},
-		CreatedAt: time.Now(),
-	})
-	e.store.UpdateRequestState(event.RequestID, map[string]interface{}{
-		"status": interfaces.StatusRunning,
-	})
+	if state.RerunOf != "" && e.startRerun(state) {
+		return
+	}
+	if state.SourceType == interfaces.SourceTypeComparison {
+		e.startComparison(state)
+		return
+	}
+	if e.pipelines.Includes(state.SourceType, interfaces.TaskVideoInfo) && e.resumeFromCheckpoint(state) {
+		return
+	}
+	e.startPipeline(state)
+}
+
+func (e *ProcessingEngine) onTextExtracted(event interfaces.Event) {
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil {
+		log.Errorf("Could not get state for request: %s", event.RequestID)
+		return
+	}
+	e.indexRequest(state)
+	textPath := state.TextPath
+	if textPath == "" {
+		payload, _ := event.Data.(interfaces.TextExtractedPayload)
+		textPath = payload.TextPath
+	}
+	if state.SourceType == interfaces.SourceTypeDocument || state.SourceType == interfaces.SourceTypeArticle {
+		e.keepTranscript(state, textPath)
+	}
+	// Extracted text is summarized exactly like a transcript
+	e.enqueueNextStage(state, interfaces.TaskTextExtraction, textPath)
 }
 
 func (e *ProcessingEngine) onVideoInfoFetched(event interfaces.Event) {
@@ -217,15 +643,33 @@ func (e *ProcessingEngine) onVideoInfoFetched(event interfaces.Event) {
 		log.Errorf("Could not get state for request: %s", event.RequestID)
 		return
 	}
-	url := state.URL
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-audio-%d", event.RequestID, time.Now().UnixNano()),
-		Type:      interfaces.TaskAudioDownload,
-		RequestID: event.RequestID,
-		Data:      map[string]interface{}{"url": url},
-		CreatedAt: time.Now(),
-	})
-	// Optionally update video_info if needed
+	e.checkpointVideoInfo(state)
+	e.indexRequest(state)
+	if e.handleNearDuplicate(state) {
+		return
+	}
+	if tasks.ShouldExtractSlides(e.GetConfig()) {
+		e.enqueue(&interfaces.Task{
+			ID:        fmt.Sprintf("task-%s-slides-%d", event.RequestID, time.Now().UnixNano()),
+			Type:      interfaces.TaskSlides,
+			RequestID: event.RequestID,
+			Data:      map[string]interface{}{"url": state.URL},
+			CreatedAt: time.Now(),
+		})
+		return
+	}
+	e.enqueueNextStage(state, interfaces.TaskVideoInfo, "")
+}
+
+// onSlidesCompleted carries on with the video's pipeline once its on-screen text has been
+// read, or failed to be
+func (e *ProcessingEngine) onSlidesCompleted(event interfaces.Event) {
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil {
+		log.Errorf("Could not get state for request: %s", event.RequestID)
+		return
+	}
+	e.enqueueNextStage(state, interfaces.TaskVideoInfo, "")
 }
 
 func (e *ProcessingEngine) onAudioDownloaded(event interfaces.Event) {
@@ -236,15 +680,14 @@ func (e *ProcessingEngine) onAudioDownloaded(event interfaces.Event) {
 	}
 	audioPath := state.AudioPath
 	if audioPath == "" {
-		audioPath = event.Data["audio_path"].(string)
+		payload, _ := event.Data.(interfaces.AudioDownloadedPayload)
+		audioPath = payload.AudioPath
 	}
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-transcribe-%d", event.RequestID, time.Now().UnixNano()),
-		Type:      interfaces.TaskTranscription,
-		RequestID: event.RequestID,
-		Data:      map[string]interface{}{"audio_path": audioPath},
-		CreatedAt: time.Now(),
-	})
+	if checkpointed := e.checkpointArtifact(state, "audio", audioPath, func(cp *Checkpoint, path string) { cp.AudioPath = path }); checkpointed != audioPath {
+		audioPath = checkpointed
+		e.store.UpdateRequestState(event.RequestID, map[string]interface{}{"audio_path": audioPath})
+	}
+	e.enqueueNextStage(state, interfaces.TaskAudioDownload, audioPath)
 }
 
 func (e *ProcessingEngine) onTranscriptionCompleted(event interfaces.Event) {
@@ -255,15 +698,15 @@ func (e *ProcessingEngine) onTranscriptionCompleted(event interfaces.Event) {
 	}
 	transcriptPath := state.Transcript
 	if transcriptPath == "" {
-		transcriptPath = event.Data["transcript"].(string)
+		payload, _ := event.Data.(interfaces.TranscriptionCompletedPayload)
+		transcriptPath = payload.TranscriptPath
 	}
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-summarize-%d", event.RequestID, time.Now().UnixNano()),
-		Type:      interfaces.TaskSummarization,
-		RequestID: event.RequestID,
-		Data:      map[string]interface{}{"transcript_path": transcriptPath},
-		CreatedAt: time.Now(),
-	})
+	if checkpointed := e.checkpointArtifact(state, "transcript", transcriptPath, func(cp *Checkpoint, path string) { cp.TranscriptPath = path }); checkpointed != transcriptPath {
+		transcriptPath = checkpointed
+		e.store.UpdateRequestState(event.RequestID, map[string]interface{}{"transcript": transcriptPath})
+	}
+	e.keepTranscript(state, transcriptPath)
+	e.enqueueNextStage(state, interfaces.TaskTranscription, transcriptPath)
 }
 
 func (e *ProcessingEngine) onSummarizationCompleted(event interfaces.Event) {
@@ -274,37 +717,196 @@ func (e *ProcessingEngine) onSummarizationCompleted(event interfaces.Event) {
 	}
 	summaryPath := state.Summary
 	if summaryPath == "" {
-		summaryPath = event.Data["summary"].(string)
+		payload, _ := event.Data.(interfaces.SummarizationCompletedPayload)
+		summaryPath = payload.SummaryPath
 	}
+	if checkpointed := e.checkpointArtifact(state, "summary", summaryPath, func(cp *Checkpoint, path string) { cp.SummaryPath = path }); checkpointed != summaryPath {
+		summaryPath = checkpointed
+		e.store.UpdateRequestState(event.RequestID, map[string]interface{}{"summary": summaryPath})
+	}
+	e.indexRequest(state)
 	log.Debugf("onSummarizationCompleted called for request: %s, summaryPath: %v", event.RequestID, summaryPath)
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-output-%d", event.RequestID, time.Now().UnixNano()),
+	if tasks.ShouldExtractHighlights(e.GetConfig(), state) {
+		e.enqueue(&interfaces.Task{
+			ID:        fmt.Sprintf("task-%s-highlights-%d", event.RequestID, time.Now().UnixNano()),
+			Type:      interfaces.TaskHighlights,
+			RequestID: event.RequestID,
+			Data:      map[string]interface{}{"summary_path": summaryPath},
+			CreatedAt: time.Now(),
+		})
+		return
+	}
+	e.enqueueEvaluationOrNext(state, summaryPath)
+}
+
+// onHighlightsCompleted moves on once the key moments have been picked, or failed to be
+func (e *ProcessingEngine) onHighlightsCompleted(event interfaces.Event) {
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil {
+		log.Errorf("Could not get state for request: %s", event.RequestID)
+		return
+	}
+	payload, _ := event.Data.(interfaces.HighlightsCompletedPayload)
+	e.enqueueEvaluationOrNext(state, payload.SummaryPath)
+}
+
+// enqueueEvaluationOrNext queues evaluation when the summary is sampled for it, and
+// redaction or the upload otherwise
+func (e *ProcessingEngine) enqueueEvaluationOrNext(state *interfaces.ProcessingState, summaryPath string) {
+	if !tasks.ShouldEvaluate(e.GetConfig()) {
+		e.enqueueRedactionOrOutput(state, summaryPath)
+		return
+	}
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-evaluate-%d", state.RequestID, time.Now().UnixNano()),
+		Type:      interfaces.TaskEvaluation,
+		RequestID: state.RequestID,
+		Data:      map[string]interface{}{"summary_path": summaryPath},
+		CreatedAt: time.Now(),
+	})
+}
+
+// onEvaluationCompleted moves on once the summary has been scored, or failed to be
+func (e *ProcessingEngine) onEvaluationCompleted(event interfaces.Event) {
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil {
+		log.Errorf("Could not get state for request: %s", event.RequestID)
+		return
+	}
+	payload, _ := event.Data.(interfaces.EvaluationCompletedPayload)
+	e.enqueueRedactionOrOutput(state, payload.SummaryPath)
+}
+
+// onRedactionCompleted uploads the redacted summary, re-indexing the redacted text
+func (e *ProcessingEngine) onRedactionCompleted(event interfaces.Event) {
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil {
+		log.Errorf("Could not get state for request: %s", event.RequestID)
+		return
+	}
+	e.indexRequest(state)
+	payload, _ := event.Data.(interfaces.RedactionCompletedPayload)
+	e.enqueueSpeechOrOutput(event.RequestID, payload.SummaryPath)
+}
+
+// enqueueRedactionOrOutput queues redaction when the request's category has redaction
+// rules, and the upload otherwise
+func (e *ProcessingEngine) enqueueRedactionOrOutput(state *interfaces.ProcessingState, summaryPath string) {
+	if cfg := e.GetConfig(); cfg == nil || !cfg.Redaction.RulesFor(state.Category).Enabled() {
+		e.enqueueSpeechOrOutput(state.RequestID, summaryPath)
+		return
+	}
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-redact-%d", state.RequestID, time.Now().UnixNano()),
+		Type:      interfaces.TaskRedaction,
+		RequestID: state.RequestID,
+		Data:      map[string]interface{}{"summary_path": summaryPath},
+		CreatedAt: time.Now(),
+	})
+}
+
+// enqueueSpeechOrOutput queues reading the final summary aloud when speech is enabled, and
+// the upload otherwise
+func (e *ProcessingEngine) enqueueSpeechOrOutput(requestID, summaryPath string) {
+	if !tasks.ShouldRenderSpeech(e.GetConfig()) {
+		e.enqueueOutput(requestID, summaryPath)
+		return
+	}
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-speech-%d", requestID, time.Now().UnixNano()),
+		Type:      interfaces.TaskSpeech,
+		RequestID: requestID,
+		Data:      map[string]interface{}{"summary_path": summaryPath},
+		CreatedAt: time.Now(),
+	})
+}
+
+// onSpeechCompleted uploads the summary once it has been read aloud, or failed to be
+func (e *ProcessingEngine) onSpeechCompleted(event interfaces.Event) {
+	payload, _ := event.Data.(interfaces.SpeechCompletedPayload)
+	e.enqueueOutput(event.RequestID, payload.SummaryPath)
+}
+
+// enqueueOutput queues the upload of a request's summary
+func (e *ProcessingEngine) enqueueOutput(requestID, summaryPath string) {
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-output-%d", requestID, time.Now().UnixNano()),
 		Type:      interfaces.TaskOutput,
-		RequestID: event.RequestID,
+		RequestID: requestID,
 		Data:      map[string]interface{}{"summary_path": summaryPath},
 		CreatedAt: time.Now(),
 	})
 }
 
+// onOutputCompleted runs the post-processing hooks of a completed request before its files
+// are cleaned up; requests with failed uploads go straight to cleanup
 func (e *ProcessingEngine) onOutputCompleted(event interfaces.Event) {
 	log.Debugf("onOutputCompleted called for request: %s", event.RequestID)
-	e.taskQueue.Enqueue(&interfaces.Task{
-		ID:        fmt.Sprintf("task-%s-cleanup-%d", event.RequestID, time.Now().UnixNano()),
+	payload, _ := event.Data.(interfaces.OutputCompletedPayload)
+	if payload.Status == string(interfaces.StatusCompleted) {
+		state, err := e.store.GetRequestState(event.RequestID)
+		if cfg := e.GetConfig(); err == nil && cfg != nil && len(cfg.HooksFor(state.Category)) > 0 {
+			e.enqueue(&interfaces.Task{
+				ID:        fmt.Sprintf("task-%s-hooks-%d", event.RequestID, time.Now().UnixNano()),
+				Type:      interfaces.TaskHooks,
+				RequestID: event.RequestID,
+				Data:      map[string]interface{}{"summary_path": payload.SummaryPath},
+				CreatedAt: time.Now(),
+			})
+			return
+		}
+	}
+	e.enqueueCleanup(event.RequestID)
+}
+
+// onHooksCompleted cleans up once the post-processing hooks are done with the request's files
+func (e *ProcessingEngine) onHooksCompleted(event interfaces.Event) {
+	log.Debugf("onHooksCompleted called for request: %s", event.RequestID)
+	e.enqueueCleanup(event.RequestID)
+}
+
+// enqueueCleanup queues the removal of a request's temporary files
+func (e *ProcessingEngine) enqueueCleanup(requestID string) {
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-cleanup-%d", requestID, time.Now().UnixNano()),
 		Type:      interfaces.TaskCleanup,
-		RequestID: event.RequestID,
+		RequestID: requestID,
 		Data:      map[string]interface{}{},
 		CreatedAt: time.Now(),
 	})
 }
 
+// publishFailure emits ProcessingFailed when a task failure left its request failed or
+// partially completed
+func (e *ProcessingEngine) publishFailure(task *interfaces.Task) {
+	state, err := e.store.GetRequestState(task.RequestID)
+	if err != nil || (state.Status != interfaces.StatusFailed && state.Status != interfaces.StatusPartiallyCompleted) {
+		return
+	}
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-failed-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeProcessingFailed,
+		Data:      interfaces.ProcessingFailedPayload{Task: string(task.Type), Error: state.Error, ErrorCode: state.ErrorCode},
+		Timestamp: time.Now(),
+	})
+}
+
 // Worker processing logic (real plugins where available)
-func (e *ProcessingEngine) WorkerProcess(task *interfaces.Task) {
-	log.Infof("WorkerProcess called for task: %s, request: %s", task.Type, task.RequestID)
+func (e *ProcessingEngine) WorkerProcess(ctx context.Context, task *interfaces.Task) {
+	ctx = interfaces.WithRequestID(ctx, task.RequestID)
+	log.WithContext(ctx).Infof("WorkerProcess called for task: %s, request: %s", task.Type, task.RequestID)
 
 	// Use task processor
 	if processor, exists := e.taskProcessorRegistry.GetProcessor(task.Type); exists {
-		if err := processor.Process(context.Background(), task, e); err != nil {
-			log.Errorf("Task processor failed for %s: %v", task.Type, err)
+		if err := processor.Process(ctx, task, e); err != nil {
+			log.WithContext(ctx).Errorf("Task processor failed for %s: %v", task.Type, err)
+			// A task the watchdog stopped failed because its work was killed
+			if cause := context.Cause(ctx); errors.Is(cause, interfaces.ErrTaskStuck) {
+				e.handleStuckTask(ctx, task, cause)
+				return
+			}
+			e.publishFailure(task)
 		}
 		return
 	}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// checkpointArtifact moves a stage's output file into the checkpoint directory, records it
+// and returns the path the request should use from now on. With encryption on, transcripts
+// and summaries are stored as an encrypted copy instead and the request keeps its path.
+// Failures are logged and the original path is returned, so checkpointing never breaks
+// processing.
+func (e *ProcessingEngine) checkpointArtifact(state *interfaces.ProcessingState, name, path string, record func(cp *Checkpoint, path string)) string {
+	if e.checkpoints == nil || path == "" {
+		return path
+	}
+	key := CheckpointKey(state)
+	sealed := e.checkpoints.Encrypted() && name != "audio"
+	var storedPath string
+	var err error
+	if sealed {
+		storedPath, err = e.checkpoints.Seal(key, name, path)
+	} else {
+		storedPath, err = e.checkpoints.Adopt(key, name, path)
+	}
+	if err != nil {
+		log.Warnf("[Engine] Failed to checkpoint %s for request %s: %v", name, state.RequestID, err)
+		return path
+	}
+	if err := e.checkpoints.Update(key, func(cp *Checkpoint) {
+		cp.URL = state.URL
+		record(cp, storedPath)
+	}); err != nil {
+		log.Warnf("[Engine] Failed to save checkpoint for request %s: %v", state.RequestID, err)
+	}
+	if sealed {
+		return path
+	}
+	return storedPath
+}
+
+// checkpointVideoInfo records fetched video metadata
+func (e *ProcessingEngine) checkpointVideoInfo(state *interfaces.ProcessingState) {
+	if e.checkpoints == nil || state.VideoInfo == nil {
+		return
+	}
+	if err := e.checkpoints.Update(CheckpointKey(state), func(cp *Checkpoint) {
+		cp.URL = state.URL
+		cp.VideoInfo = state.VideoInfo
+	}); err != nil {
+		log.Warnf("[Engine] Failed to save checkpoint for request %s: %v", state.RequestID, err)
+	}
+}
+
+// resumeFromCheckpoint restores the artifacts of an earlier attempt at the same work and
+// publishes the event for the latest completed stage. It returns false when there is
+// nothing to resume from and the request should start from the beginning.
+func (e *ProcessingEngine) resumeFromCheckpoint(state *interfaces.ProcessingState) bool {
+	if e.checkpoints == nil {
+		return false
+	}
+	cp, ok := e.checkpoints.Load(CheckpointKey(state))
+	if !ok || cp.VideoInfo == nil {
+		return false
+	}
+	// Encrypted artifacts are decrypted into the temp directory for the request to use
+	var decrypted []string
+	for _, path := range []*string{&cp.TranscriptPath, &cp.SummaryPath} {
+		if *path == "" {
+			continue
+		}
+		plainPath, err := e.checkpoints.Unseal(*path, e.GetConfig().TmpDir)
+		if err != nil {
+			log.Warnf("[Engine] Not resuming request %s from its checkpoint: %v", state.RequestID, err)
+			for _, p := range decrypted {
+				os.Remove(p)
+			}
+			return false
+		}
+		if plainPath != *path {
+			decrypted = append(decrypted, plainPath)
+		}
+		*path = plainPath
+	}
+
+	updates := map[string]interface{}{
+		"status":     interfaces.StatusRunning,
+		"video_info": cp.VideoInfo,
+	}
+	var event interfaces.Event
+	var stage string
+	switch {
+	case cp.SummaryPath != "" && cp.TranscriptPath != "":
+		stage = "summary"
+		updates["transcript"] = cp.TranscriptPath
+		updates["summary"] = cp.SummaryPath
+		if summary, err := os.ReadFile(cp.SummaryPath); err == nil {
+			updates["summary_text"] = string(summary)
+		}
+		event = interfaces.Event{Type: interfaces.EventTypeSummarizationCompleted, Data: interfaces.SummarizationCompletedPayload{SummaryPath: cp.SummaryPath}}
+	case cp.TranscriptPath != "":
+		stage = "transcript"
+		updates["transcript"] = cp.TranscriptPath
+		event = interfaces.Event{Type: interfaces.EventTypeTranscriptionCompleted, Data: interfaces.TranscriptionCompletedPayload{TranscriptPath: cp.TranscriptPath}}
+	case cp.AudioPath != "":
+		stage = "audio"
+		updates["audio_path"] = cp.AudioPath
+		event = interfaces.Event{Type: interfaces.EventTypeAudioDownloaded, Data: interfaces.AudioDownloadedPayload{AudioPath: cp.AudioPath}}
+	default:
+		stage = "video info"
+		event = interfaces.Event{Type: interfaces.EventTypeVideoInfoFetched, Data: interfaces.VideoInfoFetchedPayload{VideoInfo: cp.VideoInfo}}
+	}
+
+	if err := e.store.UpdateRequestState(state.RequestID, updates); err != nil {
+		log.Errorf("[Engine] Failed to restore checkpoint for request %s: %v", state.RequestID, err)
+		return false
+	}
+	log.Infof("[Engine] Resuming request %s from %s checkpoint", state.RequestID, stage)
+
+	event.ID = fmt.Sprintf("evt-%s-resume-%d", state.RequestID, time.Now().UnixNano())
+	event.RequestID = state.RequestID
+	event.Timestamp = time.Now()
+	e.eventBus.Publish(event)
+	return true
+}
+
+// onProcessingCompleted drops the checkpoint of a request that completed successfully
+func (e *ProcessingEngine) onProcessingCompleted(event interfaces.Event) {
+	if e.checkpoints == nil {
+		return
+	}
+	state, err := e.store.GetRequestState(event.RequestID)
+	if err != nil || state.Status != interfaces.StatusCompleted {
+		return
+	}
+	if err := e.checkpoints.Delete(CheckpointKey(state)); err != nil {
+		log.Warnf("[Engine] Failed to remove checkpoint for request %s: %v", event.RequestID, err)
+	}
+}
+
+// RetryRequest restarts a failed, cancelled or partially completed request. Stages whose
+// artifacts were checkpointed, or kept by a partially completed request, are not repeated.
+func (e *ProcessingEngine) RetryRequest(requestID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, err := e.store.GetRequestState(requestID)
+	if err != nil {
+		return fmt.Errorf("request not found: %s", requestID)
+	}
+	if state.Status == interfaces.StatusPartiallyCompleted && e.retryFailedStage(state) {
+		return nil
+	}
+	if state.Status != interfaces.StatusFailed && state.Status != interfaces.StatusCancelled && state.Status != interfaces.StatusPartiallyCompleted {
+		return fmt.Errorf("request %s cannot be retried in state: %s", requestID, state.Status)
+	}
+
+	err = e.store.UpdateRequestState(requestID, map[string]interface{}{
+		"status":       interfaces.StatusPending,
+		"error":        "",
+		"error_code":   interfaces.ErrorCode(""),
+		"completed_at": nil,
+		"audio_path":   "",
+		"transcript":   "",
+		"summary":      "",
+		"speech_path":  "",
+		"output_path":  "",
+		"outputs":      nil,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update request state: %w", err)
+	}
+
+	log.Infof("[Engine] Retrying request %s", requestID)
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-retry-%d", requestID, time.Now().UnixNano()),
+		RequestID: requestID,
+		Type:      interfaces.EventTypeVideoProcessingRequested,
+		Data:      interfaces.VideoProcessingRequestedPayload{URL: state.URL, Retry: true},
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// retryFailedStage re-runs only the stage a partially completed request failed at, starting
+// from the latest artifact it kept. It returns false when the kept artifacts are gone and
+// the request has to be retried from the start.
+func (e *ProcessingEngine) retryFailedStage(state *interfaces.ProcessingState) bool {
+	exists := func(path string) bool {
+		if path == "" {
+			return false
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	var event interfaces.Event
+	var stage string
+	switch {
+	case exists(state.Summary):
+		stage = "output"
+		event = interfaces.Event{Type: interfaces.EventTypeSummarizationCompleted, Data: interfaces.SummarizationCompletedPayload{SummaryPath: state.Summary}}
+	case exists(state.Transcript):
+		stage = "summarization"
+		event = interfaces.Event{Type: interfaces.EventTypeTranscriptionCompleted, Data: interfaces.TranscriptionCompletedPayload{TranscriptPath: state.Transcript}}
+	case exists(state.TextPath):
+		stage = "summarization"
+		event = interfaces.Event{Type: interfaces.EventTypeTextExtracted, Data: interfaces.TextExtractedPayload{TextPath: state.TextPath}}
+	default:
+		return false
+	}
+
+	err := e.store.UpdateRequestState(state.RequestID, map[string]interface{}{
+		"status":       interfaces.StatusRunning,
+		"error":        "",
+		"error_code":   interfaces.ErrorCode(""),
+		"completed_at": nil,
+	})
+	if err != nil {
+		log.Errorf("[Engine] Failed to update request %s for retry: %v", state.RequestID, err)
+		return false
+	}
+
+	log.Infof("[Engine] Retrying %s stage of partially completed request %s", stage, state.RequestID)
+	event.ID = fmt.Sprintf("evt-%s-retry-%d", state.RequestID, time.Now().UnixNano())
+	event.RequestID = state.RequestID
+	event.Timestamp = time.Now()
+	e.eventBus.Publish(event)
+	return true
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// minComparedRequests is the fewest finished requests a comparison can be built from
+const minComparedRequests = 2
+
+// startComparison waits for the requests being compared. Once they have all finished, their
+// summaries are combined and the comparison continues at the summarization stage.
+func (e *ProcessingEngine) startComparison(state *interfaces.ProcessingState) {
+	e.store.UpdateRequestState(state.RequestID, map[string]interface{}{
+		"status": interfaces.StatusRunning,
+	})
+	if state.TextPath != "" {
+		if _, err := os.Stat(state.TextPath); err == nil {
+			// Retried after the summaries were combined
+			e.publishTextExtracted(state.RequestID, state.TextPath)
+			return
+		}
+	}
+
+	e.comparisonsMu.Lock()
+	e.pendingComparisons[state.RequestID] = true
+	e.comparisonsMu.Unlock()
+	e.checkComparison(state.RequestID)
+}
+
+// onComparedRequestFinished re-checks the comparisons waiting on a request that just finished
+func (e *ProcessingEngine) onComparedRequestFinished(event interfaces.Event) {
+	e.comparisonsMu.Lock()
+	var waiting []string
+	for parentID := range e.pendingComparisons {
+		waiting = append(waiting, parentID)
+	}
+	e.comparisonsMu.Unlock()
+
+	for _, parentID := range waiting {
+		parent, err := e.store.GetRequestState(parentID)
+		if err != nil {
+			e.comparisonsMu.Lock()
+			delete(e.pendingComparisons, parentID)
+			e.comparisonsMu.Unlock()
+			continue
+		}
+		for _, childID := range parent.ChildIDs {
+			if childID == event.RequestID {
+				e.checkComparison(parentID)
+				break
+			}
+		}
+	}
+}
+
+// checkComparison combines the summaries of the compared requests once none is still running
+func (e *ProcessingEngine) checkComparison(parentID string) {
+	e.comparisonsMu.Lock()
+	pending := e.pendingComparisons[parentID]
+	e.comparisonsMu.Unlock()
+	if !pending {
+		return
+	}
+
+	parent, err := e.store.GetRequestState(parentID)
+	if err != nil || isTerminalStatus(parent.Status) {
+		// Deleted or cancelled while waiting
+		e.comparisonsMu.Lock()
+		delete(e.pendingComparisons, parentID)
+		e.comparisonsMu.Unlock()
+		return
+	}
+
+	var finished []*interfaces.ProcessingState
+	var problems []string
+	for _, childID := range parent.ChildIDs {
+		child, err := e.store.GetRequestState(childID)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: not found", childID))
+			continue
+		}
+		if !isTerminalStatus(child.Status) {
+			return
+		}
+		// Output upload failures still leave a usable summary
+		if child.SummaryText == "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", childID, child.Status))
+			continue
+		}
+		finished = append(finished, child)
+	}
+
+	// Claim the comparison so concurrent checks don't build it twice
+	e.comparisonsMu.Lock()
+	claimed := e.pendingComparisons[parentID]
+	delete(e.pendingComparisons, parentID)
+	e.comparisonsMu.Unlock()
+	if !claimed {
+		return
+	}
+
+	if len(finished) < minComparedRequests {
+		e.failComparison(parentID, fmt.Sprintf("Comparison needs at least %d summarized requests, got %d (%s)",
+			minComparedRequests, len(finished), strings.Join(problems, "; ")))
+		return
+	}
+	if len(problems) > 0 {
+		log.Warnf("[Engine] Comparison %s continues without: %s", parentID, strings.Join(problems, "; "))
+	}
+
+	textPath, err := e.writeComparisonInput(finished)
+	if err != nil {
+		e.failComparison(parentID, err.Error())
+		return
+	}
+
+	titles := make([]string, len(finished))
+	ids := make([]string, len(finished))
+	for i, child := range finished {
+		titles[i] = comparedTitle(child)
+		ids[i] = child.RequestID
+	}
+	err = e.store.UpdateRequestState(parentID, map[string]interface{}{
+		"text_path": textPath,
+		"document_info": map[string]interface{}{
+			"title":       "Comparison: " + strings.Join(titles, " vs "),
+			"items":       len(finished),
+			"request_ids": ids,
+		},
+	})
+	if err != nil {
+		os.Remove(textPath)
+		log.Errorf("[Engine] Failed to update comparison %s: %v", parentID, err)
+		return
+	}
+
+	log.Infof("[Engine] Comparing %d requests for %s", len(finished), parentID)
+	e.publishTextExtracted(parentID, textPath)
+}
+
+// writeComparisonInput writes the summaries being compared to a temp file
+func (e *ProcessingEngine) writeComparisonInput(children []*interfaces.ProcessingState) (string, error) {
+	tmpDir := ""
+	if cfg := e.GetConfig(); cfg != nil {
+		tmpDir = cfg.TmpDir
+	}
+	f, err := os.CreateTemp(tmpDir, "document-comparison-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create comparison file: %v", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for i, child := range children {
+		fmt.Fprintf(&b, "## Source %d: %s\nURL: %s\n\n%s\n\n", i+1, comparedTitle(child), child.URL, strings.TrimSpace(child.SummaryText))
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write comparison file: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// failComparison marks a comparison failed and publishes ProcessingFailed
+func (e *ProcessingEngine) failComparison(parentID, reason string) {
+	log.Errorf("[Engine] Comparison %s failed: %s", parentID, reason)
+	e.store.UpdateRequestState(parentID, map[string]interface{}{
+		"status":       interfaces.StatusFailed,
+		"error":        reason,
+		"error_code":   interfaces.ErrorCodeComparisonFailed,
+		"completed_at": time.Now(),
+	})
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-failed-%d", parentID, time.Now().UnixNano()),
+		RequestID: parentID,
+		Type:      interfaces.EventTypeProcessingFailed,
+		Data:      interfaces.ProcessingFailedPayload{Error: reason, ErrorCode: interfaces.ErrorCodeComparisonFailed},
+		Timestamp: time.Now(),
+	})
+}
+
+// publishTextExtracted sends a request whose text is ready on to summarization
+func (e *ProcessingEngine) publishTextExtracted(requestID, textPath string) {
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-text-%d", requestID, time.Now().UnixNano()),
+		RequestID: requestID,
+		Type:      interfaces.EventTypeTextExtracted,
+		Data:      interfaces.TextExtractedPayload{TextPath: textPath},
+		Timestamp: time.Now(),
+	})
+}
+
+// comparedTitle names a compared request by its title, falling back to its URL
+func comparedTitle(state *interfaces.ProcessingState) string {
+	if title := state.Title(); title != "" {
+		return title
+	}
+	return state.URL
+}
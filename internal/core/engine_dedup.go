@@ -0,0 +1,32 @@
+package core
+
+import (
+	"fmt"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/video"
+)
+
+// FindVideoRequest returns the newest request for the same video as videoURL, with any
+// prompt, that hasn't failed or been cancelled, or nil if there is none. URLs are compared
+// by video, so youtu.be and youtube.com links to one video match.
+func (e *ProcessingEngine) FindVideoRequest(videoURL string) (*interfaces.ProcessingState, error) {
+	states, err := e.store.ListRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	key := video.VideoCacheKey(videoURL)
+	var found *interfaces.ProcessingState
+	for _, state := range states {
+		if state.URL == "" || state.Status == interfaces.StatusFailed || state.Status == interfaces.StatusCancelled {
+			continue
+		}
+		if video.VideoCacheKey(state.URL) != key {
+			continue
+		}
+		if found == nil || state.CreatedAt.After(found.CreatedAt) {
+			found = state
+		}
+	}
+	return found, nil
+}
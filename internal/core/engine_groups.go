@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// AddToGroup adds a request to a group, e.g. when a submission in the group matched an
+// existing request
+func (e *ProcessingEngine) AddToGroup(requestID, group string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state, err := e.store.GetRequestState(requestID)
+	if err != nil {
+		return fmt.Errorf("request not found: %s", requestID)
+	}
+	if state.InGroup(group) {
+		return nil
+	}
+	groups := append(append([]string(nil), state.Groups...), group)
+	return e.store.UpdateRequestState(requestID, map[string]interface{}{"groups": groups})
+}
+
+// GroupRequests returns the requests in a group, oldest first
+func (e *ProcessingEngine) GroupRequests(group string) ([]*interfaces.ProcessingState, error) {
+	states, err := e.store.ListRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	var members []*interfaces.ProcessingState
+	for _, state := range states {
+		if state.InGroup(group) {
+			members = append(members, state)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].CreatedAt.Before(members[j].CreatedAt) })
+	return members, nil
+}
+
+// GetGroupStatus sums up the requests in a group, or returns nil if it has none
+func (e *ProcessingEngine) GetGroupStatus(group string) (*interfaces.GroupStatus, error) {
+	members, err := e.GroupRequests(group)
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+	status := &interfaces.GroupStatus{
+		Group:     group,
+		Total:     len(members),
+		Counts:    make(map[interfaces.ProcessingStatus]int),
+		CreatedAt: members[0].CreatedAt,
+		Requests:  make([]interfaces.GroupItem, 0, len(members)),
+	}
+	for _, state := range members {
+		status.Counts[state.Status]++
+		if isTerminalStatus(state.Status) {
+			status.Finished++
+		}
+		if state.UpdatedAt.After(status.UpdatedAt) {
+			status.UpdatedAt = state.UpdatedAt
+		}
+		status.Requests = append(status.Requests, interfaces.GroupItem{
+			RequestID:   state.RequestID,
+			URL:         state.URL,
+			Title:       state.Title(),
+			Status:      state.Status,
+			Error:       state.Error,
+			ErrorCode:   state.ErrorCode,
+			OutputPath:  state.OutputPath,
+			CompletedAt: state.CompletedAt,
+		})
+	}
+	status.Done = status.Finished == status.Total
+	return status, nil
+}
+
+// CancelGroup cancels every request of a group that hasn't finished, returning the IDs of
+// those it cancelled
+func (e *ProcessingEngine) CancelGroup(group string) ([]string, error) {
+	members, err := e.GroupRequests(group)
+	if err != nil {
+		return nil, err
+	}
+	cancelled := []string{}
+	for _, state := range members {
+		if isTerminalStatus(state.Status) {
+			continue
+		}
+		if err := e.CancelRequest(state.RequestID); err != nil {
+			// It finished in the meantime
+			log.Debugf("[Engine] Not cancelling request %s of group %s: %v", state.RequestID, group, err)
+			continue
+		}
+		cancelled = append(cancelled, state.RequestID)
+	}
+	log.Infof("[Engine] Cancelled %d request(s) of group %s", len(cancelled), group)
+	return cancelled, nil
+}
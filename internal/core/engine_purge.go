@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/core/tasks"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/video"
+)
+
+// PurgeReport lists what a purge removed, and what it could not
+type PurgeReport struct {
+	RequestIDs     []string `json:"request_ids"`
+	FilesRemoved   int      `json:"files_removed"`
+	OutputsDeleted int      `json:"outputs_deleted"`
+	// Uploaded outputs that were left in place, because deletion wasn't asked for, the output
+	// provider can't delete, or deleting failed
+	OutputsKept []string `json:"outputs_kept,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// PurgeVideo removes every request for a video, matched by video ID for YouTube URLs, along
+// with comparisons that include one of them. See purge.
+func (e *ProcessingEngine) PurgeVideo(videoURL string, deleteOutputs bool) (*PurgeReport, error) {
+	key := video.VideoCacheKey(videoURL)
+	if e.videoInfoCache != nil {
+		e.videoInfoCache.Invalidate(videoURL)
+	}
+	return e.purge(func(state *interfaces.ProcessingState) bool {
+		return state.URL != "" && video.VideoCacheKey(state.URL) == key
+	}, deleteOutputs)
+}
+
+// PurgeUser removes every request a user submitted, along with comparisons that include one
+// of them. See purge.
+func (e *ProcessingEngine) PurgeUser(user string, deleteOutputs bool) (*PurgeReport, error) {
+	return e.purge(func(state *interfaces.ProcessingState) bool {
+		return state.User == user
+	}, deleteOutputs)
+}
+
+// purge removes the matching requests and everything derived from them: their state, events
+// and dedup keys, temp and checkpoint files, search index entries and cached responses, and
+// optionally their uploaded outputs. Active requests are cancelled first. Digests that
+// already included a request's summary are not changed.
+func (e *ProcessingEngine) purge(match func(state *interfaces.ProcessingState) bool, deleteOutputs bool) (*PurgeReport, error) {
+	states, err := e.store.ListRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	var purged []*interfaces.ProcessingState
+	ids := make(map[string]bool)
+	for _, state := range states {
+		if match(state) {
+			purged = append(purged, state)
+			ids[state.RequestID] = true
+		}
+	}
+	for _, state := range states {
+		if !ids[state.RequestID] && slices.ContainsFunc(state.ChildIDs, func(id string) bool { return ids[id] }) {
+			purged = append(purged, state)
+			ids[state.RequestID] = true
+		}
+	}
+
+	report := &PurgeReport{RequestIDs: []string{}}
+	for _, state := range purged {
+		if !isTerminalStatus(state.Status) {
+			if err := e.CancelRequest(state.RequestID); err != nil {
+				log.Warnf("[Engine] Failed to cancel request %s before purging it: %v", state.RequestID, err)
+			}
+		}
+		e.purgeRequest(state, deleteOutputs, report)
+		report.RequestIDs = append(report.RequestIDs, state.RequestID)
+	}
+
+	if len(purged) > 0 && e.llmCache != nil {
+		e.llmCache.Clear()
+	}
+	log.Infof("[Engine] Purged %d requests, %d files and %d outputs", len(report.RequestIDs), report.FilesRemoved, report.OutputsDeleted)
+	return report, nil
+}
+
+// purgeRequest removes one request's files, outputs and state, adding to report
+func (e *ProcessingEngine) purgeRequest(state *interfaces.ProcessingState, deleteOutputs bool, report *PurgeReport) {
+	requestID := state.RequestID
+	for _, path := range []string{state.AudioPath, state.Transcript, state.Summary, state.InfoPath, state.ThumbnailPath, state.SegmentsPath, state.TextPath, state.SpeechPath, state.SlidesPath} {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			report.FilesRemoved++
+		} else if !os.IsNotExist(err) {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", requestID, err))
+		}
+	}
+	if e.checkpoints != nil {
+		for _, key := range []string{CheckpointKey(state), TranscriptKey(state.URL)} {
+			if err := e.checkpoints.Delete(key); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: checkpoint: %v", requestID, err))
+			}
+		}
+	}
+
+	if state.Status == interfaces.StatusCompleted || state.Status == interfaces.StatusPartiallyCompleted {
+		e.purgeOutputs(state, deleteOutputs, report)
+	}
+
+	e.searchIndex.Remove(requestID)
+	e.nearDuplicates.Remove(requestID)
+	if err := e.store.DeleteRequestState(requestID); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", requestID, err))
+	}
+}
+
+// purgeOutputs deletes a request's uploaded outputs, at each of its output providers, if
+// asked to and the provider can
+func (e *ProcessingEngine) purgeOutputs(state *interfaces.ProcessingState, deleteOutputs bool, report *PurgeReport) {
+	providers, _ := tasks.ResolveOutputProviders(state, e)
+	for _, resolved := range providers {
+		e.purgeProviderOutputs(state, resolved.Provider, resolved.Name, deleteOutputs, report)
+	}
+}
+
+func (e *ProcessingEngine) purgeProviderOutputs(state *interfaces.ProcessingState, provider interfaces.OutputProvider, name string, deleteOutputs bool, report *PurgeReport) {
+	deletable, ok := provider.(interfaces.DeletableOutputProvider)
+	switch {
+	case !deleteOutputs:
+		report.OutputsKept = append(report.OutputsKept, fmt.Sprintf("%s: %s outputs (deletion not requested)", state.RequestID, name))
+		return
+	case !ok:
+		report.OutputsKept = append(report.OutputsKept, fmt.Sprintf("%s: %s outputs (%s can't delete; remove them manually)", state.RequestID, name, name))
+		return
+	}
+
+	videoInfo := state.VideoInfo
+	if videoInfo == nil {
+		videoInfo = state.DocumentInfo
+	}
+	category, user := state.Category, state.User
+	if category == "" {
+		category = "general"
+	}
+	if user == "" {
+		user = "admin"
+	}
+	if err := deletable.DeleteOutputs(state.RequestID, videoInfo, category, user); err != nil {
+		report.OutputsKept = append(report.OutputsKept, fmt.Sprintf("%s: %s outputs (%v)", state.RequestID, name, err))
+		return
+	}
+	report.OutputsDeleted++
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// rebuildTimeTolerance is how far apart a stored and a rebuilt time may be and still match;
+// stages stamp the state a moment before they publish their event
+const rebuildTimeTolerance = 5 * time.Second
+
+// StateRebuild is a request's state reconstructed from its event log
+type StateRebuild struct {
+	RequestID string `json:"request_id"`
+	Events    int    `json:"events"`
+	// Stored state with the fields the events determine replaced by their rebuilt values
+	State *interfaces.ProcessingState `json:"state"`
+	// Fields where the stored state disagrees with the events
+	Drift   []string `json:"drift,omitempty"`
+	Applied bool     `json:"applied"`
+}
+
+// rebuiltFields are the state fields events determine, by their UpdateRequestState key
+var rebuiltFields = map[string]func(*interfaces.ProcessingState) interface{}{
+	"status":      func(s *interfaces.ProcessingState) interface{} { return s.Status },
+	"url":         func(s *interfaces.ProcessingState) interface{} { return s.URL },
+	"video_info":  func(s *interfaces.ProcessingState) interface{} { return s.VideoInfo },
+	"audio_path":  func(s *interfaces.ProcessingState) interface{} { return s.AudioPath },
+	"slides_path": func(s *interfaces.ProcessingState) interface{} { return s.SlidesPath },
+	"transcript":  func(s *interfaces.ProcessingState) interface{} { return s.Transcript },
+	"text_path":   func(s *interfaces.ProcessingState) interface{} { return s.TextPath },
+	"summary":     func(s *interfaces.ProcessingState) interface{} { return s.Summary },
+	"speech_path": func(s *interfaces.ProcessingState) interface{} { return s.SpeechPath },
+	"error":       func(s *interfaces.ProcessingState) interface{} { return s.Error },
+	"error_code":  func(s *interfaces.ProcessingState) interface{} { return s.ErrorCode },
+	"completed_at": func(s *interfaces.ProcessingState) interface{} {
+		if s.CompletedAt == nil {
+			return nil
+		}
+		return *s.CompletedAt
+	},
+}
+
+// RebuildState folds a request's events, oldest first, over a copy of its stored state. Fields
+// no event speaks to, such as the prompt or a rerun's copied transcript, keep their stored
+// values, as do artifact paths whose files no longer exist.
+func RebuildState(stored *interfaces.ProcessingState, events []interfaces.Event) *interfaces.ProcessingState {
+	state := *stored
+	// Keep the status the request was created with, which has no event
+	state.StatusHistory = nil
+	if len(stored.StatusHistory) > 0 {
+		state.StatusHistory = stored.StatusHistory[:1:1]
+	}
+	for _, event := range events {
+		applyEvent(&state, event)
+	}
+	return &state
+}
+
+// applyEvent applies one event to a state being rebuilt
+func applyEvent(state *interfaces.ProcessingState, event interfaces.Event) {
+	setStatus := func(status interfaces.ProcessingStatus) {
+		state.Status = status
+		if n := len(state.StatusHistory); n == 0 || state.StatusHistory[n-1].Status != status {
+			state.StatusHistory = append(state.StatusHistory, interfaces.StatusChange{Status: status, At: event.Timestamp})
+		}
+	}
+	setArtifact := func(field *string, path string) {
+		if path == "" {
+			return
+		}
+		if _, err := os.Stat(path); err == nil {
+			*field = path
+		}
+	}
+	// A request that starts again, or is re-driven by a replay, is running and no longer failed
+	restart := func() {
+		setStatus(interfaces.StatusRunning)
+		state.Error = ""
+		state.ErrorCode = ""
+		state.CompletedAt = nil
+	}
+	if event.ReplayOf != "" {
+		restart()
+	}
+	if event.Type != interfaces.EventTypeRequestCancelled && !isTerminalStatus(state.Status) && state.Status != interfaces.StatusRunning {
+		setStatus(interfaces.StatusRunning)
+	}
+	state.UpdatedAt = event.Timestamp
+
+	switch payload := event.Data.(type) {
+	case interfaces.VideoProcessingRequestedPayload:
+		restart()
+		if payload.URL != "" {
+			state.URL = payload.URL
+		}
+	case interfaces.VideoInfoFetchedPayload:
+		if payload.VideoInfo != nil {
+			state.VideoInfo = interfaces.TrimVideoInfo(payload.VideoInfo)
+		}
+	case interfaces.AudioDownloadedPayload:
+		setArtifact(&state.AudioPath, payload.AudioPath)
+	case interfaces.SlidesCompletedPayload:
+		setArtifact(&state.SlidesPath, payload.SlidesPath)
+	case interfaces.TranscriptionCompletedPayload:
+		setArtifact(&state.Transcript, payload.TranscriptPath)
+	case interfaces.TextExtractedPayload:
+		setArtifact(&state.TextPath, payload.TextPath)
+	case interfaces.SummarizationCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+	case interfaces.HighlightsCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+	case interfaces.EvaluationCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+	case interfaces.RedactionCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+	case interfaces.SpeechCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+		setArtifact(&state.SpeechPath, payload.SpeechPath)
+	case interfaces.OutputCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+		if payload.Status != "" {
+			setStatus(interfaces.ProcessingStatus(payload.Status))
+		}
+	case interfaces.HooksCompletedPayload:
+		setArtifact(&state.Summary, payload.SummaryPath)
+	case interfaces.ProcessingCompletedPayload:
+		if payload.Status != "" {
+			setStatus(interfaces.ProcessingStatus(payload.Status))
+		}
+		completedAt := event.Timestamp
+		state.CompletedAt = &completedAt
+	case interfaces.ProcessingFailedPayload:
+		// Failures after a partial upload leave the request partially completed
+		if state.Status != interfaces.StatusPartiallyCompleted {
+			setStatus(interfaces.StatusFailed)
+		}
+		state.Error = payload.Error
+		state.ErrorCode = payload.ErrorCode
+		completedAt := event.Timestamp
+		state.CompletedAt = &completedAt
+	case interfaces.RequestCancelledPayload:
+		setStatus(interfaces.StatusCancelled)
+		completedAt := payload.CancelledAt
+		if completedAt.IsZero() {
+			completedAt = event.Timestamp
+		}
+		state.CompletedAt = &completedAt
+	}
+}
+
+// stateDrift returns the rebuilt fields where two states disagree, sorted
+func stateDrift(stored, rebuilt *interfaces.ProcessingState) []string {
+	var drift []string
+	for field, get := range rebuiltFields {
+		if !rebuiltValuesMatch(get(stored), get(rebuilt)) {
+			drift = append(drift, field)
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+func rebuiltValuesMatch(a, b interface{}) bool {
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Sub(bt).Abs() <= rebuildTimeTolerance
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// RebuildRequestState reconstructs a request's state from its stored events and reports where
+// the stored state disagrees, e.g. when a state update was lost or only partly applied.
+// With apply, the fields that disagree are overwritten with their rebuilt values. Nothing is
+// re-run; replay the request to continue its processing.
+func (e *ProcessingEngine) RebuildRequestState(requestID string, apply bool) (*StateRebuild, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stored, err := e.store.GetRequestState(requestID)
+	if err != nil {
+		return nil, fmt.Errorf("request not found: %s", requestID)
+	}
+	events, err := e.store.GetEventsForRequest(requestID)
+	if err != nil || len(events) == 0 {
+		return nil, fmt.Errorf("request %s has no stored events", requestID)
+	}
+
+	rebuilt := RebuildState(stored, events)
+	result := &StateRebuild{
+		RequestID: requestID,
+		Events:    len(events),
+		State:     rebuilt,
+		Drift:     stateDrift(stored, rebuilt),
+	}
+	if !apply || len(result.Drift) == 0 {
+		return result, nil
+	}
+
+	updates := make(map[string]interface{}, len(result.Drift))
+	for _, field := range result.Drift {
+		updates[field] = rebuiltFields[field](rebuilt)
+	}
+	if err := e.store.UpdateRequestState(requestID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update request state: %w", err)
+	}
+	result.Applied = true
+	log.Infof("[Engine] Rebuilt state of request %s from %d events, updating %v", requestID, len(events), result.Drift)
+	return result, nil
+}
+
+// RebuildAllRequestStates rebuilds every stored request with events and returns those whose
+// stored state disagrees with their events, applying the rebuilt values with apply
+func (e *ProcessingEngine) RebuildAllRequestStates(apply bool) ([]*StateRebuild, error) {
+	states, err := e.store.ListRequests()
+	if err != nil {
+		return nil, err
+	}
+	var drifted []*StateRebuild
+	for _, state := range states {
+		result, err := e.RebuildRequestState(state.RequestID, apply)
+		if err != nil {
+			// Requests without events, e.g. scheduled ones, have nothing to rebuild
+			continue
+		}
+		if len(result.Drift) > 0 {
+			drifted = append(drifted, result)
+		}
+	}
+	sort.Slice(drifted, func(i, j int) bool { return drifted[i].RequestID < drifted[j].RequestID })
+	return drifted, nil
+}
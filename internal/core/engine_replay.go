@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// replayableEvents are the pipeline events a request can be re-driven from. Terminal
+// events are left out so replays don't re-send notifications for an old outcome.
+var replayableEvents = map[interfaces.EventType]bool{
+	interfaces.EventTypeVideoProcessingRequested: true,
+	interfaces.EventTypeVideoInfoFetched:         true,
+	interfaces.EventTypeSlidesCompleted:          true,
+	interfaces.EventTypeAudioDownloaded:          true,
+	interfaces.EventTypeTextExtracted:            true,
+	interfaces.EventTypeTranscriptionCompleted:   true,
+	interfaces.EventTypeSummarizationCompleted:   true,
+	interfaces.EventTypeOutputCompleted:          true,
+}
+
+// replayArtifact returns the state field and path of the stage artifact an event names, if any
+func replayArtifact(payload interfaces.EventPayload) (field, path string) {
+	switch p := payload.(type) {
+	case interfaces.AudioDownloadedPayload:
+		return "audio_path", p.AudioPath
+	case interfaces.SlidesCompletedPayload:
+		return "slides_path", p.SlidesPath
+	case interfaces.TranscriptionCompletedPayload:
+		return "transcript", p.TranscriptPath
+	case interfaces.SummarizationCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.HighlightsCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.EvaluationCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.RedactionCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.SpeechCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.OutputCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.HooksCompletedPayload:
+		return "summary", p.SummaryPath
+	case interfaces.TextExtractedPayload:
+		return "text_path", p.TextPath
+	}
+	return "", ""
+}
+
+// eventLog records every published event in the state store, so a request's event
+// history can be inspected and replayed
+type eventLog struct {
+	interfaces.EventBus
+	store interfaces.StateStore
+}
+
+// Publish stores the event and then delivers it
+func (b *eventLog) Publish(event interfaces.Event) error {
+	if err := b.store.LogEvent(event); err != nil {
+		log.Warnf("[Engine] Failed to log event %s for request %s: %v", event.Type, event.RequestID, err)
+	}
+	return b.EventBus.Publish(event)
+}
+
+// GetRequestEvents returns the stored events of a request, oldest first
+func (e *ProcessingEngine) GetRequestEvents(requestID string) ([]interfaces.Event, error) {
+	if _, err := e.store.GetRequestState(requestID); err != nil {
+		return nil, fmt.Errorf("request not found: %s", requestID)
+	}
+	events, err := e.store.GetEventsForRequest(requestID)
+	if err != nil {
+		return []interfaces.Event{}, nil
+	}
+	return events, nil
+}
+
+// ReplayRequest re-publishes one of a request's stored events so processing continues from
+// that point, e.g. after a fix for a handler bug is deployed. With an empty fromEventID the
+// latest replayable event is used. Requests still pending or running are only replayed
+// with force, since their original processing may still be in flight.
+func (e *ProcessingEngine) ReplayRequest(requestID, fromEventID string, force bool) (*interfaces.Event, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, err := e.store.GetRequestState(requestID)
+	if err != nil {
+		return nil, fmt.Errorf("request not found: %s", requestID)
+	}
+	if !force && !isTerminalStatus(state.Status) {
+		return nil, fmt.Errorf("request %s is still %s (use force to replay anyway)", requestID, state.Status)
+	}
+
+	events, _ := e.store.GetEventsForRequest(requestID)
+	var from *interfaces.Event
+	for i := len(events) - 1; i >= 0; i-- {
+		if (fromEventID == "" && replayableEvents[events[i].Type]) || events[i].ID == fromEventID {
+			from = &events[i]
+			break
+		}
+	}
+	if from == nil {
+		if fromEventID != "" {
+			return nil, fmt.Errorf("event %s not found for request %s", fromEventID, requestID)
+		}
+		return nil, fmt.Errorf("request %s has no replayable events", requestID)
+	}
+	if !replayableEvents[from.Type] {
+		return nil, fmt.Errorf("%s events cannot be replayed", from.Type)
+	}
+
+	// Stage handlers read artifacts from the request state, so point it back at the
+	// artifacts the event refers to
+	updates := map[string]interface{}{
+		"status":       interfaces.StatusRunning,
+		"error":        "",
+		"error_code":   interfaces.ErrorCode(""),
+		"completed_at": nil,
+	}
+	if field, path := replayArtifact(from.Data); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("artifact %s of event %s no longer exists (retry the request instead)", path, from.ID)
+		}
+		updates[field] = path
+	}
+	if err := e.store.UpdateRequestState(requestID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update request state: %w", err)
+	}
+
+	replay := interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-replay-%d", requestID, time.Now().UnixNano()),
+		RequestID: requestID,
+		Type:      from.Type,
+		Data:      from.Data,
+		Timestamp: time.Now(),
+		ReplayOf:  from.ID,
+	}
+	log.Infof("[Engine] Replaying %s event %s for request %s", from.Type, from.ID, requestID)
+	e.eventBus.Publish(replay)
+	return &replay, nil
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ErrTranscriptUnavailable is returned when a request's transcript is gone and can't be rerun
+var ErrTranscriptUnavailable = errors.New("transcript is no longer available")
+
+// keepTranscript stores a copy of a finished transcript or extracted text under its URL, so
+// a rerun can summarize it after the request's own files are cleaned up. Kept transcripts
+// are removed after artifacts_retention like checkpoints.
+func (e *ProcessingEngine) keepTranscript(state *interfaces.ProcessingState, path string) {
+	if e.checkpoints == nil || path == "" || state.URL == "" {
+		return
+	}
+	key := TranscriptKey(state.URL)
+	keptPath, err := e.checkpoints.Keep(key, "transcript", path)
+	if err != nil {
+		log.Warnf("[Engine] Failed to keep the transcript of request %s for reruns: %v", state.RequestID, err)
+		return
+	}
+	if err := e.checkpoints.Update(key, func(cp *Checkpoint) {
+		cp.URL = state.URL
+		cp.TranscriptPath = keptPath
+	}); err != nil {
+		log.Warnf("[Engine] Failed to keep the transcript of request %s for reruns: %v", state.RequestID, err)
+	}
+}
+
+// CopyTranscript writes a new temp copy of a request's transcript (extracted text for
+// source types that aren't transcribed) for a rerun to summarize, and returns its path. It is copied from
+// the request's own file while that exists, otherwise from the kept transcript.
+func (e *ProcessingEngine) CopyTranscript(state *interfaces.ProcessingState) (string, error) {
+	source := state.Transcript
+	if !e.pipelines.Includes(state.SourceType, interfaces.TaskTranscription) {
+		source = state.TextPath
+	}
+	tmpDir := e.GetConfig().TmpDir
+	if source != "" {
+		if _, err := os.Stat(source); err == nil {
+			return copyToTemp(source, tmpDir)
+		}
+	}
+
+	if e.checkpoints != nil {
+		if cp, ok := e.checkpoints.Load(TranscriptKey(state.URL)); ok && cp.TranscriptPath != "" {
+			plainPath, err := e.checkpoints.Unseal(cp.TranscriptPath, tmpDir)
+			if err != nil {
+				return "", err
+			}
+			if plainPath != cp.TranscriptPath {
+				return plainPath, nil
+			}
+			return copyToTemp(plainPath, tmpDir)
+		}
+	}
+	if e.checkpoints == nil {
+		return "", fmt.Errorf("%w for request %s (set artifacts_dir to keep transcripts for reruns)", ErrTranscriptUnavailable, state.RequestID)
+	}
+	return "", fmt.Errorf("%w for request %s", ErrTranscriptUnavailable, state.RequestID)
+}
+
+// copyToTemp copies a file to a new file in tmpDir with the same extension
+func copyToTemp(path, tmpDir string) (string, error) {
+	f, err := os.CreateTemp(tmpDir, "rerun-*"+filepath.Ext(path))
+	if err != nil {
+		return "", err
+	}
+	f.Close()
+	if err := copyFile(path, f.Name()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// startRerun continues a rerun at the summarization stage with the transcript copied for it.
+// If the copy is gone, e.g. when a failed rerun is retried after cleanup, the request is
+// processed from the start.
+func (e *ProcessingEngine) startRerun(state *interfaces.ProcessingState) bool {
+	path := state.Transcript
+	text := !e.pipelines.Includes(state.SourceType, interfaces.TaskTranscription)
+	if text {
+		path = state.TextPath
+	}
+	if path == "" {
+		return false
+	}
+	if _, err := os.Stat(path); err != nil {
+		log.Infof("[Engine] Transcript of rerun %s is gone; processing it from the start", state.RequestID)
+		return false
+	}
+
+	e.store.UpdateRequestState(state.RequestID, map[string]interface{}{
+		"status": interfaces.StatusRunning,
+	})
+	log.Infof("[Engine] Rerunning request %s as %s, from its transcript", state.RerunOf, state.RequestID)
+	if text {
+		e.publishTextExtracted(state.RequestID, path)
+		return true
+	}
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-rerun-%d", state.RequestID, time.Now().UnixNano()),
+		RequestID: state.RequestID,
+		Type:      interfaces.EventTypeTranscriptionCompleted,
+		Data:      interfaces.TranscriptionCompletedPayload{TranscriptPath: path},
+		Timestamp: time.Now(),
+	})
+	return true
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// RetentionReport counts what a retention sweep removed
+type RetentionReport struct {
+	RequestStates int `json:"request_states"`
+	Events        int `json:"events"`
+	Transcripts   int `json:"transcripts"`
+	Summaries     int `json:"summaries"`
+}
+
+// EnforceRetention removes data older than the retention configured for its class. It runs
+// with the temp directory sweep and reads the config each time, so reloaded settings apply
+// on the next sweep.
+func (e *ProcessingEngine) EnforceRetention(now time.Time) RetentionReport {
+	var report RetentionReport
+	cfg := e.GetConfig()
+	if cfg == nil {
+		return report
+	}
+	retention := cfg.Retention
+
+	if d := retention.GetRequestStates(); d > 0 {
+		removed, err := e.store.CleanupOldRequests(now.Add(-d))
+		if err != nil {
+			log.Warnf("[Engine] Failed to remove requests past retention: %v", err)
+		}
+		report.RequestStates = removed
+	}
+	if d := retention.GetEvents(); d > 0 {
+		report.Events = e.store.PruneEvents(now.Add(-d))
+	}
+	if d := retention.GetTranscripts(); d > 0 {
+		report.Transcripts = e.pruneOutputs("transcript.txt", now.Add(-d))
+	}
+	if d := retention.GetSummaries(); d > 0 {
+		report.Summaries = e.pruneOutputs("summary.txt", now.Add(-d))
+	}
+
+	if report != (RetentionReport{}) {
+		log.Infof("[Engine] Retention removed %d requests, %d events, %d transcripts and %d summaries",
+			report.RequestStates, report.Events, report.Transcripts, report.Summaries)
+	}
+	return report
+}
+
+// pruneOutputs removes output files with the suffix from the output providers that support it
+func (e *ProcessingEngine) pruneOutputs(suffix string, olderThan time.Time) int {
+	removed := 0
+	for name, provider := range e.outputProviders {
+		prunable, ok := provider.(interfaces.PrunableOutputProvider)
+		if !ok {
+			continue
+		}
+		n, err := prunable.PruneOutputs(suffix, olderThan)
+		if err != nil {
+			log.Warnf("[Engine] Failed to remove %s outputs past retention from %s: %v", suffix, name, err)
+		}
+		removed += n
+	}
+	return removed
+}
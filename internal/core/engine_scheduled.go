@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ScheduleRequest stores a request that waits in scheduled status until
+// ReleaseScheduledRequest starts it, at or after state.NotBefore. A request without a
+// NotBefore is held until its source has room.
+func (e *ProcessingEngine) ScheduleRequest(state *interfaces.ProcessingState) error {
+	state.Status = interfaces.StatusScheduled
+	if err := e.store.SaveRequestState(state.RequestID, state); err != nil {
+		return err
+	}
+	if state.NotBefore == nil {
+		log.Infof("[Engine] Holding request %s until source %s has room", state.RequestID, state.Source)
+		return nil
+	}
+	log.Infof("[Engine] Scheduled request %s for %s", state.RequestID, state.NotBefore.Format(time.RFC3339))
+	return nil
+}
+
+// DueScheduledRequests returns the scheduled requests whose not-before time is at or before
+// now, earliest first; held requests without one are ordered by when they were created
+func (e *ProcessingEngine) DueScheduledRequests(now time.Time) ([]*interfaces.ProcessingState, error) {
+	states, err := e.store.ListRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	var due []*interfaces.ProcessingState
+	for _, state := range states {
+		if state.Status == interfaces.StatusScheduled && (state.NotBefore == nil || !state.NotBefore.After(now)) {
+			due = append(due, state)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return releaseTime(due[i]).Before(releaseTime(due[j]))
+	})
+	return due, nil
+}
+
+// releaseTime is when a scheduled request became due
+func releaseTime(state *interfaces.ProcessingState) time.Time {
+	if state.NotBefore != nil {
+		return *state.NotBefore
+	}
+	return state.CreatedAt
+}
+
+// ReleaseScheduledRequest moves a scheduled request into the pipeline. It reports false when
+// the request is no longer scheduled, e.g. because it was cancelled.
+func (e *ProcessingEngine) ReleaseScheduledRequest(requestID string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state, err := e.store.GetRequestState(requestID)
+	if err != nil {
+		return false, fmt.Errorf("request not found: %s", requestID)
+	}
+	if state.Status != interfaces.StatusScheduled {
+		return false, nil
+	}
+	if err := e.store.UpdateRequestState(requestID, map[string]interface{}{"status": interfaces.StatusPending}); err != nil {
+		return false, fmt.Errorf("failed to update request state: %w", err)
+	}
+
+	log.Infof("[Engine] Starting scheduled request %s", requestID)
+	e.eventBus.Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-%d", requestID, time.Now().UnixNano()),
+		RequestID: requestID,
+		Type:      interfaces.EventTypeVideoProcessingRequested,
+		Data:      interfaces.VideoProcessingRequestedPayload{URL: state.URL},
+		Timestamp: time.Now(),
+	})
+	return true, nil
+}
@@ -1,35 +1,93 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
 	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/article"
+	"video-summarizer-go/internal/providers/document"
+	"video-summarizer-go/internal/providers/embedding"
+	"video-summarizer-go/internal/providers/faults"
 	"video-summarizer-go/internal/providers/output"
+	"video-summarizer-go/internal/providers/slides"
+	"video-summarizer-go/internal/providers/speech"
 	"video-summarizer-go/internal/providers/summarization"
 	"video-summarizer-go/internal/providers/transcription"
 	"video-summarizer-go/internal/providers/video"
 )
 
+// EngineOptions allows callers to replace the providers built from config.
+// Nil fields fall back to the configured provider.
+type EngineOptions struct {
+	VideoProvider         interfaces.VideoProvider
+	TranscriptionProvider interfaces.TranscriptionProvider
+	SummarizationProvider interfaces.SummarizationProvider
+	OutputProvider        interfaces.OutputProvider
+	DocumentProvider      interfaces.DocumentProvider
+	ArticleProvider       interfaces.DocumentProvider
+	SlideProvider         interfaces.SlideProvider
+	SpeechProvider        interfaces.SpeechProvider
+	EmbeddingProvider     interfaces.EmbeddingProvider
+}
+
 // SetupEngine wires up the event bus, state store, task queue, worker pool, providers, and processing engine.
 // Returns the engine, worker pool, and prompt manager.
 func SetupEngine(appCfg *config.AppConfig) (*ProcessingEngine, *WorkerPool, *config.PromptManager, error) {
-	store := NewInMemoryStore()
-	eventBus := NewInMemoryEventBus()
-	taskQueue := NewInMemoryTaskQueue()
+	return SetupEngineWithOptions(appCfg, EngineOptions{})
+}
 
-	concurrencyLimits := map[interfaces.TaskType]int{
-		interfaces.TaskVideoInfo:     appCfg.Concurrency["video_info"],
-		interfaces.TaskTranscription: appCfg.Concurrency["transcription"],
-		interfaces.TaskSummarization: appCfg.Concurrency["summarization"],
-		interfaces.TaskOutput:        appCfg.Concurrency["output"],
-		interfaces.TaskCleanup:       appCfg.Concurrency["cleanup"],
-		interfaces.TaskAudioDownload: appCfg.Concurrency["audio_download"],
+// SetupEngineWithOptions is SetupEngine with optional provider overrides
+func SetupEngineWithOptions(appCfg *config.AppConfig, opts EngineOptions) (*ProcessingEngine, *WorkerPool, *config.PromptManager, error) {
+	store, err := newStateStore(appCfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	eventBus := NewInMemoryEventBus()
+	taskQueue, err := newTaskQueue(appCfg)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	workerPool := NewWorkerPool(taskQueue, concurrencyLimits, nil)
+	workerPool := NewWorkerPool(taskQueue, concurrencyLimitsFromConfig(appCfg), nil)
 
-	videoProvider := video.NewYtDlpVideoProvider(appCfg.YtDlpPath, appCfg.TmpDir)
-	transcriptionProvider := transcription.NewWhisperCppTranscriptionProvider(appCfg.WhisperPath, appCfg.WhisperModelPath)
+	ytDlp := video.NewYtDlpVideoProvider(appCfg.YtDlpPath, appCfg.TmpDir)
+	if appCfg.YtDlpUserAgent != "" {
+		ytDlp.UserAgent = appCfg.YtDlpUserAgent
+	}
+	ytDlp.ExtraArgs = appCfg.GetYtDlpArgs()
+	ytDlp.SetLimitRate(appCfg.GetDownloadRateLimit())
+	var videoProvider interfaces.VideoProvider = ytDlp
+	if opts.VideoProvider != nil {
+		videoProvider = opts.VideoProvider
+		ytDlp = nil
+	}
+	whisper := transcription.NewWhisperCppTranscriptionProvider(appCfg.WhisperPath, appCfg.WhisperModelPath, appCfg.TmpDir)
+	whisper.Language = appCfg.WhisperLanguage
+	for _, model := range appCfg.WhisperModels.Models {
+		whisper.Models = append(whisper.Models, transcription.WhisperModel{Name: model.Name, Path: model.Path, MaxDuration: model.GetMaxDuration()})
+	}
+	var transcriptionProvider interfaces.TranscriptionProvider = whisper
+	if routing := appCfg.TranscriptionRouting; routing.Enabled {
+		cloud := transcription.NewOpenAITranscriptionProvider(appCfg.OpenAIKey, routing.CloudModel, appCfg.TmpDir)
+		cloud.Language = appCfg.WhisperLanguage
+		transcriptionProvider = &transcription.RoutingTranscriptionProvider{
+			Local:        whisper,
+			Cloud:        cloud,
+			Threshold:    routing.GetThreshold(),
+			ShortRoute:   routing.ShortVideos,
+			LongRoute:    routing.LongVideos,
+			UnknownRoute: routing.UnknownDuration,
+		}
+		log.Infof("Routing transcription by video length: up to %s %s, longer %s", routing.GetThreshold(), routing.ShortVideos, routing.LongVideos)
+	}
+	if opts.TranscriptionProvider != nil {
+		transcriptionProvider = opts.TranscriptionProvider
+	}
 
 	// Initialize prompt manager
 	promptManager := config.NewPromptManager()
@@ -41,19 +99,131 @@ func SetupEngine(appCfg *config.AppConfig) (*ProcessingEngine, *WorkerPool, *con
 		return nil, nil, nil, fmt.Errorf("failed to load prompts: %w", err)
 	}
 
-	summarizationProvider, err := summarization.NewConfigurableSummarizationProviderFromConfig(appCfg)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to create summarization provider: %w", err)
+	summarizationProvider := opts.SummarizationProvider
+	if summarizationProvider == nil {
+		var err error
+		summarizationProvider, err = summarization.NewConfigurableSummarizationProviderFromConfig(appCfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create summarization provider: %w", err)
+		}
 	}
 
-	var outputProvider interfaces.OutputProvider
-	if appCfg.OutputProvider == "gdrive" {
-		outputProvider, err = output.NewGDriveOutputProvider(appCfg)
+	outputProvider := opts.OutputProvider
+	if outputProvider == nil && (appCfg.OutputProvider == "gdrive" || appCfg.OutputProvider == "slack" || appCfg.OutputProvider == "discord" || appCfg.OutputProvider == "local" || appCfg.OutputProvider == "gsheets" || appCfg.OutputProvider == "readwise" || appCfg.OutputProvider == "raindrop" || appCfg.OutputProvider == "confluence") {
+		var err error
+		outputProvider, err = output.NewOutputProviderFromConfig(appCfg)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("failed to create output provider: %w", err)
 		}
 	}
 
+	documentProvider := opts.DocumentProvider
+	if documentProvider == nil {
+		documentProvider = document.NewFileDocumentProvider(appCfg.PdfToTextPath, appCfg.TmpDir, int64(appCfg.DocumentMaxSizeMB)*1024*1024)
+	}
+	articleProvider := opts.ArticleProvider
+	if articleProvider == nil {
+		articleProvider = article.NewWebArticleProvider(appCfg.TmpDir, int64(appCfg.DocumentMaxSizeMB)*1024*1024)
+	}
+
+	// Requests can override the output with any registered provider
+	outputProviders := make(map[string]interfaces.OutputProvider)
+	if outputProvider != nil {
+		outputProviders[appCfg.OutputProvider] = outputProvider
+	}
+	if _, ok := outputProviders["slack"]; !ok && appCfg.SlackBotToken != "" {
+		slack := output.NewSlackOutputProvider(appCfg.SlackBotToken, appCfg.SlackChannel)
+		slack.ShowThumbnail = appCfg.UploadThumbnail
+		outputProviders["slack"] = slack
+	}
+	if _, ok := outputProviders["discord"]; !ok && appCfg.DiscordBotToken != "" {
+		outputProviders["discord"] = output.NewDiscordOutputProvider(appCfg.DiscordBotToken, appCfg.DiscordChannel)
+	}
+	if _, ok := outputProviders["gsheets"]; !ok && appCfg.GSheets.SpreadsheetID != "" {
+		sheet, err := output.NewGSheetsOutputProvider(appCfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create gsheets output provider: %w", err)
+		}
+		outputProviders["gsheets"] = sheet
+	}
+	if _, ok := outputProviders["readwise"]; !ok && appCfg.Readwise.Token != "" {
+		outputProviders["readwise"] = output.NewReadwiseOutputProvider(appCfg.Readwise.Token, appCfg.Readwise.Mode)
+	}
+	if _, ok := outputProviders["raindrop"]; !ok && appCfg.Raindrop.Token != "" {
+		outputProviders["raindrop"] = output.NewRaindropOutputProvider(appCfg.Raindrop.Token, appCfg.Raindrop.CollectionID)
+	}
+	if _, ok := outputProviders["confluence"]; !ok && appCfg.Confluence.Token != "" {
+		outputProviders["confluence"] = output.NewConfluenceOutputProvider(appCfg.Confluence)
+	}
+	if err := checkOutputRoutes(appCfg.OutputRoutes, outputProviders); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if appCfg.FaultInjection.Enabled {
+		log.Warn("Fault injection is enabled: providers will fail and slow down on purpose")
+		wrapped := faults.Wrap(appCfg.FaultInjection, faults.Providers{
+			Video:         videoProvider,
+			Transcription: transcriptionProvider,
+			Summarization: summarizationProvider,
+			Document:      documentProvider,
+			Article:       articleProvider,
+			Outputs:       outputProviders,
+		})
+		videoProvider = wrapped.Video
+		transcriptionProvider = wrapped.Transcription
+		summarizationProvider = wrapped.Summarization
+		documentProvider = wrapped.Document
+		articleProvider = wrapped.Article
+		if outputProvider != nil {
+			outputProvider = outputProviders[appCfg.OutputProvider]
+		}
+	}
+
+	// Host limits apply to real calls only, so cache hits are never held back
+	hostLimits := video.NewHostLimitedVideoProvider(videoProvider, appCfg.HostLimits.MaxConcurrent, appCfg.HostLimits.GetMinInterval())
+	videoProvider = hostLimits
+
+	// Slide OCR downloads the video with yt-dlp, under the same host limits
+	slideProvider := opts.SlideProvider
+	if slideProvider == nil && ytDlp != nil {
+		download := func(ctx context.Context, url string, maxHeight int) (string, error) {
+			var path string
+			err := hostLimits.Do(ctx, url, func() (err error) {
+				path, err = ytDlp.DownloadVideo(ctx, url, maxHeight)
+				return err
+			})
+			return path, err
+		}
+		slideProvider = slides.NewOCRSlideProvider(download, appCfg.Slides.FfmpegPath, appCfg.Slides.TesseractPath, appCfg.TmpDir)
+	}
+
+	speechProvider := opts.SpeechProvider
+	if speechProvider == nil {
+		speechProvider = speech.NewSpeechProviderFromConfig(appCfg)
+	}
+
+	embeddingProvider := opts.EmbeddingProvider
+	if embeddingProvider == nil {
+		embeddingProvider = embedding.NewEmbeddingProviderFromConfig(appCfg)
+	}
+
+	// Cache hits skip the video provider, including any injected faults
+	var videoInfoCache *video.CachingVideoProvider
+	if ttl := appCfg.GetVideoInfoCacheTTL(); ttl > 0 {
+		videoInfoCache = video.NewCachingVideoProvider(videoProvider, ttl, appCfg.VideoInfoCacheSize)
+		videoProvider = videoInfoCache
+	}
+
+	// The preflight calls the summarization provider itself, never a cached response
+	preflightSummarizer := summarizationProvider
+
+	// Cache hits skip the summarization provider and its token spend
+	var llmCache *summarization.CachingSummarizationProvider
+	if ttl := appCfg.GetLLMCacheTTL(); ttl > 0 {
+		llmCache = summarization.NewCachingSummarizationProvider(summarizationProvider, llmCacheIdentity(appCfg), appCfg.TmpDir, ttl, appCfg.LLMCacheSize)
+		summarizationProvider = llmCache
+	}
+
 	engine := NewProcessingEngine(
 		store,
 		eventBus,
@@ -66,7 +236,180 @@ func SetupEngine(appCfg *config.AppConfig) (*ProcessingEngine, *WorkerPool, *con
 		outputProvider,
 		promptManager,
 	)
+	engine.config = appCfg
+	engine.videoInfoCache = videoInfoCache
+	engine.llmCache = llmCache
+	engine.ytDlp = ytDlp
+	engine.hostLimits = hostLimits
+	engine.outputProviders = outputProviders
+	engine.documentProvider = documentProvider
+	engine.articleProvider = articleProvider
+	engine.slideProvider = slideProvider
+	engine.speechProvider = speechProvider
+	engine.embeddingProvider = embeddingProvider
 	workerPool.SetProcessFunc(engine.WorkerProcess)
+	// Requests restored from a bolt store are searchable and matched as near-duplicates again
+	engine.indexStoredRequests()
+
+	// Track temp directory usage; audio and video downloads wait while it is over quota
+	engine.tmpDirManager = NewTmpDirManager(appCfg.TmpDir, int64(appCfg.TmpDirQuotaMB)*1024*1024,
+		appCfg.GetTmpSweepInterval(), appCfg.GetTmpOrphanMinAge(), store)
+	underQuota := func() bool {
+		return !engine.tmpDirManager.OverQuota()
+	}
+	workerPool.SetGate(interfaces.TaskAudioDownload, underQuota)
+	workerPool.SetGate(interfaces.TaskSlides, underQuota)
+
+	// Remove data past its retention period along with orphaned temp files
+	engine.tmpDirManager.AddSweepHook(func() {
+		engine.EnforceRetention(time.Now())
+	})
+
+	// Self-test the providers at startup; the service isn't ready until it passes
+	if appCfg.Preflight.Enabled {
+		engine.preflight = NewPreflight(appCfg.Preflight, transcriptionProvider, preflightSummarizer, outputProviders, appCfg.TmpDir)
+	}
+
+	// Checkpoint stage artifacts so retries and resubmissions resume where they left off
+	if appCfg.ArtifactsDir != "" {
+		checkpoints, err := NewCheckpointStore(appCfg.ArtifactsDir)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		key, err := appCfg.GetArtifactsEncryptionKey()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if key != nil {
+			if err := checkpoints.EnableEncryption(key); err != nil {
+				return nil, nil, nil, err
+			}
+			log.Infof("Checkpointed transcripts and summaries are encrypted at rest")
+		}
+		engine.checkpoints = checkpoints
+		retention := appCfg.GetArtifactsRetention()
+		engine.tmpDirManager.AddSweepHook(func() {
+			checkpoints.Prune(time.Now().Add(-retention))
+		})
+	}
 
 	return engine, workerPool, promptManager, nil
 }
+
+// concurrencyLimitsFromConfig maps the concurrency section of the config to per-task worker limits
+// checkOutputRoutes checks every provider output_routes names is registered and, when the
+// route sets a channel or folder, can send there
+func checkOutputRoutes(routes map[string][]config.OutputRoute, outputProviders map[string]interfaces.OutputProvider) error {
+	for category, targets := range routes {
+		for _, route := range targets {
+			provider, ok := outputProviders[route.Provider]
+			if !ok {
+				return fmt.Errorf("output_routes.%s: output provider %q is not configured", category, route.Provider)
+			}
+			if route.Channel == "" && route.FolderID == "" {
+				continue
+			}
+			if _, ok := provider.(interfaces.DestinationOutputProvider); !ok {
+				return fmt.Errorf("output_routes.%s: output provider %q does not support a channel or folder", category, route.Provider)
+			}
+		}
+	}
+	return nil
+}
+
+func concurrencyLimitsFromConfig(appCfg *config.AppConfig) map[interfaces.TaskType]int {
+	// Configs written before document support have no text_extraction entry
+	textExtraction := appCfg.Concurrency["text_extraction"]
+	if textExtraction == 0 {
+		textExtraction = 1
+	}
+	// nor, before the optional stages, slides, highlights, evaluation, redaction, speech and hooks entries
+	slidesLimit := appCfg.Concurrency["slides"]
+	if slidesLimit == 0 {
+		slidesLimit = 1
+	}
+	highlights := appCfg.Concurrency["highlights"]
+	if highlights == 0 {
+		highlights = 1
+	}
+	evaluation := appCfg.Concurrency["evaluation"]
+	if evaluation == 0 {
+		evaluation = 1
+	}
+	redaction := appCfg.Concurrency["redaction"]
+	if redaction == 0 {
+		redaction = 1
+	}
+	speechLimit := appCfg.Concurrency["speech"]
+	if speechLimit == 0 {
+		speechLimit = 1
+	}
+	hooks := appCfg.Concurrency["hooks"]
+	if hooks == 0 {
+		hooks = 1
+	}
+	return map[interfaces.TaskType]int{
+		interfaces.TaskTextExtraction: textExtraction,
+		interfaces.TaskHooks:          hooks,
+		interfaces.TaskSlides:         slidesLimit,
+		interfaces.TaskHighlights:     highlights,
+		interfaces.TaskEvaluation:     evaluation,
+		interfaces.TaskRedaction:      redaction,
+		interfaces.TaskSpeech:         speechLimit,
+		interfaces.TaskVideoInfo:      appCfg.Concurrency["video_info"],
+		interfaces.TaskTranscription:  appCfg.Concurrency["transcription"],
+		interfaces.TaskSummarization:  appCfg.Concurrency["summarization"],
+		interfaces.TaskOutput:         appCfg.Concurrency["output"],
+		interfaces.TaskCleanup:        appCfg.Concurrency["cleanup"],
+		interfaces.TaskAudioDownload:  appCfg.Concurrency["audio_download"],
+	}
+}
+
+// llmCacheIdentity names the summarization model and the settings that shape its responses,
+// so cached responses are never reused after switching either
+func llmCacheIdentity(appCfg *config.AppConfig) string {
+	switch appCfg.SummarizerProvider {
+	case "stub":
+		return fmt.Sprintf("stub sentences=%d", appCfg.StubSummarySentences)
+	case "text":
+		return fmt.Sprintf("text sentences=%d", appCfg.TextSummarySentences)
+	}
+	return fmt.Sprintf("%s/%s max_tokens=%d", appCfg.SummarizerProvider, appCfg.OpenAIModel, appCfg.OpenAIMaxTokens)
+}
+
+// newStateStore creates the configured state store. Requests a bolt store held as pending
+// or running are failed as interrupted unless their tasks wait on a broker.
+func newStateStore(appCfg *config.AppConfig) (interfaces.StateStore, error) {
+	if appCfg.Store.Backend != config.StoreBolt {
+		return NewInMemoryStoreWithLimits(appCfg.Store.MaxRequests, appCfg.Store.MaxEventsPerRequest), nil
+	}
+	store, err := NewBoltStateStore(appCfg.Store.Path, appCfg.Store.MaxRequests, appCfg.Store.MaxEventsPerRequest)
+	if err != nil {
+		return nil, err
+	}
+	if appCfg.TaskQueue.Backend != config.TaskQueueKafka {
+		if failed := store.FailInterruptedRequests(); failed > 0 {
+			log.Warnf("Marked %d request(s) interrupted by the restart as failed; retry them with /api/retry", failed)
+		}
+	}
+	return store, nil
+}
+
+// newTaskQueue creates the configured task queue
+func newTaskQueue(appCfg *config.AppConfig) (interfaces.TaskQueue, error) {
+	if appCfg.TaskQueue.Backend == config.TaskQueueKafka {
+		log.Infof("Queueing tasks on kafka brokers %v, consumer group %s", appCfg.TaskQueue.Kafka.Brokers, appCfg.TaskQueue.Kafka.GroupID)
+		queue, err := NewKafkaTaskQueue(appCfg.TaskQueue.Kafka)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*kafkaSessionTimeout)
+		defer cancel()
+		if err := queue.CheckSoleConsumer(ctx); err != nil {
+			queue.Close()
+			return nil, err
+		}
+		return queue, nil
+	}
+	return NewInMemoryTaskQueue(), nil
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// watchdogTimeout returns how long a task of the given type may go without a heartbeat under
+// the current config, so reloaded timeouts apply to tasks already running
+func (e *ProcessingEngine) watchdogTimeout(taskType interfaces.TaskType) time.Duration {
+	cfg := e.GetConfig()
+	if cfg == nil {
+		return 0
+	}
+	return cfg.Watchdog.GetTimeout(string(taskType))
+}
+
+// GetRunningTasks returns the tasks the workers are processing, longest running first
+func (e *ProcessingEngine) GetRunningTasks() []RunningTask {
+	if e.workerPool == nil {
+		return nil
+	}
+	return e.workerPool.RunningTasks()
+}
+
+// handleStuckTask runs a task the watchdog stopped again, replacing the failure it recorded
+// when its work was killed, or fails its request with a timeout once the task has had
+// max_attempts runs. Cancelled requests are left as they are.
+func (e *ProcessingEngine) handleStuckTask(ctx context.Context, task *interfaces.Task, cause error) {
+	state, err := e.store.GetRequestState(task.RequestID)
+	if err != nil || state.Status == interfaces.StatusCancelled {
+		return
+	}
+
+	maxAttempts := 1
+	if cfg := e.GetConfig(); cfg != nil {
+		maxAttempts = cfg.Watchdog.MaxAttempts
+	}
+	attempt := task.Attempts + 1
+	if attempt < maxAttempts {
+		log.WithContext(ctx).Warnf("Requeueing %s task of request %s (run %d of %d): %v", task.Type, task.RequestID, attempt+1, maxAttempts, cause)
+		e.store.UpdateRequestState(task.RequestID, map[string]interface{}{
+			"status":       interfaces.StatusRunning,
+			"error":        "",
+			"error_code":   interfaces.ErrorCode(""),
+			"completed_at": nil,
+		})
+		e.enqueue(&interfaces.Task{
+			ID:        fmt.Sprintf("task-%s-%s-%d", task.RequestID, task.Type, time.Now().UnixNano()),
+			Type:      task.Type,
+			RequestID: task.RequestID,
+			Data:      task.Data,
+			CreatedAt: time.Now(),
+			Attempts:  attempt,
+		})
+		return
+	}
+
+	reason := fmt.Sprintf("%s task timed out after %d run(s): %v", task.Type, attempt, cause)
+	log.WithContext(ctx).Errorf("Request %s failed: %s", task.RequestID, reason)
+	updates := map[string]interface{}{
+		"error":      reason,
+		"error_code": interfaces.ErrorCodeTimeout,
+	}
+	// Keep a partially completed request's summary; anything else fails
+	if state.Status != interfaces.StatusPartiallyCompleted {
+		updates["status"] = interfaces.StatusFailed
+		updates["completed_at"] = time.Now()
+	}
+	e.store.UpdateRequestState(task.RequestID, updates)
+	e.publishFailure(task)
+}
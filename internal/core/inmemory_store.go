@@ -1,11 +1,17 @@
 package core
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
+	log "github.com/sirupsen/logrus"
+
 	"video-summarizer-go/internal/interfaces"
 )
 
@@ -14,16 +20,96 @@ type InMemoryStateStore struct {
 	events   map[string][]interfaces.Event // keyed by requestID
 	dedup    map[string]string             // dedupKey -> requestID
 	mu       sync.RWMutex
+
+	// LRU bookkeeping: front of lru is the most recently used request
+	lru                 *list.List
+	lruIndex            map[string]*list.Element
+	maxRequests         int // <= 0 means unlimited
+	maxEventsPerRequest int // <= 0 means unlimited
+	evictions           int
+
+	// Estimated LLM spend (USD) per local day ("2006-01-02"), recorded as token usage is stored
+	spend map[string]float64
+
+	// onRemove, if set, is called with the write lock held for each request removed by
+	// eviction, cleanup or deletion
+	onRemove func(requestID string)
 }
 
 func NewInMemoryStore() *InMemoryStateStore {
+	return NewInMemoryStoreWithLimits(0, 0)
+}
+
+// NewInMemoryStoreWithLimits creates a store that keeps at most maxRequests requests by evicting the
+// least recently used terminal requests, and at most maxEventsPerRequest events per request.
+// A limit of 0 or less disables it.
+func NewInMemoryStoreWithLimits(maxRequests, maxEventsPerRequest int) *InMemoryStateStore {
 	return &InMemoryStateStore{
-		requests: make(map[string]*interfaces.ProcessingState),
-		events:   make(map[string][]interfaces.Event),
-		dedup:    make(map[string]string),
+		requests:            make(map[string]*interfaces.ProcessingState),
+		events:              make(map[string][]interfaces.Event),
+		dedup:               make(map[string]string),
+		lru:                 list.New(),
+		lruIndex:            make(map[string]*list.Element),
+		maxRequests:         maxRequests,
+		maxEventsPerRequest: maxEventsPerRequest,
+		spend:               make(map[string]float64),
+	}
+}
+
+// touch marks a request as most recently used. Caller must hold the write lock.
+func (s *InMemoryStateStore) touch(requestID string) {
+	if elem, ok := s.lruIndex[requestID]; ok {
+		s.lru.MoveToFront(elem)
+		return
+	}
+	s.lruIndex[requestID] = s.lru.PushFront(requestID)
+}
+
+// removeLocked deletes a request and everything attached to it. Caller must hold the write lock.
+func (s *InMemoryStateStore) removeLocked(requestID string) {
+	delete(s.requests, requestID)
+	delete(s.events, requestID)
+	if elem, ok := s.lruIndex[requestID]; ok {
+		s.lru.Remove(elem)
+		delete(s.lruIndex, requestID)
+	}
+	for key, id := range s.dedup {
+		if id == requestID {
+			delete(s.dedup, key)
+		}
+	}
+	if s.onRemove != nil {
+		s.onRemove(requestID)
+	}
+}
+
+// evictLocked removes least recently used terminal requests until the store is within its cap.
+// Active requests are never evicted. Caller must hold the write lock.
+func (s *InMemoryStateStore) evictLocked() {
+	if s.maxRequests <= 0 || len(s.requests) <= s.maxRequests {
+		return
+	}
+	for elem := s.lru.Back(); elem != nil && len(s.requests) > s.maxRequests; {
+		prev := elem.Prev()
+		requestID := elem.Value.(string)
+		if state, ok := s.requests[requestID]; !ok || isTerminalStatus(state.Status) {
+			s.removeLocked(requestID)
+			s.evictions++
+			log.Debugf("Evicted request %s from state store", requestID)
+		}
+		elem = prev
+	}
+	if len(s.requests) > s.maxRequests {
+		log.Warnf("State store holds %d requests (cap %d) but the rest are still active", len(s.requests), s.maxRequests)
 	}
 }
 
+// isTerminalStatus reports whether a request has reached a final status
+func isTerminalStatus(status interfaces.ProcessingStatus) bool {
+	return status == interfaces.StatusCompleted || status == interfaces.StatusCancelled || status == interfaces.StatusFailed ||
+		status == interfaces.StatusPartiallyCompleted
+}
+
 // MakeDedupKey creates a unique key for deduplication
 func MakeDedupKey(resource, promptID, model string) string {
 	return fmt.Sprintf("%s|%s|%s", resource, promptID, model)
@@ -44,21 +130,79 @@ func (s *InMemoryStateStore) AddDedupKey(dedupKey, requestID string) {
 	s.dedup[dedupKey] = requestID
 }
 
+// maxStatusHistory is how many status changes are kept per request; retries add more
+const maxStatusHistory = 50
+
+// recordStatusLocked appends a status change unless it is the current status. Caller must
+// hold the write lock.
+func recordStatusLocked(state *interfaces.ProcessingState, status interfaces.ProcessingStatus, at time.Time) {
+	if n := len(state.StatusHistory); n > 0 && state.StatusHistory[n-1].Status == status {
+		return
+	}
+	state.StatusHistory = append(state.StatusHistory, interfaces.StatusChange{Status: status, At: at})
+	if len(state.StatusHistory) > maxStatusHistory {
+		state.StatusHistory = append([]interfaces.StatusChange(nil), state.StatusHistory[len(state.StatusHistory)-maxStatusHistory:]...)
+	}
+}
+
 func (s *InMemoryStateStore) SaveRequestState(requestID string, state *interfaces.ProcessingState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.requests[requestID] = state
+	if state.Status != "" {
+		at := state.CreatedAt
+		if at.IsZero() {
+			at = time.Now()
+		}
+		recordStatusLocked(state, state.Status, at)
+	}
+	// Keep a copy, so the caller can go on using state
+	s.requests[requestID] = state.Clone()
+	s.touch(requestID)
+	s.evictLocked()
 	return nil
 }
 
+// GetRequestState returns a copy of a request, which later updates don't change
 func (s *InMemoryStateStore) GetRequestState(requestID string) (*interfaces.ProcessingState, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	state, ok := s.requests[requestID]
 	if !ok {
 		return nil, errors.New("request not found")
 	}
-	return state, nil
+	s.touch(requestID)
+	return state.Clone(), nil
+}
+
+// ViewRequestState calls view with a request while holding the lock, so no update lands
+// while it reads, without copying the request. view must not change the request, keep it
+// or call the store.
+func (s *InMemoryStateStore) ViewRequestState(requestID string, view func(state *interfaces.ProcessingState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.requests[requestID]
+	if !ok {
+		return errors.New("request not found")
+	}
+	s.touch(requestID)
+	view(state)
+	return nil
+}
+
+// GetRequestStates copies the requests under one lock, so no update lands between them
+func (s *InMemoryStateStore) GetRequestStates(requestIDs []string) map[string]*interfaces.ProcessingState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make(map[string]*interfaces.ProcessingState, len(requestIDs))
+	for _, requestID := range requestIDs {
+		state, ok := s.requests[requestID]
+		if !ok {
+			continue
+		}
+		states[requestID] = state.Clone()
+		s.touch(requestID)
+	}
+	return states
 }
 
 func (s *InMemoryStateStore) UpdateRequestState(requestID string, updates map[string]interface{}) error {
@@ -76,9 +220,18 @@ func (s *InMemoryStateStore) UpdateRequestState(requestID string, updates map[st
 			} else if val, ok := v.(string); ok {
 				state.Status = interfaces.ProcessingStatus(val)
 			}
+			recordStatusLocked(state, state.Status, time.Now())
 		case "video_info":
 			if val, ok := v.(map[string]interface{}); ok {
-				state.VideoInfo = val
+				state.VideoInfo = interfaces.CloneInfo(val)
+			}
+		case "info_path":
+			if val, ok := v.(string); ok {
+				state.InfoPath = val
+			}
+		case "thumbnail_path":
+			if val, ok := v.(string); ok {
+				state.ThumbnailPath = val
 			}
 		case "audio_path":
 			if val, ok := v.(string); ok {
@@ -88,21 +241,102 @@ func (s *InMemoryStateStore) UpdateRequestState(requestID string, updates map[st
 			if val, ok := v.(string); ok {
 				state.Transcript = val
 			}
+		case "transcript_quality":
+			if val, ok := v.(interfaces.TranscriptQuality); ok {
+				state.TranscriptQuality = &val
+			} else if v == nil {
+				state.TranscriptQuality = nil
+			}
 		case "summary":
 			if val, ok := v.(string); ok {
 				state.Summary = val
 			}
+		case "summary_text":
+			if val, ok := v.(string); ok {
+				state.SummaryText = val
+			}
+		case "partial_summary":
+			if val, ok := v.(string); ok {
+				state.PartialSummary = val
+			}
+		case "token_usage":
+			if val, ok := v.(interfaces.TokenUsage); ok {
+				state.TokenUsage = &val
+				s.recordSpendLocked(val.CostUSD)
+			}
+		case "segments_path":
+			if val, ok := v.(string); ok {
+				state.SegmentsPath = val
+			}
+		case "slides_path":
+			if val, ok := v.(string); ok {
+				state.SlidesPath = val
+			}
+		case "speech_path":
+			if val, ok := v.(string); ok {
+				state.SpeechPath = val
+			}
+		case "highlights":
+			if val, ok := v.(interfaces.Highlights); ok {
+				val.Moments = slices.Clone(val.Moments)
+				state.Highlights = &val
+				if val.TokenUsage != nil {
+					s.recordSpendLocked(val.TokenUsage.CostUSD)
+				}
+			}
+		case "evaluation":
+			if val, ok := v.(interfaces.SummaryEvaluation); ok {
+				state.Evaluation = &val
+				if val.TokenUsage != nil {
+					s.recordSpendLocked(val.TokenUsage.CostUSD)
+				}
+			} else if v == nil {
+				state.Evaluation = nil
+			}
+		case "redactions":
+			if val, ok := v.(map[string]int); ok {
+				state.Redactions = maps.Clone(val)
+			}
+		case "language":
+			if val, ok := v.(string); ok {
+				state.Language = val
+			}
+		case "whisper_model":
+			if val, ok := v.(string); ok {
+				state.WhisperModel = val
+			}
+		case "transcription_route":
+			if val, ok := v.(string); ok {
+				state.TranscriptionRoute = val
+			}
+		case "hooks":
+			if val, ok := v.([]interfaces.HookResult); ok {
+				state.Hooks = slices.Clone(val)
+			}
 		case "error":
 			if val, ok := v.(string); ok {
 				state.Error = val
 			}
+		case "error_code":
+			if val, ok := v.(interfaces.ErrorCode); ok {
+				state.ErrorCode = val
+				state.ErrorRetryable = val.Retryable()
+			}
 		case "output_path":
 			if val, ok := v.(string); ok {
 				state.OutputPath = val
 			}
+		case "outputs":
+			if val, ok := v.([]interfaces.OutputFile); ok {
+				state.Outputs = slices.Clone(val)
+			} else if v == nil {
+				state.Outputs = nil
+			}
 		case "completed_at":
 			if val, ok := v.(time.Time); ok {
 				state.CompletedAt = &val
+			} else if v == nil {
+				state.CompletedAt = nil
 			}
 		case "source_type":
 			if val, ok := v.(string); ok {
@@ -114,30 +348,44 @@ func (s *InMemoryStateStore) UpdateRequestState(requestID string, updates map[st
 			}
 		case "document_info":
 			if val, ok := v.(map[string]interface{}); ok {
-				state.DocumentInfo = val
+				state.DocumentInfo = interfaces.CloneInfo(val)
 			}
 		case "text_path":
 			if val, ok := v.(string); ok {
 				state.TextPath = val
 			}
+		case "groups":
+			if val, ok := v.([]string); ok {
+				state.Groups = slices.Clone(val)
+			}
+		case "duplicate_of":
+			if val, ok := v.(string); ok {
+				state.DuplicateOf = val
+			}
 		}
 	}
 	state.UpdatedAt = time.Now()
+	s.touch(requestID)
+	s.evictLocked()
 	return nil
 }
 
 func (s *InMemoryStateStore) DeleteRequestState(requestID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.requests, requestID)
-	delete(s.events, requestID)
+	s.removeLocked(requestID)
 	return nil
 }
 
 func (s *InMemoryStateStore) LogEvent(event interfaces.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.events[event.RequestID] = append(s.events[event.RequestID], event)
+	events := append(s.events[event.RequestID], event)
+	if s.maxEventsPerRequest > 0 && len(events) > s.maxEventsPerRequest {
+		// Keep the most recent events
+		events = append([]interfaces.Event(nil), events[len(events)-s.maxEventsPerRequest:]...)
+	}
+	s.events[event.RequestID] = events
 	return nil
 }
 
@@ -151,28 +399,124 @@ func (s *InMemoryStateStore) GetEventsForRequest(requestID string) ([]interfaces
 	return events, nil
 }
 
+// ListRequests returns copies of all stored requests, oldest first
+func (s *InMemoryStateStore) ListRequests() ([]*interfaces.ProcessingState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	requests := make([]*interfaces.ProcessingState, 0, len(s.requests))
+	for _, state := range s.requests {
+		requests = append(requests, state.Clone())
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests, nil
+}
+
 func (s *InMemoryStateStore) GetAllActiveRequests() ([]*interfaces.ProcessingState, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	var active []*interfaces.ProcessingState
 	for _, state := range s.requests {
-		if state.Status != interfaces.StatusCompleted && state.Status != interfaces.StatusCancelled && state.Status != interfaces.StatusFailed {
-			active = append(active, state)
+		if !isTerminalStatus(state.Status) {
+			active = append(active, state.Clone())
 		}
 	}
 	return active, nil
 }
 
-func (s *InMemoryStateStore) CleanupOldRequests(olderThan time.Time) error {
+func (s *InMemoryStateStore) CleanupOldRequests(olderThan time.Time) (int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	removed := 0
 	for id, state := range s.requests {
-		if (state.Status == interfaces.StatusCompleted || state.Status == interfaces.StatusCancelled || state.Status == interfaces.StatusFailed) && state.UpdatedAt.Before(olderThan) {
-			delete(s.requests, id)
+		if isTerminalStatus(state.Status) && state.UpdatedAt.Before(olderThan) {
+			s.removeLocked(id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// PruneEvents drops events older than olderThan from finished requests. Active requests keep
+// their whole history, since replay and resume read it.
+func (s *InMemoryStateStore) PruneEvents(olderThan time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dropped := 0
+	for id, events := range s.events {
+		if state, ok := s.requests[id]; ok && !isTerminalStatus(state.Status) {
+			continue
+		}
+		// Readers may hold the old slice, so build a new one
+		var kept []interfaces.Event
+		for _, event := range events {
+			if event.Timestamp.Before(olderThan) {
+				dropped++
+				continue
+			}
+			kept = append(kept, event)
+		}
+		switch {
+		case len(kept) == len(events):
+		case len(kept) == 0:
 			delete(s.events, id)
+		default:
+			s.events[id] = kept
 		}
 	}
-	return nil
+	return dropped
+}
+
+// spendRetentionDays is how many days of spend are kept, enough for a monthly budget
+const spendRetentionDays = 62
+
+// recordSpendLocked adds to today's spend and drops days past retention. Caller must hold
+// the write lock.
+func (s *InMemoryStateStore) recordSpendLocked(costUSD float64) {
+	if costUSD <= 0 {
+		return
+	}
+	now := time.Now()
+	s.spend[now.Format("2006-01-02")] += costUSD
+	oldest := now.AddDate(0, 0, -spendRetentionDays).Format("2006-01-02")
+	for day := range s.spend {
+		if day < oldest {
+			delete(s.spend, day)
+		}
+	}
+}
+
+// GetSpendSince returns the spend recorded since the start of from's local day
+func (s *InMemoryStateStore) GetSpendSince(from time.Time) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	first := from.Local().Format("2006-01-02")
+	total := 0.0
+	for day, cost := range s.spend {
+		if day >= first {
+			total += cost
+		}
+	}
+	return total
+}
+
+// GetStoreStats reports how much the store currently holds
+func (s *InMemoryStateStore) GetStoreStats() interfaces.StoreStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := 0
+	for _, requestEvents := range s.events {
+		events += len(requestEvents)
+	}
+	return interfaces.StoreStats{
+		Requests:            len(s.requests),
+		Events:              events,
+		DedupKeys:           len(s.dedup),
+		Evictions:           s.evictions,
+		MaxRequests:         s.maxRequests,
+		MaxEventsPerRequest: s.maxEventsPerRequest,
+	}
 }
 
 // GetRequestCountsByStatus returns a map of status to count
@@ -198,7 +542,9 @@ func (s *InMemoryStateStore) CreateOrGetDedupRequest(dedupKey string, state *int
 		}
 		// If failed, allow a new request (replace mapping)
 	}
-	s.requests[state.RequestID] = state
+	s.requests[state.RequestID] = state.Clone()
 	s.dedup[dedupKey] = state.RequestID
+	s.touch(state.RequestID)
+	s.evictLocked()
 	return state.RequestID, false, nil
 }
@@ -11,20 +11,34 @@ import (
 
 type InMemoryTaskQueue struct {
 	queues map[interfaces.TaskType][]*interfaces.Task
-	mu     sync.RWMutex
+	// Fair-share bookkeeping per task type: when each tenant was last served, as a
+	// dequeue count, so the tenant waiting longest goes next
+	served   map[interfaces.TaskType]map[string]uint64
+	dequeues uint64
+	mu       sync.RWMutex
 }
 
 func NewInMemoryTaskQueue() *InMemoryTaskQueue {
 	return &InMemoryTaskQueue{
 		queues: make(map[interfaces.TaskType][]*interfaces.Task),
+		served: make(map[interfaces.TaskType]map[string]uint64),
 	}
 }
 
+// Enqueue adds a task behind every queued task of the same or higher priority
 func (q *InMemoryTaskQueue) Enqueue(task *interfaces.Task) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.queues[task.Type] = append(q.queues[task.Type], task)
-	log.Infof("Enqueued task: %s for request: %s", task.Type, task.RequestID)
+	queue := q.queues[task.Type]
+	i := len(queue)
+	for i > 0 && queue[i-1].Priority < task.Priority {
+		i--
+	}
+	queue = append(queue, nil)
+	copy(queue[i+1:], queue[i:])
+	queue[i] = task
+	q.queues[task.Type] = queue
+	log.Infof("Enqueued task: %s for request: %s (priority %s, tenant %q)", task.Type, task.RequestID, task.Priority, task.Tenant)
 	// Debug: print current queue for this type
 	queueIDs := make([]string, len(q.queues[task.Type]))
 	for i, t := range q.queues[task.Type] {
@@ -34,6 +48,9 @@ func (q *InMemoryTaskQueue) Enqueue(task *interfaces.Task) error {
 	return nil
 }
 
+// Dequeue removes the next task of a type. Among the queued tasks of the highest priority,
+// tenants take turns: the oldest task of the tenant served least recently goes first, so one
+// tenant's large batch can't starve the others.
 func (q *InMemoryTaskQueue) Dequeue(taskType interfaces.TaskType) (*interfaces.Task, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -41,8 +58,29 @@ func (q *InMemoryTaskQueue) Dequeue(taskType interfaces.TaskType) (*interfaces.T
 	if !exists || len(queue) == 0 {
 		return nil, errors.New("no tasks available")
 	}
-	task := queue[0]
-	q.queues[taskType] = queue[1:]
+	served := q.served[taskType]
+	if served == nil {
+		served = make(map[string]uint64)
+		q.served[taskType] = served
+	}
+	next := 0
+	for i := 1; i < len(queue) && queue[i].Priority == queue[0].Priority; i++ {
+		if served[queue[i].Tenant] < served[queue[next].Tenant] {
+			next = i
+		}
+	}
+	task := queue[next]
+	if next == 0 {
+		q.queues[taskType] = queue[1:]
+	} else {
+		q.queues[taskType] = append(queue[:next:next], queue[next+1:]...)
+	}
+	q.dequeues++
+	served[task.Tenant] = q.dequeues
+	if len(q.queues[taskType]) == 0 {
+		// Nobody is waiting, so there is no turn order to keep
+		delete(q.served, taskType)
+	}
 	return task, nil
 }
 
@@ -0,0 +1,481 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+const (
+	// kafkaPollTimeout is how long Dequeue waits for a task before reporting none available
+	kafkaPollTimeout = time.Second
+	// kafkaPriorityPoll is how long Dequeue waits on one priority's topic before looking at
+	// the others again
+	kafkaPriorityPoll = 50 * time.Millisecond
+	// kafkaRequestTimeout bounds writes and the broker requests made to read consumer lag
+	kafkaRequestTimeout = 10 * time.Second
+	// kafkaLagRefresh is how often QueueLength re-reads a topic's lag from the broker
+	kafkaLagRefresh = 15 * time.Second
+	// kafkaRemovedRequestTTL is how long the tasks of a removed request are skipped
+	kafkaRemovedRequestTTL = 24 * time.Hour
+	// kafkaSessionTimeout is how long the broker keeps a consumer that stopped without leaving
+	// its group, e.g. because its process crashed
+	kafkaSessionTimeout = 30 * time.Second
+)
+
+// KafkaTaskQueue keeps tasks on a Kafka-compatible broker, one topic per task type and
+// priority. Tasks are keyed by request ID, so a request's tasks of one type and priority stay
+// in order on one partition, and higher-priority topics are read first. A task is committed
+// once it has run (Ack), so tasks a process was running when it died are delivered again when
+// it restarts; a task may then run twice. Request state and events live in the process, so
+// only one process may consume the queue: it makes the queue durable, but doesn't spread
+// workers across processes. Tenant fair sharing is not applied.
+type KafkaTaskQueue struct {
+	cfg    config.KafkaConfig
+	writer *kafka.Writer
+	client *kafka.Client
+	dialer *kafka.Dialer
+
+	mu       sync.Mutex
+	readers  map[string]*kafka.Reader // by topic
+	lags     map[interfaces.TaskType]*topicLag
+	removed  map[string]time.Time // request ID -> when its tasks were removed
+	inflight map[*interfaces.Task]kafka.Message
+	// Messages taken from each partition that can't be committed yet, oldest first
+	uncommitted map[kafkaPartition]*partitionOffsets
+}
+
+type kafkaPartition struct {
+	topic     string
+	partition int
+}
+
+// partitionOffsets tracks the tasks taken from a partition. A partition's committed offset
+// only moves past tasks that have run, so it waits for the oldest one still running.
+type partitionOffsets struct {
+	messages []kafka.Message // in offset order
+	done     map[int64]bool
+}
+
+// topicLag is the last known number of tasks waiting on a topic
+type topicLag struct {
+	length    int
+	checkedAt time.Time
+	checking  bool
+}
+
+// NewKafkaTaskQueue creates a task queue on the configured brokers. Readers for a task type's
+// topic join the consumer group on its first Dequeue, so a process only consumes the task
+// types it runs workers for.
+func NewKafkaTaskQueue(cfg config.KafkaConfig) (*KafkaTaskQueue, error) {
+	mechanism, err := kafkaSASLMechanism(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tlsConfig *tls.Config
+	if cfg.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport := &kafka.Transport{TLS: tlsConfig, SASL: mechanism}
+	return &KafkaTaskQueue{
+		cfg: cfg,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// Write each task as it is enqueued rather than waiting to fill a batch
+			BatchSize:              1,
+			AllowAutoTopicCreation: true,
+			Transport:              transport,
+		},
+		client:  &kafka.Client{Addr: kafka.TCP(cfg.Brokers...), Timeout: kafkaRequestTimeout, Transport: transport},
+		dialer:  &kafka.Dialer{Timeout: kafkaRequestTimeout, DualStack: true, TLS: tlsConfig, SASLMechanism: mechanism},
+		readers: make(map[string]*kafka.Reader),
+		lags:    make(map[interfaces.TaskType]*topicLag),
+		removed: make(map[string]time.Time),
+
+		inflight:    make(map[*interfaces.Task]kafka.Message),
+		uncommitted: make(map[kafkaPartition]*partitionOffsets),
+	}, nil
+}
+
+// kafkaSASLMechanism returns the configured SASL mechanism, or nil when SASL is off
+func kafkaSASLMechanism(cfg config.KafkaConfig) (sasl.Mechanism, error) {
+	switch cfg.SASLMechanism {
+	case "":
+		return nil, nil
+	case "plain":
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	}
+	return nil, fmt.Errorf("unsupported kafka SASL mechanism %q", cfg.SASLMechanism)
+}
+
+// kafkaPriorities are the priorities with their own topics, in the order they are read
+var kafkaPriorities = []interfaces.Priority{interfaces.PriorityHigh, interfaces.PriorityNormal, interfaces.PriorityLow}
+
+// topic returns the topic of a task type's tasks of a priority: <prefix><type> for normal
+// priority, with ".high" or ".low" appended for the others
+func (q *KafkaTaskQueue) topic(taskType interfaces.TaskType, priority interfaces.Priority) string {
+	topic := q.cfg.TopicPrefix + string(taskType)
+	if priority != interfaces.PriorityNormal {
+		topic += "." + priority.String()
+	}
+	return topic
+}
+
+// Enqueue writes a task to the topic of its type and priority
+func (q *KafkaTaskQueue) Enqueue(task *interfaces.Task) error {
+	value, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task %s: %w", task.ID, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+	defer cancel()
+	err = q.writer.WriteMessages(ctx, kafka.Message{
+		Topic: q.topic(task.Type, task.Priority),
+		Key:   []byte(task.RequestID),
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue %s task for request %s: %w", task.Type, task.RequestID, err)
+	}
+	log.Infof("Enqueued task: %s for request: %s on topic %s", task.Type, task.RequestID, q.topic(task.Type, task.Priority))
+	return nil
+}
+
+// Dequeue takes the next task of a type from its topics, highest priority first, waiting up
+// to kafkaPollTimeout for one
+func (q *KafkaTaskQueue) Dequeue(taskType interfaces.TaskType) (*interfaces.Task, error) {
+	reader, msg, err := q.fetch(taskType)
+	if err != nil {
+		return nil, err
+	}
+	q.take(msg)
+
+	var task interfaces.Task
+	if err := json.Unmarshal(msg.Value, &task); err != nil {
+		log.Errorf("Dropping unreadable task at %s/%d offset %d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+		q.commit(reader, msg)
+		return nil, err
+	}
+	if q.wasRemoved(&task) {
+		log.Debugf("Skipping %s task of removed request %s", task.Type, task.RequestID)
+		q.commit(reader, msg)
+		return nil, errors.New("no tasks available")
+	}
+	q.mu.Lock()
+	q.inflight[&task] = msg
+	q.mu.Unlock()
+	return &task, nil
+}
+
+// fetch reads the next message of a task type, looking at its priorities' topics in turn
+// until one has a message or kafkaPollTimeout has passed
+func (q *KafkaTaskQueue) fetch(taskType interfaces.TaskType) (*kafka.Reader, kafka.Message, error) {
+	deadline := time.Now().Add(kafkaPollTimeout)
+	for time.Now().Before(deadline) {
+		for _, priority := range kafkaPriorities {
+			reader := q.reader(q.topic(taskType, priority))
+			ctx, cancel := context.WithTimeout(context.Background(), kafkaPriorityPoll)
+			msg, err := reader.FetchMessage(ctx)
+			cancel()
+			if err == nil {
+				return reader, msg, nil
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				log.Warnf("Failed to read %s tasks from kafka: %v", taskType, err)
+				// Wait out the poll so a broker outage doesn't spin the workers
+				time.Sleep(time.Until(deadline))
+				return nil, kafka.Message{}, errors.New("no tasks available")
+			}
+		}
+	}
+	return nil, kafka.Message{}, errors.New("no tasks available")
+}
+
+// Ack commits a task that has run, along with the tasks before it on its partition that
+// have too
+func (q *KafkaTaskQueue) Ack(task *interfaces.Task) {
+	q.mu.Lock()
+	msg, ok := q.inflight[task]
+	delete(q.inflight, task)
+	reader := q.readers[msg.Topic]
+	q.mu.Unlock()
+	if !ok || reader == nil {
+		return
+	}
+	q.commit(reader, msg)
+}
+
+// take records a message a worker is about to run
+func (q *KafkaTaskQueue) take(msg kafka.Message) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := kafkaPartition{topic: msg.Topic, partition: msg.Partition}
+	offsets := q.uncommitted[key]
+	if offsets == nil {
+		offsets = &partitionOffsets{done: make(map[int64]bool)}
+		q.uncommitted[key] = offsets
+	}
+	// Messages come in offset order, except when a rebalance delivers some again
+	i := sort.Search(len(offsets.messages), func(i int) bool { return offsets.messages[i].Offset >= msg.Offset })
+	if i < len(offsets.messages) && offsets.messages[i].Offset == msg.Offset {
+		return
+	}
+	offsets.messages = slices.Insert(offsets.messages, i, msg)
+}
+
+// commit marks a message as run and commits its partition up to the oldest message still
+// running
+func (q *KafkaTaskQueue) commit(reader *kafka.Reader, msg kafka.Message) {
+	last, ok := q.finish(msg)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+	defer cancel()
+	if err := reader.CommitMessages(ctx, last); err != nil {
+		// The tasks are delivered again after a restart or rebalance
+		log.Warnf("Failed to commit %s offset %d: %v", last.Topic, last.Offset, err)
+	}
+}
+
+// finish marks a message as run and returns the newest message of its partition that can be
+// committed, if running it let the committed offset move
+func (q *KafkaTaskQueue) finish(msg kafka.Message) (kafka.Message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := kafkaPartition{topic: msg.Topic, partition: msg.Partition}
+	offsets := q.uncommitted[key]
+	if offsets == nil || msg.Offset < offsets.messages[0].Offset {
+		// Already committed, e.g. a task delivered again by a rebalance
+		return kafka.Message{}, false
+	}
+	offsets.done[msg.Offset] = true
+	var last kafka.Message
+	moved := false
+	for len(offsets.messages) > 0 && offsets.done[offsets.messages[0].Offset] {
+		last, moved = offsets.messages[0], true
+		delete(offsets.done, last.Offset)
+		offsets.messages = offsets.messages[1:]
+	}
+	if len(offsets.messages) == 0 {
+		delete(q.uncommitted, key)
+	}
+	return last, moved
+}
+
+// CheckSoleConsumer returns an error if another process consumes the queue's consumer group.
+// Request state and events are kept in each process, so a task another process took would
+// run against a request it doesn't know and be lost. A member left by a process that crashed
+// is waited out for up to the session timeout.
+func (q *KafkaTaskQueue) CheckSoleConsumer(ctx context.Context) error {
+	deadline := time.Now().Add(kafkaSessionTimeout + kafkaPollTimeout)
+	for {
+		resp, err := q.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{q.cfg.GroupID}})
+		if err != nil {
+			return fmt.Errorf("failed to read kafka consumer group %s: %w", q.cfg.GroupID, err)
+		}
+		var hosts []string
+		for _, group := range resp.Groups {
+			if group.Error != nil {
+				return fmt.Errorf("failed to read kafka consumer group %s: %w", q.cfg.GroupID, group.Error)
+			}
+			for _, member := range group.Members {
+				hosts = append(hosts, member.ClientHost)
+			}
+		}
+		if len(hosts) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("kafka consumer group %s is in use by %v; request state and events are kept in each process, so only one process may consume a group (give each deployment its own task_queue.kafka.group_id)", q.cfg.GroupID, hosts)
+		}
+		log.Infof("Kafka consumer group %s still lists %v, waiting for it to expire", q.cfg.GroupID, hosts)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// reader returns the consumer group reader of a topic, creating it on first use
+func (q *KafkaTaskQueue) reader(topic string) *kafka.Reader {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if reader, ok := q.readers[topic]; ok {
+		return reader
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        q.cfg.Brokers,
+		GroupID:        q.cfg.GroupID,
+		Topic:          topic,
+		Dialer:         q.dialer,
+		StartOffset:    kafka.FirstOffset,
+		MaxWait:        kafkaPollTimeout,
+		SessionTimeout: kafkaSessionTimeout,
+		// Topics are created with their first task, and may be given more partitions later
+		WatchPartitionChanges: true,
+	})
+	q.readers[topic] = reader
+	return reader
+}
+
+// QueueLength returns the consumer group's lag on a task type's topics, i.e. the tasks
+// written but not yet taken. The lag is read from the broker in the background at most
+// every kafkaLagRefresh, so it trails the broker by up to that long.
+func (q *KafkaTaskQueue) QueueLength(taskType interfaces.TaskType) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	lag := q.lags[taskType]
+	if lag == nil {
+		lag = &topicLag{}
+		q.lags[taskType] = lag
+	}
+	if !lag.checking && time.Since(lag.checkedAt) >= kafkaLagRefresh {
+		lag.checking = true
+		go q.refreshLag(taskType, lag)
+	}
+	return lag.length
+}
+
+func (q *KafkaTaskQueue) refreshLag(taskType interfaces.TaskType, lag *topicLag) {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout)
+	defer cancel()
+	length := 0
+	var err error
+	for _, priority := range kafkaPriorities {
+		var topicLength int
+		if topicLength, err = q.readLag(ctx, q.topic(taskType, priority)); err != nil {
+			break
+		}
+		length += topicLength
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	lag.checking = false
+	lag.checkedAt = time.Now()
+	if err != nil {
+		log.Warnf("Failed to read the kafka lag of %s tasks: %v", taskType, err)
+		return
+	}
+	lag.length = length
+}
+
+// readLag sums, over a topic's partitions, the records after the consumer group's committed
+// offset, or after the first record if the group hasn't committed any
+func (q *KafkaTaskQueue) readLag(ctx context.Context, topic string) (int, error) {
+	metadata, err := q.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, err
+	}
+	if len(metadata.Topics) == 0 || metadata.Topics[0].Error != nil {
+		// Topics are created with their first task, so nothing has been queued yet
+		return 0, nil
+	}
+	var partitions []int
+	var requests []kafka.OffsetRequest
+	for _, partition := range metadata.Topics[0].Partitions {
+		partitions = append(partitions, partition.ID)
+		requests = append(requests, kafka.FirstOffsetOf(partition.ID), kafka.LastOffsetOf(partition.ID))
+	}
+
+	offsets, err := q.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err != nil {
+		return 0, err
+	}
+	committed, err := q.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: q.cfg.GroupID,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if committed.Error != nil {
+		return 0, committed.Error
+	}
+	next := make(map[int]int64)
+	for _, partition := range committed.Topics[topic] {
+		next[partition.Partition] = partition.CommittedOffset
+	}
+
+	var length int64
+	for _, partition := range offsets.Topics[topic] {
+		if partition.Error != nil {
+			return 0, partition.Error
+		}
+		start, ok := next[partition.Partition]
+		if !ok || start < partition.FirstOffset {
+			start = partition.FirstOffset
+		}
+		if partition.LastOffset > start {
+			length += partition.LastOffset - start
+		}
+	}
+	return int(length), nil
+}
+
+// RemoveTasksForRequest skips the request's queued tasks when they are dequeued, since
+// records can't be deleted from a topic. Tasks enqueued after the removal, as when the
+// request is retried, still run. Only this process skips them.
+func (q *KafkaTaskQueue) RemoveTasksForRequest(requestID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	for id, removedAt := range q.removed {
+		if now.Sub(removedAt) > kafkaRemovedRequestTTL {
+			delete(q.removed, id)
+		}
+	}
+	q.removed[requestID] = now
+	return nil
+}
+
+func (q *KafkaTaskQueue) wasRemoved(task *interfaces.Task) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	removedAt, ok := q.removed[task.RequestID]
+	return ok && !task.CreatedAt.After(removedAt)
+}
+
+// Close leaves the consumer group and flushes pending writes
+func (q *KafkaTaskQueue) Close() error {
+	q.mu.Lock()
+	readers := q.readers
+	q.readers = make(map[string]*kafka.Reader)
+	q.mu.Unlock()
+
+	var errs []error
+	for topic, reader := range readers {
+		if err := reader.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s reader: %w", topic, err))
+		}
+	}
+	if err := q.writer.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close writer: %w", err))
+	}
+	return errors.Join(errs...)
+}
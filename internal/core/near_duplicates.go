@@ -0,0 +1,250 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/video"
+)
+
+// Limits on what is embedded for near-duplicate detection, and how long the embedding may take
+const (
+	nearDuplicateDescriptionChars = 1000
+	nearDuplicateEmbedTimeout     = 30 * time.Second
+	// Stored requests embedded per call when the index is restored at startup
+	nearDuplicateRestoreBatch = 64
+)
+
+// NearDuplicateMatch is an indexed request similar to a new one
+type NearDuplicateMatch struct {
+	RequestID  string
+	Similarity float64
+}
+
+type nearDuplicateEntry struct {
+	vector    []float32
+	videoKey  string
+	createdAt time.Time
+}
+
+// NearDuplicateIndex holds the title and description embeddings of recent video requests.
+// It is in memory only, and restored from the stored requests at startup.
+type NearDuplicateIndex struct {
+	mu      sync.RWMutex
+	entries map[string]nearDuplicateEntry
+}
+
+// NewNearDuplicateIndex creates an empty near-duplicate index
+func NewNearDuplicateIndex() *NearDuplicateIndex {
+	return &NearDuplicateIndex{entries: make(map[string]nearDuplicateEntry)}
+}
+
+// Add indexes a request's embedding; videoURL identifies the video, so other requests for
+// the same video are never matched against it
+func (idx *NearDuplicateIndex) Add(requestID, videoURL string, vector []float32, createdAt time.Time) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries[requestID] = nearDuplicateEntry{vector: vector, videoKey: video.VideoCacheKey(videoURL), createdAt: createdAt}
+}
+
+// Remove drops a request from the index
+func (idx *NearDuplicateIndex) Remove(requestID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.entries, requestID)
+}
+
+// Prune drops requests created before since
+func (idx *NearDuplicateIndex) Prune(since time.Time) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for requestID, entry := range idx.entries {
+		if entry.createdAt.Before(since) {
+			delete(idx.entries, requestID)
+		}
+	}
+}
+
+// Similar returns the requests created since the given time, for other videos than videoURL,
+// whose embeddings are at least threshold similar to vector, most similar first
+func (idx *NearDuplicateIndex) Similar(vector []float32, videoURL string, since time.Time, threshold float64) []NearDuplicateMatch {
+	key := video.VideoCacheKey(videoURL)
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var matches []NearDuplicateMatch
+	for requestID, entry := range idx.entries {
+		if entry.videoKey == key || entry.createdAt.Before(since) {
+			continue
+		}
+		if similarity := cosineSimilarity(vector, entry.vector); similarity >= threshold {
+			matches = append(matches, NearDuplicateMatch{RequestID: requestID, Similarity: similarity})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Similarity > matches[j].Similarity })
+	return matches
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// handleNearDuplicate embeds a video request's title and description and compares it with
+// recent requests. A background-source request close enough to one that hasn't failed or
+// been cancelled is skipped or linked to it, as near_duplicates.action says, and true is
+// returned. Otherwise the request is indexed for later ones and false is returned.
+// Embedding errors are logged and the request carries on.
+func (e *ProcessingEngine) handleNearDuplicate(state *interfaces.ProcessingState) bool {
+	cfg := e.GetConfig()
+	if cfg == nil || !cfg.NearDuplicates.Enabled || e.embeddingProvider == nil || e.nearDuplicates == nil {
+		return false
+	}
+	text := nearDuplicateText(state)
+	if text == "" {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), nearDuplicateEmbedTimeout)
+	defer cancel()
+	vectors, err := e.embeddingProvider.Embed(ctx, []string{text})
+	if err != nil || len(vectors) != 1 {
+		log.Warnf("[Engine] Near-duplicate check skipped for request %s: %v", state.RequestID, err)
+		return false
+	}
+
+	since := time.Now().Add(-cfg.NearDuplicates.GetWindow())
+	e.nearDuplicates.Prune(since)
+	if state.Source != "" {
+		for _, match := range e.nearDuplicates.Similar(vectors[0], state.URL, since, cfg.NearDuplicates.Threshold) {
+			original, err := e.store.GetRequestState(match.RequestID)
+			if err != nil {
+				e.nearDuplicates.Remove(match.RequestID)
+				continue
+			}
+			if original.Status == interfaces.StatusFailed || original.Status == interfaces.StatusCancelled {
+				continue
+			}
+			e.markNearDuplicate(state, original, match.Similarity, cfg.NearDuplicates.Action)
+			return true
+		}
+	}
+	e.nearDuplicates.Add(state.RequestID, state.URL, vectors[0], state.CreatedAt)
+	return false
+}
+
+// restoreNearDuplicates embeds and indexes the stored video requests created within the
+// near-duplicate window, as handleNearDuplicate did before a restart. Requests that failed,
+// were cancelled or were near-duplicates themselves are left out, as they were then.
+func (e *ProcessingEngine) restoreNearDuplicates(states []*interfaces.ProcessingState) {
+	cfg := e.GetConfig()
+	if cfg == nil || !cfg.NearDuplicates.Enabled || e.embeddingProvider == nil || e.nearDuplicates == nil {
+		return
+	}
+	since := time.Now().Add(-cfg.NearDuplicates.GetWindow())
+	var restored []*interfaces.ProcessingState
+	var texts []string
+	for _, state := range states {
+		if state.CreatedAt.Before(since) || state.DuplicateOf != "" ||
+			state.Status == interfaces.StatusFailed || state.Status == interfaces.StatusCancelled ||
+			!e.pipelines.Includes(state.SourceType, interfaces.TaskVideoInfo) {
+			continue
+		}
+		if text := nearDuplicateText(state); text != "" {
+			restored = append(restored, state)
+			texts = append(texts, text)
+		}
+	}
+
+	for start := 0; start < len(texts); start += nearDuplicateRestoreBatch {
+		end := min(start+nearDuplicateRestoreBatch, len(texts))
+		ctx, cancel := context.WithTimeout(context.Background(), nearDuplicateEmbedTimeout)
+		vectors, err := e.embeddingProvider.Embed(ctx, texts[start:end])
+		cancel()
+		if err == nil && len(vectors) != end-start {
+			err = fmt.Errorf("got %d embeddings for %d requests", len(vectors), end-start)
+		}
+		if err != nil {
+			log.Warnf("[Engine] Restored %d of %d stored requests to the near-duplicate index: %v", start, len(texts), err)
+			return
+		}
+		for i, vector := range vectors {
+			state := restored[start+i]
+			e.nearDuplicates.Add(state.RequestID, state.URL, vector, state.CreatedAt)
+		}
+	}
+	if len(texts) > 0 {
+		log.Infof("[Engine] Restored %d stored request(s) to the near-duplicate index", len(texts))
+	}
+}
+
+// markNearDuplicate ends a request as a near-duplicate of original: cancelled for "skip",
+// or completed with the original's output link for "link"
+func (e *ProcessingEngine) markNearDuplicate(state, original *interfaces.ProcessingState, similarity float64, action string) {
+	log.Infof("[Engine] Request %s (%s) is a near-duplicate of %s (similarity %.2f), action %s",
+		state.RequestID, state.URL, original.RequestID, similarity, action)
+	updates := map[string]interface{}{
+		"duplicate_of": original.RequestID,
+	}
+	if action == "skip" {
+		updates["status"] = interfaces.StatusCancelled
+		updates["error"] = fmt.Sprintf("near-duplicate of %s (similarity %.2f)", original.RequestID, similarity)
+	} else {
+		updates["status"] = interfaces.StatusCompleted
+		updates["output_path"] = original.OutputPath
+	}
+	if err := e.store.UpdateRequestState(state.RequestID, updates); err != nil {
+		log.Errorf("[Engine] Failed to mark request %s as a near-duplicate: %v", state.RequestID, err)
+		return
+	}
+	e.enqueueCleanup(state.RequestID)
+}
+
+// nearDuplicateText is the title followed by the start of the description from the full
+// video info, or "" if the title is not known
+func nearDuplicateText(state *interfaces.ProcessingState) string {
+	title := strings.TrimSpace(state.Title())
+	if title == "" {
+		return ""
+	}
+	if state.InfoPath == "" {
+		return title
+	}
+	data, err := os.ReadFile(state.InfoPath)
+	if err != nil {
+		return title
+	}
+	var info struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return title
+	}
+	description := strings.TrimSpace(info.Description)
+	if runes := []rune(description); len(runes) > nearDuplicateDescriptionChars {
+		description = string(runes[:nearDuplicateDescriptionChars])
+	}
+	if description == "" {
+		return title
+	}
+	return title + "\n\n" + description
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// stageFollows lists the stages each pipeline stage can follow, i.e. the stages that produce
+// its input; "" is the start of a pipeline, where stages read the request's URL or its
+// prepared text
+var stageFollows = map[interfaces.TaskType][]interfaces.TaskType{
+	interfaces.TaskVideoInfo:      {""},
+	interfaces.TaskAudioDownload:  {"", interfaces.TaskVideoInfo},
+	interfaces.TaskTextExtraction: {""},
+	interfaces.TaskTranscription:  {interfaces.TaskAudioDownload},
+	interfaces.TaskSummarization:  {"", interfaces.TaskTranscription, interfaces.TaskTextExtraction},
+}
+
+// stageTaskNames name a stage's tasks in their IDs
+var stageTaskNames = map[interfaces.TaskType]string{
+	interfaces.TaskVideoInfo:      "video",
+	interfaces.TaskAudioDownload:  "audio",
+	interfaces.TaskTextExtraction: "text",
+	interfaces.TaskTranscription:  "transcribe",
+	interfaces.TaskSummarization:  "summarize",
+}
+
+// PipelineRegistry maps source types to the ordered stages their requests run, up to and
+// including summarization. The optional stages after it (highlights, evaluation, redaction,
+// output, hooks and cleanup) are the same for every source type.
+type PipelineRegistry struct {
+	mu        sync.RWMutex
+	pipelines map[string][]interfaces.TaskType
+}
+
+// NewPipelineRegistry creates a registry with the built-in pipelines
+func NewPipelineRegistry() *PipelineRegistry {
+	registry := &PipelineRegistry{pipelines: make(map[string][]interfaces.TaskType)}
+	registry.Register(interfaces.SourceTypeVideo, interfaces.TaskVideoInfo, interfaces.TaskAudioDownload, interfaces.TaskTranscription, interfaces.TaskSummarization)
+	registry.Register(interfaces.SourceTypeAudio, interfaces.TaskAudioDownload, interfaces.TaskTranscription, interfaces.TaskSummarization)
+	registry.Register(interfaces.SourceTypeDocument, interfaces.TaskTextExtraction, interfaces.TaskSummarization)
+	registry.Register(interfaces.SourceTypeArticle, interfaces.TaskTextExtraction, interfaces.TaskSummarization)
+	// Digests arrive with their text already prepared
+	registry.Register(interfaces.SourceTypeDigest, interfaces.TaskSummarization)
+	return registry
+}
+
+// Register sets the stages requests of a source type run, replacing its current pipeline.
+// Pipelines end with summarization, and each stage must take its input from the one before.
+func (r *PipelineRegistry) Register(sourceType string, stages ...interfaces.TaskType) error {
+	if sourceType == "" {
+		return fmt.Errorf("source type is required")
+	}
+	if len(stages) == 0 || stages[len(stages)-1] != interfaces.TaskSummarization {
+		return fmt.Errorf("pipeline for %s must end with %s", sourceType, interfaces.TaskSummarization)
+	}
+	previous := interfaces.TaskType("")
+	for _, stage := range stages {
+		follows, ok := stageFollows[stage]
+		if !ok {
+			return fmt.Errorf("%s is not a pipeline stage", stage)
+		}
+		if !containsStage(follows, previous) {
+			if previous == "" {
+				return fmt.Errorf("pipeline for %s can't start with %s", sourceType, stage)
+			}
+			return fmt.Errorf("pipeline for %s can't run %s after %s", sourceType, stage, previous)
+		}
+		previous = stage
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pipelines[sourceType] = append([]interfaces.TaskType(nil), stages...)
+	return nil
+}
+
+// Get returns the stages of a source type's pipeline; requests without a source type are
+// videos
+func (r *PipelineRegistry) Get(sourceType string) ([]interfaces.TaskType, bool) {
+	if sourceType == "" {
+		sourceType = interfaces.SourceTypeVideo
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stages, ok := r.pipelines[sourceType]
+	return stages, ok
+}
+
+// SourceTypes returns the source types with a registered pipeline, sorted
+func (r *PipelineRegistry) SourceTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.pipelines))
+	for sourceType := range r.pipelines {
+		types = append(types, sourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Includes reports whether a source type's pipeline runs a stage
+func (r *PipelineRegistry) Includes(sourceType string, stage interfaces.TaskType) bool {
+	stages, _ := r.Get(sourceType)
+	return containsStage(stages, stage)
+}
+
+// Next returns the stage a request of a source type runs after completed. A stage outside the
+// pipeline, like a rerun's copied transcript or the prepared text of a comparison, is
+// followed by summarization.
+func (r *PipelineRegistry) Next(sourceType string, completed interfaces.TaskType) interfaces.TaskType {
+	stages, _ := r.Get(sourceType)
+	for i, stage := range stages {
+		if stage == completed && i+1 < len(stages) {
+			return stages[i+1]
+		}
+	}
+	return interfaces.TaskSummarization
+}
+
+func containsStage(stages []interfaces.TaskType, stage interfaces.TaskType) bool {
+	for _, s := range stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPipelineRegistry returns the registry of per source type pipelines
+func (e *ProcessingEngine) GetPipelineRegistry() *PipelineRegistry {
+	return e.pipelines
+}
+
+// startPipeline queues the first stage of a request's pipeline, failing requests whose
+// source type has none
+func (e *ProcessingEngine) startPipeline(state *interfaces.ProcessingState) {
+	stages, ok := e.pipelines.Get(state.SourceType)
+	if !ok {
+		reason := fmt.Sprintf("no pipeline is registered for source type %q", state.SourceType)
+		log.Errorf("[Engine] Request %s failed: %s", state.RequestID, reason)
+		e.store.UpdateRequestState(state.RequestID, map[string]interface{}{
+			"status":       interfaces.StatusFailed,
+			"error":        reason,
+			"error_code":   interfaces.ErrorCodeNotConfigured,
+			"completed_at": time.Now(),
+		})
+		e.eventBus.Publish(interfaces.Event{
+			ID:        fmt.Sprintf("evt-%s-failed-%d", state.RequestID, time.Now().UnixNano()),
+			RequestID: state.RequestID,
+			Type:      interfaces.EventTypeProcessingFailed,
+			Data:      interfaces.ProcessingFailedPayload{Error: reason, ErrorCode: interfaces.ErrorCodeNotConfigured},
+			Timestamp: time.Now(),
+		})
+		return
+	}
+	log.Debugf("[Engine] Starting %s pipeline for request: %s", state.SourceType, state.RequestID)
+	// A pipeline that starts at summarization reads the text prepared with the request
+	e.enqueueStage(state, stages[0], state.TextPath)
+	e.store.UpdateRequestState(state.RequestID, map[string]interface{}{
+		"status": interfaces.StatusRunning,
+	})
+}
+
+// enqueueNextStage queues the stage that follows completed in a request's pipeline. input is
+// the artifact completed produced, if any.
+func (e *ProcessingEngine) enqueueNextStage(state *interfaces.ProcessingState, completed interfaces.TaskType, input string) {
+	e.enqueueStage(state, e.pipelines.Next(state.SourceType, completed), input)
+}
+
+// enqueueStage queues a pipeline stage for a request. Stages at the start of a pipeline read
+// the request's URL; later ones read input, the artifact of the stage before.
+func (e *ProcessingEngine) enqueueStage(state *interfaces.ProcessingState, stage interfaces.TaskType, input string) {
+	var data map[string]interface{}
+	switch stage {
+	case interfaces.TaskVideoInfo, interfaces.TaskAudioDownload:
+		data = map[string]interface{}{"url": state.URL}
+	case interfaces.TaskTextExtraction:
+		data = map[string]interface{}{"source": state.URL}
+	case interfaces.TaskTranscription:
+		data = map[string]interface{}{"audio_path": input}
+	default:
+		data = map[string]interface{}{"transcript_path": input}
+	}
+	log.Debugf("[Engine] Enqueueing %s task for request: %s", stage, state.RequestID)
+	e.enqueue(&interfaces.Task{
+		ID:        fmt.Sprintf("task-%s-%s-%d", state.RequestID, stageTaskNames[stage], time.Now().UnixNano()),
+		Type:      stage,
+		RequestID: state.RequestID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,279 @@
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// preflightSampleSeconds is the length of the silent audio sample sent through transcription
+const preflightSampleSeconds = 2
+
+// preflightText is summarized by the summarization check. The time is added so the call
+// reaches the model instead of a cached response.
+const preflightText = "This is a startup self-test of the summarization provider."
+
+// PreflightCheck is the result of one preflight check
+type PreflightCheck struct {
+	Name     string    `json:"name"`
+	Passed   bool      `json:"passed"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration"`
+	RanAt    time.Time `json:"ran_at"`
+}
+
+// PreflightStatus reports the startup self-test. Checks is empty until the first run finishes.
+type PreflightStatus struct {
+	Passed bool             `json:"passed"`
+	Runs   int              `json:"runs"`
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// Preflight runs a short self-test of the providers at startup: a second or two of silence
+// through transcription, a one-token summarization call and a check that each output
+// destination is reachable. Failed checks run again every retry interval until they pass.
+type Preflight struct {
+	checks        []string
+	timeout       time.Duration
+	retryInterval time.Duration
+	tmpDir        string
+
+	transcription interfaces.TranscriptionProvider
+	summarization interfaces.SummarizationProvider
+	outputs       map[string]interfaces.OutputProvider
+
+	mu       sync.Mutex
+	status   PreflightStatus
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewPreflight creates a preflight running the configured checks against the given providers.
+// The summarization provider should be the uncached one.
+func NewPreflight(cfg config.PreflightConfig, transcription interfaces.TranscriptionProvider, summarization interfaces.SummarizationProvider, outputs map[string]interfaces.OutputProvider, tmpDir string) *Preflight {
+	return &Preflight{
+		checks:        cfg.Checks,
+		timeout:       cfg.GetTimeout(),
+		retryInterval: cfg.GetRetryInterval(),
+		tmpDir:        tmpDir,
+		transcription: transcription,
+		summarization: summarization,
+		outputs:       outputs,
+	}
+}
+
+// Start runs the checks in the background, repeating them until they all pass
+func (p *Preflight) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopChan != nil {
+		return
+	}
+	p.stopChan = make(chan struct{})
+	p.wg.Add(1)
+	go p.run(p.stopChan)
+	log.Infof("Preflight started: %v", p.checks)
+}
+
+// Stop stops retrying failed checks, cancelling any check that is running
+func (p *Preflight) Stop() {
+	p.mu.Lock()
+	stopChan := p.stopChan
+	p.stopChan = nil
+	p.mu.Unlock()
+	if stopChan != nil {
+		close(stopChan)
+		p.wg.Wait()
+	}
+}
+
+// Status returns the results of the latest run
+func (p *Preflight) Status() PreflightStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := p.status
+	status.Checks = append([]PreflightCheck(nil), p.status.Checks...)
+	return status
+}
+
+func (p *Preflight) run(stopChan chan struct{}) {
+	defer p.wg.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopChan
+		cancel()
+	}()
+
+	for {
+		if p.Run(ctx) {
+			return
+		}
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(p.retryInterval):
+		}
+	}
+}
+
+// Run runs every check once, or after the first run only the ones that failed, and reports
+// whether all of them have passed
+func (p *Preflight) Run(ctx context.Context) bool {
+	p.mu.Lock()
+	previous := make(map[string]PreflightCheck, len(p.status.Checks))
+	for _, check := range p.status.Checks {
+		previous[check.Name] = check
+	}
+	p.mu.Unlock()
+
+	results := make(map[string]PreflightCheck)
+	for name, check := range p.checkFuncs() {
+		if prev, ok := previous[name]; ok && prev.Passed {
+			results[name] = prev
+			continue
+		}
+		results[name] = p.runCheck(ctx, name, check)
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	status := PreflightStatus{Passed: true, Checks: make([]PreflightCheck, 0, len(names))}
+	for _, name := range names {
+		status.Checks = append(status.Checks, results[name])
+		status.Passed = status.Passed && results[name].Passed
+	}
+
+	p.mu.Lock()
+	status.Runs = p.status.Runs + 1
+	p.status = status
+	p.mu.Unlock()
+	if status.Passed {
+		log.Infof("Preflight passed (%d checks)", len(status.Checks))
+	} else {
+		log.Warnf("Preflight failed; retrying failed checks in %s", p.retryInterval)
+	}
+	return status.Passed
+}
+
+// checkFuncs returns the configured checks by name. The output check covers every output
+// provider that can check its destination, as "output:<provider>".
+func (p *Preflight) checkFuncs() map[string]func(context.Context) error {
+	checks := make(map[string]func(context.Context) error)
+	for _, name := range p.checks {
+		switch name {
+		case config.PreflightTranscription:
+			if p.transcription != nil {
+				checks[name] = p.checkTranscription
+			}
+		case config.PreflightSummarization:
+			if p.summarization != nil {
+				checks[name] = p.checkSummarization
+			}
+		case config.PreflightOutput:
+			for providerName, provider := range p.outputs {
+				if checkable, ok := provider.(interfaces.CheckableOutputProvider); ok {
+					checks[name+":"+providerName] = checkable.CheckDestination
+				}
+			}
+		}
+	}
+	return checks
+}
+
+func (p *Preflight) runCheck(ctx context.Context, name string, check func(context.Context) error) PreflightCheck {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	start := time.Now()
+	err := check(ctx)
+	result := PreflightCheck{
+		Name:     name,
+		Passed:   err == nil,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+		RanAt:    start,
+	}
+	if err != nil {
+		result.Error = err.Error()
+		log.Warnf("Preflight check %s failed: %v", name, err)
+	} else {
+		log.Infof("Preflight check %s passed in %s", name, result.Duration)
+	}
+	return result
+}
+
+// checkTranscription transcribes a short silent sample
+func (p *Preflight) checkTranscription(ctx context.Context) error {
+	samplePath, err := writeSilentWAV(p.tmpDir, preflightSampleSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to write audio sample: %w", err)
+	}
+	defer os.Remove(samplePath)
+
+	var transcriptPath string
+	if detailed, ok := p.transcription.(interfaces.DetailedTranscriptionProvider); ok {
+		transcription, err := detailed.TranscribeAudioDetailed(ctx, samplePath, interfaces.TranscriptionOptions{})
+		if err != nil {
+			return err
+		}
+		transcriptPath = transcription.Path
+	} else if transcriptPath, err = p.transcription.TranscribeAudio(samplePath); err != nil {
+		return err
+	}
+	os.Remove(transcriptPath)
+	return nil
+}
+
+// checkSummarization makes a one-token summarization call
+func (p *Preflight) checkSummarization(ctx context.Context) error {
+	text := fmt.Sprintf("%s (%s)", preflightText, time.Now().Format(time.RFC3339Nano))
+	summaryPath, err := p.summarization.SummarizeText(ctx, text, "Reply with OK.", 1)
+	if err != nil {
+		return err
+	}
+	os.Remove(summaryPath)
+	return nil
+}
+
+// writeSilentWAV writes seconds of silence as 16 kHz mono 16-bit PCM, the format whisper.cpp
+// reads, and returns its path
+func writeSilentWAV(dir string, seconds int) (string, error) {
+	const sampleRate = 16000
+	dataSize := uint32(seconds * sampleRate * 2)
+	f, err := os.CreateTemp(dir, "preflight-*.wav")
+	if err != nil {
+		return "", err
+	}
+	header := []interface{}{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + dataSize, [4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '}, uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * 2), uint16(2), uint16(16),
+		[4]byte{'d', 'a', 't', 'a'}, dataSize,
+	}
+	for _, field := range header {
+		if err = binary.Write(f, binary.LittleEndian, field); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		_, err = f.Write(make([]byte, dataSize))
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field weights: a match in the title counts more than one in the summary body
+const (
+	searchWeightTitle   = 5
+	searchWeightChannel = 3
+	searchWeightSummary = 1
+)
+
+// searchStopWords are too common to be useful as search terms
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true, "about": true,
+}
+
+// SearchDocument is the searchable text of one request
+type SearchDocument struct {
+	Title   string
+	Channel string
+	Summary string
+}
+
+// SearchHit is a request matching a search, best matches first
+type SearchHit struct {
+	RequestID string
+	Score     int
+}
+
+// SearchIndex is an in-memory inverted index over request titles, channels and summaries
+type SearchIndex struct {
+	mu       sync.RWMutex
+	postings map[string]map[string]int // term -> requestID -> weighted term frequency
+	docs     map[string][]string       // requestID -> indexed terms, for removal
+}
+
+// NewSearchIndex creates an empty search index
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		postings: make(map[string]map[string]int),
+		docs:     make(map[string][]string),
+	}
+}
+
+// Index replaces the indexed text of a request
+func (idx *SearchIndex) Index(requestID string, doc SearchDocument) {
+	weights := make(map[string]int)
+	for _, field := range []struct {
+		text   string
+		weight int
+	}{
+		{doc.Title, searchWeightTitle},
+		{doc.Channel, searchWeightChannel},
+		{doc.Summary, searchWeightSummary},
+	} {
+		for _, term := range tokenize(field.text) {
+			weights[term] += field.weight
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(requestID)
+	terms := make([]string, 0, len(weights))
+	for term, weight := range weights {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]int)
+		}
+		idx.postings[term][requestID] = weight
+		terms = append(terms, term)
+	}
+	idx.docs[requestID] = terms
+}
+
+// Remove drops a request from the index
+func (idx *SearchIndex) Remove(requestID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(requestID)
+}
+
+func (idx *SearchIndex) removeLocked(requestID string) {
+	for _, term := range idx.docs[requestID] {
+		delete(idx.postings[term], requestID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, requestID)
+}
+
+// Search returns the requests containing every term of the query, ranked by weighted
+// term frequency. Queries without usable terms match nothing.
+func (idx *SearchIndex) Search(query string) []SearchHit {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[string]int)
+	for id, weight := range idx.postings[terms[0]] {
+		scores[id] = weight
+	}
+	for _, term := range terms[1:] {
+		postings := idx.postings[term]
+		for id := range scores {
+			weight, ok := postings[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] += weight
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, SearchHit{RequestID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].RequestID > hits[j].RequestID
+	})
+	return hits
+}
+
+// Size returns the number of indexed requests
+func (idx *SearchIndex) Size() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// tokenize lowercases text and splits it into words, dropping stop words and single characters
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) < 2 || searchStopWords[word] {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
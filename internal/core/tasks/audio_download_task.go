@@ -22,18 +22,19 @@ func (p *AudioDownloadTask) GetTaskType() interfaces.TaskType {
 }
 
 func (p *AudioDownloadTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskAudioDownload for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskAudioDownload for request: %s", task.RequestID)
 
 	url, ok := task.Data.(map[string]interface{})["url"].(string)
 	if !ok || url == "" {
 		return fmt.Errorf("audio_download task missing url in data")
 	}
 
-	audioPath, err := engine.GetVideoProvider().DownloadAudio(url)
+	audioPath, err := engine.GetVideoProvider().DownloadAudio(ctx, url)
 	if err != nil {
 		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-			"status": interfaces.StatusFailed,
-			"error":  fmt.Sprintf("Failed to download audio: %v", err),
+			"status":     interfaces.StatusFailed,
+			"error":      fmt.Sprintf("Failed to download audio: %v", err),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeDownloadFailed),
 		})
 		return err
 	}
@@ -43,7 +44,7 @@ func (p *AudioDownloadTask) Process(ctx context.Context, task *interfaces.Task,
 		"audio_path": audioPath,
 	})
 	if err != nil {
-		log.Errorf("Failed to update state with audio path: %v", err)
+		log.WithContext(ctx).Errorf("Failed to update state with audio path: %v", err)
 		return err
 	}
 
@@ -51,8 +52,8 @@ func (p *AudioDownloadTask) Process(ctx context.Context, task *interfaces.Task,
 	engine.GetEventBus().Publish(interfaces.Event{
 		ID:        fmt.Sprintf("evt-%s-audio-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
-		Type:      "AudioDownloaded",
-		Data:      map[string]interface{}{"audio_path": audioPath},
+		Type:      interfaces.EventTypeAudioDownloaded,
+		Data:      interfaces.AudioDownloadedPayload{AudioPath: audioPath},
 		Timestamp: time.Now(),
 	})
 
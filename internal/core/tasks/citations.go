@@ -0,0 +1,97 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// citationInstruction asks for a supporting timestamp on every bullet of the summary
+const citationInstruction = "\n\nThe input is a transcript with an [HH:MM:SS] timestamp at the start of each line. " +
+	"Write the summary as bullet points, and end each bullet with the [HH:MM:SS] timestamp of the transcript line that best supports it, " +
+	"copied exactly from the input."
+
+// citationChunkInstruction keeps the timestamps in the partial summaries of long transcripts
+const citationChunkInstruction = " End each point with the [HH:MM:SS] timestamp of the transcript line it comes from, copied exactly."
+
+// citationPattern matches a [H:MM:SS] or [M:SS] citation, with the link that may follow it
+var citationPattern = regexp.MustCompile(`\[(\d{1,2}:\d{2}(?::\d{2})?)\](\([^)\s]*\))?`)
+
+// wantsCitations reports whether a request's summary should cite transcript timestamps: the
+// request's own choice, or else the configured default
+func wantsCitations(state *interfaces.ProcessingState, cfg *config.AppConfig) bool {
+	if state.Citations != nil {
+		return *state.Citations
+	}
+	return cfg != nil && cfg.SummaryCitations
+}
+
+// writeTimedTranscript writes the segments as an [HH:MM:SS]-stamped transcript file in dir
+func writeTimedTranscript(dir string, segments []timedSegment) (string, error) {
+	f, err := os.CreateTemp(dir, "transcript-*.txt")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(renderTimedTranscript(segments, formatClock))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// linkCitations rewrites the citations in a summary file as [HH:MM:SS](link) markdown links
+// to the start of the cited segment. Citations are left unlinked for sites without
+// timestamp links, and citations past the end of the transcript are dropped.
+func linkCitations(ctx context.Context, summaryPath, videoURL string, segments []timedSegment) error {
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return err
+	}
+	end := 0.0
+	for _, segment := range segments {
+		end = max(end, segment.End)
+	}
+	linked, dropped := 0, 0
+	summary := citationPattern.ReplaceAllStringFunc(string(data), func(match string) string {
+		groups := citationPattern.FindStringSubmatch(match)
+		if groups[2] != "" {
+			return match
+		}
+		seconds, err := parseTimestamp(groups[1])
+		if err != nil {
+			return match
+		}
+		if end > 0 && seconds > end {
+			dropped++
+			return ""
+		}
+		seconds = segmentStartAt(segments, seconds)
+		citation := "[" + formatClock(seconds) + "]"
+		if url := interfaces.VideoTimestampURL(videoURL, seconds); url != "" {
+			linked++
+			return citation + "(" + url + ")"
+		}
+		return citation
+	})
+	if dropped > 0 {
+		log.WithContext(ctx).Warnf("Dropped %d citations past the end of the transcript in %s", dropped, summaryPath)
+	}
+	log.WithContext(ctx).Debugf("Linked %d citations in %s", linked, summaryPath)
+	return os.WriteFile(summaryPath, []byte(summary), 0644)
+}
+
+// formatClock formats seconds as HH:MM:SS
+func formatClock(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total/60%60, total%60)
+}
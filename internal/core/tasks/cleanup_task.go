@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"video-summarizer-go/internal/interfaces"
@@ -26,49 +28,125 @@ func (p *CleanupTask) GetTaskType() interfaces.TaskType {
 
 // Process handles the cleanup task
 func (p *CleanupTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskCleanup for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskCleanup for request: %s", task.RequestID)
 
 	// Get request state for cleanup
 	state, err := engine.GetStore().GetRequestState(task.RequestID)
 	if err != nil {
-		log.Errorf("Failed to get request state for cleanup: %v", err)
+		log.WithContext(ctx).Errorf("Failed to get request state for cleanup: %v", err)
 		return err
 	}
 
 	// Clean up temporary files
-	log.Debugf("Starting cleanup for request: %s", task.RequestID)
+	log.WithContext(ctx).Debugf("Starting cleanup for request: %s", task.RequestID)
 	cleanupErrors := []string{}
 
 	// Clean up audio file
-	if state.AudioPath != "" {
+	if state.AudioPath != "" && !keepForRetry(state, engine, state.AudioPath) {
 		if err := os.Remove(state.AudioPath); err != nil {
 			cleanupError := fmt.Sprintf("Failed to remove audio file %s: %v", state.AudioPath, err)
-			log.Warnf("%s", cleanupError)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
 			cleanupErrors = append(cleanupErrors, cleanupError)
 		} else {
-			log.Debugf("Removed audio file: %s", state.AudioPath)
+			log.WithContext(ctx).Debugf("Removed audio file: %s", state.AudioPath)
 		}
 	}
 
 	// Clean up transcript file
-	if state.Transcript != "" {
+	if state.Transcript != "" && !keepForRetry(state, engine, state.Transcript) {
 		if err := os.Remove(state.Transcript); err != nil {
 			cleanupError := fmt.Sprintf("Failed to remove transcript file %s: %v", state.Transcript, err)
-			log.Warnf("%s", cleanupError)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
 			cleanupErrors = append(cleanupErrors, cleanupError)
 		} else {
-			log.Debugf("Removed transcript file: %s", state.Transcript)
+			log.WithContext(ctx).Debugf("Removed transcript file: %s", state.Transcript)
 		}
 	}
 
 	// Clean up summary file
-	if state.Summary != "" {
+	if state.Summary != "" && !keepForRetry(state, engine, state.Summary) {
 		if err := os.Remove(state.Summary); err != nil {
 			cleanupError := fmt.Sprintf("Failed to remove summary file %s: %v", state.Summary, err)
-			log.Warnf("%s", cleanupError)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
 			cleanupErrors = append(cleanupErrors, cleanupError)
 		} else {
-			log.Debugf("Removed summary file: %s", state.Summary)
+			log.WithContext(ctx).Debugf("Removed summary file: %s", state.Summary)
+		}
+	}
+
+	// Clean up text extracted from a document
+	if state.TextPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.TextPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove extracted text file %s: %v", state.TextPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed extracted text file: %s", state.TextPath)
+		}
+	}
+
+	// Clean up the full video info, which a retried upload needs again
+	if state.InfoPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.InfoPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove video info file %s: %v", state.InfoPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed video info file: %s", state.InfoPath)
+		}
+	}
+
+	// Clean up the timed transcript segments
+	if state.SegmentsPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.SegmentsPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove transcript segments file %s: %v", state.SegmentsPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed transcript segments file: %s", state.SegmentsPath)
+		}
+	}
+
+	// Clean up the text read from the video's frames, which a retried summarization needs again
+	if state.SlidesPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.SlidesPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove slides text file %s: %v", state.SlidesPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed slides text file: %s", state.SlidesPath)
+		}
+	}
+
+	// Clean up the summary's audio, also kept for a retried upload
+	if state.SpeechPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.SpeechPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove summary audio file %s: %v", state.SpeechPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed summary audio file: %s", state.SpeechPath)
+		}
+	}
+
+	// Clean up the downloaded thumbnail, also kept for a retried upload
+	if state.ThumbnailPath != "" && state.Status != interfaces.StatusPartiallyCompleted {
+		if err := os.Remove(state.ThumbnailPath); err != nil {
+			cleanupError := fmt.Sprintf("Failed to remove thumbnail file %s: %v", state.ThumbnailPath, err)
+			log.WithContext(ctx).Warnf("%s", cleanupError)
+			cleanupErrors = append(cleanupErrors, cleanupError)
+		} else {
+			log.WithContext(ctx).Debugf("Removed thumbnail file: %s", state.ThumbnailPath)
+		}
+	}
+
+	// Remove an uploaded document once its request has completed; failed requests keep it
+	// so they can be retried, and the temp directory sweeper removes it later
+	if state.SourceType == interfaces.SourceTypeDocument && state.Status == interfaces.StatusCompleted && isUploadedDocument(state.URL, engine) {
+		if err := os.Remove(state.URL); err != nil {
+			log.WithContext(ctx).Warnf("Failed to remove uploaded document %s: %v", state.URL, err)
+		} else {
+			log.WithContext(ctx).Debugf("Removed uploaded document: %s", state.URL)
 		}
 	}
 
@@ -79,21 +157,49 @@ func (p *CleanupTask) Process(ctx context.Context, task *interfaces.Task, engine
 
 	if len(cleanupErrors) > 0 {
 		// Cleanup errors are warnings, don't fail the request but log them
-		log.Warnf("Cleanup completed with warnings for request: %s", task.RequestID)
+		log.WithContext(ctx).Warnf("Cleanup completed with warnings for request: %s", task.RequestID)
 	}
 
 	engine.GetStore().UpdateRequestState(task.RequestID, updateData)
 
-	log.Debugf("TaskCleanup completed for request: %s", task.RequestID)
+	log.WithContext(ctx).Debugf("TaskCleanup completed for request: %s", task.RequestID)
 
 	// Publish final completion event
 	engine.GetEventBus().Publish(interfaces.Event{
 		ID:        fmt.Sprintf("evt-%s-completed-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
-		Type:      "ProcessingCompleted",
-		Data:      map[string]interface{}{"status": "completed"},
+		Type:      interfaces.EventTypeProcessingCompleted,
+		Data:      interfaces.ProcessingCompletedPayload{Status: string(state.Status)},
 		Timestamp: time.Now(),
 	})
 
 	return nil
 }
+
+// keepForRetry reports whether a file is a checkpointed artifact of a request that did not
+// complete, or the transcript or summary of a partially completed request, so a retry can
+// reuse it instead of redoing the stage
+func keepForRetry(state *interfaces.ProcessingState, engine interfaces.Engine, path string) bool {
+	if state.Status == interfaces.StatusCompleted {
+		return false
+	}
+	if state.Status == interfaces.StatusPartiallyCompleted && (path == state.Transcript || path == state.Summary) {
+		return true
+	}
+	cfg := engine.GetConfig()
+	if cfg == nil || cfg.ArtifactsDir == "" {
+		return false
+	}
+	artifactsDir := filepath.Clean(cfg.ArtifactsDir) + string(filepath.Separator)
+	return strings.HasPrefix(filepath.Clean(path), artifactsDir)
+}
+
+// isUploadedDocument reports whether path is a document uploaded through the API into TmpDir
+func isUploadedDocument(path string, engine interfaces.Engine) bool {
+	cfg := engine.GetConfig()
+	if cfg == nil || cfg.TmpDir == "" {
+		return false
+	}
+	return filepath.Dir(filepath.Clean(path)) == filepath.Clean(cfg.TmpDir) &&
+		strings.HasPrefix(filepath.Base(path), interfaces.UploadedDocumentPrefix)
+}
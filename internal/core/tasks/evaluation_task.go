@@ -0,0 +1,188 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// evaluationPrompt asks the judge for rubric scores as JSON
+const evaluationPrompt = `You are grading a summary of a %s. The input contains the SOURCE and the SUMMARY.%s
+Score the summary from 1 (poor) to 5 (excellent) on each criterion:
+- coverage: the key points of the source are in the summary
+- faithfulness: the summary states nothing the source does not support
+- length: the summary is as long as it needs to be and no longer
+Reply with only a JSON object, for example:
+{"coverage": 4, "faithfulness": 5, "length": 3, "comments": "one or two sentences"}`
+
+// EvaluationTask scores a summary with the summarization provider acting as a judge
+type EvaluationTask struct{}
+
+// NewEvaluationTask creates a new EvaluationTask
+func NewEvaluationTask() *EvaluationTask {
+	return &EvaluationTask{}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *EvaluationTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskEvaluation
+}
+
+// Process scores the summary and records the evaluation. A failed evaluation is logged and
+// the request carries on to output without one.
+func (p *EvaluationTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskEvaluation for request: %s", task.RequestID)
+
+	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
+	payload := interfaces.EvaluationCompletedPayload{SummaryPath: summaryPath}
+
+	evaluation, err := p.evaluate(ctx, task.RequestID, summaryPath, engine)
+	if err != nil {
+		log.WithContext(ctx).Warnf("Failed to evaluate summary for request %s: %v", task.RequestID, err)
+		payload.Error = err.Error()
+	} else {
+		if evaluation.BelowThreshold {
+			log.WithContext(ctx).Warnf("Summary quality regression for request %s: score %.2f (coverage %.0f, faithfulness %.0f, length %.0f) is below %.2f with %s",
+				task.RequestID, evaluation.Score, evaluation.Coverage, evaluation.Faithfulness, evaluation.Length,
+				engine.GetConfig().Evaluation.MinScore, evaluation.Summarizer)
+		}
+		if err := engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"evaluation": *evaluation,
+		}); err != nil {
+			log.WithContext(ctx).Errorf("Failed to update state with evaluation: %v", err)
+			return err
+		}
+	}
+
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-evaluation-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeEvaluationCompleted,
+		Data:      payload,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// evaluate asks the judge to score the summary against the start of its source
+func (p *EvaluationTask) evaluate(ctx context.Context, requestID, summaryPath string, engine interfaces.Engine) (*interfaces.SummaryEvaluation, error) {
+	state, err := engine.GetStore().GetRequestState(requestID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary: %w", err)
+	}
+
+	cfg := engine.GetConfig()
+	chunkSize := defaultSummarizationChunkSize
+	if cfg.SummarizationChunkSize > 0 {
+		chunkSize = cfg.SummarizationChunkSize
+	}
+	source, truncated, err := readSourceStart(state, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	note := ""
+	if truncated {
+		note = " The source was too long and is cut off; judge coverage and faithfulness against the part shown."
+	}
+
+	ctx, usageRecorder := interfaces.WithUsageRecorder(ctx)
+	input := fmt.Sprintf("SOURCE:\n%s\n\nSUMMARY:\n%s", source, summary)
+	reply, err := summarizeToString(ctx, engine.GetSummarizationProvider(), input, fmt.Sprintf(evaluationPrompt, sourceDescription(state), note), 500)
+	if err != nil {
+		return nil, fmt.Errorf("judge failed: %w", err)
+	}
+	evaluation, err := parseEvaluation(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := usageRecorder.Usage()
+	usage.CostUSD = cfg.EstimateCost(usage.PromptTokens, usage.CompletionTokens)
+	evaluation.TokenUsage = &usage
+	evaluation.Summarizer = summarizerName(cfg)
+	evaluation.BelowThreshold = evaluation.Score < cfg.Evaluation.MinScore
+	evaluation.EvaluatedAt = time.Now()
+	return evaluation, nil
+}
+
+// readSourceStart returns the first chunk of the transcript or extracted text a summary was
+// written from, and whether there is more
+func readSourceStart(state *interfaces.ProcessingState, chunkSize int) (string, bool, error) {
+	path := state.Transcript
+	if path == "" {
+		path = state.TextPath
+	}
+	if path == "" {
+		return "", false, fmt.Errorf("request has no transcript or text to evaluate against")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read source: %w", err)
+	}
+	defer f.Close()
+	chunker := newTranscriptChunker(f, chunkSize)
+	text, err := chunker.Next()
+	if err != nil && err != io.EOF {
+		return "", false, fmt.Errorf("failed to read source: %w", err)
+	}
+	_, err = chunker.Next()
+	return text, err != io.EOF, nil
+}
+
+// parseEvaluation reads the judge's JSON reply, which may be wrapped in prose or a code fence
+func parseEvaluation(reply string) (*interfaces.SummaryEvaluation, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("judge reply has no JSON object: %q", truncateForLog(reply))
+	}
+	var evaluation interfaces.SummaryEvaluation
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &evaluation); err != nil {
+		return nil, fmt.Errorf("failed to parse judge reply: %w", err)
+	}
+	for name, score := range map[string]float64{"coverage": evaluation.Coverage, "faithfulness": evaluation.Faithfulness, "length": evaluation.Length} {
+		if score < 1 || score > 5 {
+			return nil, fmt.Errorf("judge gave %s score %g, expected 1-5", name, score)
+		}
+	}
+	evaluation.Score = (evaluation.Coverage + evaluation.Faithfulness + evaluation.Length) / 3
+	return &evaluation, nil
+}
+
+// truncateForLog shortens text quoted in an error message
+func truncateForLog(s string) string {
+	if len(s) > 200 {
+		return s[:200] + "..."
+	}
+	return s
+}
+
+// summarizerName identifies the configured summarizer, including the model where there is one
+func summarizerName(cfg *config.AppConfig) string {
+	if cfg.SummarizerProvider == "openai" && cfg.OpenAIModel != "" {
+		return "openai/" + cfg.OpenAIModel
+	}
+	return cfg.SummarizerProvider
+}
+
+// ShouldEvaluate reports whether a new summary should be evaluated, sampling at the
+// configured rate
+func ShouldEvaluate(cfg *config.AppConfig) bool {
+	if cfg == nil || !cfg.Evaluation.Enabled {
+		return false
+	}
+	return cfg.Evaluation.SampleRate >= 1 || rand.Float64() < cfg.Evaluation.SampleRate
+}
@@ -0,0 +1,213 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// highlightsPrompt asks for the key moments of a timed transcript as JSON
+const highlightsPrompt = `The input is a video transcript%s with a [timestamp] at the start of each line.
+Pick at most %d key moments a viewer would want to jump straight to: main points, conclusions, demonstrations, notable quotes.
+For each moment give the timestamp of the line where it starts, a short title, one sentence on why it matters, and its importance from 1 (minor) to 5 (essential).
+Reply with only a JSON object, for example:
+{"moments": [{"timestamp": "12:34", "title": "Benchmark results", "description": "The new version is twice as fast.", "importance": 4}]}`
+
+// HighlightsTask picks the key moments of a video from its timed transcript, with the
+// summarization provider choosing among the transcript segments
+type HighlightsTask struct{}
+
+// NewHighlightsTask creates a new HighlightsTask
+func NewHighlightsTask() *HighlightsTask {
+	return &HighlightsTask{}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *HighlightsTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskHighlights
+}
+
+// Process picks the key moments and records them. A failure is logged and the request
+// carries on without highlights.
+func (p *HighlightsTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskHighlights for request: %s", task.RequestID)
+
+	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
+	payload := interfaces.HighlightsCompletedPayload{SummaryPath: summaryPath}
+
+	highlights, err := p.extract(ctx, task.RequestID, engine)
+	if err != nil {
+		log.WithContext(ctx).Warnf("Failed to pick highlights for request %s: %v", task.RequestID, err)
+		payload.Error = err.Error()
+	} else {
+		log.WithContext(ctx).Debugf("Picked %d highlights for request %s", len(highlights.Moments), task.RequestID)
+		if err := engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"highlights": *highlights,
+		}); err != nil {
+			log.WithContext(ctx).Errorf("Failed to update state with highlights: %v", err)
+			return err
+		}
+	}
+
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-highlights-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeHighlightsCompleted,
+		Data:      payload,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// extract asks for the key moments of each chunk of the timed transcript and keeps the most
+// important ones, in the order they appear in the video
+func (p *HighlightsTask) extract(ctx context.Context, requestID string, engine interfaces.Engine) (*interfaces.Highlights, error) {
+	state, err := engine.GetStore().GetRequestState(requestID)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := readSegmentsArtifact(state.SegmentsPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("transcript has no timed segments")
+	}
+
+	cfg := engine.GetConfig()
+	chunkSize := defaultSummarizationChunkSize
+	if cfg.SummarizationChunkSize > 0 {
+		chunkSize = cfg.SummarizationChunkSize
+	}
+	maxMoments := cfg.Highlights.MaxMoments
+
+	ctx, usageRecorder := interfaces.WithUsageRecorder(ctx)
+	timedTranscript := renderTimedTranscript(segments, formatTimestamp)
+	chunker := newTranscriptChunker(strings.NewReader(timedTranscript), chunkSize)
+	var moments []interfaces.Highlight
+	for part := 1; ; part++ {
+		text, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		note := ""
+		if len(timedTranscript) > chunkSize {
+			note = fmt.Sprintf(" (part %d of a longer video)", part)
+		}
+		reply, err := summarizeToString(ctx, engine.GetSummarizationProvider(), text, fmt.Sprintf(highlightsPrompt, note, maxMoments), 1000)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pick highlights of part %d: %w", part, err)
+		}
+		picked, err := parseHighlights(ctx, reply, segments)
+		if err != nil {
+			return nil, err
+		}
+		moments = append(moments, picked...)
+	}
+
+	videoURL := state.URL
+	if webpageURL, ok := state.VideoInfo["webpage_url"].(string); ok && webpageURL != "" {
+		videoURL = webpageURL
+	}
+	moments = topHighlights(moments, maxMoments)
+	for i := range moments {
+		moments[i].URL = interfaces.VideoTimestampURL(videoURL, moments[i].Start)
+	}
+
+	usage := usageRecorder.Usage()
+	usage.CostUSD = cfg.EstimateCost(usage.PromptTokens, usage.CompletionTokens)
+	return &interfaces.Highlights{Moments: moments, TokenUsage: &usage, ExtractedAt: time.Now()}, nil
+}
+
+// parseHighlights reads the model's JSON reply, which may be wrapped in prose or a code
+// fence, moving each moment to the start of the segment it falls in. Moments with an
+// unreadable timestamp are dropped.
+func parseHighlights(ctx context.Context, reply string, segments []timedSegment) ([]interfaces.Highlight, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("highlights reply has no JSON object: %q", truncateForLog(reply))
+	}
+	var parsed struct {
+		Moments []struct {
+			Timestamp   string `json:"timestamp"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Importance  int    `json:"importance"`
+		} `json:"moments"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse highlights reply: %w", err)
+	}
+
+	var moments []interfaces.Highlight
+	for _, moment := range parsed.Moments {
+		seconds, err := parseTimestamp(moment.Timestamp)
+		if err != nil || strings.TrimSpace(moment.Title) == "" {
+			log.WithContext(ctx).Debugf("Dropping highlight %q at %q", moment.Title, moment.Timestamp)
+			continue
+		}
+		seconds = segmentStartAt(segments, seconds)
+		moments = append(moments, interfaces.Highlight{
+			Start:       seconds,
+			Timestamp:   formatTimestamp(seconds),
+			Title:       strings.TrimSpace(moment.Title),
+			Description: strings.TrimSpace(moment.Description),
+			Importance:  min(max(moment.Importance, 1), 5),
+		})
+	}
+	return moments, nil
+}
+
+// topHighlights keeps the most important moment at each timestamp and at most limit moments
+// overall, favoring the most important, and returns them in video order
+func topHighlights(moments []interfaces.Highlight, limit int) []interfaces.Highlight {
+	sort.SliceStable(moments, func(i, j int) bool { return moments[i].Importance > moments[j].Importance })
+	seen := make(map[float64]bool)
+	var kept []interfaces.Highlight
+	for _, moment := range moments {
+		if seen[moment.Start] || len(kept) == limit {
+			continue
+		}
+		seen[moment.Start] = true
+		kept = append(kept, moment)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start < kept[j].Start })
+	return kept
+}
+
+// renderHighlights formats highlights as text for upload, one moment per paragraph
+func renderHighlights(title string, highlights *interfaces.Highlights) string {
+	var b strings.Builder
+	if title != "" {
+		fmt.Fprintf(&b, "Key moments: %s\n\n", title)
+	}
+	for _, moment := range highlights.Moments {
+		fmt.Fprintf(&b, "[%s] %s\n", moment.Timestamp, moment.Title)
+		if moment.Description != "" {
+			fmt.Fprintf(&b, "%s\n", moment.Description)
+		}
+		if moment.URL != "" {
+			fmt.Fprintf(&b, "%s\n", moment.URL)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ShouldExtractHighlights reports whether a request's summary should be followed by picking
+// its key moments, which needs a transcript with segment times
+func ShouldExtractHighlights(cfg *config.AppConfig, state *interfaces.ProcessingState) bool {
+	return cfg != nil && cfg.Highlights.Enabled && state.SegmentsPath != ""
+}
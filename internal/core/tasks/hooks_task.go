@@ -0,0 +1,192 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// hookOutputLimit caps how much of a failed hook's output or response is kept in its error
+const hookOutputLimit = 500
+
+// HookRequest describes a request to a post-processing hook. Commands receive it as JSON on
+// stdin, webhooks as the JSON body of a POST.
+type HookRequest struct {
+	Hook           string            `json:"hook"`
+	RequestID      string            `json:"request_id"`
+	SourceType     string            `json:"source_type"`
+	URL            string            `json:"url"`
+	Title          string            `json:"title,omitempty"`
+	Category       string            `json:"category"`
+	User           string            `json:"user,omitempty"`
+	SummaryPath    string            `json:"summary_path"`
+	TranscriptPath string            `json:"transcript_path,omitempty"`
+	TextPath       string            `json:"text_path,omitempty"`
+	InfoPath       string            `json:"info_path,omitempty"` // full video metadata as JSON
+	ThumbnailPath  string            `json:"thumbnail_path,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// HooksTask runs the configured post-processing hooks after a request's outputs are uploaded
+type HooksTask struct {
+	client *http.Client
+}
+
+// NewHooksTask creates a new HooksTask
+func NewHooksTask() *HooksTask {
+	return &HooksTask{client: &http.Client{}}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *HooksTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskHooks
+}
+
+// Process runs the hooks for the request's category one after another, while the summary and
+// transcript files still exist. Hook failures are recorded and logged; the request stays
+// completed either way.
+func (p *HooksTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskHooks for request: %s", task.RequestID)
+
+	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
+	state, err := engine.GetStore().GetRequestState(task.RequestID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("Failed to get request state for hooks: %v", err)
+		return err
+	}
+
+	var results []interfaces.HookResult
+	for _, hook := range engine.GetConfig().HooksFor(state.Category) {
+		request := HookRequest{
+			Hook:           hook.Name,
+			RequestID:      state.RequestID,
+			SourceType:     state.SourceType,
+			URL:            state.URL,
+			Title:          state.Title(),
+			Category:       state.Category,
+			User:           state.User,
+			SummaryPath:    summaryPath,
+			TranscriptPath: state.Transcript,
+			TextPath:       state.TextPath,
+			InfoPath:       state.InfoPath,
+			ThumbnailPath:  state.ThumbnailPath,
+			Tags:           state.Tags,
+			Metadata:       state.Metadata,
+		}
+		start := time.Now()
+		err := p.run(ctx, hook, request)
+		result := interfaces.HookResult{
+			Name:       hook.Name,
+			DurationMs: time.Since(start).Milliseconds(),
+			FinishedAt: time.Now(),
+		}
+		if err != nil {
+			log.WithContext(ctx).Warnf("Hook %s failed for request %s: %v", hook.Name, task.RequestID, err)
+			result.Error = err.Error()
+		} else {
+			log.WithContext(ctx).Debugf("Hook %s completed for request %s in %dms", hook.Name, task.RequestID, result.DurationMs)
+		}
+		results = append(results, result)
+	}
+
+	if err := engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+		"hooks": results,
+	}); err != nil {
+		log.WithContext(ctx).Errorf("Failed to update state with hook results: %v", err)
+	}
+
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-hooks-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeHooksCompleted,
+		Data:      interfaces.HooksCompletedPayload{SummaryPath: summaryPath},
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// run invokes one hook, giving up after its timeout
+func (p *HooksTask) run(ctx context.Context, hook config.HookConfig, request HookRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	var runErr error
+	if hook.URL != "" {
+		runErr = p.post(ctx, hook.URL, body)
+	} else {
+		runErr = runHookCommand(ctx, hook.Command, request, body)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", hook.GetTimeout())
+	}
+	return runErr
+}
+
+// post sends the request to a webhook, treating any non-2xx response as a failure
+func (p *HooksTask) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, hookOutputLimit))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// runHookCommand runs a hook program with the request as JSON on stdin and the main fields in
+// VS_HOOK_* environment variables
+func runHookCommand(ctx context.Context, command []string, request HookRequest, body []byte) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"VS_HOOK_NAME="+request.Hook,
+		"VS_HOOK_REQUEST_ID="+request.RequestID,
+		"VS_HOOK_SOURCE_TYPE="+request.SourceType,
+		"VS_HOOK_URL="+request.URL,
+		"VS_HOOK_TITLE="+request.Title,
+		"VS_HOOK_CATEGORY="+request.Category,
+		"VS_HOOK_USER="+request.User,
+		"VS_HOOK_SUMMARY_PATH="+request.SummaryPath,
+		"VS_HOOK_TRANSCRIPT_PATH="+request.TranscriptPath,
+		"VS_HOOK_TEXT_PATH="+request.TextPath,
+		"VS_HOOK_INFO_PATH="+request.InfoPath,
+		"VS_HOOK_THUMBNAIL_PATH="+request.ThumbnailPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		if len(out) > hookOutputLimit {
+			out = out[len(out)-hookOutputLimit:]
+		}
+		if out == "" {
+			return err
+		}
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -26,12 +28,12 @@ func (p *OutputTask) GetTaskType() interfaces.TaskType {
 
 // Process handles the output task
 func (p *OutputTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskOutput for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskOutput for request: %s", task.RequestID)
 
 	// Get request state for upload
 	state, err := engine.GetStore().GetRequestState(task.RequestID)
 	if err != nil {
-		log.Errorf("Failed to get request state for output: %v", err)
+		log.WithContext(ctx).Errorf("Failed to get request state for output: %v", err)
 		return err
 	}
 
@@ -41,33 +43,128 @@ func (p *OutputTask) Process(ctx context.Context, task *interfaces.Task, engine
 		category = "general"
 	}
 
-	// Use hardcoded values for user and category for now
-	user := "admin"
+	// Outputs are filed under the submitting user; requests without one belong to admin
+	user := state.User
+	if user == "" {
+		user = "admin"
+	}
+
+	// Upload toggles are read per task so config reloads apply to the next output
+	uploadSummary, uploadTranscript, uploadInfo, uploadThumbnail := true, true, false, false
+	if cfg := engine.GetConfig(); cfg != nil {
+		uploadSummary, uploadTranscript = cfg.UploadSummary, cfg.UploadTranscript
+		uploadInfo, uploadThumbnail = cfg.UploadInfoJSON, cfg.UploadThumbnail
+	}
 
-	// Upload summary and/or transcript if outputProvider is set
+	// Upload summary and/or transcript to each of the request's output providers
 	uploadErrors := []string{}
-	if engine.GetOutputProvider() != nil {
+	// The code of the first failed upload categorizes the request's failure
+	var errorCode interfaces.ErrorCode
+	uploadFailed := func(uploadError string, err error) {
+		log.WithContext(ctx).Errorf("%s", uploadError)
+		uploadErrors = append(uploadErrors, uploadError)
+		if errorCode == "" {
+			errorCode = interfaces.ErrorCodeOf(err, interfaces.ErrorCodeUploadFailed)
+		}
+	}
+	outputProviders, resolveErrs := ResolveOutputProviders(state, engine)
+	for _, err := range resolveErrs {
+		log.WithContext(ctx).Errorf("Output for request %s: %v", task.RequestID, err)
+		uploadErrors = append(uploadErrors, err.Error())
+		if errorCode == "" {
+			errorCode = interfaces.ErrorCodeNotConfigured
+		}
+	}
+	// Uploads the providers verified, and the summary's link at the first provider with one
+	var outputs []interfaces.OutputFile
+	outputPath := ""
+	for _, resolved := range outputProviders {
+		outputProvider, providerName := resolved.Provider, resolved.Name
 		videoInfo := state.VideoInfo
-		if state.Summary != "" && videoInfo != nil {
-			log.Debugf("Uploading summary for request: %s to user: %s, category: %s", task.RequestID, user, category)
-			err := engine.GetOutputProvider().UploadSummary(task.RequestID, videoInfo, state.Summary, category, user)
+		if videoInfo == nil {
+			// Documents, articles and digests have no video info; their title comes from the document metadata
+			videoInfo = state.DocumentInfo
+		}
+		// verify checks an uploaded file arrived intact when the provider can, so the local
+		// copy is only cleaned up once the remote one is known to be good
+		verify := func(what, localPath, suffix string) *interfaces.OutputFile {
+			verifiable, ok := outputProvider.(interfaces.VerifiableOutputProvider)
+			if !ok {
+				return nil
+			}
+			uploaded, err := verifiable.VerifyOutput(task.RequestID, videoInfo, localPath, suffix, category, user)
+			if err != nil {
+				uploadFailed(fmt.Sprintf("%s verify %s error: %v", providerName, what, err), interfaces.WithErrorCode(interfaces.ErrorCodeUploadUnverified, err))
+				return nil
+			}
+			if uploaded != nil {
+				outputs = append(outputs, *uploaded)
+			}
+			return uploaded
+		}
+		if uploadSummary && state.Summary != "" && videoInfo != nil {
+			log.WithContext(ctx).Debugf("Uploading summary for request: %s to user: %s, category: %s", task.RequestID, user, category)
+			err := outputProvider.UploadSummary(task.RequestID, videoInfo, state.Summary, category, user)
+			if err != nil {
+				uploadFailed(fmt.Sprintf("%s upload summary error: %v", providerName, err), err)
+			} else {
+				log.WithContext(ctx).Debugf("Summary uploaded successfully for request: %s", task.RequestID)
+				if uploaded := verify("summary", state.Summary, "summary.txt"); uploaded != nil && outputPath == "" {
+					outputPath = uploaded.URL
+					if outputPath == "" {
+						outputPath = uploaded.ID
+					}
+				}
+			}
+			if withMetadata, ok := outputProvider.(interfaces.MetadataOutputProvider); ok {
+				err := withMetadata.UploadMetadata(task.RequestID, videoInfo, outputMetadata(state), category, user)
+				if err != nil {
+					uploadFailed(fmt.Sprintf("%s upload metadata error: %v", providerName, err), err)
+				}
+			}
+		}
+		if uploadTranscript && state.Transcript != "" && videoInfo != nil {
+			log.WithContext(ctx).Debugf("Uploading transcript for request: %s to user: %s, category: %s", task.RequestID, user, category)
+			err := outputProvider.UploadTranscript(task.RequestID, videoInfo, state.Transcript, category, user)
+			if err != nil {
+				uploadFailed(fmt.Sprintf("%s upload transcript error: %v", providerName, err), err)
+			} else {
+				log.WithContext(ctx).Debugf("Transcript uploaded successfully for request: %s", task.RequestID)
+				verify("transcript", state.Transcript, "transcript.txt")
+			}
+		}
+		if withAttachments, ok := outputProvider.(interfaces.AttachmentOutputProvider); ok && uploadInfo && state.InfoPath != "" && videoInfo != nil {
+			err := withAttachments.UploadAttachment(task.RequestID, videoInfo, state.InfoPath, "info.json", category, user)
+			if err != nil {
+				uploadFailed(fmt.Sprintf("%s upload video info error: %v", providerName, err), err)
+			} else {
+				verify("video info", state.InfoPath, "info.json")
+			}
+		}
+		if withAttachments, ok := outputProvider.(interfaces.AttachmentOutputProvider); ok && uploadThumbnail && state.ThumbnailPath != "" && videoInfo != nil {
+			suffix := "thumbnail" + filepath.Ext(state.ThumbnailPath)
+			err := withAttachments.UploadAttachment(task.RequestID, videoInfo, state.ThumbnailPath, suffix, category, user)
 			if err != nil {
-				uploadError := fmt.Sprintf("GDrive upload summary error: %v", err)
-				log.Errorf("%s", uploadError)
-				uploadErrors = append(uploadErrors, uploadError)
+				uploadFailed(fmt.Sprintf("%s upload thumbnail error: %v", providerName, err), err)
 			} else {
-				log.Debugf("Summary uploaded successfully for request: %s", task.RequestID)
+				verify("thumbnail", state.ThumbnailPath, suffix)
 			}
 		}
-		if state.Transcript != "" && videoInfo != nil {
-			log.Debugf("Uploading transcript for request: %s to user: %s, category: %s", task.RequestID, user, category)
-			err := engine.GetOutputProvider().UploadTranscript(task.RequestID, videoInfo, state.Transcript, category, user)
+		if withAttachments, ok := outputProvider.(interfaces.AttachmentOutputProvider); ok && state.SpeechPath != "" && videoInfo != nil {
+			suffix := "summary" + filepath.Ext(state.SpeechPath)
+			err := withAttachments.UploadAttachment(task.RequestID, videoInfo, state.SpeechPath, suffix, category, user)
 			if err != nil {
-				uploadError := fmt.Sprintf("GDrive upload transcript error: %v", err)
-				log.Errorf("%s", uploadError)
-				uploadErrors = append(uploadErrors, uploadError)
+				uploadFailed(fmt.Sprintf("%s upload summary audio error: %v", providerName, err), err)
 			} else {
-				log.Debugf("Transcript uploaded successfully for request: %s", task.RequestID)
+				verify("summary audio", state.SpeechPath, suffix)
+			}
+		}
+		if withAttachments, ok := outputProvider.(interfaces.AttachmentOutputProvider); ok && state.Highlights != nil && len(state.Highlights.Moments) > 0 && videoInfo != nil {
+			err := uploadHighlights(withAttachments, state, videoInfo, category, user, func(path string) {
+				verify("highlights", path, "highlights.txt")
+			})
+			if err != nil {
+				uploadFailed(fmt.Sprintf("%s upload highlights error: %v", providerName, err), err)
 			}
 		}
 	}
@@ -77,25 +174,29 @@ func (p *OutputTask) Process(ctx context.Context, task *interfaces.Task, engine
 	finalError := ""
 
 	if len(uploadErrors) > 0 {
-		finalStatus = interfaces.StatusFailed
+		// The transcript and summary are kept, so a retry only repeats the upload
+		finalStatus = interfaces.StatusPartiallyCompleted
 		finalError = fmt.Sprintf("Upload errors: %s", strings.Join(uploadErrors, "; "))
 	}
 
 	// Update state with upload results
 	updateData := map[string]interface{}{
-		"status": finalStatus,
+		"status":      finalStatus,
+		"outputs":     outputs,
+		"output_path": outputPath,
 	}
 
 	if finalError != "" {
 		updateData["error"] = finalError
+		updateData["error_code"] = errorCode
 	}
 
 	err = engine.GetStore().UpdateRequestState(task.RequestID, updateData)
 	if err != nil {
-		log.Errorf("Failed to update state after output: %v", err)
+		log.WithContext(ctx).Errorf("Failed to update state after output: %v", err)
 	}
 
-	log.Debugf("TaskOutput completed for request: %s with status: %s", task.RequestID, finalStatus)
+	log.WithContext(ctx).Debugf("TaskOutput completed for request: %s with status: %s", task.RequestID, finalStatus)
 
 	// Publish output completion event (cleanup will be triggered by this)
 	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
@@ -103,9 +204,150 @@ func (p *OutputTask) Process(ctx context.Context, task *interfaces.Task, engine
 		ID:        fmt.Sprintf("evt-%s-output-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
 		Type:      interfaces.EventTypeOutputCompleted,
-		Data:      map[string]interface{}{"summary": summaryPath, "status": finalStatus},
+		Data:      interfaces.OutputCompletedPayload{SummaryPath: summaryPath, Status: string(finalStatus)},
 		Timestamp: time.Now(),
 	})
 
 	return nil
 }
+
+// ResolvedOutput is an output provider a request's outputs go to, named for error messages
+type ResolvedOutput struct {
+	Provider interfaces.OutputProvider
+	Name     string
+}
+
+// ResolveOutputProviders returns the output providers for a request: its own output
+// override, else the output_routes entry for its category, else the configured output
+// provider. A route can list several providers, or none to keep the category's requests
+// out of every output (they still reach digests, feeds and the API). Targets that can't be
+// used are returned as errors alongside the rest.
+func ResolveOutputProviders(state *interfaces.ProcessingState, engine interfaces.Engine) ([]ResolvedOutput, []error) {
+	if state.Output != nil {
+		provider, err := resolveOutputTarget(state.Output, engine)
+		if err != nil {
+			return nil, []error{err}
+		}
+		return []ResolvedOutput{{provider, state.Output.Provider}}, nil
+	}
+
+	cfg := engine.GetConfig()
+	if cfg == nil {
+		if provider := engine.GetOutputProvider(); provider != nil {
+			return []ResolvedOutput{{provider, "Output"}}, nil
+		}
+		return nil, nil
+	}
+	category := state.Category
+	if category == "" {
+		category = "general"
+	}
+	routes, routed := cfg.OutputRoutes[category]
+	if !routed {
+		if provider := engine.GetOutputProvider(); provider != nil {
+			return []ResolvedOutput{{provider, cfg.OutputProvider}}, nil
+		}
+		return nil, nil
+	}
+	var resolved []ResolvedOutput
+	var errs []error
+	for _, route := range routes {
+		target := &interfaces.OutputTarget{Provider: route.Provider, Channel: route.Channel, FolderID: route.FolderID}
+		provider, err := resolveOutputTarget(target, engine)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("category %s route: %w", category, err))
+			continue
+		}
+		resolved = append(resolved, ResolvedOutput{provider, route.Provider})
+	}
+	return resolved, errs
+}
+
+// resolveOutputTarget returns the registered provider a target names, sending to the
+// target's destination if it has one
+func resolveOutputTarget(target *interfaces.OutputTarget, engine interfaces.Engine) (interfaces.OutputProvider, error) {
+	provider := engine.GetNamedOutputProvider(target.Provider)
+	if provider == nil {
+		return nil, fmt.Errorf("output provider %q is not configured", target.Provider)
+	}
+	if destination := target.Destination(); destination != "" {
+		withDestination, ok := provider.(interfaces.DestinationOutputProvider)
+		if !ok {
+			return nil, fmt.Errorf("output provider %q does not support a destination override", target.Provider)
+		}
+		provider = withDestination.WithDestination(destination)
+	}
+	return provider, nil
+}
+
+// uploadHighlights writes the request's key moments to a temp file and uploads it, calling
+// verify with the file once it is uploaded
+func uploadHighlights(provider interfaces.AttachmentOutputProvider, state *interfaces.ProcessingState, videoInfo map[string]interface{}, category, user string, verify func(path string)) error {
+	f, err := os.CreateTemp("", "highlights-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to write highlights: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(renderHighlights(state.Title(), state.Highlights))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write highlights: %w", err)
+	}
+	if err := provider.UploadAttachment(state.RequestID, videoInfo, f.Name(), "highlights.txt", category, user); err != nil {
+		return err
+	}
+	verify(f.Name())
+	return nil
+}
+
+// outputMetadata describes a request for the metadata sidecar stored with its output
+func outputMetadata(state *interfaces.ProcessingState) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"request_id":  state.RequestID,
+		"url":         state.URL,
+		"title":       state.Title(),
+		"source_type": state.SourceType,
+		"category":    state.Category,
+		"prompt":      state.Prompt,
+		"created_at":  state.CreatedAt,
+	}
+	if state.User != "" {
+		metadata["user"] = state.User
+	}
+	if state.Source != "" {
+		metadata["source"] = state.Source
+	}
+	if len(state.Tags) > 0 {
+		metadata["tags"] = state.Tags
+	}
+	if len(state.Metadata) > 0 {
+		metadata["metadata"] = state.Metadata
+	}
+	if state.TokenUsage != nil {
+		metadata["token_usage"] = state.TokenUsage
+	}
+	if state.TranscriptQuality != nil {
+		metadata["transcript_quality"] = state.TranscriptQuality
+	}
+	if state.Language != "" {
+		metadata["language"] = state.Language
+	}
+	if state.WhisperModel != "" {
+		metadata["whisper_model"] = state.WhisperModel
+	}
+	if state.TranscriptionRoute != "" {
+		metadata["transcription_route"] = state.TranscriptionRoute
+	}
+	if state.Highlights != nil {
+		metadata["highlights"] = state.Highlights.Moments
+	}
+	if state.Evaluation != nil {
+		metadata["evaluation"] = state.Evaluation
+	}
+	if len(state.Redactions) > 0 {
+		metadata["redactions"] = state.Redactions
+	}
+	return metadata
+}
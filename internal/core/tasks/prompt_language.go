@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// languageNames names the languages whisper commonly detects, for the respond-in instruction
+var languageNames = map[string]string{
+	"ar": "Arabic", "bn": "Bengali", "cs": "Czech", "da": "Danish", "de": "German", "el": "Greek",
+	"en": "English", "es": "Spanish", "fa": "Persian", "fi": "Finnish", "fr": "French", "he": "Hebrew",
+	"hi": "Hindi", "hu": "Hungarian", "id": "Indonesian", "it": "Italian", "ja": "Japanese", "ko": "Korean",
+	"ms": "Malay", "nl": "Dutch", "no": "Norwegian", "pl": "Polish", "pt": "Portuguese", "ro": "Romanian",
+	"ru": "Russian", "sv": "Swedish", "ta": "Tamil", "te": "Telugu", "th": "Thai", "tr": "Turkish",
+	"uk": "Ukrainian", "ur": "Urdu", "vi": "Vietnamese", "zh": "Chinese",
+}
+
+// languageName returns the English name of a language code, or the code if it isn't known
+func languageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
+
+// promptForLanguage adapts the resolved prompt text to the transcript's language. With a
+// prompt ID in variant mode the prompt's variant for the language is used when one exists;
+// otherwise, in variant and instruct mode, the model is told to respond in that language.
+// Transcripts in the prompt's own language, or of unknown language, keep the prompt as is.
+func promptForLanguage(ctx context.Context, state *interfaces.ProcessingState, pm *config.PromptManager, cfg *config.AppConfig, promptText string) string {
+	mode := state.LanguageMode
+	if mode == "" && cfg != nil {
+		mode = cfg.PromptLanguageMode
+	}
+	language := strings.ToLower(state.Language)
+	if mode == "" || mode == config.PromptLanguageOff || language == "" || language == "auto" {
+		return promptText
+	}
+
+	promptLanguage := "en"
+	if state.Prompt.Type == interfaces.PromptTypeID && pm != nil {
+		if prompt, err := pm.GetPrompt(state.Prompt.Prompt); err == nil {
+			promptLanguage = prompt.PromptLanguage()
+		}
+	}
+	if strings.EqualFold(promptLanguage, language) {
+		return promptText
+	}
+
+	if mode == config.PromptLanguageVariant && state.Prompt.Type == interfaces.PromptTypeID && pm != nil {
+		if variant, ok := pm.GetVariant(state.Prompt.Prompt, language); ok {
+			log.WithContext(ctx).Infof("Using prompt %s for %s transcript of request %s", variant.ID, languageName(language), state.RequestID)
+			return variant.Content
+		}
+	}
+	log.WithContext(ctx).Infof("Asking for a %s summary of request %s", languageName(language), state.RequestID)
+	return fmt.Sprintf("%s\n\nThe input is in %s. Write your response in %s.", promptText, languageName(language), languageName(language))
+}
@@ -0,0 +1,175 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/redaction"
+)
+
+// namesPrompt asks the summarization provider to act as a named-entity recognizer
+const namesPrompt = `List the names of all people mentioned in the input, including every form a name ` +
+	`appears in (for example both "Jane Doe" and "Jane"). Do not list organizations, places or products. ` +
+	`Reply with only a JSON array of strings, or [] if no people are named.`
+
+// RedactionTask scrubs the transcript and summary of a request before they are uploaded
+type RedactionTask struct{}
+
+// NewRedactionTask creates a new RedactionTask
+func NewRedactionTask() *RedactionTask {
+	return &RedactionTask{}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *RedactionTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskRedaction
+}
+
+// Process redacts the transcript and summary files in place. If redaction fails nothing is
+// uploaded; the request is left partially completed so a retry repeats the redaction.
+func (p *RedactionTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskRedaction for request: %s", task.RequestID)
+
+	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
+	counts, summaryText, err := p.redact(ctx, task.RequestID, summaryPath, engine)
+	if err != nil {
+		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"status":     interfaces.StatusPartiallyCompleted,
+			"error":      fmt.Sprintf("Failed to redact outputs: %v", err),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeRedactionFailed),
+		})
+		return err
+	}
+
+	err = engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+		"redactions":   counts,
+		"summary_text": summaryText,
+	})
+	if err != nil {
+		log.WithContext(ctx).Errorf("Failed to update state with redactions: %v", err)
+		return err
+	}
+	log.WithContext(ctx).Infof("Redacted request %s: %v", task.RequestID, counts)
+
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-redaction-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeRedactionCompleted,
+		Data:      interfaces.RedactionCompletedPayload{SummaryPath: summaryPath},
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// redact applies the request category's rules to the transcript and summary, returning the
+// replacements made and the redacted summary text
+func (p *RedactionTask) redact(ctx context.Context, requestID, summaryPath string, engine interfaces.Engine) (map[string]int, string, error) {
+	state, err := engine.GetStore().GetRequestState(requestID)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := engine.GetConfig()
+	rules := cfg.Redaction.RulesFor(state.Category)
+
+	var files []string
+	if state.Transcript != "" {
+		files = append(files, state.Transcript)
+	}
+	files = append(files, summaryPath)
+
+	var names []string
+	if rules.Names {
+		chunkSize := defaultSummarizationChunkSize
+		if cfg.SummarizationChunkSize > 0 {
+			chunkSize = cfg.SummarizationChunkSize
+		}
+		if names, err = findNames(ctx, engine.GetSummarizationProvider(), files, chunkSize); err != nil {
+			return nil, "", fmt.Errorf("failed to find names: %w", err)
+		}
+	}
+
+	redactor, err := redaction.New(rules, names)
+	if err != nil {
+		return nil, "", err
+	}
+	counts := make(map[string]int)
+	for _, path := range files {
+		if err := redactor.RedactFile(path, counts); err != nil {
+			return nil, "", err
+		}
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read redacted summary: %w", err)
+	}
+	return counts, string(summary), nil
+}
+
+// findNames asks the summarization provider for the person names in each chunk of the files
+func findNames(ctx context.Context, provider interfaces.SummarizationProvider, files []string, chunkSize int) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		chunker := newTranscriptChunker(f, chunkSize)
+		for {
+			text, err := chunker.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			reply, err := summarizeToString(ctx, provider, text, namesPrompt, 1000)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			found, err := parseNames(reply)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			for _, name := range found {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		f.Close()
+	}
+	return names, nil
+}
+
+// parseNames reads the JSON array of names in a reply, which may be wrapped in prose or a
+// code fence. Single letters are dropped, since they would match far too much.
+func parseNames(reply string) ([]string, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("name list reply has no JSON array: %q", truncateForLog(reply))
+	}
+	var found []string
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &found); err != nil {
+		return nil, fmt.Errorf("failed to parse name list reply: %w", err)
+	}
+	names := found[:0]
+	for _, name := range found {
+		if name = strings.TrimSpace(name); len([]rune(name)) > 1 {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
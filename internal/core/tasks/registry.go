@@ -21,6 +21,13 @@ func NewTaskProcessorRegistry() *TaskProcessorRegistry {
 	registry.Register(NewOutputTask())
 	registry.Register(NewCleanupTask())
 	registry.Register(NewAudioDownloadTask())
+	registry.Register(NewTextExtractionTask())
+	registry.Register(NewSlidesTask())
+	registry.Register(NewHighlightsTask())
+	registry.Register(NewEvaluationTask())
+	registry.Register(NewRedactionTask())
+	registry.Register(NewSpeechTask())
+	registry.Register(NewHooksTask())
 	return registry
 }
 
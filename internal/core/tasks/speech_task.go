@@ -0,0 +1,122 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+var (
+	// markdownLinkPattern matches a [text](url) link, which is read as its text
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)\s]*\)`)
+	// bareURLPattern matches a URL written out in the text, which isn't read at all
+	bareURLPattern = regexp.MustCompile(`<?https?://\S+>?`)
+	// markdownLinePattern matches heading, quote and bullet markers at the start of a line
+	markdownLinePattern = regexp.MustCompile(`^\s*(#{1,6}\s+|>\s*|[-*+]\s+)`)
+)
+
+// SpeechTask reads a request's final summary aloud, so it can be listened to as well as read
+type SpeechTask struct{}
+
+// NewSpeechTask creates a new SpeechTask
+func NewSpeechTask() *SpeechTask {
+	return &SpeechTask{}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *SpeechTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskSpeech
+}
+
+// Process reads the summary aloud and records the audio file. A failure is logged and the
+// summary is uploaded without it.
+func (p *SpeechTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskSpeech for request: %s", task.RequestID)
+
+	summaryPath := task.Data.(map[string]interface{})["summary_path"].(string)
+	payload := interfaces.SpeechCompletedPayload{SummaryPath: summaryPath}
+	speechPath, err := p.render(ctx, task.RequestID, summaryPath, engine)
+	if err != nil {
+		log.WithContext(ctx).Warnf("Failed to read the summary of request %s aloud: %v", task.RequestID, err)
+		payload.Error = err.Error()
+	} else {
+		payload.SpeechPath = speechPath
+		if err := engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"speech_path": speechPath,
+		}); err != nil {
+			log.WithContext(ctx).Errorf("Failed to update state with speech: %v", err)
+			return err
+		}
+	}
+
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-speech-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeSpeechCompleted,
+		Data:      payload,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+// render turns the summary into plain text, introduced by the title and cut at max_chars,
+// and has the speech provider read it
+func (p *SpeechTask) render(ctx context.Context, requestID, summaryPath string, engine interfaces.Engine) (string, error) {
+	provider := engine.GetSpeechProvider()
+	if provider == nil {
+		return "", fmt.Errorf("speech is not configured")
+	}
+	state, err := engine.GetStore().GetRequestState(requestID)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read summary: %w", err)
+	}
+	text := speechText(string(data))
+	if text == "" {
+		return "", fmt.Errorf("summary is empty")
+	}
+	cfg := engine.GetConfig().Speech
+	if runes := []rune(text); cfg.MaxChars > 0 && len(runes) > cfg.MaxChars {
+		text = strings.TrimSpace(string(runes[:cfg.MaxChars])) + "\n\nThe rest of this summary is in the written version."
+	}
+	if title := state.Title(); title != "" {
+		text = fmt.Sprintf("Summary of %s.\n\n%s", title, text)
+	}
+	return provider.Synthesize(ctx, text, interfaces.SpeechOptions{Voice: cfg.Voice, Speed: cfg.Speed})
+}
+
+// ShouldRenderSpeech reports whether summaries are read aloud before they are uploaded
+func ShouldRenderSpeech(cfg *config.AppConfig) bool {
+	return cfg != nil && cfg.Speech.Enabled
+}
+
+// speechText strips a markdown summary down to the words to be read: links are read as their
+// text, and citations, URLs and formatting marks are dropped
+func speechText(summary string) string {
+	summary = citationPattern.ReplaceAllString(summary, "")
+	summary = markdownLinkPattern.ReplaceAllString(summary, "$1")
+	summary = bareURLPattern.ReplaceAllString(summary, "")
+	replacer := strings.NewReplacer("**", "", "__", "", "`", "", "*", "")
+
+	var lines []string
+	for _, line := range strings.Split(summary, "\n") {
+		line = markdownLinePattern.ReplaceAllString(line, "")
+		line = strings.Join(strings.Fields(replacer.Replace(line)), " ")
+		if line == "" || strings.Trim(line, "-=_|: ") == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
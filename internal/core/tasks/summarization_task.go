@@ -3,11 +3,17 @@ package tasks
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/interfaces"
 )
 
@@ -26,22 +32,14 @@ func (p *SummarizationTask) GetTaskType() interfaces.TaskType {
 
 // Process handles the summarization task
 func (p *SummarizationTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskSummarization for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskSummarization for request: %s", task.RequestID)
 
 	transcriptPath := task.Data.(map[string]interface{})["transcript_path"].(string)
-	transcriptBytes, err := os.ReadFile(transcriptPath)
-	if err != nil {
-		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-			"status": interfaces.StatusFailed,
-			"error":  fmt.Sprintf("Failed to read transcript file: %v", err),
-		})
-		return err
-	}
 
 	// Read promptID and maxTokens from state
 	state, err := engine.GetStore().GetRequestState(task.RequestID)
 	if err != nil {
-		log.Errorf("Failed to get state: %v", err)
+		log.WithContext(ctx).Errorf("Failed to get state: %v", err)
 		return err
 	}
 	prompt := state.Prompt
@@ -60,26 +58,90 @@ func (p *SummarizationTask) Process(ctx context.Context, task *interfaces.Task,
 	if promptText == "" {
 		promptText = "summarize"
 	}
-	maxTokens := state.MaxTokens
-	if maxTokens == 0 {
-		maxTokens = 10000
+	cfg := engine.GetConfig()
+	maxTokens := summaryMaxTokens(ctx, state, engine.GetPromptManager(), cfg)
+	promptText = promptForLanguage(ctx, state, engine.GetPromptManager(), cfg, promptText)
+	promptText = promptWithVideoContext(ctx, state, cfg, promptText)
+	promptText = promptWithSlides(ctx, state, cfg, promptText)
+	chunkSize := defaultSummarizationChunkSize
+	if cfg != nil && cfg.SummarizationChunkSize > 0 {
+		chunkSize = cfg.SummarizationChunkSize
+	}
+	chunkConcurrency := 1
+	if cfg != nil && cfg.SummarizationChunkConcurrency > 0 {
+		chunkConcurrency = cfg.SummarizationChunkConcurrency
+	}
+
+	// In citation mode the model reads the transcript with segment timestamps and cites them
+	sourcePath, chunkInstruction := transcriptPath, ""
+	var segments []timedSegment
+	if wantsCitations(state, cfg) {
+		if state.SegmentsPath == "" {
+			log.WithContext(ctx).Warnf("Summarizing request %s without citations: its transcript has no segment times", task.RequestID)
+		} else if segments, err = readSegmentsArtifact(state.SegmentsPath); err != nil {
+			log.WithContext(ctx).Warnf("Summarizing request %s without citations: %v", task.RequestID, err)
+		} else if timedPath, err := writeTimedTranscript(filepath.Dir(transcriptPath), segments); err != nil {
+			log.WithContext(ctx).Warnf("Summarizing request %s without citations: %v", task.RequestID, err)
+			segments = nil
+		} else {
+			defer os.Remove(timedPath)
+			sourcePath, chunkInstruction = timedPath, citationChunkInstruction
+			promptText += citationInstruction
+		}
 	}
+	promptText = promptWithFormat(ctx, state, promptText, segments != nil)
 
-	summaryPath, err := engine.GetSummarizationProvider().SummarizeText(ctx, string(transcriptBytes), promptText, maxTokens)
+	ctx, usageRecorder := interfaces.WithUsageRecorder(interfaces.WithSummaryModel(ctx, state.SummaryModel))
+	ctx = interfaces.WithSummaryProgress(ctx, func(text string) {
+		if err := engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{"partial_summary": text}); err != nil {
+			log.WithContext(ctx).Warnf("Failed to save partial summary of request %s: %v", task.RequestID, err)
+		}
+	})
+	summaryPath, err := p.summarizeTranscript(ctx, engine.GetSummarizationProvider(), task.RequestID, sourcePath, sourceDescription(state), promptText, chunkInstruction, maxTokens, chunkSize, chunkConcurrency, documentPages(state))
 	if err != nil {
+		// Keep the transcript for a retry unless it is the reason summarization failed
+		status := interfaces.StatusFailed
+		if _, statErr := os.Stat(transcriptPath); statErr == nil {
+			status = interfaces.StatusPartiallyCompleted
+		}
 		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-			"status": interfaces.StatusFailed,
-			"error":  fmt.Sprintf("Failed to summarize text: %v", err),
+			"status":     status,
+			"error":      err.Error(),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeSummarizationFailed),
 		})
 		return err
 	}
 
+	if sourcePath != transcriptPath {
+		videoURL := state.URL
+		if webpageURL, ok := state.VideoInfo["webpage_url"].(string); ok && webpageURL != "" {
+			videoURL = webpageURL
+		}
+		if err := linkCitations(ctx, summaryPath, videoURL, segments); err != nil {
+			log.WithContext(ctx).Warnf("Failed to link citations for request %s: %v", task.RequestID, err)
+		}
+	}
+
+	// Keep the summary text and token usage in state; the summary file is removed on cleanup
+	usage := usageRecorder.Usage()
+	if cfg != nil {
+		usage.CostUSD = cfg.EstimateCost(usage.PromptTokens, usage.CompletionTokens)
+	}
+	updates := map[string]interface{}{
+		"summary":         summaryPath,
+		"token_usage":     usage,
+		"partial_summary": "",
+	}
+	if summaryBytes, err := os.ReadFile(summaryPath); err == nil {
+		updates["summary_text"] = string(summaryBytes)
+	} else {
+		log.WithContext(ctx).Warnf("Failed to read summary file %s: %v", summaryPath, err)
+	}
+
 	// Write summary path to state
-	err = engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-		"summary": summaryPath,
-	})
+	err = engine.GetStore().UpdateRequestState(task.RequestID, updates)
 	if err != nil {
-		log.Errorf("Failed to update state with summary: %v", err)
+		log.WithContext(ctx).Errorf("Failed to update state with summary: %v", err)
 		return err
 	}
 
@@ -88,9 +150,227 @@ func (p *SummarizationTask) Process(ctx context.Context, task *interfaces.Task,
 		ID:        fmt.Sprintf("evt-%s-summary-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
 		Type:      interfaces.EventTypeSummarizationCompleted,
-		Data:      map[string]interface{}{"summary": summaryPath},
+		Data:      interfaces.SummarizationCompletedPayload{SummaryPath: summaryPath},
 		Timestamp: time.Now(),
 	})
 
 	return nil
 }
+
+// sourceDescription names the kind of text being summarized, for the chunk prompts
+func sourceDescription(state *interfaces.ProcessingState) string {
+	switch state.SourceType {
+	case interfaces.SourceTypeAudio:
+		return "audio transcript"
+	case interfaces.SourceTypeDocument:
+		return "document"
+	case interfaces.SourceTypeArticle:
+		return "web article"
+	case interfaces.SourceTypeDigest:
+		return "collection of summaries"
+	case interfaces.SourceTypeComparison:
+		return "set of summaries being compared"
+	}
+	return "video transcript"
+}
+
+// documentPages returns the page count of a PDF request, or 0 for text without pages
+func documentPages(state *interfaces.ProcessingState) int {
+	switch pages := state.DocumentInfo["pages"].(type) {
+	case int:
+		return pages
+	case float64:
+		return int(pages)
+	}
+	return 0
+}
+
+// summaryMaxTokens returns the request's own max tokens if set, else the configured limit for
+// its prompt and category, lowered to what the summarization model can write
+func summaryMaxTokens(ctx context.Context, state *interfaces.ProcessingState, pm *config.PromptManager, cfg *config.AppConfig) int {
+	if cfg == nil {
+		if state.MaxTokens > 0 {
+			return state.MaxTokens
+		}
+		return 10000
+	}
+	maxTokens := state.MaxTokens
+	if maxTokens <= 0 {
+		promptMaxTokens := 0
+		if pm != nil && state.Prompt.Type == interfaces.PromptTypeID {
+			if prompt, err := pm.GetPrompt(state.Prompt.Prompt); err == nil {
+				promptMaxTokens = prompt.MaxTokens
+			}
+		}
+		maxTokens = cfg.MaxTokensFor(state.Category, promptMaxTokens)
+	}
+	if cfg.SummarizerProvider == "openai" && !cfg.DryRun {
+		model := state.SummaryModel
+		if model == "" {
+			model = cfg.SummaryModel()
+		}
+		if limit := config.ModelMaxOutputTokens(model); limit > 0 && maxTokens > limit {
+			log.WithContext(ctx).Warnf("Lowering max tokens of request %s from %d to the %d %s can write", state.RequestID, maxTokens, limit, model)
+			maxTokens = limit
+		}
+	}
+	return maxTokens
+}
+
+// summarizeTranscript summarizes a transcript file without loading it into memory at once.
+// Transcripts that fit in a single chunk are summarized directly. Longer ones are read chunk
+// by chunk, each chunk is summarized on its own, and the partial summaries are combined with
+// the request's prompt in a final pass. chunkInstruction is added to the prompt for each chunk,
+// and up to chunkConcurrency chunks are summarized at a time. The chunks of a document with
+// more than one page end at page breaks where possible, and their prompts name their pages.
+func (p *SummarizationTask) summarizeTranscript(ctx context.Context, provider interfaces.SummarizationProvider, requestID, transcriptPath, description, promptText, chunkInstruction string, maxTokens, chunkSize, chunkConcurrency, pages int) (string, error) {
+	f, err := os.Open(transcriptPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read transcript file: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("Failed to read transcript file: %v", err)
+	}
+	totalChunks := int((info.Size() + int64(chunkSize) - 1) / int64(chunkSize))
+
+	chunker := newTranscriptChunker(f, chunkSize)
+	if totalChunks <= 1 {
+		text, err := chunker.Next()
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("Failed to read transcript file: %v", err)
+		}
+		summaryPath, err := provider.SummarizeText(ctx, text, promptText, maxTokens)
+		if err != nil {
+			return "", fmt.Errorf("Failed to summarize text: %w", err)
+		}
+		return summaryPath, nil
+	}
+
+	log.WithContext(ctx).Infof("Text for request %s is %d bytes, summarizing in about %d chunks, %d at a time", requestID, info.Size(), totalChunks, chunkConcurrency)
+
+	partials, err := summarizeChunks(ctx, provider, chunker, chunkConcurrency, func(part, firstPage, lastPage int) string {
+		which := fmt.Sprintf("part %d of a long %s", part, description)
+		if pages > 1 && firstPage == lastPage {
+			which = fmt.Sprintf("part %d (page %d) of a %d-page %s", part, firstPage, pages, description)
+		} else if pages > 1 {
+			which = fmt.Sprintf("part %d (pages %d-%d) of a %d-page %s", part, firstPage, lastPage, pages, description)
+		}
+		return fmt.Sprintf("You are summarizing %s that was split into about %d parts. "+
+			"Write a detailed summary of this part only, keeping key points, names, numbers and notable quotes, "+
+			"so it can later be combined with the summaries of the other parts.", which, totalChunks) + chunkInstruction
+	}, maxTokens, requestID, totalChunks)
+	if err != nil {
+		return "", err
+	}
+
+	combinedPrompt := fmt.Sprintf("%s\n\nThe input is a series of summaries of consecutive parts of one %s, in order. Treat them as a single %s.", promptText, description, description)
+	summaryPath, err := provider.SummarizeText(ctx, strings.Join(partials, "\n\n"), combinedPrompt, maxTokens)
+	if err != nil {
+		return "", fmt.Errorf("Failed to summarize text: %w", err)
+	}
+	return summaryPath, nil
+}
+
+// summarizeChunks summarizes each chunk with the prompt for its part number and pages, running up to
+// concurrency provider calls at a time, and returns the "Part N:" summaries in order. Once the
+// provider rate-limits a call, the remaining chunks are summarized one at a time and the
+// limited chunk is retried.
+func summarizeChunks(ctx context.Context, provider interfaces.SummarizationProvider, chunker *transcriptChunker, concurrency int, promptFor func(part, firstPage, lastPage int) string, maxTokens int, requestID string, totalChunks int) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Only the combined summary is the request's summary as it streams
+	ctx = interfaces.WithSummaryProgress(ctx, nil)
+
+	var (
+		mu       sync.Mutex
+		partials []string
+		firstErr error
+		wg       sync.WaitGroup
+		// Calls share the gate while it is open, and take it exclusively once throttled
+		gate      sync.RWMutex
+		throttled atomic.Bool
+	)
+	call := func(prompt, text string) (string, error) {
+		if throttled.Load() {
+			gate.Lock()
+			defer gate.Unlock()
+		} else {
+			gate.RLock()
+			defer gate.RUnlock()
+		}
+		return summarizeToString(ctx, provider, text, prompt, maxTokens)
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	slots := make(chan struct{}, concurrency)
+	for part := 1; ctx.Err() == nil; part++ {
+		text, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fail(fmt.Errorf("Failed to read transcript file: %v", err))
+			break
+		}
+		firstPage, lastPage := chunker.Pages()
+		prompt := promptFor(part, firstPage, lastPage)
+		slots <- struct{}{}
+		mu.Lock()
+		partials = append(partials, "")
+		mu.Unlock()
+		wg.Add(1)
+		go func(part int, prompt, text string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			partial, err := call(prompt, text)
+			if err != nil && concurrency > 1 && interfaces.ErrorCodeOf(err, "") == interfaces.ErrorCodeLLMRateLimited {
+				if !throttled.Swap(true) {
+					log.WithContext(ctx).Warnf("Summarization of request %s was rate limited, summarizing the remaining parts one at a time", requestID)
+				}
+				partial, err = call(prompt, text)
+			}
+			if err != nil {
+				fail(fmt.Errorf("Failed to summarize transcript part %d: %w", part, err))
+				return
+			}
+			mu.Lock()
+			partials[part-1] = fmt.Sprintf("Part %d:\n%s", part, partial)
+			mu.Unlock()
+			interfaces.Heartbeat(ctx)
+			log.WithContext(ctx).Debugf("Summarized part %d/%d for request %s", part, totalChunks, requestID)
+		}(part, prompt, text)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return partials, nil
+}
+
+// summarizeToString runs the provider and returns the summary text, removing the summary file
+func summarizeToString(ctx context.Context, provider interfaces.SummarizationProvider, text, prompt string, maxTokens int) (string, error) {
+	path, err := provider.SummarizeText(ctx, text, prompt, maxTokens)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
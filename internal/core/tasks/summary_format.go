@@ -0,0 +1,40 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Instructions for the summary styles a request can ask for
+const (
+	bulletsInstruction = "\n\nWrite the summary as bullet points."
+	proseInstruction   = "\n\nWrite the summary as prose paragraphs, without bullet points or lists."
+)
+
+// promptWithFormat appends the request's summary length and style to the prompt, after the
+// prompt's own instructions so they take precedence. Citations are always bullets, so a prose
+// style is dropped when citing.
+func promptWithFormat(ctx context.Context, state *interfaces.ProcessingState, promptText string, citing bool) string {
+	if words, err := interfaces.ParseSummaryLength(state.SummaryLength); err != nil {
+		log.WithContext(ctx).Warnf("Ignoring summary length of request %s: %v", state.RequestID, err)
+	} else if words > 0 {
+		promptText += fmt.Sprintf("\n\nKeep the summary to about %d words.", words)
+	}
+	switch state.SummaryStyle {
+	case interfaces.SummaryStyleBullets:
+		if !citing {
+			promptText += bulletsInstruction
+		}
+	case interfaces.SummaryStyleProse:
+		if citing {
+			log.WithContext(ctx).Warnf("Summarizing request %s as bullet points: citations need them", state.RequestID)
+		} else {
+			promptText += proseInstruction
+		}
+	}
+	return promptText
+}
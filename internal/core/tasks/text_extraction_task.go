@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// TextExtractionTask extracts the text of a document or web article so it can be summarized like a transcript
+type TextExtractionTask struct{}
+
+// NewTextExtractionTask creates a new TextExtractionTask
+func NewTextExtractionTask() *TextExtractionTask {
+	return &TextExtractionTask{}
+}
+
+// GetTaskType returns the task type this processor handles
+func (p *TextExtractionTask) GetTaskType() interfaces.TaskType {
+	return interfaces.TaskTextExtraction
+}
+
+// Process handles the text extraction task
+func (p *TextExtractionTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
+	log.WithContext(ctx).Infof("Processing TaskTextExtraction for request: %s", task.RequestID)
+
+	source, ok := task.Data.(map[string]interface{})["source"].(string)
+	if !ok || source == "" {
+		return fmt.Errorf("text_extraction task missing source in data")
+	}
+
+	state, err := engine.GetStore().GetRequestState(task.RequestID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("Failed to get state: %v", err)
+		return err
+	}
+	provider := engine.GetDocumentProvider()
+	if state.SourceType == interfaces.SourceTypeArticle {
+		provider = engine.GetArticleProvider()
+	}
+	if provider == nil {
+		err := fmt.Errorf("%s summarization is not configured", state.SourceType)
+		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"status":     interfaces.StatusFailed,
+			"error":      err.Error(),
+			"error_code": interfaces.ErrorCodeNotConfigured,
+		})
+		return err
+	}
+
+	textPath, documentInfo, err := provider.ExtractText(ctx, source)
+	if err != nil {
+		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+			"status":     interfaces.StatusFailed,
+			"error":      fmt.Sprintf("Failed to extract %s text: %v", state.SourceType, err),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeTextExtractionFailed),
+		})
+		return err
+	}
+
+	// Write text path and document info to state
+	err = engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
+		"text_path":     textPath,
+		"document_info": documentInfo,
+	})
+	if err != nil {
+		log.WithContext(ctx).Errorf("Failed to update state with extracted text: %v", err)
+		return err
+	}
+
+	// Publish text extracted event
+	engine.GetEventBus().Publish(interfaces.Event{
+		ID:        fmt.Sprintf("evt-%s-text-%d", task.RequestID, time.Now().UnixNano()),
+		RequestID: task.RequestID,
+		Type:      interfaces.EventTypeTextExtracted,
+		Data:      interfaces.TextExtractedPayload{TextPath: textPath},
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"bytes"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultSummarizationChunkSize is used when the engine has no config
+const defaultSummarizationChunkSize = 60000
+
+// pageBreak ends each page of the text pdftotext extracts
+const pageBreak = '\f'
+
+// transcriptChunker reads a transcript in chunks of at most size bytes, splitting on
+// page breaks, line breaks or spaces where possible so words are never cut in half
+type transcriptChunker struct {
+	r     io.Reader
+	size  int
+	carry []byte
+	eof   bool
+	// page is the page the unread text starts on; first and last are the pages of the
+	// chunk Next returned last
+	page        int
+	first, last int
+}
+
+func newTranscriptChunker(r io.Reader, size int) *transcriptChunker {
+	return &transcriptChunker{r: r, size: size, page: 1}
+}
+
+// Pages returns the first and last page of the chunk Next returned last. Text without page
+// breaks is all on page 1.
+func (c *transcriptChunker) Pages() (first, last int) {
+	return c.first, c.last
+}
+
+// Next returns the next chunk, or io.EOF once the transcript is exhausted
+func (c *transcriptChunker) Next() (string, error) {
+	for {
+		buf := make([]byte, c.size)
+		n := copy(buf, c.carry)
+		c.carry = nil
+		if !c.eof {
+			m, err := io.ReadFull(c.r, buf[n:])
+			n += m
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				c.eof = true
+			} else if err != nil {
+				return "", err
+			}
+		}
+		buf = buf[:n]
+		if n == 0 {
+			return "", io.EOF
+		}
+
+		cut := n
+		if n == c.size {
+			cut = splitPoint(buf)
+		}
+		c.carry = append([]byte(nil), buf[cut:]...)
+		chunk := bytes.TrimSpace(buf[:cut])
+		leading := buf[:cut-len(bytes.TrimLeftFunc(buf[:cut], unicode.IsSpace))]
+		c.first = c.page + bytes.Count(leading, []byte{pageBreak})
+		c.last = c.first + bytes.Count(chunk, []byte{pageBreak})
+		c.page += bytes.Count(buf[:cut], []byte{pageBreak})
+		if len(chunk) > 0 {
+			return string(chunk), nil
+		}
+		if c.eof && len(c.carry) == 0 {
+			return "", io.EOF
+		}
+	}
+}
+
+// splitPoint finds where to end a full buffer: after the last page break, else after the
+// last newline, else after the last space, else at the last complete UTF-8 character
+func splitPoint(buf []byte) int {
+	if i := bytes.LastIndexByte(buf, pageBreak); i > len(buf)/2 {
+		return i + 1
+	}
+	if i := bytes.LastIndexByte(buf, '\n'); i > len(buf)/2 {
+		return i + 1
+	}
+	if i := bytes.LastIndexByte(buf, ' '); i > len(buf)/2 {
+		return i + 1
+	}
+	start := len(buf) - 1
+	for start > 0 && !utf8.RuneStart(buf[start]) {
+		start--
+	}
+	if start == 0 || utf8.FullRune(buf[start:]) {
+		return len(buf)
+	}
+	return start
+}
@@ -0,0 +1,107 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// timedSegment is a transcript segment as stored in a request's segments file, with its
+// times in seconds
+type timedSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// writeSegmentsArtifact writes the timed segments of a transcript to a segments-*.json file in dir
+func writeSegmentsArtifact(dir string, segments []interfaces.TranscriptSegment) (string, error) {
+	timed := make([]timedSegment, 0, len(segments))
+	for _, segment := range segments {
+		timed = append(timed, timedSegment{
+			Start: segment.Start.Seconds(),
+			End:   segment.End.Seconds(),
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	data, err := json.Marshal(timed)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "segments-*.json")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// readSegmentsArtifact reads a segments file, ordered by start time
+func readSegmentsArtifact(path string) ([]timedSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript segments: %w", err)
+	}
+	var segments []timedSegment
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript segments: %w", err)
+	}
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].Start < segments[j].Start })
+	return segments, nil
+}
+
+// renderTimedTranscript writes one "[timestamp] text" line per segment, with timestamps
+// written by format
+func renderTimedTranscript(segments []timedSegment, format func(float64) string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", format(segment.Start), segment.Text)
+	}
+	return b.String()
+}
+
+// parseTimestamp reads a M:SS or H:MM:SS timestamp, optionally in brackets, as seconds
+func parseTimestamp(timestamp string) (float64, error) {
+	timestamp = strings.Trim(strings.TrimSpace(timestamp), "[]")
+	parts := strings.Split(timestamp, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+		total = total*60 + n
+	}
+	return float64(total), nil
+}
+
+// segmentStartAt returns the start of the segment playing at the given second, so a moment
+// always links to the beginning of a sentence
+func segmentStartAt(segments []timedSegment, seconds float64) float64 {
+	start := 0.0
+	for _, segment := range segments {
+		if segment.Start > seconds {
+			break
+		}
+		start = segment.Start
+	}
+	return start
+}
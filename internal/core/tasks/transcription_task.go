@@ -25,24 +25,54 @@ func (p *TranscriptionTask) GetTaskType() interfaces.TaskType {
 
 // Process handles the transcription task
 func (p *TranscriptionTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskTranscription for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskTranscription for request: %s", task.RequestID)
 
 	audioPath := task.Data.(map[string]interface{})["audio_path"].(string)
-	transcriptPath, err := engine.GetTranscriptionProvider().TranscribeAudio(audioPath)
+	transcription := &interfaces.Transcription{}
+	var err error
+	provider := engine.GetTranscriptionProvider()
+	if detailed, ok := provider.(interfaces.DetailedTranscriptionProvider); ok {
+		transcription, err = detailed.TranscribeAudioDetailed(ctx, audioPath, transcriptionOptions(engine, task.RequestID))
+	} else {
+		transcription.Path, err = provider.TranscribeAudio(audioPath)
+	}
 	if err != nil {
 		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-			"status": interfaces.StatusFailed,
-			"error":  fmt.Sprintf("Failed to transcribe audio: %v", err),
+			"status":     interfaces.StatusFailed,
+			"error":      fmt.Sprintf("Failed to transcribe audio: %v", err),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeTranscriptionFailed),
 		})
 		return err
 	}
 
-	// Write transcript path to state
-	err = engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-		"transcript": transcriptPath,
-	})
+	transcriptPath := transcription.Path
+
+	// Write transcript path, language and quality to state
+	updateData := map[string]interface{}{
+		"transcript":          transcriptPath,
+		"language":            transcription.Language,
+		"whisper_model":       transcription.Model,
+		"transcription_route": transcription.Route,
+		"transcript_quality":  nil,
+	}
+	if quality := interfaces.NewTranscriptQuality(transcription.Segments, engine.GetConfig().TranscriptLowConfidenceThreshold); quality != nil {
+		updateData["transcript_quality"] = *quality
+		if quality.LowConfidence {
+			log.WithContext(ctx).Warnf("Low confidence transcript for request %s: score %.2f, %d of %d segments below threshold",
+				task.RequestID, quality.Score, quality.LowConfidenceSegments, quality.Segments)
+		}
+	}
+	// Keep the segment times for the stages that point into the video
+	if len(transcription.Segments) > 0 {
+		if segmentsPath, err := writeSegmentsArtifact(engine.GetConfig().TmpDir, transcription.Segments); err != nil {
+			log.WithContext(ctx).Warnf("Failed to store transcript segments for request %s: %v", task.RequestID, err)
+		} else {
+			updateData["segments_path"] = segmentsPath
+		}
+	}
+	err = engine.GetStore().UpdateRequestState(task.RequestID, updateData)
 	if err != nil {
-		log.Errorf("Failed to update state with transcript: %v", err)
+		log.WithContext(ctx).Errorf("Failed to update state with transcript: %v", err)
 		return err
 	}
 
@@ -51,9 +81,24 @@ func (p *TranscriptionTask) Process(ctx context.Context, task *interfaces.Task,
 		ID:        fmt.Sprintf("evt-%s-transcript-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
 		Type:      interfaces.EventTypeTranscriptionCompleted,
-		Data:      map[string]interface{}{"transcript": transcriptPath},
+		Data:      interfaces.TranscriptionCompletedPayload{TranscriptPath: transcriptPath},
 		Timestamp: time.Now(),
 	})
 
 	return nil
 }
+
+// transcriptionOptions describes the request to the transcription provider: the video's
+// duration from its info and the submitter's whisper quality hint
+func transcriptionOptions(engine interfaces.Engine, requestID string) interfaces.TranscriptionOptions {
+	var opts interfaces.TranscriptionOptions
+	state, err := engine.GetStore().GetRequestState(requestID)
+	if err != nil || state == nil {
+		return opts
+	}
+	if duration, ok := state.VideoInfo["duration"].(float64); ok && duration > 0 {
+		opts.Duration = time.Duration(duration * float64(time.Second))
+	}
+	opts.Quality = state.WhisperQuality
+	return opts
+}
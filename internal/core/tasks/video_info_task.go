@@ -2,7 +2,13 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -10,12 +16,25 @@ import (
 	"video-summarizer-go/internal/interfaces"
 )
 
+// thumbnailMaxBytes caps the size of a downloaded thumbnail
+const thumbnailMaxBytes = 10 * 1024 * 1024
+
+// thumbnailExtensions maps the image types accepted as thumbnails to their file extension
+var thumbnailExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
 // VideoInfoTask handles video information extraction and audio download
-type VideoInfoTask struct{}
+type VideoInfoTask struct {
+	client *http.Client
+}
 
 // NewVideoInfoTask creates a new VideoInfoTask
 func NewVideoInfoTask() *VideoInfoTask {
-	return &VideoInfoTask{}
+	return &VideoInfoTask{client: &http.Client{Timeout: 30 * time.Second}}
 }
 
 // GetTaskType returns the task type this processor handles
@@ -25,24 +44,44 @@ func (p *VideoInfoTask) GetTaskType() interfaces.TaskType {
 
 // Process handles the video info task
 func (p *VideoInfoTask) Process(ctx context.Context, task *interfaces.Task, engine interfaces.Engine) error {
-	log.Infof("Processing TaskVideoInfo for request: %s", task.RequestID)
+	log.WithContext(ctx).Infof("Processing TaskVideoInfo for request: %s", task.RequestID)
 
 	url := task.Data.(map[string]interface{})["url"].(string)
-	videoInfo, err := engine.GetVideoProvider().GetVideoInfo(url)
+	videoInfo, err := engine.GetVideoProvider().GetVideoInfo(ctx, url)
 	if err != nil {
 		engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-			"status": interfaces.StatusFailed,
-			"error":  fmt.Sprintf("Failed to get video info: %v", err),
+			"status":     interfaces.StatusFailed,
+			"error":      fmt.Sprintf("Failed to get video info: %v", err),
+			"error_code": interfaces.ErrorCodeOf(err, interfaces.ErrorCodeVideoInfoFailed),
 		})
 		return err
 	}
 
-	// Write video info to state
-	err = engine.GetStore().UpdateRequestState(task.RequestID, map[string]interface{}{
-		"video_info": videoInfo,
-	})
+	// Keep the full metadata as an artifact and only the main fields in the state
+	updateData := map[string]interface{}{
+		"video_info": interfaces.TrimVideoInfo(videoInfo),
+	}
+	tmpDir, uploadThumbnail := "", false
+	if cfg := engine.GetConfig(); cfg != nil {
+		tmpDir, uploadThumbnail = cfg.TmpDir, cfg.UploadThumbnail
+	}
+	if infoPath, err := writeInfoArtifact(tmpDir, videoInfo); err != nil {
+		log.WithContext(ctx).Warnf("Failed to store full video info for request %s: %v", task.RequestID, err)
+	} else {
+		updateData["info_path"] = infoPath
+	}
+	// A missing thumbnail only leaves it out of the outputs
+	if thumbnailURL, _ := videoInfo["thumbnail"].(string); uploadThumbnail && thumbnailURL != "" {
+		if thumbnailPath, err := p.downloadThumbnail(ctx, tmpDir, thumbnailURL); err != nil {
+			log.WithContext(ctx).Warnf("Failed to download thumbnail for request %s: %v", task.RequestID, err)
+		} else {
+			updateData["thumbnail_path"] = thumbnailPath
+		}
+	}
+	videoInfo = updateData["video_info"].(map[string]interface{})
+	err = engine.GetStore().UpdateRequestState(task.RequestID, updateData)
 	if err != nil {
-		log.Errorf("Failed to update state with video info: %v", err)
+		log.WithContext(ctx).Errorf("Failed to update state with video info: %v", err)
 		return err
 	}
 
@@ -50,10 +89,73 @@ func (p *VideoInfoTask) Process(ctx context.Context, task *interfaces.Task, engi
 	engine.GetEventBus().Publish(interfaces.Event{
 		ID:        fmt.Sprintf("evt-%s-videoinfo-%d", task.RequestID, time.Now().UnixNano()),
 		RequestID: task.RequestID,
-		Type:      "VideoInfoFetched",
-		Data:      videoInfo,
+		Type:      interfaces.EventTypeVideoInfoFetched,
+		Data:      interfaces.VideoInfoFetchedPayload{VideoInfo: videoInfo},
 		Timestamp: time.Now(),
 	})
 
 	return nil
 }
+
+// writeInfoArtifact writes the full video metadata to an info-*.json file in dir
+func writeInfoArtifact(dir string, videoInfo map[string]interface{}) (string, error) {
+	data, err := json.MarshalIndent(videoInfo, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "info-*.json")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// downloadThumbnail saves the image at url to a thumbnail-* file in dir, named with the
+// extension of its image type
+func (p *VideoInfoTask) downloadThumbnail(ctx context.Context, dir, url string) (string, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", fmt.Errorf("unsupported thumbnail URL %q", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("thumbnail request returned %s", resp.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ext, ok := thumbnailExtensions[mediaType]
+	if !ok {
+		return "", fmt.Errorf("unsupported thumbnail type %q", mediaType)
+	}
+
+	f, err := os.CreateTemp(dir, "thumbnail-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(f, io.LimitReader(resp.Body, thumbnailMaxBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > thumbnailMaxBytes {
+		err = fmt.Errorf("thumbnail is larger than %d MB", thumbnailMaxBytes/(1024*1024))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// tmpFilePatterns match the per-request files written into TmpDir by the providers
+var tmpFilePatterns = []string{"audio-*", "transcript-*", "document-*", "info-*", "thumbnail-*", "segments-*", "speech-*", "slides-*", "summary-*"}
+
+// usageRefreshInterval bounds how often the quota check rescans TmpDir
+const usageRefreshInterval = 5 * time.Second
+
+// TmpDirUsage reports disk usage of the pipeline's files in TmpDir
+type TmpDirUsage struct {
+	Dir            string `json:"dir"`
+	Files          int    `json:"files"`
+	Bytes          int64  `json:"bytes"`
+	QuotaBytes     int64  `json:"quota_bytes,omitempty"`
+	OverQuota      bool   `json:"over_quota"`
+	OrphansRemoved int    `json:"orphans_removed"`
+}
+
+// TmpDirManager tracks disk usage of TmpDir, enforces a quota and removes orphaned files
+type TmpDirManager struct {
+	dir           string
+	quotaBytes    int64
+	sweepInterval time.Duration
+	orphanMinAge  time.Duration
+	store         interfaces.StateStore
+
+	mu             sync.Mutex
+	usage          TmpDirUsage
+	lastScan       time.Time
+	orphansRemoved int
+	sweepHooks     []func()
+	stopChan       chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewTmpDirManager creates a manager for dir. A quota of 0 disables the quota.
+func NewTmpDirManager(dir string, quotaBytes int64, sweepInterval, orphanMinAge time.Duration, store interfaces.StateStore) *TmpDirManager {
+	return &TmpDirManager{
+		dir:           dir,
+		quotaBytes:    quotaBytes,
+		sweepInterval: sweepInterval,
+		orphanMinAge:  orphanMinAge,
+		store:         store,
+	}
+}
+
+// Start runs the orphan sweeper in the background until Stop is called
+func (m *TmpDirManager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopChan != nil || m.sweepInterval <= 0 {
+		return
+	}
+	m.stopChan = make(chan struct{})
+	m.wg.Add(1)
+	go m.run(m.stopChan)
+	log.Infof("Temp directory sweeper started for %s (every %s, quota %d bytes)", m.dir, m.sweepInterval, m.quotaBytes)
+}
+
+// Stop stops the orphan sweeper
+func (m *TmpDirManager) Stop() {
+	m.mu.Lock()
+	stopChan := m.stopChan
+	m.stopChan = nil
+	m.mu.Unlock()
+	if stopChan != nil {
+		close(stopChan)
+		m.wg.Wait()
+	}
+}
+
+func (m *TmpDirManager) run(stopChan chan struct{}) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			m.SweepOrphans()
+			m.mu.Lock()
+			hooks := append([]func(){}, m.sweepHooks...)
+			m.mu.Unlock()
+			for _, hook := range hooks {
+				hook()
+			}
+		}
+	}
+}
+
+// AddSweepHook registers extra housekeeping to run after each orphan sweep
+func (m *TmpDirManager) AddSweepHook(hook func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweepHooks = append(m.sweepHooks, hook)
+}
+
+// Usage returns the current disk usage, rescanning TmpDir if the cached value is stale
+func (m *TmpDirManager) Usage() TmpDirUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Since(m.lastScan) >= usageRefreshInterval {
+		m.scanLocked()
+	}
+	return m.usage
+}
+
+// OverQuota reports whether TmpDir usage has reached the quota
+func (m *TmpDirManager) OverQuota() bool {
+	if m.quotaBytes <= 0 {
+		return false
+	}
+	return m.Usage().OverQuota
+}
+
+// scanLocked recomputes usage. Caller must hold m.mu.
+func (m *TmpDirManager) scanLocked() {
+	usage := TmpDirUsage{Dir: m.dir, QuotaBytes: m.quotaBytes, OrphansRemoved: m.orphansRemoved}
+	for _, path := range m.listFiles() {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		usage.Files++
+		usage.Bytes += info.Size()
+	}
+	usage.OverQuota = m.quotaBytes > 0 && usage.Bytes >= m.quotaBytes
+	if usage.OverQuota && !m.usage.OverQuota {
+		log.Warnf("Temp directory %s is over quota (%d of %d bytes); pausing audio downloads", m.dir, usage.Bytes, m.quotaBytes)
+	} else if !usage.OverQuota && m.usage.OverQuota {
+		log.Infof("Temp directory %s is back under quota (%d of %d bytes); resuming audio downloads", m.dir, usage.Bytes, m.quotaBytes)
+	}
+	m.usage = usage
+	m.lastScan = time.Now()
+}
+
+// listFiles returns the pipeline's files in TmpDir
+func (m *TmpDirManager) listFiles() []string {
+	var files []string
+	for _, pattern := range tmpFilePatterns {
+		matches, err := filepath.Glob(filepath.Join(m.dir, pattern))
+		if err != nil {
+			continue
+		}
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// SweepOrphans removes pipeline files older than the minimum age that no unfinished
+// request references, e.g. audio left behind by failed or cancelled requests. The files
+// kept for retrying a partially completed request stay until the request leaves the store.
+func (m *TmpDirManager) SweepOrphans() int {
+	referenced := make(map[string]bool)
+	requests, err := m.store.ListRequests()
+	if err != nil {
+		log.Errorf("Temp directory sweep skipped: %v", err)
+		return 0
+	}
+	for _, state := range requests {
+		var paths []string
+		switch {
+		case state.Status == interfaces.StatusPartiallyCompleted:
+			paths = []string{state.Transcript, state.Summary, state.TextPath, state.InfoPath, state.ThumbnailPath, state.SegmentsPath, state.SpeechPath, state.SlidesPath}
+		case !isTerminalStatus(state.Status):
+			paths = []string{state.AudioPath, state.Transcript, state.Summary, state.TextPath, state.InfoPath, state.ThumbnailPath, state.SegmentsPath, state.SpeechPath, state.SlidesPath}
+		}
+		if state.SourceType == interfaces.SourceTypeDocument && !isTerminalStatus(state.Status) {
+			// Uploaded documents live in TmpDir until the request finishes
+			paths = append(paths, state.URL)
+		}
+		for _, path := range paths {
+			if path != "" {
+				referenced[filepath.Clean(path)] = true
+			}
+		}
+	}
+
+	removed := 0
+	cutoff := time.Now().Add(-m.orphanMinAge)
+	for _, path := range m.listFiles() {
+		if referenced[filepath.Clean(path)] || referencedByPrefix(path, referenced) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			log.Warnf("Failed to remove orphaned temp file %s: %v", path, err)
+			continue
+		}
+		log.Debugf("Removed orphaned temp file: %s", path)
+		removed++
+	}
+
+	m.mu.Lock()
+	m.orphansRemoved += removed
+	m.scanLocked()
+	m.mu.Unlock()
+
+	if removed > 0 {
+		log.Infof("Removed %d orphaned temp file(s) from %s", removed, m.dir)
+	}
+	return removed
+}
+
+// referencedByPrefix keeps side files of a referenced path, such as the extensionless
+// placeholder whisper.cpp writes next to transcript-*.txt or yt-dlp's partial downloads
+func referencedByPrefix(path string, referenced map[string]bool) bool {
+	for ref := range referenced {
+		ext := filepath.Ext(ref)
+		base := ref[:len(ref)-len(ext)]
+		if path == base || (len(path) > len(ref) && path[:len(ref)] == ref) {
+			return true
+		}
+	}
+	return false
+}
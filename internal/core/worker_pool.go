@@ -1,7 +1,11 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"video-summarizer-go/internal/interfaces"
@@ -14,17 +18,52 @@ type WorkerPool struct {
 	limits      map[interfaces.TaskType]int
 	workers     map[interfaces.TaskType][]chan struct{}
 	stopChans   map[interfaces.TaskType]chan struct{}
-	processFunc func(task *interfaces.Task)
+	processFunc func(ctx context.Context, task *interfaces.Task)
+	gates       map[interfaces.TaskType]func() bool
+	paused      atomic.Bool
+	running     map[string]*runningTask
 	mu          sync.Mutex
+
+	watchdogStop chan struct{}
+	watchdogWG   sync.WaitGroup
+
+	// Done channels of workers replaced by a limit change, which may still be finishing a task
+	retired []chan struct{}
 }
 
-func NewWorkerPool(queue interfaces.TaskQueue, limits map[interfaces.TaskType]int, processFunc func(task *interfaces.Task)) *WorkerPool {
+// runningTask is a task a worker is processing, with the time it last showed progress
+type runningTask struct {
+	task      *interfaces.Task
+	startedAt time.Time
+	heartbeat atomic.Int64 // unix nanoseconds
+	cancel    context.CancelCauseFunc
+	stopped   atomic.Bool // cancelled by the watchdog
+}
+
+func (r *runningTask) beat() {
+	r.heartbeat.Store(time.Now().UnixNano())
+}
+
+// RunningTask describes a task a worker is processing
+type RunningTask struct {
+	ID            string              `json:"id"`
+	Type          interfaces.TaskType `json:"type"`
+	RequestID     string              `json:"request_id"`
+	StartedAt     time.Time           `json:"started_at"`
+	LastHeartbeat time.Time           `json:"last_heartbeat"`
+	// Stopped tasks were cancelled by the watchdog and are waiting for their work to return
+	Stopped bool `json:"stopped,omitempty"`
+}
+
+func NewWorkerPool(queue interfaces.TaskQueue, limits map[interfaces.TaskType]int, processFunc func(ctx context.Context, task *interfaces.Task)) *WorkerPool {
 	wp := &WorkerPool{
 		queue:       queue,
 		limits:      limits,
 		workers:     make(map[interfaces.TaskType][]chan struct{}),
 		stopChans:   make(map[interfaces.TaskType]chan struct{}),
 		processFunc: processFunc,
+		gates:       make(map[interfaces.TaskType]func() bool),
+		running:     make(map[string]*runningTask),
 	}
 	for taskType, limit := range limits {
 		wp.startWorkers(taskType, limit)
@@ -39,6 +78,7 @@ func (wp *WorkerPool) startWorkers(taskType interfaces.TaskType, count int) {
 		close(wp.stopChans[taskType])
 	}
 	wp.stopChans[taskType] = make(chan struct{})
+	wp.retireWorkersLocked(wp.workers[taskType])
 	wp.workers[taskType] = nil
 	for i := 0; i < count; i++ {
 		workerDone := make(chan struct{})
@@ -47,6 +87,20 @@ func (wp *WorkerPool) startWorkers(taskType interfaces.TaskType, count int) {
 	}
 }
 
+// retireWorkersLocked keeps the done channels of stopped workers until they exit, so Stop
+// can wait for them, and forgets those that already have. Caller must hold the lock.
+func (wp *WorkerPool) retireWorkersLocked(dones []chan struct{}) {
+	var kept []chan struct{}
+	for _, done := range append(wp.retired, dones...) {
+		select {
+		case <-done:
+		default:
+			kept = append(kept, done)
+		}
+	}
+	wp.retired = kept
+}
+
 func (wp *WorkerPool) worker(taskType interfaces.TaskType, stopChan chan struct{}, done chan struct{}) {
 	log.Infof("Worker goroutine started for task type: %s", taskType)
 	defer close(done)
@@ -55,6 +109,14 @@ func (wp *WorkerPool) worker(taskType interfaces.TaskType, stopChan chan struct{
 		case <-stopChan:
 			return
 		default:
+			if wp.paused.Load() {
+				time.Sleep(time.Second)
+				continue
+			}
+			if gate := wp.gate(taskType); gate != nil && !gate() {
+				time.Sleep(time.Second)
+				continue
+			}
 			task, err := wp.queue.Dequeue(taskType)
 			if err != nil {
 				time.Sleep(100 * time.Millisecond)
@@ -66,9 +128,12 @@ func (wp *WorkerPool) worker(taskType interfaces.TaskType, stopChan chan struct{
 			processFunc := wp.processFunc
 			wp.mu.Unlock()
 			if processFunc != nil {
-				processFunc(task)
+				wp.process(processFunc, task)
 				// Debug: log after processing function returns
 				log.Infof("Worker finished task: %s for request: %s", task.Type, task.RequestID)
+				if acking, ok := wp.queue.(interfaces.AckingTaskQueue); ok {
+					acking.Ack(task)
+				}
 			} else {
 				log.Warnf("No process function set for task: %s", task.Type)
 				time.Sleep(100 * time.Millisecond)
@@ -77,27 +142,174 @@ func (wp *WorkerPool) worker(taskType interfaces.TaskType, stopChan chan struct{
 	}
 }
 
+// process runs a task with a context the watchdog can cancel, recording its heartbeats
+func (wp *WorkerPool) process(processFunc func(ctx context.Context, task *interfaces.Task), task *interfaces.Task) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	run := &runningTask{task: task, startedAt: time.Now(), cancel: cancel}
+	run.beat()
+	wp.mu.Lock()
+	wp.running[task.ID] = run
+	wp.mu.Unlock()
+	defer func() {
+		wp.mu.Lock()
+		delete(wp.running, task.ID)
+		wp.mu.Unlock()
+	}()
+	processFunc(interfaces.WithHeartbeat(ctx, run.beat), task)
+}
+
+// SetConcurrencyLimit restarts the workers for a task type with a new limit.
+// Workers that are busy finish their current task before exiting, so in-flight
+// tasks are not dropped.
 func (wp *WorkerPool) SetConcurrencyLimit(taskType interfaces.TaskType, limit int) {
+	wp.mu.Lock()
+	wp.limits[taskType] = limit
+	wp.mu.Unlock()
 	wp.startWorkers(taskType, limit)
 }
 
+// GetConcurrencyLimit returns the current worker limit for a task type
+func (wp *WorkerPool) GetConcurrencyLimit(taskType interfaces.TaskType) int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.limits[taskType]
+}
+
+// SetGate sets a check that must return true before workers of a task type pick up
+// another task. Queued tasks wait while it returns false; running tasks are unaffected.
+func (wp *WorkerPool) SetGate(taskType interfaces.TaskType, gate func() bool) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	wp.gates[taskType] = gate
+}
+
+// gate returns the gate of a task type. The lock is released before the gate runs, so a
+// gate can't hold up Stop.
+func (wp *WorkerPool) gate(taskType interfaces.TaskType) func() bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.gates[taskType]
+}
+
+// Pause stops all workers from picking up new tasks. Running tasks finish; queued tasks
+// wait until Resume is called.
+func (wp *WorkerPool) Pause() {
+	wp.paused.Store(true)
+}
+
+// Resume lets workers pick up queued tasks again
+func (wp *WorkerPool) Resume() {
+	wp.paused.Store(false)
+}
+
+// IsPaused reports whether the workers are paused
+func (wp *WorkerPool) IsPaused() bool {
+	return wp.paused.Load()
+}
+
 // SetProcessFunc sets the task processing function
-func (wp *WorkerPool) SetProcessFunc(processFunc func(task *interfaces.Task)) {
+func (wp *WorkerPool) SetProcessFunc(processFunc func(ctx context.Context, task *interfaces.Task)) {
 	wp.mu.Lock()
 	defer wp.mu.Unlock()
 	wp.processFunc = processFunc
 }
 
-func (wp *WorkerPool) Stop() {
+// RunningTasks returns the tasks the workers are processing, longest running first
+func (wp *WorkerPool) RunningTasks() []RunningTask {
 	wp.mu.Lock()
 	defer wp.mu.Unlock()
-	for _, stopChan := range wp.stopChans {
-		close(stopChan)
+	tasks := make([]RunningTask, 0, len(wp.running))
+	for _, run := range wp.running {
+		tasks = append(tasks, RunningTask{
+			ID:            run.task.ID,
+			Type:          run.task.Type,
+			RequestID:     run.task.RequestID,
+			StartedAt:     run.startedAt,
+			LastHeartbeat: time.Unix(0, run.heartbeat.Load()),
+			Stopped:       run.stopped.Load(),
+		})
 	}
-	// Optionally wait for all workers to finish
-	for _, workerChans := range wp.workers {
-		for _, done := range workerChans {
-			<-done
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].StartedAt.Before(tasks[j].StartedAt) })
+	return tasks
+}
+
+// StartWatchdog checks the running tasks every interval and cancels those that have gone
+// longer than timeout(task type) without a heartbeat, with ErrTaskStuck as the cause. A
+// timeout of 0 never stops tasks of that type.
+func (wp *WorkerPool) StartWatchdog(interval time.Duration, timeout func(interfaces.TaskType) time.Duration) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	if wp.watchdogStop != nil {
+		return
+	}
+	wp.watchdogStop = make(chan struct{})
+	wp.watchdogWG.Add(1)
+	go func(stopChan chan struct{}) {
+		defer wp.watchdogWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopChan:
+				return
+			case now := <-ticker.C:
+				wp.StopStuckTasks(now, timeout)
+			}
+		}
+	}(wp.watchdogStop)
+}
+
+// StopStuckTasks cancels the running tasks that have gone longer than their type's timeout
+// without a heartbeat at now, and returns how many it stopped
+func (wp *WorkerPool) StopStuckTasks(now time.Time, timeout func(interfaces.TaskType) time.Duration) int {
+	wp.mu.Lock()
+	runs := make([]*runningTask, 0, len(wp.running))
+	for _, run := range wp.running {
+		runs = append(runs, run)
+	}
+	wp.mu.Unlock()
+
+	stopped := 0
+	for _, run := range runs {
+		limit := timeout(run.task.Type)
+		idle := now.Sub(time.Unix(0, run.heartbeat.Load()))
+		if limit <= 0 || idle < limit || run.stopped.Swap(true) {
+			continue
 		}
+		log.Warnf("Task %s for request %s made no progress for %s, stopping it", run.task.Type, run.task.RequestID, idle.Round(time.Second))
+		run.cancel(fmt.Errorf("%w: no progress for %s", interfaces.ErrTaskStuck, limit))
+		stopped++
+	}
+	return stopped
+}
+
+func (wp *WorkerPool) Stop() {
+	wp.mu.Lock()
+	watchdogStop := wp.watchdogStop
+	wp.watchdogStop = nil
+	wp.mu.Unlock()
+	if watchdogStop != nil {
+		close(watchdogStop)
+		wp.watchdogWG.Wait()
+	}
+
+	// Busy workers need the lock to finish their task, so wait for them without it
+	wp.mu.Lock()
+	stopChans := wp.stopChans
+	wp.stopChans = make(map[interfaces.TaskType]chan struct{})
+	dones := wp.retired
+	wp.retired = nil
+	for _, workerChans := range wp.workers {
+		dones = append(dones, workerChans...)
+	}
+	wp.workers = make(map[interfaces.TaskType][]chan struct{})
+	wp.mu.Unlock()
+
+	for _, stopChan := range stopChans {
+		close(stopChan)
+	}
+	for _, done := range dones {
+		<-done
 	}
 }
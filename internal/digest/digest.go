@@ -0,0 +1,360 @@
+// Package digest rolls the summaries completed in the last day or week up into a single
+// digest per category or source. Digests run through the regular pipeline as requests of
+// source type "digest", so they are summarized and uploaded like any other request, and
+// are emailed to their recipients once complete.
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// defaultPrompt is used for the roll-up pass when a digest has no prompt_id
+const defaultPrompt = "You are writing a digest of the summaries below, each of one video, article or document. " +
+	"Open with the main themes across all items, then give a short section per item with its key points, " +
+	"and note where items agree, disagree or build on each other. Mention each item's title."
+
+// ErrNothingToDigest is returned when no summaries were completed in the digest window
+var ErrNothingToDigest = errors.New("no summaries completed in the digest window")
+
+// RequestStarter starts a fully prepared request
+type RequestStarter interface {
+	StartPreparedRequest(state *interfaces.ProcessingState) error
+}
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+// Status reports a digest's schedule and its last run
+type Status struct {
+	Name          string     `json:"name"`
+	Schedule      string     `json:"schedule"`
+	Category      string     `json:"category,omitempty"`
+	Source        string     `json:"source,omitempty"`
+	NextRun       time.Time  `json:"next_run"`
+	LastRun       *time.Time `json:"last_run,omitempty"`
+	LastRequestID string     `json:"last_request_id,omitempty"`
+	LastItems     int        `json:"last_items"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Scheduler generates the configured digests on their schedule
+type Scheduler struct {
+	digests map[string]config.DigestConfig
+	store   interfaces.StateStore
+	starter RequestStarter
+	mailer  Mailer
+	tmpDir  string
+
+	mu       sync.Mutex
+	statuses map[string]*Status
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler for the given digests. mailer may be nil when no
+// digest has email recipients.
+func NewScheduler(digests []config.DigestConfig, store interfaces.StateStore, starter RequestStarter, mailer Mailer, tmpDir string) *Scheduler {
+	s := &Scheduler{
+		digests:  make(map[string]config.DigestConfig),
+		store:    store,
+		starter:  starter,
+		mailer:   mailer,
+		tmpDir:   tmpDir,
+		statuses: make(map[string]*Status),
+	}
+	now := time.Now()
+	for _, digest := range digests {
+		s.digests[digest.Name] = digest
+		s.statuses[digest.Name] = &Status{
+			Name:     digest.Name,
+			Schedule: digest.Schedule,
+			Category: digest.Category,
+			Source:   digest.Source,
+			NextRun:  nextRun(digest, now),
+		}
+	}
+	return s
+}
+
+// Attach subscribes the scheduler to completion events so finished digests are emailed
+func (s *Scheduler) Attach(bus interfaces.EventBus) {
+	bus.Subscribe(interfaces.EventTypeProcessingCompleted, s.onCompleted)
+}
+
+// Start runs every digest on its schedule until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopChan != nil || len(s.digests) == 0 {
+		return
+	}
+	s.stopChan = make(chan struct{})
+	for name := range s.digests {
+		s.wg.Add(1)
+		go s.run(name, s.stopChan)
+	}
+	log.Infof("Digest scheduler started with %d digest(s)", len(s.digests))
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stopChan := s.stopChan
+	s.stopChan = nil
+	s.mu.Unlock()
+	if stopChan != nil {
+		close(stopChan)
+		s.wg.Wait()
+	}
+}
+
+func (s *Scheduler) run(name string, stopChan chan struct{}) {
+	defer s.wg.Done()
+	digest := s.digests[name]
+	for {
+		s.mu.Lock()
+		next := s.statuses[name].NextRun
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stopChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.generate(digest, next); err != nil && !errors.Is(err, ErrNothingToDigest) {
+			log.Errorf("Digest %s failed: %v", name, err)
+		}
+		s.mu.Lock()
+		s.statuses[name].NextRun = nextRun(digest, next)
+		s.mu.Unlock()
+	}
+}
+
+// RunNow generates a digest immediately over the window ending now and returns its request ID
+func (s *Scheduler) RunNow(name string) (string, error) {
+	digest, ok := s.digests[name]
+	if !ok {
+		return "", fmt.Errorf("unknown digest: %s", name)
+	}
+	return s.generate(digest, time.Now())
+}
+
+// Statuses returns the schedule and last run of every digest, sorted by name
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// generate collects the summaries completed in the window ending at end, writes them to
+// a text file and starts a digest request that summarizes them
+func (s *Scheduler) generate(digest config.DigestConfig, end time.Time) (string, error) {
+	start := end.Add(-digest.GetWindow())
+	items, err := s.collect(digest, start, end)
+	if err == nil && len(items) == 0 {
+		err = ErrNothingToDigest
+	}
+	if err != nil {
+		s.recordRun(digest.Name, end, "", 0, err)
+		if errors.Is(err, ErrNothingToDigest) {
+			log.Infof("Digest %s skipped: %v", digest.Name, err)
+		}
+		return "", err
+	}
+
+	textPath, err := s.writeItems(digest, items)
+	if err != nil {
+		s.recordRun(digest.Name, end, "", len(items), err)
+		return "", err
+	}
+
+	requestIDs := make([]string, len(items))
+	for i, item := range items {
+		requestIDs[i] = item.RequestID
+	}
+	prompt := interfaces.Prompt{Type: interfaces.PromptTypeText, Prompt: defaultPrompt}
+	if digest.PromptID != "" {
+		prompt = interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: digest.PromptID}
+	}
+	category := digest.Category
+	if category == "" {
+		category = "digests"
+	}
+
+	now := time.Now()
+	state := &interfaces.ProcessingState{
+		RequestID:  fmt.Sprintf("req-%d", now.UnixNano()),
+		Status:     interfaces.StatusPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		SourceType: interfaces.SourceTypeDigest,
+		URL:        fmt.Sprintf("digest://%s/%s", digest.Name, end.Format(time.RFC3339)),
+		Prompt:     prompt,
+		Category:   category,
+		Source:     digest.Source,
+		TextPath:   textPath,
+		DocumentInfo: map[string]interface{}{
+			"title":        digestTitle(digest, end),
+			"digest":       digest.Name,
+			"window_start": start,
+			"window_end":   end,
+			"items":        len(items),
+			"request_ids":  requestIDs,
+		},
+	}
+	if err := s.starter.StartPreparedRequest(state); err != nil {
+		os.Remove(textPath)
+		s.recordRun(digest.Name, end, "", len(items), err)
+		return "", fmt.Errorf("failed to start digest request: %w", err)
+	}
+
+	log.Infof("Digest %s started as request %s with %d summaries", digest.Name, state.RequestID, len(items))
+	s.recordRun(digest.Name, end, state.RequestID, len(items), nil)
+	return state.RequestID, nil
+}
+
+// collect returns the completed requests in the window that match the digest's filters, oldest first
+func (s *Scheduler) collect(digest config.DigestConfig, start, end time.Time) ([]*interfaces.ProcessingState, error) {
+	requests, err := s.store.ListRequests()
+	if err != nil {
+		return nil, err
+	}
+	var items []*interfaces.ProcessingState
+	for _, state := range requests {
+		if state.Status != interfaces.StatusCompleted || state.SourceType == interfaces.SourceTypeDigest || state.SummaryText == "" {
+			continue
+		}
+		if state.CompletedAt == nil || !state.CompletedAt.After(start) || state.CompletedAt.After(end) {
+			continue
+		}
+		if digest.Category != "" && state.Category != digest.Category {
+			continue
+		}
+		if digest.Source != "" && state.Source != digest.Source {
+			continue
+		}
+		items = append(items, state)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CompletedAt.Before(*items[j].CompletedAt) })
+	return items, nil
+}
+
+// writeItems writes the summaries to a temp file for the roll-up pass
+func (s *Scheduler) writeItems(digest config.DigestConfig, items []*interfaces.ProcessingState) (string, error) {
+	f, err := os.CreateTemp(s.tmpDir, "document-digest-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create digest file: %w", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	for i, item := range items {
+		title := item.Title()
+		if title == "" {
+			title = item.URL
+		}
+		fmt.Fprintf(&b, "## Item %d: %s\nURL: %s\nCompleted: %s\n\n%s\n\n", i+1, title, item.URL,
+			item.CompletedAt.Format("2006-01-02 15:04"), strings.TrimSpace(item.SummaryText))
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write digest file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func (s *Scheduler) recordRun(name string, at time.Time, requestID string, items int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.statuses[name]
+	status.LastRun = &at
+	status.LastRequestID = requestID
+	status.LastItems = items
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+}
+
+// onCompleted emails a finished digest to its recipients
+func (s *Scheduler) onCompleted(event interfaces.Event) {
+	state, err := s.store.GetRequestState(event.RequestID)
+	if err != nil || state.SourceType != interfaces.SourceTypeDigest || state.Status != interfaces.StatusCompleted {
+		return
+	}
+	name, _ := state.DocumentInfo["digest"].(string)
+	digest, ok := s.digests[name]
+	if !ok || len(digest.Email) == 0 {
+		return
+	}
+	if s.mailer == nil {
+		log.Warnf("Digest %s has email recipients but email is not configured", name)
+		return
+	}
+
+	subject := state.Title()
+	body := state.SummaryText
+	if state.OutputPath != "" {
+		body += "\n\n---\nFull digest: " + state.OutputPath
+	}
+	// Email delivery must not hold up the event bus
+	go func() {
+		if err := s.mailer.Send(digest.Email, subject, body); err != nil {
+			log.Errorf("Failed to email digest %s: %v", name, err)
+			return
+		}
+		log.Infof("Emailed digest %s to %d recipient(s)", name, len(digest.Email))
+	}()
+}
+
+// digestTitle names a digest after its schedule and the day it covers up to
+func digestTitle(digest config.DigestConfig, end time.Time) string {
+	kind := "Daily"
+	if digest.Schedule == "weekly" {
+		kind = "Weekly"
+	}
+	return fmt.Sprintf("%s digest %s %s", kind, digest.Name, end.Format("2006-01-02"))
+}
+
+// nextRun returns the first scheduled time of a digest after t
+func nextRun(digest config.DigestConfig, t time.Time) time.Time {
+	hour, minute, err := digest.GetTimeOfDay()
+	if err != nil {
+		hour, minute = 7, 0
+	}
+	next := time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if digest.Schedule == "weekly" {
+		weekday, err := digest.GetWeekday()
+		if err != nil {
+			weekday = time.Monday
+		}
+		for next.Weekday() != weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
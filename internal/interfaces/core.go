@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"io"
 	"time"
 	"video-summarizer-go/internal/config"
 )
@@ -9,21 +10,46 @@ import (
 // StateStore defines methods for request state and event persistence
 type StateStore interface {
 	SaveRequestState(requestID string, state *ProcessingState) error
+	// A copy of the request, which later updates don't change
 	GetRequestState(requestID string) (*ProcessingState, error)
+	// Calls view with the stored request under the store's lock, without copying it. view
+	// must only read the request, must not keep it and must not call the store.
+	ViewRequestState(requestID string, view func(state *ProcessingState)) error
+	// Copies of the requests that exist among requestIDs, keyed by ID, all read at one moment
+	GetRequestStates(requestIDs []string) map[string]*ProcessingState
 	UpdateRequestState(requestID string, updates map[string]interface{}) error
 	DeleteRequestState(requestID string) error
 
 	LogEvent(event Event) error
 	GetEventsForRequest(requestID string) ([]Event, error)
 
+	// Copies of the unfinished requests, and of all requests oldest first
 	GetAllActiveRequests() ([]*ProcessingState, error)
-	CleanupOldRequests(olderThan time.Time) error
+	ListRequests() ([]*ProcessingState, error)
+	// Removes finished requests last updated before olderThan, returning how many were removed
+	CleanupOldRequests(olderThan time.Time) (int, error)
+	// Drops events older than olderThan from finished requests, returning how many were dropped
+	PruneEvents(olderThan time.Time) int
 	GetRequestCountsByStatus() map[string]int
+	GetStoreStats() StoreStats
+	// Estimated LLM spend (USD) recorded from token usage since the start of from's day.
+	// Spend stays counted after its requests are evicted or cleaned up.
+	GetSpendSince(from time.Time) float64
 
 	// Deduplication: create or get a request for a dedup key
 	CreateOrGetDedupRequest(dedupKey string, state *ProcessingState) (requestID string, alreadyExists bool, err error)
 }
 
+// StoreStats reports the size of a state store
+type StoreStats struct {
+	Requests            int `json:"requests"`
+	Events              int `json:"events"`
+	DedupKeys           int `json:"dedup_keys"`
+	Evictions           int `json:"evictions"`
+	MaxRequests         int `json:"max_requests,omitempty"`
+	MaxEventsPerRequest int `json:"max_events_per_request,omitempty"`
+}
+
 // EventBus defines pub/sub for events
 type EventHandler func(event Event)
 
@@ -40,6 +66,13 @@ type TaskQueue interface {
 	RemoveTasksForRequest(requestID string) error
 }
 
+// AckingTaskQueue is a TaskQueue that keeps a dequeued task until Ack is called once it has
+// run, so the task is delivered again if the process dies first
+type AckingTaskQueue interface {
+	TaskQueue
+	Ack(task *Task)
+}
+
 // AudioProcessor defines methods for audio processing
 type AudioProcessor interface {
 	ProcessAudio(inputPath string) (string, error)
@@ -52,13 +85,65 @@ type TaskProcessor interface {
 	GetTaskType() TaskType
 }
 
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the ID of the request being processed, so log
+// entries made with it are tagged with the request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+type heartbeatKey struct{}
+
+// WithHeartbeat returns a context whose Heartbeat calls beat, so the worker running a task
+// learns that it is still making progress
+func WithHeartbeat(ctx context.Context, beat func()) context.Context {
+	return context.WithValue(ctx, heartbeatKey{}, beat)
+}
+
+// Heartbeat reports that the task running with ctx is making progress, so the watchdog
+// doesn't stop it as hung. Long-running work calls it as it goes.
+func Heartbeat(ctx context.Context) {
+	if beat, ok := ctx.Value(heartbeatKey{}).(func()); ok {
+		beat()
+	}
+}
+
+// HeartbeatWriter returns a writer that passes writes on to w and reports a heartbeat for
+// each, for the output of tools that print as they progress
+func HeartbeatWriter(ctx context.Context, w io.Writer) io.Writer {
+	return heartbeatWriter{ctx: ctx, w: w}
+}
+
+type heartbeatWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (h heartbeatWriter) Write(p []byte) (int, error) {
+	Heartbeat(h.ctx)
+	return h.w.Write(p)
+}
+
 // Engine defines the interface for the processing engine
 type Engine interface {
 	GetVideoProvider() VideoProvider
 	GetTranscriptionProvider() TranscriptionProvider
 	GetSummarizationProvider() SummarizationProvider
 	GetOutputProvider() OutputProvider
+	GetNamedOutputProvider(name string) OutputProvider
+	GetDocumentProvider() DocumentProvider
+	GetArticleProvider() DocumentProvider
+	GetSlideProvider() SlideProvider
+	GetSpeechProvider() SpeechProvider
 	GetPromptManager() *config.PromptManager
+	GetConfig() *config.AppConfig
 	GetStore() StateStore
 	GetEventBus() EventBus
 	GetTaskQueue() TaskQueue
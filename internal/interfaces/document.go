@@ -0,0 +1,14 @@
+package interfaces
+
+import "context"
+
+// DocumentProvider defines methods for extracting plain text from documents
+type DocumentProvider interface {
+	// ExtractText writes the text of the document at source (a local path or URL) to a
+	// temp file and returns its path along with document metadata such as the title
+	ExtractText(ctx context.Context, source string) (textPath string, info map[string]interface{}, err error)
+	GetSupportedFormats() []string
+}
+
+// UploadedDocumentPrefix is the file name prefix of documents uploaded through the API into TmpDir
+const UploadedDocumentPrefix = "document-upload-"
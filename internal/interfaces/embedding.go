@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+// EmbeddingProvider turns texts into vectors whose cosine similarity reflects how alike the
+// texts are
+type EmbeddingProvider interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorCode categorizes why a request failed, so clients and retry logic don't have to
+// parse error messages
+type ErrorCode string
+
+const (
+	// The source can't be fetched and retrying won't change that
+	ErrorCodeSourceUnavailable ErrorCode = "source_unavailable" // private, removed or not found
+	ErrorCodeDownloadBlocked   ErrorCode = "download_blocked"   // geo-blocked, age-gated or a sign-in/bot check
+	ErrorCodeNotConfigured     ErrorCode = "not_configured"     // no provider is configured for the source type
+	// The whisper.cpp binary can't load the model, e.g. a corrupt file or a format it predates
+	ErrorCodeWhisperModelUnusable ErrorCode = "whisper_model_unusable"
+
+	// Summarization provider failures
+	ErrorCodeLLMRateLimited   ErrorCode = "llm_rate_limited"
+	ErrorCodeLLMQuotaExceeded ErrorCode = "llm_quota_exceeded"
+	ErrorCodeLLMAuthFailed    ErrorCode = "llm_auth_failed"
+	ErrorCodeLLMInputTooLong  ErrorCode = "llm_input_too_long"
+	ErrorCodeLLMUnavailable   ErrorCode = "llm_unavailable"
+
+	// Output provider failures
+	ErrorCodeUploadAuthExpired   ErrorCode = "upload_auth_expired"
+	ErrorCodeUploadRateLimited   ErrorCode = "upload_rate_limited"
+	ErrorCodeUploadQuotaExceeded ErrorCode = "upload_quota_exceeded"
+	ErrorCodeUploadUnavailable   ErrorCode = "upload_unavailable"
+	ErrorCodeUploadUnverified    ErrorCode = "upload_unverified" // missing or changed at the destination after upload
+
+	ErrorCodeTimeout       ErrorCode = "timeout"
+	ErrorCodeInjectedFault ErrorCode = "injected_fault"
+	ErrorCodeInterrupted   ErrorCode = "interrupted" // the service restarted while the request was processing
+
+	// Stage failures without a more specific cause
+	ErrorCodeVideoInfoFailed      ErrorCode = "video_info_failed"
+	ErrorCodeDownloadFailed       ErrorCode = "download_failed"
+	ErrorCodeTranscriptionFailed  ErrorCode = "transcription_failed"
+	ErrorCodeTextExtractionFailed ErrorCode = "text_extraction_failed"
+	ErrorCodeSummarizationFailed  ErrorCode = "summarization_failed"
+	ErrorCodeUploadFailed         ErrorCode = "upload_failed"
+	ErrorCodeComparisonFailed     ErrorCode = "comparison_failed"
+	ErrorCodeRedactionFailed      ErrorCode = "redaction_failed"
+)
+
+// ErrTaskStuck is why the watchdog cancels a task that has stopped making progress
+var ErrTaskStuck = errors.New("task stuck")
+
+// permanentErrorCodes fail again on retry until something outside the request changes
+var permanentErrorCodes = map[ErrorCode]bool{
+	ErrorCodeSourceUnavailable:    true,
+	ErrorCodeDownloadBlocked:      true,
+	ErrorCodeNotConfigured:        true,
+	ErrorCodeWhisperModelUnusable: true,
+	ErrorCodeLLMQuotaExceeded:     true,
+	ErrorCodeLLMAuthFailed:        true,
+	ErrorCodeLLMInputTooLong:      true,
+	ErrorCodeUploadAuthExpired:    true,
+	ErrorCodeUploadQuotaExceeded:  true,
+}
+
+// Retryable reports whether a request that failed with this code may succeed if retried
+// as is. Stage failures without a known cause count as retryable.
+func (c ErrorCode) Retryable() bool {
+	return c != "" && !permanentErrorCodes[c]
+}
+
+// CodedError attaches an error code to an error
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string { return e.Err.Error() }
+func (e *CodedError) Unwrap() error { return e.Err }
+
+// WithErrorCode attaches code to err. An error that already carries a code keeps it, since
+// the code closest to the cause is the most specific one. A nil err stays nil.
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return err
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// SourceHTTPError attaches source_unavailable to the error of a source fetch that failed with
+// an HTTP status retrying won't fix (not found, gone or access denied)
+func SourceHTTPError(status int, err error) error {
+	switch status {
+	case 401, 403, 404, 410:
+		return WithErrorCode(ErrorCodeSourceUnavailable, err)
+	}
+	return err
+}
+
+// ErrorCodeOf returns the code attached to err, or fallback if it carries none
+func ErrorCodeOf(err error, fallback ErrorCode) ErrorCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+	return fallback
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import "time"
+
+// SummaryEvaluation is an LLM judge's rubric scores for a summary, each from 1 (poor) to 5 (excellent)
+type SummaryEvaluation struct {
+	Coverage     float64 `json:"coverage"`     // the key points of the source are in the summary
+	Faithfulness float64 `json:"faithfulness"` // the summary states nothing the source doesn't support
+	Length       float64 `json:"length"`       // the summary is as long as it needs to be and no longer
+	Score        float64 `json:"score"`        // mean of the three criteria
+	Comments     string  `json:"comments,omitempty"`
+	// Summarizer that wrote the summary, e.g. "openai/gpt-4o", so scores can be compared across models
+	Summarizer string `json:"summarizer,omitempty"`
+	// The score is below evaluation.min_score
+	BelowThreshold bool        `json:"below_threshold,omitempty"`
+	TokenUsage     *TokenUsage `json:"token_usage,omitempty"` // tokens spent by the judge
+	EvaluatedAt    time.Time   `json:"evaluated_at"`
+}
@@ -0,0 +1,275 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventSchemaVersion is the version of the event payload schema written by this build.
+// Bump it when a payload changes incompatibly and teach decodeEventPayload to upgrade
+// the older version, so events already persisted by a bus or store backend stay readable.
+//
+// Version 0 is the untyped map events carried before payloads were typed. Its keys match
+// the JSON field names of version 1, except VideoInfoFetched, whose map was the video info.
+const EventSchemaVersion = 1
+
+// ErrUnsupportedEventVersion is returned when decoding an event written by a newer build
+var ErrUnsupportedEventVersion = errors.New("unsupported event schema version")
+
+// Event represents a system event
+type Event struct {
+	ID        string       `json:"id"`
+	RequestID string       `json:"request_id"`
+	Type      EventType    `json:"type"`
+	Data      EventPayload `json:"data"`
+	Timestamp time.Time    `json:"timestamp"`
+	// ReplayOf is the ID of the stored event this one re-publishes, if any
+	ReplayOf string `json:"replay_of,omitempty"`
+}
+
+// EventPayload is the typed data of an event. Each event type has its own payload struct.
+type EventPayload interface {
+	EventType() EventType
+}
+
+// VideoProcessingRequestedPayload starts (or, with Retry, restarts) a request
+type VideoProcessingRequestedPayload struct {
+	URL   string `json:"url"`
+	Retry bool   `json:"retry,omitempty"`
+}
+
+// VideoInfoFetchedPayload carries the fetched video metadata
+type VideoInfoFetchedPayload struct {
+	VideoInfo map[string]interface{} `json:"video_info"`
+}
+
+// AudioDownloadedPayload names the downloaded audio file
+type AudioDownloadedPayload struct {
+	AudioPath string `json:"audio_path"`
+}
+
+// SlidesCompletedPayload names the file with the text read from a video's frames. It is
+// empty when no text was found, and Error is set when reading it failed; the request carries
+// on either way.
+type SlidesCompletedPayload struct {
+	SlidesPath string `json:"slides_path,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TranscriptionCompletedPayload names the transcript file
+type TranscriptionCompletedPayload struct {
+	TranscriptPath string `json:"transcript"`
+}
+
+// TextExtractedPayload names the text extracted from a document or article
+type TextExtractedPayload struct {
+	TextPath string `json:"text_path"`
+}
+
+// SummarizationCompletedPayload names the summary file
+type SummarizationCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+}
+
+// HighlightsCompletedPayload names the summary whose video's key moments were picked. Error
+// is set when that failed; the summary is uploaded either way.
+type HighlightsCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+	Error       string `json:"error,omitempty"`
+}
+
+// EvaluationCompletedPayload names the evaluated summary. Error is set when the evaluation
+// failed; the summary is uploaded either way.
+type EvaluationCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RedactionCompletedPayload names the redacted summary
+type RedactionCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+}
+
+// SpeechCompletedPayload names the summary that was read aloud and the audio file. Error is
+// set when that failed; the summary is uploaded either way.
+type SpeechCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+	SpeechPath  string `json:"speech_path,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// OutputCompletedPayload reports the uploaded summary and the request's final status
+type OutputCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+	Status      string `json:"status"`
+}
+
+// HooksCompletedPayload names the summary the post-processing hooks were run for
+type HooksCompletedPayload struct {
+	SummaryPath string `json:"summary"`
+}
+
+// ProcessingCompletedPayload reports the status a finished request ended with
+type ProcessingCompletedPayload struct {
+	Status string `json:"status"`
+}
+
+// ProcessingFailedPayload reports the task that failed, if any, and why
+type ProcessingFailedPayload struct {
+	Task      string    `json:"task,omitempty"`
+	Error     string    `json:"error"`
+	ErrorCode ErrorCode `json:"error_code,omitempty"`
+}
+
+// RequestCancelledPayload reports when a request was cancelled
+type RequestCancelledPayload struct {
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// RawEventPayload holds the data of an event type this build doesn't know, so it can be
+// stored and re-encoded unchanged
+type RawEventPayload struct {
+	Type EventType
+	Raw  json.RawMessage
+}
+
+func (VideoProcessingRequestedPayload) EventType() EventType {
+	return EventTypeVideoProcessingRequested
+}
+func (VideoInfoFetchedPayload) EventType() EventType       { return EventTypeVideoInfoFetched }
+func (AudioDownloadedPayload) EventType() EventType        { return EventTypeAudioDownloaded }
+func (SlidesCompletedPayload) EventType() EventType        { return EventTypeSlidesCompleted }
+func (TranscriptionCompletedPayload) EventType() EventType { return EventTypeTranscriptionCompleted }
+func (TextExtractedPayload) EventType() EventType          { return EventTypeTextExtracted }
+func (SummarizationCompletedPayload) EventType() EventType { return EventTypeSummarizationCompleted }
+func (HighlightsCompletedPayload) EventType() EventType    { return EventTypeHighlightsCompleted }
+func (EvaluationCompletedPayload) EventType() EventType    { return EventTypeEvaluationCompleted }
+func (RedactionCompletedPayload) EventType() EventType     { return EventTypeRedactionCompleted }
+func (SpeechCompletedPayload) EventType() EventType        { return EventTypeSpeechCompleted }
+func (OutputCompletedPayload) EventType() EventType        { return EventTypeOutputCompleted }
+func (HooksCompletedPayload) EventType() EventType         { return EventTypeHooksCompleted }
+func (ProcessingCompletedPayload) EventType() EventType    { return EventTypeProcessingCompleted }
+func (ProcessingFailedPayload) EventType() EventType       { return EventTypeProcessingFailed }
+func (RequestCancelledPayload) EventType() EventType       { return EventTypeRequestCancelled }
+func (p RawEventPayload) EventType() EventType             { return p.Type }
+
+// MarshalJSON writes the raw data back unchanged
+func (p RawEventPayload) MarshalJSON() ([]byte, error) {
+	if p.Raw == nil {
+		return []byte("null"), nil
+	}
+	return p.Raw, nil
+}
+
+// eventJSON is the serialized form of an event
+type eventJSON struct {
+	ID        string          `json:"id"`
+	RequestID string          `json:"request_id"`
+	Type      EventType       `json:"type"`
+	Version   int             `json:"version"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+	ReplayOf  string          `json:"replay_of,omitempty"`
+}
+
+// MarshalJSON writes the event with the current schema version
+func (e Event) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event data: %w", e.Type, err)
+	}
+	return json.Marshal(eventJSON{
+		ID:        e.ID,
+		RequestID: e.RequestID,
+		Type:      e.Type,
+		Version:   EventSchemaVersion,
+		Data:      data,
+		Timestamp: e.Timestamp,
+		ReplayOf:  e.ReplayOf,
+	})
+}
+
+// UnmarshalJSON decodes the payload struct matching the event type, upgrading older
+// schema versions
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw eventJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if raw.Version > EventSchemaVersion {
+		return fmt.Errorf("event %s has schema version %d, this build reads up to %d: %w", raw.ID, raw.Version, EventSchemaVersion, ErrUnsupportedEventVersion)
+	}
+	data, err := decodeEventPayload(raw.Type, raw.Version, raw.Data)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", raw.Type, raw.ID, err)
+	}
+	*e = Event{
+		ID:        raw.ID,
+		RequestID: raw.RequestID,
+		Type:      raw.Type,
+		Data:      data,
+		Timestamp: raw.Timestamp,
+		ReplayOf:  raw.ReplayOf,
+	}
+	return nil
+}
+
+// decodeEventPayload decodes the data of an event of the given type and schema version
+func decodeEventPayload(eventType EventType, version int, data json.RawMessage) (EventPayload, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	switch eventType {
+	case EventTypeVideoProcessingRequested:
+		return decodePayload[VideoProcessingRequestedPayload](data)
+	case EventTypeVideoInfoFetched:
+		if version == 0 {
+			var info map[string]interface{}
+			if err := json.Unmarshal(data, &info); err != nil {
+				return nil, err
+			}
+			return VideoInfoFetchedPayload{VideoInfo: info}, nil
+		}
+		return decodePayload[VideoInfoFetchedPayload](data)
+	case EventTypeAudioDownloaded:
+		return decodePayload[AudioDownloadedPayload](data)
+	case EventTypeSlidesCompleted:
+		return decodePayload[SlidesCompletedPayload](data)
+	case EventTypeTranscriptionCompleted:
+		return decodePayload[TranscriptionCompletedPayload](data)
+	case EventTypeTextExtracted:
+		return decodePayload[TextExtractedPayload](data)
+	case EventTypeSummarizationCompleted:
+		return decodePayload[SummarizationCompletedPayload](data)
+	case EventTypeHighlightsCompleted:
+		return decodePayload[HighlightsCompletedPayload](data)
+	case EventTypeEvaluationCompleted:
+		return decodePayload[EvaluationCompletedPayload](data)
+	case EventTypeRedactionCompleted:
+		return decodePayload[RedactionCompletedPayload](data)
+	case EventTypeSpeechCompleted:
+		return decodePayload[SpeechCompletedPayload](data)
+	case EventTypeOutputCompleted:
+		return decodePayload[OutputCompletedPayload](data)
+	case EventTypeHooksCompleted:
+		return decodePayload[HooksCompletedPayload](data)
+	case EventTypeProcessingCompleted:
+		return decodePayload[ProcessingCompletedPayload](data)
+	case EventTypeProcessingFailed:
+		return decodePayload[ProcessingFailedPayload](data)
+	case EventTypeRequestCancelled:
+		return decodePayload[RequestCancelledPayload](data)
+	default:
+		return RawEventPayload{Type: eventType, Raw: append(json.RawMessage(nil), data...)}, nil
+	}
+}
+
+func decodePayload[T EventPayload](data json.RawMessage) (EventPayload, error) {
+	var payload T
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
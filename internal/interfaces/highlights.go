@@ -0,0 +1,21 @@
+package interfaces
+
+import "time"
+
+// Highlights are the most important moments of a video, picked by the summarization
+// provider from the timed transcript
+type Highlights struct {
+	Moments     []Highlight `json:"moments"`
+	TokenUsage  *TokenUsage `json:"token_usage,omitempty"` // tokens spent picking the moments
+	ExtractedAt time.Time   `json:"extracted_at"`
+}
+
+// Highlight is one key moment of a video
+type Highlight struct {
+	Start       float64 `json:"start"`     // seconds from the start of the video
+	Timestamp   string  `json:"timestamp"` // Start as M:SS or H:MM:SS
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"` // why the moment matters
+	Importance  int     `json:"importance"`            // 1-5, as rated by the model
+	URL         string  `json:"url,omitempty"`         // opens the video at Start, where the site supports it
+}
@@ -1,8 +1,90 @@
 package interfaces
 
+import (
+	"context"
+	"time"
+)
+
 // OutputProvider defines methods for uploading summary and transcript
 // Implementations may upload to Google Drive, S3, webhooks, etc.
 type OutputProvider interface {
 	UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error
 	UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error
 }
+
+// DestinationOutputProvider is an output provider that can upload to a destination other
+// than its configured one, e.g. another Slack channel or Drive folder
+type DestinationOutputProvider interface {
+	OutputProvider
+	WithDestination(destination string) OutputProvider
+}
+
+// MetadataOutputProvider is an output provider that also stores a metadata sidecar
+// (request details, tags and metadata) next to the summary
+type MetadataOutputProvider interface {
+	OutputProvider
+	UploadMetadata(requestID string, videoInfo map[string]interface{}, metadata map[string]interface{}, category string, user string) error
+}
+
+// AttachmentOutputProvider is an output provider that can also store extra files next to
+// the summary, such as the video's full metadata. suffix names the file, e.g. "info.json".
+type AttachmentOutputProvider interface {
+	OutputProvider
+	UploadAttachment(requestID string, videoInfo map[string]interface{}, filePath string, suffix string, category string, user string) error
+}
+
+// DeletableOutputProvider is an output provider that can remove everything it stored for a
+// request, for takedown and data deletion requests
+type DeletableOutputProvider interface {
+	OutputProvider
+	DeleteOutputs(requestID string, videoInfo map[string]interface{}, category string, user string) error
+}
+
+// PrunableOutputProvider is implemented by output providers that can remove outputs past
+// their retention period
+type PrunableOutputProvider interface {
+	OutputProvider
+	// Removes output files whose names end with suffix (e.g. "summary.txt") that were written
+	// before olderThan, returning how many were removed
+	PruneOutputs(suffix string, olderThan time.Time) (int, error)
+}
+
+// VerifiableOutputProvider is an output provider that can check an uploaded file arrived
+// intact. suffix names the file as in AttachmentOutputProvider.
+type VerifiableOutputProvider interface {
+	OutputProvider
+	// VerifyOutput finds the uploaded copy of localPath and returns where it is, or an error
+	// if it is missing or its size or checksum differ from localPath's. It returns nil and no
+	// error when the upload can't be checked.
+	VerifyOutput(requestID string, videoInfo map[string]interface{}, localPath string, suffix string, category string, user string) (*OutputFile, error)
+}
+
+// CheckableOutputProvider is an output provider that can check its destination is reachable
+// and writable without uploading anything, for the startup preflight
+type CheckableOutputProvider interface {
+	OutputProvider
+	CheckDestination(ctx context.Context) error
+}
+
+// OutputFile is where an output provider stored one of a request's files
+type OutputFile struct {
+	Name string `json:"name"`          // file name at the destination
+	ID   string `json:"id,omitempty"`  // provider's ID for the file, e.g. its Drive file ID
+	URL  string `json:"url,omitempty"` // link to the file, or its path for local outputs
+	Size int64  `json:"size"`
+}
+
+// OutputTarget overrides where a single request's output is uploaded
+type OutputTarget struct {
+	Provider string `json:"provider"`            // registered output provider, e.g. "gdrive" or "slack"
+	Channel  string `json:"channel,omitempty"`   // Slack channel name or ID, or Discord channel ID
+	FolderID string `json:"folder_id,omitempty"` // Google Drive folder ID, Raindrop collection ID or Confluence parent page ID
+}
+
+// Destination returns the provider-specific destination, or "" for the provider's default
+func (t *OutputTarget) Destination() string {
+	if t.Channel != "" {
+		return t.Channel
+	}
+	return t.FolderID
+}
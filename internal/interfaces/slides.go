@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// SlideProvider reads the text shown on screen in a video, such as the slides of a talk
+type SlideProvider interface {
+	// ExtractSlideText samples frames of the video at url and writes the text found on them,
+	// with the time each was shown, to a temp file and returns its path; "" when no text
+	// was found
+	ExtractSlideText(ctx context.Context, url string, opts SlideOptions) (string, error)
+}
+
+// SlideOptions control how a video's frames are sampled and read
+type SlideOptions struct {
+	Interval  time.Duration // time between sampled frames
+	MaxFrames int           // most frames read
+	MaxHeight int           // highest video resolution downloaded
+	Language  string        // OCR language(s), e.g. "eng+deu"
+}
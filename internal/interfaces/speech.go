@@ -0,0 +1,15 @@
+package interfaces
+
+import "context"
+
+// SpeechProvider reads text aloud, for an audio rendition of a summary
+type SpeechProvider interface {
+	// Synthesize writes the spoken text to a temp audio file and returns its path
+	Synthesize(ctx context.Context, text string, opts SpeechOptions) (string, error)
+}
+
+// SpeechOptions control how text is spoken
+type SpeechOptions struct {
+	Voice string  // provider's voice name, e.g. "alloy"; "" for the provider's default
+	Speed float64 // 1 is normal speed; 0 for the provider's default
+}
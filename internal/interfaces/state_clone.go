@@ -0,0 +1,97 @@
+package interfaces
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the state, sharing nothing with it, so the copy can be read
+// and changed while the original is updated
+func (s *ProcessingState) Clone() *ProcessingState {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	if s.Output != nil {
+		output := *s.Output
+		c.Output = &output
+	}
+	c.StatusHistory = slices.Clone(s.StatusHistory)
+	if s.CompletedAt != nil {
+		completedAt := *s.CompletedAt
+		c.CompletedAt = &completedAt
+	}
+	if s.NotBefore != nil {
+		notBefore := *s.NotBefore
+		c.NotBefore = &notBefore
+	}
+	c.VideoInfo = CloneInfo(s.VideoInfo)
+	if s.TranscriptQuality != nil {
+		quality := *s.TranscriptQuality
+		c.TranscriptQuality = &quality
+	}
+	if s.Citations != nil {
+		citations := *s.Citations
+		c.Citations = &citations
+	}
+	c.Outputs = slices.Clone(s.Outputs)
+	c.TokenUsage = cloneTokenUsage(s.TokenUsage)
+	if s.Highlights != nil {
+		highlights := *s.Highlights
+		highlights.Moments = slices.Clone(s.Highlights.Moments)
+		highlights.TokenUsage = cloneTokenUsage(s.Highlights.TokenUsage)
+		c.Highlights = &highlights
+	}
+	if s.Evaluation != nil {
+		evaluation := *s.Evaluation
+		evaluation.TokenUsage = cloneTokenUsage(s.Evaluation.TokenUsage)
+		c.Evaluation = &evaluation
+	}
+	c.Redactions = maps.Clone(s.Redactions)
+	c.Hooks = slices.Clone(s.Hooks)
+	c.DocumentInfo = CloneInfo(s.DocumentInfo)
+	c.Tags = slices.Clone(s.Tags)
+	c.Metadata = maps.Clone(s.Metadata)
+	c.Groups = slices.Clone(s.Groups)
+	c.ChildIDs = slices.Clone(s.ChildIDs)
+	return &c
+}
+
+func cloneTokenUsage(usage *TokenUsage) *TokenUsage {
+	if usage == nil {
+		return nil
+	}
+	copied := *usage
+	return &copied
+}
+
+// CloneInfo deep-copies video or document info, including the maps and lists nested in it
+func CloneInfo(info map[string]interface{}) map[string]interface{} {
+	if info == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		copied[k] = cloneInfoValue(v)
+	}
+	return copied
+}
+
+func cloneInfoValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return CloneInfo(val)
+	case []interface{}:
+		copied := make([]interface{}, len(val))
+		for i, item := range val {
+			copied[i] = cloneInfoValue(item)
+		}
+		return copied
+	case []string:
+		return slices.Clone(val)
+	case map[string]string:
+		return maps.Clone(val)
+	default:
+		return v
+	}
+}
@@ -1,8 +1,89 @@
 package interfaces
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 // SummarizationProvider defines methods for text summarization
 type SummarizationProvider interface {
 	SummarizeText(ctx context.Context, text string, prompt string, maxTokens int) (string /*summaryFilePath*/, error)
 }
+
+// TokenUsage records the tokens consumed by summarization calls and their estimated cost
+type TokenUsage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd,omitempty"`
+}
+
+// UsageRecorder accumulates token usage across the provider calls made for one request
+type UsageRecorder struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// Usage returns the usage recorded so far
+func (r *UsageRecorder) Usage() TokenUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.usage
+}
+
+type usageRecorderKey struct{}
+
+// WithUsageRecorder returns a context that collects token usage reported by providers
+func WithUsageRecorder(ctx context.Context) (context.Context, *UsageRecorder) {
+	recorder := &UsageRecorder{}
+	return context.WithValue(ctx, usageRecorderKey{}, recorder), recorder
+}
+
+type summaryModelKey struct{}
+
+// WithSummaryModel returns a context asking providers to summarize with model instead of
+// their configured one
+func WithSummaryModel(ctx context.Context, model string) context.Context {
+	if model == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, summaryModelKey{}, model)
+}
+
+// SummaryModel returns the model asked for in ctx, or "" for the provider's own
+func SummaryModel(ctx context.Context) string {
+	model, _ := ctx.Value(summaryModelKey{}).(string)
+	return model
+}
+
+// RecordUsage adds token usage to the recorder in ctx, if there is one.
+// Providers call this after each API call.
+func RecordUsage(ctx context.Context, usage TokenUsage) {
+	recorder, ok := ctx.Value(usageRecorderKey{}).(*UsageRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.usage.PromptTokens += usage.PromptTokens
+	recorder.usage.CompletionTokens += usage.CompletionTokens
+	recorder.usage.TotalTokens += usage.TotalTokens
+	recorder.usage.CostUSD += usage.CostUSD
+}
+
+type summaryProgressKey struct{}
+
+// WithSummaryProgress returns a context whose ReportSummaryProgress calls report with the
+// summary text written so far; a nil report turns reporting off, e.g. for the partial
+// summaries of a long transcript's chunks
+func WithSummaryProgress(ctx context.Context, report func(text string)) context.Context {
+	return context.WithValue(ctx, summaryProgressKey{}, report)
+}
+
+// ReportSummaryProgress passes the summary text written so far to the reporter in ctx, if
+// there is one. Providers that stream their output call this as it arrives.
+func ReportSummaryProgress(ctx context.Context, text string) {
+	if report, ok := ctx.Value(summaryProgressKey{}).(func(string)); ok && report != nil {
+		report(text)
+	}
+}
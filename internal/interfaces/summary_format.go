@@ -0,0 +1,55 @@
+package interfaces
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SummaryLengthWords is the number of words each named summary length aims at
+var SummaryLengthWords = map[string]int{
+	"short":    150,
+	"medium":   400,
+	"detailed": 1000,
+}
+
+// Bounds on a summary length given as a word count
+const (
+	MinSummaryWords = 20
+	MaxSummaryWords = 5000
+)
+
+// Summary styles a request can ask for
+const (
+	SummaryStyleBullets = "bullets"
+	SummaryStyleProse   = "prose"
+)
+
+// ParseSummaryLength returns the number of words a summary length aims at: "short",
+// "medium", "detailed" or a word count. "" is 0, leaving the length to the prompt.
+func ParseSummaryLength(length string) (int, error) {
+	length = strings.TrimSpace(length)
+	if length == "" {
+		return 0, nil
+	}
+	if words, ok := SummaryLengthWords[length]; ok {
+		return words, nil
+	}
+	words, err := strconv.Atoi(length)
+	if err != nil {
+		return 0, fmt.Errorf("unknown summary length %q (use short, medium, detailed or a word count)", length)
+	}
+	if words < MinSummaryWords || words > MaxSummaryWords {
+		return 0, fmt.Errorf("summary length must be between %d and %d words, got %d", MinSummaryWords, MaxSummaryWords, words)
+	}
+	return words, nil
+}
+
+// CheckSummaryStyle checks a summary style: "bullets", "prose" or "" to leave it to the prompt
+func CheckSummaryStyle(style string) error {
+	switch style {
+	case "", SummaryStyleBullets, SummaryStyleProse:
+		return nil
+	}
+	return fmt.Errorf("unknown summary style %q (use bullets or prose)", style)
+}
@@ -1,7 +1,82 @@
 package interfaces
 
+import (
+	"context"
+	"time"
+)
+
 // TranscriptionProvider defines methods for audio transcription
 type TranscriptionProvider interface {
 	TranscribeAudio(audioPath string) (string /*transcriptFilePath*/, error)
 	GetSupportedLanguages() []string
 }
+
+// DetailedTranscriptionProvider is a transcription provider that also reports how confident
+// it is in each segment of the transcript and the language it heard, and takes per-request
+// options
+type DetailedTranscriptionProvider interface {
+	TranscriptionProvider
+	TranscribeAudioDetailed(ctx context.Context, audioPath string, opts TranscriptionOptions) (*Transcription, error)
+}
+
+// TranscriptionOptions describe the request being transcribed, for providers that adapt to it
+type TranscriptionOptions struct {
+	Duration time.Duration // length of the video; 0 if unknown
+	// Model name or quality hint ("auto", "fast" or "accurate"); "" picks by duration
+	Quality string
+}
+
+// Transcription is a transcript file with the details a provider reports about it
+type Transcription struct {
+	Path     string
+	Segments []TranscriptSegment
+	Language string // ISO 639-1 code of the spoken language, e.g. "en"; "" if unknown
+	Model    string // model the transcript was made with; "" if the provider has only one
+	Route    string // "local" or "cloud" when transcription is routed by video length; "" otherwise
+}
+
+// TranscriptSegment is one segment of a transcript and the provider's confidence in it
+type TranscriptSegment struct {
+	Start      time.Duration
+	End        time.Duration
+	Text       string
+	Confidence float64 // 0-1, e.g. the mean token probability
+}
+
+// TranscriptQuality aggregates the segment confidences of a transcript
+type TranscriptQuality struct {
+	Score                 float64 `json:"score"` // mean segment confidence, weighted by segment duration
+	MinSegmentConfidence  float64 `json:"min_segment_confidence"`
+	Segments              int     `json:"segments"`
+	LowConfidenceSegments int     `json:"low_confidence_segments"`
+	// The score is below the configured threshold, so the summary may be unreliable
+	LowConfidence bool `json:"low_confidence"`
+}
+
+// NewTranscriptQuality aggregates segment confidences, flagging segments and transcripts that
+// score below threshold. Segments without a duration count as if they were one second long.
+// It returns nil when there are no segments.
+func NewTranscriptQuality(segments []TranscriptSegment, threshold float64) *TranscriptQuality {
+	if len(segments) == 0 {
+		return nil
+	}
+	quality := &TranscriptQuality{Segments: len(segments), MinSegmentConfidence: 1}
+	var weighted, totalWeight float64
+	for _, segment := range segments {
+		weight := (segment.End - segment.Start).Seconds()
+		if weight <= 0 {
+			weight = 1
+		}
+		weighted += segment.Confidence * weight
+		totalWeight += weight
+		if segment.Confidence < quality.MinSegmentConfidence {
+			quality.MinSegmentConfidence = segment.Confidence
+		}
+		if segment.Confidence < threshold {
+			quality.LowConfidenceSegments++
+		}
+	}
+	quality.Score = weighted / totalWeight
+	quality.LowConfidence = quality.Score < threshold
+	return quality
+}
@@ -14,71 +14,238 @@ const (
 	TaskSummarization TaskType = "summarization"
 	TaskOutput        TaskType = "output"
 	TaskCleanup       TaskType = "cleanup"
+	// Documents and articles skip the video stages and start with text extraction
+	TaskTextExtraction TaskType = "text_extraction"
+	// Optional stage after video info that reads the text on screen, e.g. slides
+	TaskSlides TaskType = "slides"
+	// Optional stage after summarization that picks the key moments of a timed transcript
+	TaskHighlights TaskType = "highlights"
+	// Optional stage between summarization and output that scores the summary
+	TaskEvaluation TaskType = "evaluation"
+	// Optional stage before output that scrubs personal data from the transcript and summary
+	TaskRedaction TaskType = "redaction"
+	// Optional stage before output that reads the summary aloud to an audio file
+	TaskSpeech TaskType = "speech"
+	// Optional stage after output that runs the configured post-processing hooks
+	TaskHooks TaskType = "hooks"
+)
+
+// Source types of a request
+const (
+	SourceTypeVideo    = "video"
+	SourceTypeAudio    = "audio"
+	SourceTypeDocument = "document"
+	SourceTypeArticle  = "article"
+	SourceTypeDigest   = "digest"
+	// A comparison summarizes several other requests together once they have finished
+	SourceTypeComparison = "comparison"
 )
 
 // Task represents a processing task
 type Task struct {
-	ID        string                 `json:"id"`
-	Type      TaskType               `json:"type"`
-	RequestID string                 `json:"request_id"`
-	Data      interface{}            `json:"data"`
-	CreatedAt time.Time              `json:"created_at"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	ID        string   `json:"id"`
+	Type      TaskType `json:"type"`
+	RequestID string   `json:"request_id"`
+	Priority  Priority `json:"priority"`
+	// Tenant is who the task's request belongs to (a user or background source); queued
+	// tasks of equal priority are shared out round-robin across tenants
+	Tenant    string      `json:"tenant,omitempty"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+	// Attempts counts the earlier runs of the task the watchdog stopped
+	Attempts int                    `json:"attempts,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // EventType represents different types of system events
 type EventType string
 
 const (
-	EventTypeSummarizationCompleted EventType = "SummarizationCompleted"
-	EventTypeTranscriptionCompleted EventType = "TranscriptionCompleted"
-	EventTypeVideoInfoCompleted     EventType = "VideoInfoCompleted"
-	EventTypeOutputCompleted        EventType = "OutputCompleted"
-	EventTypeCleanupCompleted       EventType = "CleanupCompleted"
+	EventTypeVideoProcessingRequested EventType = "VideoProcessingRequested"
+	EventTypeVideoInfoFetched         EventType = "VideoInfoFetched"
+	EventTypeAudioDownloaded          EventType = "AudioDownloaded"
+	EventTypeRequestCancelled         EventType = "RequestCancelled"
+	EventTypeSummarizationCompleted   EventType = "SummarizationCompleted"
+	EventTypeTranscriptionCompleted   EventType = "TranscriptionCompleted"
+	EventTypeVideoInfoCompleted       EventType = "VideoInfoCompleted"
+	EventTypeOutputCompleted          EventType = "OutputCompleted"
+	EventTypeCleanupCompleted         EventType = "CleanupCompleted"
+	EventTypeProcessingCompleted      EventType = "ProcessingCompleted"
+	EventTypeProcessingFailed         EventType = "ProcessingFailed"
+	EventTypeTextExtracted            EventType = "TextExtracted"
+	EventTypeHighlightsCompleted      EventType = "HighlightsCompleted"
+	EventTypeSlidesCompleted          EventType = "SlidesCompleted"
+	EventTypeEvaluationCompleted      EventType = "EvaluationCompleted"
+	EventTypeRedactionCompleted       EventType = "RedactionCompleted"
+	EventTypeHooksCompleted           EventType = "HooksCompleted"
+	EventTypeSpeechCompleted          EventType = "SpeechCompleted"
 )
 
-// Event represents a system event
-type Event struct {
-	ID        string                 `json:"id"`
-	RequestID string                 `json:"request_id"`
-	Type      EventType              `json:"type"`
-	Data      map[string]interface{} `json:"data"`
-	Timestamp time.Time              `json:"timestamp"`
-}
-
 // ProcessingStatus represents the status of a request
 type ProcessingStatus string
 
 const (
+	// Submitted with a not-before time; the request becomes pending at that time
+	StatusScheduled ProcessingStatus = "scheduled"
 	StatusPending   ProcessingStatus = "pending"
 	StatusRunning   ProcessingStatus = "running"
 	StatusCompleted ProcessingStatus = "completed"
 	StatusFailed    ProcessingStatus = "failed"
 	StatusCancelled ProcessingStatus = "cancelled"
+	// Summarization or output failed after the transcript was produced. The transcript
+	// (and summary, if any) are kept so a retry only repeats the failed stage.
+	StatusPartiallyCompleted ProcessingStatus = "partially_completed"
 )
 
 // ProcessingState represents the state of a video processing request
 type ProcessingState struct {
-	RequestID   string           `json:"request_id"`
-	SourceType  string           `json:"source_type"` // e.g., "video", "document", etc.
-	URL         string           `json:"url"`
-	Prompt      Prompt           `json:"prompt"`
-	MaxTokens   int              `json:"max_tokens"`
-	Category    string           `json:"category"`
-	Status      ProcessingStatus `json:"status"`
-	Progress    float64          `json:"progress"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	CompletedAt *time.Time       `json:"completed_at,omitempty"`
-	Error       string           `json:"error,omitempty"`
+	RequestID  string           `json:"request_id"`
+	SourceType string           `json:"source_type"` // e.g., "video", "document", etc.
+	URL        string           `json:"url"`
+	Prompt     Prompt           `json:"prompt"`
+	MaxTokens  int              `json:"max_tokens"`
+	Category   string           `json:"category"`
+	User       string           `json:"user,omitempty"`
+	Source     string           `json:"source,omitempty"` // background source that submitted the request
+	Output     *OutputTarget    `json:"output,omitempty"` // overrides the configured output destination
+	Priority   Priority         `json:"priority"`         // priority of the request's tasks
+	Status     ProcessingStatus `json:"status"`
+	// Every status the request has had, oldest first
+	StatusHistory []StatusChange `json:"status_history,omitempty"`
+	Progress      float64        `json:"progress"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+	// A scheduled request enters the pipeline no earlier than this
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	// Category of the failure, and whether retrying the request as is may succeed
+	ErrorCode      ErrorCode `json:"error_code,omitempty"`
+	ErrorRetryable bool      `json:"error_retryable,omitempty"`
 	// Video-specific fields
-	VideoInfo  map[string]interface{} `json:"video_info,omitempty"`
-	AudioPath  string                 `json:"audio_path,omitempty"`
-	Transcript string                 `json:"transcript_path,omitempty"`
-	Summary    string                 `json:"summary_path,omitempty"`
-	OutputPath string                 `json:"output_path,omitempty"`
-	// Document-specific fields (future)
+	VideoInfo map[string]interface{} `json:"video_info,omitempty"`
+	// Full video metadata from the provider (e.g. yt-dlp --dump-json), as a JSON file
+	InfoPath string `json:"info_path,omitempty"`
+	// Downloaded video thumbnail image
+	ThumbnailPath string `json:"thumbnail_path,omitempty"`
+	AudioPath     string `json:"audio_path,omitempty"`
+	Transcript    string `json:"transcript_path,omitempty"`
+	// Transcript segments with their start and end times, as JSON
+	SegmentsPath string `json:"segments_path,omitempty"`
+	// Text read from the video's frames, when slide OCR is enabled
+	SlidesPath string `json:"slides_path,omitempty"`
+	// Audio rendition of the summary, when speech is enabled
+	SpeechPath string `json:"speech_path,omitempty"`
+	// Whisper model name or quality hint ("fast", "accurate") asked for by the submitter
+	WhisperQuality string `json:"whisper_quality,omitempty"`
+	// Whisper model the transcript was made with, when several are configured
+	WhisperModel string `json:"whisper_model,omitempty"`
+	// "local" or "cloud" when transcription is routed by video length
+	TranscriptionRoute string `json:"transcription_route,omitempty"`
+	// Confidence in the transcript, when the transcription provider reports it
+	TranscriptQuality *TranscriptQuality `json:"transcript_quality,omitempty"`
+	// Spoken language of the transcript, when the transcription provider reports it
+	Language string `json:"language,omitempty"`
+	// Overrides the configured prompt_language_mode, e.g. for a background source
+	LanguageMode string `json:"language_mode,omitempty"`
+	// Overrides the configured summary_citations: cite [HH:MM:SS] transcript timestamps in the summary
+	Citations *bool `json:"citations,omitempty"`
+	// Target summary length ("short", "medium", "detailed" or a word count) and style
+	// ("bullets" or "prose"); "" leaves them to the prompt
+	SummaryLength string `json:"summary_length,omitempty"`
+	SummaryStyle  string `json:"summary_style,omitempty"`
+	Summary       string `json:"summary_path,omitempty"`
+	OutputPath    string `json:"output_path,omitempty"`
+	// Uploaded files the output provider verified, with their remote IDs and links;
+	// OutputPath is the summary's link
+	Outputs []OutputFile `json:"outputs,omitempty"`
+	// Summary text and token usage are kept after the summary file is cleaned up
+	SummaryText string `json:"summary_text,omitempty"`
+	// Summary text written so far while it streams with summary_streaming, kept if the call fails
+	PartialSummary string      `json:"partial_summary,omitempty"`
+	TokenUsage     *TokenUsage `json:"token_usage,omitempty"`
+	// Key moments of the video, when highlights are enabled
+	Highlights *Highlights `json:"highlights,omitempty"`
+	// Quality scores of the summary, when evaluation is enabled
+	Evaluation *SummaryEvaluation `json:"evaluation,omitempty"`
+	// Number of redactions made per label (e.g. EMAIL, NAME) before upload
+	Redactions map[string]int `json:"redactions,omitempty"`
+	// Outcome of each post-processing hook run after upload
+	Hooks []HookResult `json:"hooks,omitempty"`
+	// Document and article fields
 	DocumentInfo map[string]interface{} `json:"document_info,omitempty"`
 	TextPath     string                 `json:"text_path,omitempty"`
+	// Caller-supplied labels, kept with the request and written to the output metadata
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Groups the request was submitted in, e.g. one per playlist or source run
+	Groups []string `json:"groups,omitempty"`
+	// Requests compared by a comparison request
+	ChildIDs []string `json:"child_ids,omitempty"`
+	// Request whose transcript a rerun summarizes again, and the model it asked for
+	RerunOf      string `json:"rerun_of,omitempty"`
+	SummaryModel string `json:"summary_model,omitempty"`
+	// Earlier request this background-source video is a near-duplicate of
+	DuplicateOf string `json:"duplicate_of,omitempty"`
 	// Add more source-specific fields as needed
 }
+
+// StatusChange records when a request entered a status
+type StatusChange struct {
+	Status ProcessingStatus `json:"status"`
+	At     time.Time        `json:"at"`
+}
+
+// HookResult is the outcome of one post-processing hook
+type HookResult struct {
+	Name       string    `json:"name"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// GroupStatus sums up the requests submitted in a group
+type GroupStatus struct {
+	Group string `json:"group"`
+	Total int    `json:"total"`
+	// Requests in a final status; the group is done once all of them are
+	Finished  int                      `json:"finished"`
+	Done      bool                     `json:"done"`
+	Counts    map[ProcessingStatus]int `json:"counts"`     // requests per status
+	CreatedAt time.Time                `json:"created_at"` // when the first request was submitted
+	UpdatedAt time.Time                `json:"updated_at"` // when any request last changed
+	Requests  []GroupItem              `json:"requests"`   // oldest first
+}
+
+// GroupItem is one request of a group
+type GroupItem struct {
+	RequestID   string           `json:"request_id"`
+	URL         string           `json:"url"`
+	Title       string           `json:"title,omitempty"`
+	Status      ProcessingStatus `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	ErrorCode   ErrorCode        `json:"error_code,omitempty"`
+	OutputPath  string           `json:"output_path,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// InGroup reports whether the request was submitted in the group
+func (s *ProcessingState) InGroup(group string) bool {
+	for _, g := range s.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Title returns the video or document title, or "" if it is not known yet
+func (s *ProcessingState) Title() string {
+	if title, ok := s.VideoInfo["title"].(string); ok {
+		return title
+	}
+	if title, ok := s.DocumentInfo["title"].(string); ok {
+		return title
+	}
+	return ""
+}
@@ -1,8 +1,55 @@
 package interfaces
 
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// VideoInfoFields are the video info fields kept in a request's state. The provider's full
+// metadata, with the description, tags, chapters and formats, is written to the request's
+// info.json artifact instead.
+var VideoInfoFields = []string{
+	"id", "title", "channel", "channel_id", "uploader", "duration", "upload_date",
+	"webpage_url", "thumbnail", "view_count", "like_count", "extractor",
+}
+
+// TrimVideoInfo returns the VideoInfoFields of info
+func TrimVideoInfo(info map[string]interface{}) map[string]interface{} {
+	trimmed := make(map[string]interface{}, len(VideoInfoFields))
+	for _, field := range VideoInfoFields {
+		if value, ok := info[field]; ok {
+			trimmed[field] = value
+		}
+	}
+	return trimmed
+}
+
+// VideoTimestampURL returns a link that opens the video at the given second, or "" for sites
+// that don't support one. YouTube and Vimeo links are supported.
+func VideoTimestampURL(videoURL string, seconds float64) string {
+	u, err := url.Parse(videoURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "youtu.be" || host == "youtube.com" || strings.HasSuffix(host, ".youtube.com"):
+		query := u.Query()
+		query.Set("t", fmt.Sprintf("%ds", int(seconds)))
+		u.RawQuery = query.Encode()
+	case host == "vimeo.com":
+		u.Fragment = fmt.Sprintf("t=%ds", int(seconds))
+	default:
+		return ""
+	}
+	return u.String()
+}
+
 // VideoProvider defines methods for video information and audio extraction
 type VideoProvider interface {
-	GetVideoInfo(url string) (map[string]interface{}, error)
-	DownloadAudio(url string) (string, error)
+	GetVideoInfo(ctx context.Context, url string) (map[string]interface{}, error)
+	DownloadAudio(ctx context.Context, url string) (string, error)
 	SupportsURL(url string) bool
 }
@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// modulePrefix is stripped from caller package paths to name modules
+const modulePrefix = "video-summarizer-go/"
+
+// LevelSettings are the global log level and the per-module levels that override it.
+// Modules are package paths without the module prefix and internal/, e.g. "sources",
+// "core" or "providers/transcription"; a module's level also applies to the packages
+// beneath it unless they have their own.
+type LevelSettings struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// levelController holds the levels the standard logger filters entries by
+type levelController struct {
+	mu      sync.RWMutex
+	base    log.Level
+	modules map[string]log.Level
+}
+
+var levels = &levelController{base: log.InfoLevel, modules: make(map[string]log.Level)}
+
+// Levels returns the current log levels
+func Levels() LevelSettings {
+	levels.mu.RLock()
+	defer levels.mu.RUnlock()
+	settings := LevelSettings{Level: levels.base.String(), Modules: make(map[string]string, len(levels.modules))}
+	for module, level := range levels.modules {
+		settings.Modules[module] = level.String()
+	}
+	return settings
+}
+
+// SetLevel changes the global log level
+func SetLevel(level string) error {
+	parsed, err := log.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	levels.base = parsed
+	levels.applyLocked()
+	return nil
+}
+
+// SetModuleLevel changes the log level of a module; an empty level removes the override so
+// the module logs at the global level again
+func SetModuleLevel(module, level string) error {
+	module = moduleName(module)
+	if module == "" {
+		return fmt.Errorf("module is required")
+	}
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	if level == "" {
+		delete(levels.modules, module)
+		levels.applyLocked()
+		return nil
+	}
+	parsed, err := log.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.modules[module] = parsed
+	levels.applyLocked()
+	return nil
+}
+
+// UpdateLevels changes the global level, unless update.Level is empty, and the levels of the
+// modules in update.Modules, where an empty level removes the module's override. Nothing
+// changes if any level is invalid.
+func UpdateLevels(update LevelSettings) error {
+	var base log.Level
+	if update.Level != "" {
+		parsed, err := log.ParseLevel(update.Level)
+		if err != nil {
+			return err
+		}
+		base = parsed
+	}
+	modules := make(map[string]*log.Level, len(update.Modules))
+	for module, level := range update.Modules {
+		name := moduleName(module)
+		if name == "" {
+			return fmt.Errorf("module is required")
+		}
+		if level == "" {
+			modules[name] = nil
+			continue
+		}
+		parsed, err := log.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", module, err)
+		}
+		modules[name] = &parsed
+	}
+
+	levels.mu.Lock()
+	defer levels.mu.Unlock()
+	if update.Level != "" {
+		levels.base = base
+	}
+	for module, level := range modules {
+		if level == nil {
+			delete(levels.modules, module)
+		} else {
+			levels.modules[module] = *level
+		}
+	}
+	levels.applyLocked()
+	return nil
+}
+
+// moduleName normalizes a module name given as a package path, e.g.
+// "video-summarizer-go/internal/sources" or "internal/sources/" to "sources"
+func moduleName(module string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimPrefix(strings.Trim(module, "/"), modulePrefix), "internal/"), "/")
+}
+
+// applyLocked sets the logger to the most verbose level any module needs; entries from
+// other modules are dropped by enabled. Caller must hold the lock.
+func (c *levelController) applyLocked() {
+	level := c.base
+	for _, moduleLevel := range c.modules {
+		if moduleLevel > level {
+			level = moduleLevel
+		}
+	}
+	log.SetLevel(level)
+}
+
+// enabled reports whether an entry passes the level of the module that logged it
+func (c *levelController) enabled(entry *log.Entry) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.modules) == 0 {
+		return entry.Level <= c.base
+	}
+	return entry.Level <= c.levelForLocked(entryModule(entry))
+}
+
+// levelForLocked returns the level of the closest module containing module. Caller must
+// hold the lock.
+func (c *levelController) levelForLocked(module string) log.Level {
+	for module != "" {
+		if level, ok := c.modules[module]; ok {
+			return level
+		}
+		slash := strings.LastIndex(module, "/")
+		if slash == -1 {
+			break
+		}
+		module = module[:slash]
+	}
+	return c.base
+}
+
+// entryModule names the module of the function that logged an entry, e.g. "core/tasks"
+// for video-summarizer-go/internal/core/tasks.(*SummarizationTask).Process; "" when the
+// caller isn't reported
+func entryModule(entry *log.Entry) string {
+	if !entry.HasCaller() {
+		return ""
+	}
+	function := entry.Caller.Function
+	pkg := function
+	if slash := strings.LastIndex(function, "/"); slash != -1 {
+		if dot := strings.Index(function[slash:], "."); dot != -1 {
+			pkg = function[:slash+dot]
+		}
+	} else if dot := strings.Index(function, "."); dot != -1 {
+		pkg = function[:dot]
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(pkg, modulePrefix), "internal/")
+}
+
+// levelFilter drops the entries below their module's level before formatting them
+type levelFilter struct {
+	log.Formatter
+}
+
+// Format formats the entry, or returns nothing for entries their module doesn't log
+func (f levelFilter) Format(entry *log.Entry) ([]byte, error) {
+	if !levels.enabled(entry) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
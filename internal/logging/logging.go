@@ -20,6 +20,11 @@ type Config struct {
 	MaxBackups int  `yaml:"max_backups"` // Maximum number of old log files to retain
 	MaxAge     int  `yaml:"max_age"`     // Maximum number of days to retain old log files
 	Compress   bool `yaml:"compress"`    // Whether to compress rotated log files
+	// Per-request log lines kept for GET /api/requests/{id}/logs
+	RequestLogLines    int `yaml:"request_log_lines"`    // lines kept per request
+	RequestLogRequests int `yaml:"request_log_requests"` // requests whose lines are kept
+	// Per-module levels overriding level, e.g. sources: debug (see LevelSettings)
+	Modules map[string]string `yaml:"modules"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -41,11 +46,18 @@ func LoadConfig(path string) (*Config, error) {
 	if cfg.MaxAge == 0 {
 		cfg.MaxAge = 28 // Keep logs for 28 days default
 	}
+	if cfg.RequestLogLines == 0 {
+		cfg.RequestLogLines = 500
+	}
+	if cfg.RequestLogRequests == 0 {
+		cfg.RequestLogRequests = 1000
+	}
 	return &cfg, nil
 }
 
-// Custom formatter to put log message first, then file:line and method
-// e.g. INFO[time] My log message | core/engine.go:302 (*ProcessingEngine).WorkerProcess
+// Custom formatter to put log message first, then file:line and method, with the request
+// the entry belongs to ahead of the message when it's tagged with one
+// e.g. INFO[time] [req-1718000000000000000] My log message | core/engine.go:302 (*ProcessingEngine).WorkerProcess
 
 type MessageFirstFormatter struct {
 	log.TextFormatter
@@ -55,6 +67,9 @@ func (f *MessageFirstFormatter) Format(entry *log.Entry) ([]byte, error) {
 	// Use the standard formatter to get the prefix (level, time, etc.)
 	prefix := fmt.Sprintf("%s[%s] ", strings.ToUpper(entry.Level.String()), entry.Time.Format(f.TimestampFormat))
 	msg := entry.Message
+	if requestID, ok := entry.Data[RequestIDField].(string); ok && requestID != "" {
+		msg = fmt.Sprintf("[%s] %s", requestID, msg)
+	}
 	var caller string
 	if entry.HasCaller() {
 		relFile := entry.Caller.File
@@ -70,30 +85,35 @@ func (f *MessageFirstFormatter) Format(entry *log.Entry) ([]byte, error) {
 	return []byte(fmt.Sprintf("%s%s%s\n", prefix, msg, caller)), nil
 }
 
-func SetupLogging(path string) error {
+// SetupLogging configures the standard logger from the file at path and returns the
+// per-request log buffer it feeds
+func SetupLogging(path string) (*RequestLogs, error) {
 	cfg, err := LoadConfig(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Set log level
-	level, err := log.ParseLevel(cfg.Level)
-	if err != nil {
-		level = log.InfoLevel
+	if err := SetLevel(cfg.Level); err != nil {
+		SetLevel("info")
+	}
+	for module, level := range cfg.Modules {
+		if err := SetModuleLevel(module, level); err != nil {
+			return nil, fmt.Errorf("invalid level for module %s: %w", module, err)
+		}
 	}
-	log.SetLevel(level)
 
 	// Set log format
 	switch cfg.Format {
 	case "json":
-		log.SetFormatter(&log.JSONFormatter{})
+		log.SetFormatter(levelFilter{&log.JSONFormatter{}})
 	default:
-		log.SetFormatter(&MessageFirstFormatter{
+		log.SetFormatter(levelFilter{&MessageFirstFormatter{
 			TextFormatter: log.TextFormatter{
 				FullTimestamp:   true,
 				TimestampFormat: "2006-01-02T15:04:05-07:00",
 				DisableQuote:    true,
 			},
-		})
+		}})
 	}
 
 	// Enable caller reporting
@@ -112,5 +132,10 @@ func SetupLogging(path string) error {
 	} else {
 		log.SetOutput(io.MultiWriter(os.Stderr))
 	}
-	return nil
+
+	// Tag entries with their request first, so the request logs see the tag
+	log.AddHook(requestIDHook{})
+	requestLogs := NewRequestLogs(cfg.RequestLogLines, cfg.RequestLogRequests)
+	log.AddHook(requestLogs)
+	return requestLogs, nil
 }
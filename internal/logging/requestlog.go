@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// requestIDPattern matches the request IDs the service assigns, e.g. req-1718000000000000000
+var requestIDPattern = regexp.MustCompile(`\breq-\d+\b`)
+
+// RequestIDField is the log field that tags an entry with the request it belongs to
+const RequestIDField = "requestID"
+
+// requestIDHook tags entries logged with a context carrying a request ID (see
+// interfaces.WithRequestID), so formatters print it and RequestLogs can file them
+type requestIDHook struct{}
+
+// Levels reports that the hook sees entries at every level the logger emits
+func (requestIDHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire adds the context's request ID to the entry, unless it already names one
+func (requestIDHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if _, ok := entry.Data[RequestIDField]; ok {
+		return nil
+	}
+	if requestID := interfaces.RequestID(entry.Context); requestID != "" {
+		entry.Data[RequestIDField] = requestID
+	}
+	return nil
+}
+
+// RequestLogLine is one log entry kept for a request
+type RequestLogLine struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// RequestLogs is a logrus hook that keeps the recent log lines of each request, so they can
+// be read or followed per request. An entry belongs to the request in its request_id field,
+// or to every request ID its message mentions.
+type RequestLogs struct {
+	maxLines    int
+	maxRequests int
+
+	mu          sync.Mutex
+	lines       map[string][]RequestLogLine
+	order       *list.List // request IDs, least recently logged first
+	orderIndex  map[string]*list.Element
+	subscribers map[string]map[chan RequestLogLine]struct{}
+}
+
+// NewRequestLogs keeps up to maxLines lines for each of the maxRequests most recently
+// logged requests
+func NewRequestLogs(maxLines, maxRequests int) *RequestLogs {
+	return &RequestLogs{
+		maxLines:    maxLines,
+		maxRequests: maxRequests,
+		lines:       make(map[string][]RequestLogLine),
+		order:       list.New(),
+		orderIndex:  make(map[string]*list.Element),
+		subscribers: make(map[string]map[chan RequestLogLine]struct{}),
+	}
+}
+
+// Levels reports that the hook sees entries at every level the logger emits
+func (l *RequestLogs) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire records the entry for the requests it belongs to
+func (l *RequestLogs) Fire(entry *log.Entry) error {
+	if !levels.enabled(entry) {
+		return nil
+	}
+	var ids []string
+	if id, ok := entry.Data[RequestIDField].(string); ok && id != "" {
+		ids = []string{id}
+	} else {
+		ids = requestIDPattern.FindAllString(entry.Message, -1)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	line := RequestLogLine{Time: entry.Time, Level: entry.Level.String(), Message: entry.Message}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		l.appendLocked(id, line)
+		for ch := range l.subscribers[id] {
+			select {
+			case ch <- line:
+			default:
+				// A slow follower misses lines rather than blocking logging
+			}
+		}
+	}
+	return nil
+}
+
+// appendLocked adds a line to a request's buffer. Caller must hold the lock.
+func (l *RequestLogs) appendLocked(id string, line RequestLogLine) {
+	lines := append(l.lines[id], line)
+	if l.maxLines > 0 && len(lines) > l.maxLines {
+		lines = append([]RequestLogLine(nil), lines[len(lines)-l.maxLines:]...)
+	}
+	l.lines[id] = lines
+
+	if elem, ok := l.orderIndex[id]; ok {
+		l.order.MoveToBack(elem)
+	} else {
+		l.orderIndex[id] = l.order.PushBack(id)
+	}
+	for l.maxRequests > 0 && l.order.Len() > l.maxRequests {
+		oldest := l.order.Front()
+		oldID := oldest.Value.(string)
+		l.order.Remove(oldest)
+		delete(l.orderIndex, oldID)
+		delete(l.lines, oldID)
+	}
+}
+
+// Lines returns the lines kept for a request, oldest first
+func (l *RequestLogs) Lines(requestID string) []RequestLogLine {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]RequestLogLine(nil), l.lines[requestID]...)
+}
+
+// Follow returns the lines kept for a request and a channel that receives its new lines
+// until cancel is called
+func (l *RequestLogs) Follow(requestID string) (lines []RequestLogLine, updates <-chan RequestLogLine, cancel func()) {
+	ch := make(chan RequestLogLine, 256)
+	l.mu.Lock()
+	lines = append([]RequestLogLine(nil), l.lines[requestID]...)
+	if l.subscribers[requestID] == nil {
+		l.subscribers[requestID] = make(map[chan RequestLogLine]struct{})
+	}
+	l.subscribers[requestID][ch] = struct{}{}
+	l.mu.Unlock()
+
+	return lines, ch, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers[requestID], ch)
+		if len(l.subscribers[requestID]) == 0 {
+			delete(l.subscribers, requestID)
+		}
+	}
+}
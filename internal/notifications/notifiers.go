@@ -0,0 +1,205 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Notification describes a finished request, or a group whose requests have all finished
+type Notification struct {
+	Event     string    `json:"event"` // completed, failed or group_completed
+	RequestID string    `json:"request_id,omitempty"`
+	User      string    `json:"user"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	Output    string    `json:"output_path,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// For group_completed: the group, and the recipient's own requests in it per status and
+	// each one's result
+	Group  string                              `json:"group,omitempty"`
+	Counts map[interfaces.ProcessingStatus]int `json:"counts,omitempty"`
+	Items  []interfaces.GroupItem              `json:"items,omitempty"`
+}
+
+// subject returns a one-line description of the notification
+func (n Notification) subject() string {
+	if n.Event == EventGroupCompleted {
+		return fmt.Sprintf("Group finished: %s (%d of %d completed)", n.Group, n.Counts[interfaces.StatusCompleted], len(n.Items))
+	}
+	name := n.Title
+	if name == "" {
+		name = n.URL
+	}
+	if n.Event == EventFailed {
+		return fmt.Sprintf("Summary failed: %s", name)
+	}
+	return fmt.Sprintf("Summary ready: %s", name)
+}
+
+// body returns a plain-text description of the notification
+func (n Notification) body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", n.subject())
+	if n.Event == EventGroupCompleted {
+		for _, item := range n.Items {
+			name := item.Title
+			if name == "" {
+				name = item.URL
+			}
+			fmt.Fprintf(&b, "- %s: %s", name, item.Status)
+			if item.OutputPath != "" {
+				fmt.Fprintf(&b, " %s", item.OutputPath)
+			}
+			if item.Error != "" {
+				fmt.Fprintf(&b, " (%s)", item.Error)
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Request: %s\nURL: %s\nStatus: %s\n", n.RequestID, n.URL, n.Status)
+	if n.Category != "" {
+		fmt.Fprintf(&b, "Category: %s\n", n.Category)
+	}
+	if n.Output != "" {
+		fmt.Fprintf(&b, "Output: %s\n", n.Output)
+	}
+	if n.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", n.Error)
+	}
+	return b.String()
+}
+
+// Notifier delivers notifications over one channel
+type Notifier interface {
+	Notify(ctx context.Context, target string, n Notification) error
+}
+
+// WebhookNotifier POSTs the notification as JSON to the target URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+// Notify posts the notification to the webhook URL
+func (w *WebhookNotifier) Notify(ctx context.Context, target string, n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.client, target, payload, nil)
+}
+
+// SlackNotifier sends a direct message with the Slack chat.postMessage API.
+// The target is a Slack user ID (for a DM) or channel ID.
+type SlackNotifier struct {
+	client   *http.Client
+	botToken string
+	apiURL   string
+}
+
+// NewSlackNotifier creates a Slack notifier using a bot token
+func NewSlackNotifier(client *http.Client, botToken string) *SlackNotifier {
+	return &SlackNotifier{client: client, botToken: botToken, apiURL: "https://slack.com/api/chat.postMessage"}
+}
+
+// Notify posts a message to the Slack user or channel
+func (s *SlackNotifier) Notify(ctx context.Context, target string, n Notification) error {
+	if s.botToken == "" {
+		return fmt.Errorf("slack bot token is not configured")
+	}
+	payload, err := json.Marshal(map[string]string{
+		"channel": target,
+		"text":    n.body(),
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.client, s.apiURL, payload, map[string]string{"Authorization": "Bearer " + s.botToken})
+}
+
+// SMTPConfig holds the mail server settings for email notifications
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// EmailNotifier sends plain-text email through an SMTP server
+type EmailNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewEmailNotifier creates an email notifier
+func NewEmailNotifier(cfg SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Notify emails the notification to the target address
+func (e *EmailNotifier) Notify(ctx context.Context, target string, n Notification) error {
+	return e.Send([]string{target}, n.subject(), n.body())
+}
+
+// Send emails a plain-text message to the recipients
+func (e *EmailNotifier) Send(to []string, subject, body string) error {
+	if e.cfg.Host == "" || e.cfg.From == "" {
+		return fmt.Errorf("smtp host and from address are not configured")
+	}
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		e.cfg.From, strings.Join(to, ", "), subject, strings.ReplaceAll(body, "\n", "\r\n"))
+	addr := fmt.Sprintf("%s:%d", e.cfg.Host, e.cfg.Port)
+	return smtp.SendMail(addr, auth, e.cfg.From, to, []byte(msg))
+}
+
+// postJSON posts a JSON payload and treats non-2xx responses as errors
+func postJSON(ctx context.Context, client *http.Client, url string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	// Slack reports errors in the body with a 200 status
+	var slackResp struct {
+		OK    *bool  `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &slackResp) == nil && slackResp.OK != nil && !*slackResp.OK {
+		return fmt.Errorf("slack error: %s", slackResp.Error)
+	}
+	return nil
+}
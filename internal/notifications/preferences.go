@@ -0,0 +1,175 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Channel is a notification delivery channel
+type Channel string
+
+const (
+	ChannelWebhook Channel = "webhook"
+	ChannelSlack   Channel = "slack"
+	ChannelEmail   Channel = "email"
+)
+
+// Event names users can subscribe to
+const (
+	EventCompleted = "completed"
+	EventFailed    = "failed"
+	// Every request of a group the user has requests in has finished
+	EventGroupCompleted = "group_completed"
+)
+
+// Preference is a user's notification setting
+type Preference struct {
+	User    string  `json:"user"`
+	Channel Channel `json:"channel"`
+	// Target is the webhook URL, Slack user/channel ID, or email address
+	Target string `json:"target"`
+	// Events to notify about (default: completed and failed)
+	Events []string `json:"events,omitempty"`
+}
+
+// Validate checks the preference and fills in default events
+func (p *Preference) Validate() error {
+	if p.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	if p.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+	switch p.Channel {
+	case ChannelWebhook:
+		if err := validateWebhookURL(p.Target); err != nil {
+			return err
+		}
+	case ChannelSlack, ChannelEmail:
+	default:
+		return fmt.Errorf("unsupported channel %q (supported: webhook, slack, email)", p.Channel)
+	}
+	if len(p.Events) == 0 {
+		p.Events = []string{EventCompleted, EventFailed}
+	}
+	for _, event := range p.Events {
+		if event != EventCompleted && event != EventFailed && event != EventGroupCompleted {
+			return fmt.Errorf("unsupported event %q (supported: completed, failed, group_completed)", event)
+		}
+	}
+	return nil
+}
+
+// wants reports whether the preference subscribes to event
+func (p *Preference) wants(event string) bool {
+	for _, e := range p.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// PreferenceStore keeps notification preferences per user, optionally persisted to a JSON file
+type PreferenceStore struct {
+	path  string
+	prefs map[string]Preference
+	mu    sync.RWMutex
+}
+
+// NewPreferenceStore loads preferences from path. An empty path keeps them in memory only.
+func NewPreferenceStore(path string) (*PreferenceStore, error) {
+	s := &PreferenceStore{path: path, prefs: make(map[string]Preference)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification preferences %s: %w", path, err)
+	}
+	var prefs []Preference
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("failed to parse notification preferences %s: %w", path, err)
+	}
+	for _, pref := range prefs {
+		s.prefs[pref.User] = pref
+	}
+	return s, nil
+}
+
+// Get returns the preference for a user
+func (s *PreferenceStore) Get(user string) (Preference, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pref, ok := s.prefs[user]
+	return pref, ok
+}
+
+// List returns all preferences sorted by user
+func (s *PreferenceStore) List() []Preference {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prefs := make([]Preference, 0, len(s.prefs))
+	for _, pref := range s.prefs {
+		prefs = append(prefs, pref)
+	}
+	sort.Slice(prefs, func(i, j int) bool { return prefs[i].User < prefs[j].User })
+	return prefs
+}
+
+// Set validates and stores a user's preference
+func (s *PreferenceStore) Set(pref Preference) error {
+	if err := pref.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefs := maps.Clone(s.prefs)
+	prefs[pref.User] = pref
+	return s.saveLocked(prefs)
+}
+
+// Delete removes a user's preference
+func (s *PreferenceStore) Delete(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefs := maps.Clone(s.prefs)
+	delete(prefs, user)
+	return s.saveLocked(prefs)
+}
+
+// saveLocked writes prefs to disk and, once they are saved, makes them the current
+// preferences. Caller must hold the write lock.
+func (s *PreferenceStore) saveLocked(prefs map[string]Preference) error {
+	if s.path != "" {
+		list := make([]Preference, 0, len(prefs))
+		for _, pref := range prefs {
+			list = append(list, pref)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].User < list[j].User })
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+			return err
+		}
+		tmpPath := s.path + ".tmp"
+		if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpPath, s.path); err != nil {
+			return err
+		}
+	}
+	s.prefs = prefs
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package notifications tells users when their requests complete or fail, over the
+// channel each user prefers. It listens to engine events and is independent of the
+// output provider.
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/netguard"
+)
+
+// maxAttempts is how many times a notification is tried before it is dropped
+const maxAttempts = 3
+
+// groupPruneInterval is how often groups whose requests are all gone are forgotten
+const groupPruneInterval = time.Hour
+
+// GroupStatusFunc sums up the requests of a group, or returns nil if it has none
+type GroupStatusFunc func(group string) (*interfaces.GroupStatus, error)
+
+// Service dispatches notifications for finished requests
+type Service struct {
+	prefs     *PreferenceStore
+	notifiers map[Channel]Notifier
+	store     interfaces.StateStore
+	timeout   time.Duration
+
+	// Groups notified as finished, with their size then, so a group is only notified
+	// again if requests were added to it since
+	groupStatus    GroupStatusFunc
+	notifiedGroup  map[string]int
+	groupsPrunedAt time.Time
+	groupsMu       sync.Mutex
+}
+
+// NewService creates a notification service with webhook, Slack and email notifiers
+func NewService(prefs *PreferenceStore, slackBotToken string, smtpCfg SMTPConfig) *Service {
+	client := &http.Client{Timeout: 15 * time.Second}
+	return &Service{
+		prefs: prefs,
+		notifiers: map[Channel]Notifier{
+			ChannelWebhook: NewWebhookNotifier(netguard.NewPublicClient(client.Timeout)),
+			ChannelSlack:   NewSlackNotifier(client, slackBotToken),
+			ChannelEmail:   NewEmailNotifier(smtpCfg),
+		},
+		timeout:       30 * time.Second,
+		notifiedGroup: make(map[string]int),
+	}
+}
+
+// Preferences returns the preference store
+func (s *Service) Preferences() *PreferenceStore {
+	return s.prefs
+}
+
+// Attach subscribes the service to completion and failure events
+func (s *Service) Attach(bus interfaces.EventBus, store interfaces.StateStore) {
+	s.store = store
+	bus.Subscribe(interfaces.EventTypeProcessingCompleted, s.onFinished)
+	bus.Subscribe(interfaces.EventTypeProcessingFailed, s.onFinished)
+}
+
+// AttachGroups sends group_completed notifications once every request of a group has
+// finished, looking groups up with groupStatus
+func (s *Service) AttachGroups(bus interfaces.EventBus, groupStatus GroupStatusFunc) {
+	s.groupStatus = groupStatus
+	bus.Subscribe(interfaces.EventTypeProcessingCompleted, s.onGroupMemberFinished)
+	bus.Subscribe(interfaces.EventTypeProcessingFailed, s.onGroupMemberFinished)
+	bus.Subscribe(interfaces.EventTypeRequestCancelled, s.onGroupMemberFinished)
+}
+
+// onGroupMemberFinished notifies the users with requests in each of the request's groups
+// that are now finished, once per group, with the results of their own requests. Group
+// names are chosen by clients, so a group can hold other users' requests.
+func (s *Service) onGroupMemberFinished(event interfaces.Event) {
+	state, err := s.store.GetRequestState(event.RequestID)
+	if err != nil || len(state.Groups) == 0 {
+		return
+	}
+	s.pruneGroups()
+	for _, group := range state.Groups {
+		status, err := s.groupStatus(group)
+		if err != nil || status == nil || !status.Done {
+			continue
+		}
+		s.groupsMu.Lock()
+		notified := s.notifiedGroup[group] == status.Total
+		s.notifiedGroup[group] = status.Total
+		s.groupsMu.Unlock()
+		if notified {
+			continue
+		}
+
+		for user, items := range s.groupItemsByUser(status) {
+			pref, ok := s.prefs.Get(user)
+			if !ok || !pref.wants(EventGroupCompleted) {
+				continue
+			}
+			n := Notification{
+				Event:     EventGroupCompleted,
+				Group:     group,
+				User:      user,
+				Status:    string(interfaces.StatusCompleted),
+				Counts:    make(map[interfaces.ProcessingStatus]int),
+				Items:     items,
+				Timestamp: time.Now(),
+			}
+			for _, item := range items {
+				n.Counts[item.Status]++
+			}
+			if n.Counts[interfaces.StatusCompleted] < len(items) {
+				n.Status = string(interfaces.StatusPartiallyCompleted)
+			}
+			go s.deliver(pref, n)
+		}
+	}
+}
+
+// groupItemsByUser splits the group's requests by the user who submitted them
+func (s *Service) groupItemsByUser(status *interfaces.GroupStatus) map[string][]interfaces.GroupItem {
+	items := make(map[string][]interfaces.GroupItem)
+	for _, item := range status.Requests {
+		state, err := s.store.GetRequestState(item.RequestID)
+		if err != nil || state.User == "" {
+			continue
+		}
+		items[state.User] = append(items[state.User], item)
+	}
+	return items
+}
+
+// pruneGroups forgets, at most every groupPruneInterval, the notified groups that no longer
+// have any requests
+func (s *Service) pruneGroups() {
+	s.groupsMu.Lock()
+	if time.Since(s.groupsPrunedAt) < groupPruneInterval {
+		s.groupsMu.Unlock()
+		return
+	}
+	s.groupsPrunedAt = time.Now()
+	groups := make([]string, 0, len(s.notifiedGroup))
+	for group := range s.notifiedGroup {
+		groups = append(groups, group)
+	}
+	s.groupsMu.Unlock()
+
+	for _, group := range groups {
+		if status, err := s.groupStatus(group); err == nil && status == nil {
+			s.groupsMu.Lock()
+			delete(s.notifiedGroup, group)
+			s.groupsMu.Unlock()
+		}
+	}
+}
+
+// onFinished builds a notification for a finished request and delivers it in the background,
+// so slow channels never hold up the pipeline
+func (s *Service) onFinished(event interfaces.Event) {
+	state, err := s.store.GetRequestState(event.RequestID)
+	if err != nil || state.User == "" {
+		return
+	}
+	pref, ok := s.prefs.Get(state.User)
+	if !ok {
+		return
+	}
+
+	n := Notification{
+		Event:     EventCompleted,
+		RequestID: state.RequestID,
+		User:      state.User,
+		URL:       state.URL,
+		Category:  state.Category,
+		Status:    string(state.Status),
+		Error:     state.Error,
+		ErrorCode: string(state.ErrorCode),
+		Output:    state.OutputPath,
+		Timestamp: time.Now(),
+	}
+	if state.Status != interfaces.StatusCompleted {
+		n.Event = EventFailed
+	}
+	n.Title = state.Title()
+	if !pref.wants(n.Event) {
+		return
+	}
+
+	go s.deliver(pref, n)
+}
+
+// deliver sends a notification, retrying with backoff
+func (s *Service) deliver(pref Preference, n Notification) {
+	notifier, ok := s.notifiers[pref.Channel]
+	if !ok {
+		log.Warnf("No notifier for channel %s (user %s)", pref.Channel, pref.User)
+		return
+	}
+	subject := "request " + n.RequestID
+	if n.Event == EventGroupCompleted {
+		subject = "group " + n.Group
+	}
+	backoff := 2 * time.Second
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err := notifier.Notify(ctx, pref.Target, n)
+		cancel()
+		if err == nil {
+			if n.Event == EventGroupCompleted {
+				log.Infof("Notified %s via %s that group %s finished", pref.User, pref.Channel, n.Group)
+			} else {
+				log.Infof("Notified %s via %s that request %s %s", pref.User, pref.Channel, n.RequestID, n.Event)
+			}
+			return
+		}
+		log.Warnf("Notification attempt %d/%d for %s via %s failed: %v", attempt, maxAttempts, subject, pref.Channel, err)
+		if attempt < maxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
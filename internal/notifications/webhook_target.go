@@ -0,0 +1,37 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"video-summarizer-go/internal/netguard"
+)
+
+// errPrivateWebhookTarget is returned for webhooks pointing at this host or a private network
+var errPrivateWebhookTarget = errors.New("webhook target must be a public address")
+
+// validateWebhookURL checks a webhook target is an http(s) URL whose host isn't local or a
+// private address. Hosts named by DNS are checked again when the webhook is sent.
+func validateWebhookURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook URL must use http or https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("webhook URL has no host")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errPrivateWebhookTarget
+	}
+	if ip := net.ParseIP(host); ip != nil && !netguard.IsPublicIP(ip) {
+		return errPrivateWebhookTarget
+	}
+	return nil
+}
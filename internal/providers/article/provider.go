@@ -0,0 +1,133 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/netguard"
+)
+
+// minArticleRunes is the least text a page must yield to be treated as an article
+const minArticleRunes = 200
+
+// WebArticleProvider implements interfaces.DocumentProvider for web articles. Pages are
+// fetched over http(s), from public addresses only, and reduced to their main text with
+// readability extraction.
+type WebArticleProvider struct {
+	TmpDir   string // where to write extracted text
+	MaxBytes int64  // largest accepted page (0 = unlimited)
+	client   *http.Client
+}
+
+func NewWebArticleProvider(tmpDir string, maxBytes int64) *WebArticleProvider {
+	return &WebArticleProvider{
+		TmpDir:   tmpDir,
+		MaxBytes: maxBytes,
+		client:   netguard.NewPublicClient(time.Minute),
+	}
+}
+
+// GetSupportedFormats returns the formats this provider can extract
+func (p *WebArticleProvider) GetSupportedFormats() []string {
+	return []string{"html"}
+}
+
+// ExtractText fetches an article and writes its readable text to a document-*.txt temp file
+func (p *WebArticleProvider) ExtractText(ctx context.Context, source string) (string, map[string]interface{}, error) {
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", nil, fmt.Errorf("article URL must be an http(s) URL: %s", source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid article URL: %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	resp, err := p.client.Do(req)
+	if errors.Is(err, netguard.ErrPrivateAddress) {
+		return "", nil, interfaces.WithErrorCode(interfaces.ErrorCodeSourceUnavailable, fmt.Errorf("failed to fetch article: %w", err))
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch article: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, interfaces.SourceHTTPError(resp.StatusCode, fmt.Errorf("failed to fetch article: HTTP %d", resp.StatusCode))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return "", nil, fmt.Errorf("article URL returned %s, not an HTML page (use the document source type for PDFs and text files)", mediaType)
+	}
+
+	body := io.Reader(resp.Body)
+	if p.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, p.MaxBytes)
+	}
+	// Decode to UTF-8 using the charset from the header or the page's meta tags
+	body, err = charset.NewReader(body, contentType)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode article: %v", err)
+	}
+	doc, err := html.Parse(body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse article: %v", err)
+	}
+
+	readable := Extract(doc)
+	if utf8.RuneCountInString(readable.Text) < minArticleRunes {
+		return "", nil, fmt.Errorf("no article text found at %s", source)
+	}
+
+	out, err := os.CreateTemp(p.TmpDir, "document-*.txt")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp text file: %v", err)
+	}
+	text := readable.Text
+	if readable.Title != "" && !strings.HasPrefix(text, readable.Title) {
+		text = readable.Title + "\n\n" + text
+	}
+	_, err = out.WriteString(text)
+	out.Close()
+	if err != nil {
+		os.Remove(out.Name())
+		return "", nil, fmt.Errorf("failed to write article text: %v", err)
+	}
+
+	title := readable.Title
+	if title == "" {
+		title = u.Host + u.Path
+	}
+	siteName := readable.SiteName
+	if siteName == "" {
+		siteName = strings.TrimPrefix(u.Host, "www.")
+	}
+	info := map[string]interface{}{
+		"title":      title,
+		"site_name":  siteName,
+		"format":     "html",
+		"source":     source,
+		"word_count": len(strings.Fields(readable.Text)),
+	}
+	if readable.Author != "" {
+		info["author"] = readable.Author
+	}
+	log.WithContext(ctx).Debugf("Extracted article %q (%d words) to %s", title, info["word_count"], out.Name())
+	return out.Name(), info, nil
+}
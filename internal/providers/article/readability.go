@@ -0,0 +1,231 @@
+package article
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Readable is the main content of a web page
+type Readable struct {
+	Title    string
+	SiteName string
+	Author   string
+	Text     string
+}
+
+// skippedElements never contain article content
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Iframe: true, atom.Svg: true,
+	atom.Figure: true, atom.Select: true,
+}
+
+// blockElements end a line of text
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Li: true, atom.Ul: true, atom.Ol: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Br: true, atom.Tr: true, atom.Table: true, atom.Dd: true, atom.Dt: true,
+}
+
+// boilerplateHints in a class or id mark navigation, comments, ads and similar page furniture
+var boilerplateHints = []string{
+	"comment", "sidebar", "footer", "header", "menu", "nav", "share", "social", "related",
+	"promo", "advert", "banner", "cookie", "subscribe", "newsletter", "popup", "breadcrumb",
+}
+
+// Extract finds the main content of an HTML page, in the spirit of Readability: page
+// furniture is dropped, and the container whose paragraphs hold the most text (with
+// links counting against it) is taken as the article. <article> and <main> elements
+// are preferred when they hold a reasonable amount of text.
+func Extract(doc *html.Node) Readable {
+	r := Readable{}
+	var title string
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.Title:
+			if title == "" {
+				title = strings.TrimSpace(textOf(n))
+			}
+		case atom.Meta:
+			content := strings.TrimSpace(attr(n, "content"))
+			switch strings.ToLower(attr(n, "property") + attr(n, "name")) {
+			case "og:title":
+				r.Title = content
+			case "og:site_name":
+				r.SiteName = content
+			case "author", "article:author":
+				if r.Author == "" {
+					r.Author = content
+				}
+			}
+		}
+		return true
+	})
+	if r.Title == "" {
+		r.Title = title
+	}
+
+	r.Text = contentText(bestCandidate(doc))
+	return r
+}
+
+// bestCandidate returns the node most likely to hold the article body
+func bestCandidate(doc *html.Node) *html.Node {
+	var semantic *html.Node
+	scores := make(map[*html.Node]float64)
+	walk(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if skippedElements[n.DataAtom] || isBoilerplate(n) {
+			return false
+		}
+		if (n.DataAtom == atom.Article || n.DataAtom == atom.Main) && semantic == nil {
+			if utf8.RuneCountInString(contentText(n)) >= 500 {
+				semantic = n
+			}
+		}
+		if n.DataAtom == atom.P || n.DataAtom == atom.Pre || n.DataAtom == atom.Blockquote {
+			text := strings.TrimSpace(textOf(n))
+			length := utf8.RuneCountInString(text)
+			if length < 25 {
+				return false
+			}
+			// Long paragraphs with commas read like prose
+			score := 1 + float64(strings.Count(text, ",")) + float64(min(length/100, 3))
+			score *= 1 - linkDensity(n)
+			if parent := n.Parent; parent != nil {
+				scores[parent] += score
+				if grandparent := parent.Parent; grandparent != nil {
+					scores[grandparent] += score / 2
+				}
+			}
+			return false
+		}
+		return true
+	})
+	if semantic != nil {
+		return semantic
+	}
+
+	var best *html.Node
+	bestScore := 0.0
+	for n, score := range scores {
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best == nil {
+		// No prose found; fall back to the whole body
+		return doc
+	}
+	return best
+}
+
+// contentText renders the readable text of n with one paragraph per line
+func contentText(n *html.Node) string {
+	var b strings.Builder
+	var render func(*html.Node)
+	render = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if skippedElements[n.DataAtom] || isBoilerplate(n) {
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			render(c)
+		}
+		if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+			b.WriteString("\n")
+		}
+	}
+	render(n)
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// linkDensity is the share of a node's text that sits inside links
+func linkDensity(n *html.Node) float64 {
+	total := utf8.RuneCountInString(textOf(n))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += utf8.RuneCountInString(textOf(c))
+			return false
+		}
+		return true
+	})
+	return float64(linked) / float64(total)
+}
+
+// isBoilerplate reports whether an element's class or id marks it as page furniture
+func isBoilerplate(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	hints := strings.ToLower(attr(n, "class") + " " + attr(n, "id"))
+	if strings.TrimSpace(hints) == "" {
+		return false
+	}
+	for _, hint := range boilerplateHints {
+		if strings.Contains(hints, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// textOf returns all text below n
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.ElementNode && skippedElements[c.DataAtom] {
+			return false
+		}
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+// walk visits n and its descendants depth first; returning false skips a node's children
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
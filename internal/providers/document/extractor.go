@@ -0,0 +1,255 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/netguard"
+)
+
+// Supported document formats
+const (
+	FormatPDF  = "pdf"
+	FormatText = "text"
+)
+
+// textExtensions are file extensions read as plain text
+var textExtensions = map[string]bool{".txt": true, ".text": true, ".md": true, ".markdown": true}
+
+// FileDocumentProvider implements interfaces.DocumentProvider for PDF and plain text files.
+// PDFs are converted with pdftotext (poppler-utils). Documents at URLs are downloaded from
+// public addresses only.
+type FileDocumentProvider struct {
+	PdfToTextPath string // path to pdftotext binary
+	TmpDir        string // where to write downloads and extracted text
+	MaxBytes      int64  // largest accepted document (0 = unlimited)
+	client        *http.Client
+}
+
+func NewFileDocumentProvider(pdfToTextPath, tmpDir string, maxBytes int64) *FileDocumentProvider {
+	return &FileDocumentProvider{
+		PdfToTextPath: pdfToTextPath,
+		TmpDir:        tmpDir,
+		MaxBytes:      maxBytes,
+		client:        netguard.NewPublicClient(2 * time.Minute),
+	}
+}
+
+// GetSupportedFormats returns the document formats this provider can extract
+func (p *FileDocumentProvider) GetSupportedFormats() []string {
+	return []string{FormatPDF, FormatText}
+}
+
+// ExtractText extracts the text of a local file or http(s) URL into a document-*.txt temp file
+func (p *FileDocumentProvider) ExtractText(ctx context.Context, source string) (string, map[string]interface{}, error) {
+	localPath, filename, contentType, cleanup, err := p.fetch(ctx, source)
+	if err != nil {
+		return "", nil, err
+	}
+	defer cleanup()
+
+	stat, err := os.Stat(localPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read document: %v", err)
+	}
+	if p.MaxBytes > 0 && stat.Size() > p.MaxBytes {
+		return "", nil, fmt.Errorf("document is %d bytes, larger than the %d byte limit", stat.Size(), p.MaxBytes)
+	}
+
+	format, err := detectFormat(localPath, filename, contentType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	out, err := os.CreateTemp(p.TmpDir, "document-*.txt")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp text file: %v", err)
+	}
+	textPath := out.Name()
+	out.Close()
+
+	switch format {
+	case FormatPDF:
+		err = p.extractPDF(ctx, localPath, textPath)
+	default:
+		err = copyText(localPath, textPath)
+	}
+	if err == nil {
+		err = checkNotEmpty(textPath, format)
+	}
+	if err != nil {
+		os.Remove(textPath)
+		return "", nil, err
+	}
+
+	info := map[string]interface{}{
+		"title":      strings.TrimSuffix(filename, filepath.Ext(filename)),
+		"filename":   filename,
+		"format":     format,
+		"size_bytes": stat.Size(),
+		"source":     source,
+	}
+	if format == FormatPDF {
+		if pages := countPages(textPath); pages > 0 {
+			info["pages"] = pages
+		}
+	}
+	log.WithContext(ctx).Debugf("Extracted %s document %s to %s", format, filename, textPath)
+	return textPath, info, nil
+}
+
+// fetch returns a local path for source, downloading URLs into TmpDir first.
+// The returned cleanup removes anything fetch created.
+func (p *FileDocumentProvider) fetch(ctx context.Context, source string) (localPath, filename, contentType string, cleanup func(), err error) {
+	noop := func() {}
+	u, parseErr := url.Parse(source)
+	if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		if _, err := os.Stat(source); err != nil {
+			return "", "", "", noop, interfaces.WithErrorCode(interfaces.ErrorCodeSourceUnavailable, fmt.Errorf("document not found: %v", err))
+		}
+		return source, filepath.Base(source), "", noop, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return "", "", "", noop, fmt.Errorf("invalid document URL: %v", err)
+	}
+	resp, err := p.client.Do(req)
+	if errors.Is(err, netguard.ErrPrivateAddress) {
+		return "", "", "", noop, interfaces.WithErrorCode(interfaces.ErrorCodeSourceUnavailable, fmt.Errorf("failed to download document: %w", err))
+	}
+	if err != nil {
+		return "", "", "", noop, fmt.Errorf("failed to download document: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", "", noop, interfaces.SourceHTTPError(resp.StatusCode, fmt.Errorf("failed to download document: HTTP %d", resp.StatusCode))
+	}
+
+	filename = path.Base(u.Path)
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = filepath.Base(params["filename"])
+	}
+	if filename == "" || filename == "/" || filename == "." {
+		filename = u.Host
+	}
+
+	tmp, err := os.CreateTemp(p.TmpDir, "document-download-*"+filepath.Ext(filename))
+	if err != nil {
+		return "", "", "", noop, fmt.Errorf("failed to create temp file: %v", err)
+	}
+	cleanup = func() { os.Remove(tmp.Name()) }
+	body := io.Reader(resp.Body)
+	if p.MaxBytes > 0 {
+		// Read one byte past the limit so oversized documents are detected
+		body = io.LimitReader(resp.Body, p.MaxBytes+1)
+	}
+	_, err = io.Copy(tmp, body)
+	tmp.Close()
+	if err != nil {
+		cleanup()
+		return "", "", "", noop, fmt.Errorf("failed to download document: %v", err)
+	}
+	return tmp.Name(), filename, resp.Header.Get("Content-Type"), cleanup, nil
+}
+
+// detectFormat picks the format from the file extension, the server's content type, or
+// the first bytes of the file, in that order
+func detectFormat(localPath, filename, contentType string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".pdf" {
+		return FormatPDF, nil
+	}
+	if textExtensions[ext] {
+		return FormatText, nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case mediaType == "application/pdf":
+			return FormatPDF, nil
+		case mediaType == "text/plain" || mediaType == "text/markdown":
+			return FormatText, nil
+		}
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read document: %v", err)
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	if bytes.HasPrefix(head, []byte("%PDF-")) {
+		return FormatPDF, nil
+	}
+	if strings.HasPrefix(http.DetectContentType(head), "text/plain") {
+		return FormatText, nil
+	}
+	return "", fmt.Errorf("unsupported document type %q (supported: PDF, plain text)", filename)
+}
+
+// extractPDF converts a PDF to UTF-8 text with pdftotext
+func (p *FileDocumentProvider) extractPDF(ctx context.Context, pdfPath, textPath string) error {
+	cmd := exec.CommandContext(ctx, p.PdfToTextPath, "-enc", "UTF-8", pdfPath, textPath)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pdftotext error: %v, output: %s", err, out.String())
+	}
+	return nil
+}
+
+// countPages counts the pages of text extracted from a PDF; pdftotext ends each page with a
+// form feed
+func countPages(textPath string) int {
+	data, err := os.ReadFile(textPath)
+	if err != nil {
+		return 0
+	}
+	return bytes.Count(data, []byte{'\f'})
+}
+
+// copyText copies a text file, rejecting files that are not valid UTF-8
+func copyText(srcPath, textPath string) error {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to read document: %v", err)
+	}
+	if !utf8.Valid(data) {
+		return fmt.Errorf("document is not valid UTF-8 text")
+	}
+	return os.WriteFile(textPath, data, 0644)
+}
+
+// checkNotEmpty fails when extraction produced no text, e.g. for scanned PDFs without a text layer
+func checkNotEmpty(textPath, format string) error {
+	data, err := os.ReadFile(textPath)
+	if err != nil {
+		return fmt.Errorf("failed to read extracted text: %v", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		if format == FormatPDF {
+			return fmt.Errorf("no text could be extracted from the PDF (scanned documents are not supported)")
+		}
+		return fmt.Errorf("document is empty")
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package embedding
+
+import (
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// NewEmbeddingProviderFromConfig returns the near_duplicates embedding provider
+func NewEmbeddingProviderFromConfig(cfg *config.AppConfig) interfaces.EmbeddingProvider {
+	if cfg.NearDuplicates.Provider == "local" {
+		return NewLocalEmbeddingProvider()
+	}
+	return NewOpenAIEmbeddingProvider(cfg.OpenAIKey, cfg.NearDuplicates.Model)
+}
@@ -0,0 +1,61 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// localDimensions is the length of the local provider's vectors
+const localDimensions = 1024
+
+// LocalEmbeddingProvider implements interfaces.EmbeddingProvider without an API: each text
+// becomes a normalized vector of hashed word and word-pair counts. It only finds texts that
+// share their wording, such as re-uploads under the same or a lightly edited title, not
+// ones that say the same thing in other words.
+type LocalEmbeddingProvider struct{}
+
+// NewLocalEmbeddingProvider creates a local embedding provider
+func NewLocalEmbeddingProvider() *LocalEmbeddingProvider {
+	return &LocalEmbeddingProvider{}
+}
+
+// Embed returns the hashed word vector of each text
+func (p *LocalEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = hashedVector(text)
+	}
+	return vectors, nil
+}
+
+func hashedVector(text string) []float32 {
+	vector := make([]float32, localDimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	add := func(term string, weight float32) {
+		h := fnv.New32a()
+		h.Write([]byte(term))
+		vector[h.Sum32()%localDimensions] += weight
+	}
+	for i, word := range words {
+		add(word, 1)
+		if i > 0 {
+			add(words[i-1]+" "+word, 0.5)
+		}
+	}
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vector {
+			vector[i] *= scale
+		}
+	}
+	return vector
+}
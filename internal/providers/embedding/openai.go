@@ -0,0 +1,47 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAIEmbeddingProvider implements interfaces.EmbeddingProvider with the OpenAI embeddings API
+type OpenAIEmbeddingProvider struct {
+	client *openai.Client
+	Model  string // e.g. text-embedding-3-small
+}
+
+// NewOpenAIEmbeddingProvider creates a provider calling the OpenAI API with apiKey
+func NewOpenAIEmbeddingProvider(apiKey, model string) *OpenAIEmbeddingProvider {
+	return &OpenAIEmbeddingProvider{client: openai.NewClient(apiKey), Model: model}
+}
+
+// Embed embeds the texts in one API call. Newlines are replaced with spaces, as OpenAI
+// recommends.
+func (p *OpenAIEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	input := make([]string, len(texts))
+	for i, text := range texts {
+		input[i] = strings.Join(strings.Fields(text), " ")
+	}
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: input,
+		Model: openai.EmbeddingModel(p.Model),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings response has %d vectors for %d texts", len(resp.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings response has an unexpected index %d", data.Index)
+		}
+		vectors[data.Index] = data.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,255 @@
+// Package faults wraps providers so their calls fail at random or are delayed, as configured
+// under fault_injection. It exists to exercise retry, timeout and failure handling in staging.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ErrInjected is wrapped by every error returned by an injected failure
+var ErrInjected = errors.New("injected fault")
+
+// Injector decides, call by call, whether a provider call is delayed and whether it fails
+type Injector struct {
+	provider    string
+	failureRate float64
+	latency     time.Duration
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewInjector creates an injector for the named provider. It returns nil when the fault
+// config injects nothing, so callers can skip wrapping.
+func NewInjector(provider string, fault config.FaultConfig) *Injector {
+	if fault.FailureRate <= 0 && fault.GetLatency() <= 0 {
+		return nil
+	}
+	return &Injector{
+		provider:    provider,
+		failureRate: fault.FailureRate,
+		latency:     fault.GetLatency(),
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Inject waits the configured latency and then fails with the configured probability.
+// The wait ends early if ctx is done.
+func (i *Injector) Inject(ctx context.Context, call string) error {
+	if i.latency > 0 {
+		timer := time.NewTimer(i.latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	i.mu.Lock()
+	fail := i.rand.Float64() < i.failureRate
+	i.mu.Unlock()
+	if fail {
+		log.WithContext(ctx).Warnf("Injecting failure into %s provider %s", i.provider, call)
+		return interfaces.WithErrorCode(interfaces.ErrorCodeInjectedFault, fmt.Errorf("%s provider %s: %w", i.provider, call, ErrInjected))
+	}
+	return nil
+}
+
+// Providers is the set of providers Wrap can inject faults into
+type Providers struct {
+	Video         interfaces.VideoProvider
+	Transcription interfaces.TranscriptionProvider
+	Summarization interfaces.SummarizationProvider
+	Document      interfaces.DocumentProvider
+	Article       interfaces.DocumentProvider
+	Outputs       map[string]interfaces.OutputProvider // wrapped in place
+}
+
+// Wrap wraps every provider in opts that has faults configured and returns the result.
+// Nil providers are left nil.
+func Wrap(cfg config.FaultInjectionConfig, opts Providers) Providers {
+	if !cfg.Enabled {
+		return opts
+	}
+	injector := func(name string) *Injector {
+		return NewInjector(name, cfg.Providers[name])
+	}
+	if i := injector("video"); i != nil && opts.Video != nil {
+		opts.Video = &videoProvider{VideoProvider: opts.Video, injector: i}
+	}
+	if i := injector("transcription"); i != nil && opts.Transcription != nil {
+		wrapped := &transcriptionProvider{TranscriptionProvider: opts.Transcription, injector: i}
+		if _, ok := opts.Transcription.(interfaces.DetailedTranscriptionProvider); ok {
+			opts.Transcription = &detailedTranscriptionProvider{transcriptionProvider: wrapped}
+		} else {
+			opts.Transcription = wrapped
+		}
+	}
+	if i := injector("summarization"); i != nil && opts.Summarization != nil {
+		opts.Summarization = &summarizationProvider{provider: opts.Summarization, injector: i}
+	}
+	if i := injector("document"); i != nil && opts.Document != nil {
+		opts.Document = &documentProvider{DocumentProvider: opts.Document, injector: i}
+	}
+	if i := injector("article"); i != nil && opts.Article != nil {
+		opts.Article = &documentProvider{DocumentProvider: opts.Article, injector: i}
+	}
+	if i := injector("output"); i != nil {
+		for name, provider := range opts.Outputs {
+			opts.Outputs[name] = WrapOutput(provider, i)
+		}
+	}
+	return opts
+}
+
+type videoProvider struct {
+	interfaces.VideoProvider
+	injector *Injector
+}
+
+func (p *videoProvider) GetVideoInfo(ctx context.Context, url string) (map[string]interface{}, error) {
+	if err := p.injector.Inject(ctx, "GetVideoInfo"); err != nil {
+		return nil, err
+	}
+	return p.VideoProvider.GetVideoInfo(ctx, url)
+}
+
+func (p *videoProvider) DownloadAudio(ctx context.Context, url string) (string, error) {
+	if err := p.injector.Inject(ctx, "DownloadAudio"); err != nil {
+		return "", err
+	}
+	return p.VideoProvider.DownloadAudio(ctx, url)
+}
+
+type transcriptionProvider struct {
+	interfaces.TranscriptionProvider
+	injector *Injector
+}
+
+func (p *transcriptionProvider) TranscribeAudio(audioPath string) (string, error) {
+	if err := p.injector.Inject(context.Background(), "TranscribeAudio"); err != nil {
+		return "", err
+	}
+	return p.TranscriptionProvider.TranscribeAudio(audioPath)
+}
+
+// detailedTranscriptionProvider keeps segment confidences and the detected language
+// available through the wrapper
+type detailedTranscriptionProvider struct {
+	*transcriptionProvider
+}
+
+func (p *detailedTranscriptionProvider) TranscribeAudioDetailed(ctx context.Context, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	if err := p.injector.Inject(ctx, "TranscribeAudio"); err != nil {
+		return nil, err
+	}
+	return p.TranscriptionProvider.(interfaces.DetailedTranscriptionProvider).TranscribeAudioDetailed(ctx, audioPath, opts)
+}
+
+type summarizationProvider struct {
+	provider interfaces.SummarizationProvider
+	injector *Injector
+}
+
+func (p *summarizationProvider) SummarizeText(ctx context.Context, text string, prompt string, maxTokens int) (string, error) {
+	if err := p.injector.Inject(ctx, "SummarizeText"); err != nil {
+		return "", err
+	}
+	return p.provider.SummarizeText(ctx, text, prompt, maxTokens)
+}
+
+type documentProvider struct {
+	interfaces.DocumentProvider
+	injector *Injector
+}
+
+func (p *documentProvider) ExtractText(ctx context.Context, source string) (string, map[string]interface{}, error) {
+	if err := p.injector.Inject(ctx, "ExtractText"); err != nil {
+		return "", nil, err
+	}
+	return p.DocumentProvider.ExtractText(ctx, source)
+}
+
+// WrapOutput injects faults into an output provider's uploads. Destination overrides and
+// metadata sidecars keep working when the wrapped provider supports them.
+func WrapOutput(provider interfaces.OutputProvider, injector *Injector) interfaces.OutputProvider {
+	wrapped := &outputProvider{provider: provider, injector: injector}
+	if _, ok := provider.(interfaces.DestinationOutputProvider); ok {
+		return &destinationOutputProvider{outputProvider: wrapped}
+	}
+	return wrapped
+}
+
+type outputProvider struct {
+	provider interfaces.OutputProvider
+	injector *Injector
+}
+
+func (p *outputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	if err := p.injector.Inject(context.Background(), "UploadSummary"); err != nil {
+		return err
+	}
+	return p.provider.UploadSummary(requestID, videoInfo, summaryPath, category, user)
+}
+
+func (p *outputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	if err := p.injector.Inject(context.Background(), "UploadTranscript"); err != nil {
+		return err
+	}
+	return p.provider.UploadTranscript(requestID, videoInfo, transcriptPath, category, user)
+}
+
+// UploadMetadata is a no-op when the wrapped provider stores no metadata sidecar
+func (p *outputProvider) UploadMetadata(requestID string, videoInfo map[string]interface{}, metadata map[string]interface{}, category string, user string) error {
+	withMetadata, ok := p.provider.(interfaces.MetadataOutputProvider)
+	if !ok {
+		return nil
+	}
+	if err := p.injector.Inject(context.Background(), "UploadMetadata"); err != nil {
+		return err
+	}
+	return withMetadata.UploadMetadata(requestID, videoInfo, metadata, category, user)
+}
+
+// UploadAttachment is a no-op when the wrapped provider stores no extra files
+func (p *outputProvider) UploadAttachment(requestID string, videoInfo map[string]interface{}, filePath string, suffix string, category string, user string) error {
+	withAttachments, ok := p.provider.(interfaces.AttachmentOutputProvider)
+	if !ok {
+		return nil
+	}
+	if err := p.injector.Inject(context.Background(), "UploadAttachment"); err != nil {
+		return err
+	}
+	return withAttachments.UploadAttachment(requestID, videoInfo, filePath, suffix, category, user)
+}
+
+// VerifyOutput reports nothing to verify when the wrapped provider can't check its uploads
+func (p *outputProvider) VerifyOutput(requestID string, videoInfo map[string]interface{}, localPath string, suffix string, category string, user string) (*interfaces.OutputFile, error) {
+	verifiable, ok := p.provider.(interfaces.VerifiableOutputProvider)
+	if !ok {
+		return nil, nil
+	}
+	if err := p.injector.Inject(context.Background(), "VerifyOutput"); err != nil {
+		return nil, err
+	}
+	return verifiable.VerifyOutput(requestID, videoInfo, localPath, suffix, category, user)
+}
+
+type destinationOutputProvider struct {
+	*outputProvider
+}
+
+func (p *destinationOutputProvider) WithDestination(destination string) interfaces.OutputProvider {
+	provider := p.provider.(interfaces.DestinationOutputProvider).WithDestination(destination)
+	return WrapOutput(provider, p.injector)
+}
@@ -0,0 +1,351 @@
+// Package mock provides deterministic fake providers for every pipeline stage. They need no
+// yt-dlp, whisper, pdftotext or API keys: each stage writes a small text file derived only
+// from its input, so the same request always produces the same transcript and summary.
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// fingerprint returns a short stable hash of s, used for titles, IDs and file names
+func fingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// writeFile writes content to a new file in dir named after prefix and key
+func writeFile(dir, prefix, key, content string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%s-%s-*.txt", prefix, fingerprint(key)))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// VideoProvider returns fixed video info and writes a placeholder audio file
+type VideoProvider struct {
+	Dir       string
+	Thumbnail string // reported as the video's thumbnail URL when set
+}
+
+// NewVideoProvider creates a fake video provider writing audio files to dir
+func NewVideoProvider(dir string) *VideoProvider {
+	return &VideoProvider{Dir: dir}
+}
+
+// GetVideoInfo returns video info derived from the URL
+func (p *VideoProvider) GetVideoInfo(ctx context.Context, url string) (map[string]interface{}, error) {
+	id := fingerprint(url)
+	info := map[string]interface{}{
+		"id":          id,
+		"title":       "Mock video " + id,
+		"channel":     "Mock channel",
+		"uploader":    "Mock channel",
+		"duration":    float64(60),
+		"webpage_url": url,
+	}
+	if p.Thumbnail != "" {
+		info["thumbnail"] = p.Thumbnail
+	}
+	return info, nil
+}
+
+// DownloadAudio writes a placeholder audio file whose content is the URL
+func (p *VideoProvider) DownloadAudio(ctx context.Context, url string) (string, error) {
+	return writeFile(p.Dir, "audio", url, url)
+}
+
+// SupportsURL accepts every URL
+func (p *VideoProvider) SupportsURL(url string) bool {
+	return true
+}
+
+// TranscriptionProvider writes a transcript derived from the audio file
+type TranscriptionProvider struct {
+	Dir        string
+	Confidence float64 // reported for every transcript segment
+	Language   string  // reported as the spoken language
+}
+
+// NewTranscriptionProvider creates a fake transcription provider writing transcripts to dir
+func NewTranscriptionProvider(dir string) *TranscriptionProvider {
+	return &TranscriptionProvider{Dir: dir, Confidence: 0.9, Language: "en"}
+}
+
+// TranscribeAudio writes a transcript naming the audio's source
+func (p *TranscriptionProvider) TranscribeAudio(audioPath string) (string, error) {
+	transcription, err := p.TranscribeAudioDetailed(context.Background(), audioPath, interfaces.TranscriptionOptions{})
+	if err != nil {
+		return "", err
+	}
+	return transcription.Path, nil
+}
+
+// TranscribeAudioDetailed writes the transcript and reports one segment per sentence, each
+// with the provider's Confidence, and the provider's Language
+func (p *TranscriptionProvider) TranscribeAudioDetailed(ctx context.Context, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	source := strings.TrimSpace(string(audio))
+	sentences := []string{
+		fmt.Sprintf("This is the mock transcript of %s.", source),
+		fmt.Sprintf("It covers topic %s.", fingerprint(source)),
+	}
+	path, err := writeFile(p.Dir, "transcript", source, strings.Join(sentences, " ")+"\n")
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]interfaces.TranscriptSegment, len(sentences))
+	for i, sentence := range sentences {
+		segments[i] = interfaces.TranscriptSegment{
+			Start:      time.Duration(i) * 30 * time.Second,
+			End:        time.Duration(i+1) * 30 * time.Second,
+			Text:       sentence,
+			Confidence: p.Confidence,
+		}
+	}
+	return &interfaces.Transcription{Path: path, Segments: segments, Language: p.Language}, nil
+}
+
+// GetSupportedLanguages returns the languages the fake transcriber claims to support
+func (p *TranscriptionProvider) GetSupportedLanguages() []string {
+	return []string{"en"}
+}
+
+// SummarizationProvider writes a summary derived from the text and prompt, and records a
+// token usage proportional to their length
+type SummarizationProvider struct {
+	Dir string
+}
+
+// NewSummarizationProvider creates a fake summarization provider writing summaries to dir
+func NewSummarizationProvider(dir string) *SummarizationProvider {
+	return &SummarizationProvider{Dir: dir}
+}
+
+// SummarizeText writes a summary quoting the start of the text
+func (p *SummarizationProvider) SummarizeText(ctx context.Context, text string, prompt string, maxTokens int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	firstLine := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if runes := []rune(firstLine); len(runes) > 200 {
+		firstLine = string(runes[:200])
+	}
+	summary := fmt.Sprintf("Mock summary %s (prompt %s, %d characters): %s\n",
+		fingerprint(prompt+"\x00"+text), fingerprint(prompt), len(text), firstLine)
+
+	promptTokens := (len(prompt) + len(text)) / 4
+	completionTokens := len(summary) / 4
+	interfaces.RecordUsage(ctx, interfaces.TokenUsage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+	})
+	return writeFile(p.Dir, "summary", prompt+text, summary)
+}
+
+// DocumentProvider writes text derived from the document source instead of parsing it
+type DocumentProvider struct {
+	Dir string
+}
+
+// NewDocumentProvider creates a fake document provider writing text files to dir
+func NewDocumentProvider(dir string) *DocumentProvider {
+	return &DocumentProvider{Dir: dir}
+}
+
+// ExtractText writes a short text naming the source
+func (p *DocumentProvider) ExtractText(ctx context.Context, source string) (string, map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return "", nil, err
+	}
+	id := fingerprint(source)
+	path, err := writeFile(p.Dir, "document", source, fmt.Sprintf("This is the mock text of %s.\n", source))
+	if err != nil {
+		return "", nil, err
+	}
+	return path, map[string]interface{}{
+		"title":  "Mock document " + id,
+		"source": source,
+		"format": strings.TrimPrefix(filepath.Ext(source), "."),
+	}, nil
+}
+
+// GetSupportedFormats returns the formats the fake extractor claims to support
+func (p *DocumentProvider) GetSupportedFormats() []string {
+	return []string{"pdf", "txt", "md", "html"}
+}
+
+// SlideProvider writes on-screen text derived from the URL instead of reading video frames
+type SlideProvider struct {
+	Dir string
+}
+
+// NewSlideProvider creates a fake slide provider writing text files to dir
+func NewSlideProvider(dir string) *SlideProvider {
+	return &SlideProvider{Dir: dir}
+}
+
+// ExtractSlideText writes one slide naming the video
+func (p *SlideProvider) ExtractSlideText(ctx context.Context, url string, opts interfaces.SlideOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return writeFile(p.Dir, "slides", url, fmt.Sprintf("[0:00]\nMock slide %s\n", fingerprint(url)))
+}
+
+// SpeechProvider writes the text it is given to a file instead of reading it aloud
+type SpeechProvider struct {
+	Dir string
+}
+
+// NewSpeechProvider creates a fake speech provider writing .mp3 files to dir
+func NewSpeechProvider(dir string) *SpeechProvider {
+	return &SpeechProvider{Dir: dir}
+}
+
+// Synthesize writes the voice and text, so tests can check what would have been read
+func (p *SpeechProvider) Synthesize(ctx context.Context, text string, opts interfaces.SpeechOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(p.Dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(p.Dir, "speech-*.mp3")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "voice=%s\n%s\n", opts.Voice, text)
+	return f.Name(), err
+}
+
+// Upload is one file recorded by the fake output provider
+type Upload struct {
+	RequestID string
+	Kind      string // "summary", "transcript", "metadata" or an attachment's suffix, e.g. "info.json"
+	Title     string
+	Category  string
+	User      string
+	Content   string
+	Metadata  map[string]interface{}
+}
+
+// OutputProvider records uploads in memory instead of sending them anywhere
+type OutputProvider struct {
+	mu      sync.Mutex
+	uploads []Upload
+}
+
+// NewOutputProvider creates a fake output provider
+func NewOutputProvider() *OutputProvider {
+	return &OutputProvider{}
+}
+
+// UploadSummary records the summary
+func (p *OutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	return p.record(requestID, "summary", videoInfo, summaryPath, category, user)
+}
+
+// UploadTranscript records the transcript
+func (p *OutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	return p.record(requestID, "transcript", videoInfo, transcriptPath, category, user)
+}
+
+// UploadAttachment records an extra file under its suffix
+func (p *OutputProvider) UploadAttachment(requestID string, videoInfo map[string]interface{}, filePath string, suffix string, category string, user string) error {
+	return p.record(requestID, suffix, videoInfo, filePath, category, user)
+}
+
+// UploadMetadata records the metadata sidecar
+func (p *OutputProvider) UploadMetadata(requestID string, videoInfo map[string]interface{}, metadata map[string]interface{}, category string, user string) error {
+	title, _ := videoInfo["title"].(string)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploads = append(p.uploads, Upload{RequestID: requestID, Kind: "metadata", Title: title, Category: category, User: user, Metadata: metadata})
+	return nil
+}
+
+func (p *OutputProvider) record(requestID, kind string, videoInfo map[string]interface{}, path, category, user string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s file: %w", kind, err)
+	}
+	title, _ := videoInfo["title"].(string)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploads = append(p.uploads, Upload{RequestID: requestID, Kind: kind, Title: title, Category: category, User: user, Content: string(content)})
+	return nil
+}
+
+// VerifyOutput checks the newest recorded upload of the file matches localPath, returning a
+// mock:// link to it
+func (p *OutputProvider) VerifyOutput(requestID string, videoInfo map[string]interface{}, localPath string, suffix string, category string, user string) (*interfaces.OutputFile, error) {
+	kind := strings.TrimSuffix(suffix, ".txt")
+	if kind != "summary" && kind != "transcript" {
+		kind = suffix
+	}
+	uploads := p.UploadsFor(requestID)
+	for i := len(uploads) - 1; i >= 0; i-- {
+		if uploads[i].Kind != kind {
+			continue
+		}
+		content, err := os.ReadFile(localPath)
+		if err != nil {
+			return nil, err
+		}
+		if uploads[i].Content != string(content) {
+			return nil, fmt.Errorf("uploaded %s of request %s differs from %s", kind, requestID, localPath)
+		}
+		name := requestID + "_" + suffix
+		return &interfaces.OutputFile{Name: name, ID: name, URL: "mock://" + name, Size: int64(len(content))}, nil
+	}
+	return nil, fmt.Errorf("no %s uploaded for request %s", kind, requestID)
+}
+
+// Uploads returns every upload recorded so far, oldest first
+func (p *OutputProvider) Uploads() []Upload {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Upload(nil), p.uploads...)
+}
+
+// UploadsFor returns the uploads recorded for one request
+func (p *OutputProvider) UploadsFor(requestID string) []Upload {
+	var uploads []Upload
+	for _, upload := range p.Uploads() {
+		if upload.RequestID == requestID {
+			uploads = append(uploads, upload)
+		}
+	}
+	return uploads
+}
+
+// Reset forgets all recorded uploads
+func (p *OutputProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.uploads = nil
+}
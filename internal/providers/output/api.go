@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// apiStatusError is an unexpected HTTP status from an output service's REST API
+type apiStatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *apiStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Status, e.Body)
+}
+
+// postJSON sends body as JSON with the given Authorization header and decodes the response
+// into out, unless out is nil
+func postJSON(client *http.Client, url, authorization string, body, out interface{}) error {
+	return requestJSON(client, http.MethodPost, url, authorization, body, out)
+}
+
+// requestJSON sends a request with body, if not nil, as JSON and decodes the response into
+// out, unless out is nil
+func requestJSON(client *http.Client, method, url, authorization string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if resp.StatusCode >= 300 {
+		return &apiStatusError{Status: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// apiError attaches the error code an output service's HTTP status indicates
+func apiError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var statusErr *apiStatusError
+	if !errors.As(err, &statusErr) {
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadFailed, err)
+	}
+	switch {
+	case statusErr.Status == 401 || statusErr.Status == 403:
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadAuthExpired, err)
+	case statusErr.Status == 429:
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadRateLimited, err)
+	case statusErr.Status >= 500:
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadUnavailable, err)
+	}
+	return interfaces.WithErrorCode(interfaces.ErrorCodeUploadFailed, err)
+}
+
+// videoLink returns the original link of a video or article, or "" for uploaded documents
+func videoLink(videoInfo map[string]interface{}) string {
+	link, _ := videoInfo["webpage_url"].(string)
+	if link == "" {
+		link, _ = videoInfo["url"].(string)
+	}
+	return link
+}
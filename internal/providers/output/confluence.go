@@ -0,0 +1,251 @@
+package output
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// confluenceMaxTitleRunes is Confluence's page title length limit
+const confluenceMaxTitleRunes = 255
+
+// ConfluenceOutputProvider writes each summary as a Confluence page in a space, under a
+// parent page if one is set, and the transcript as a child page of it. Page titles come
+// from title_template; a page that already has the title is updated rather than duplicated,
+// so a retried or re-run request replaces its page. Pages are labelled with the category.
+type ConfluenceOutputProvider struct {
+	client        *http.Client
+	baseURL       string
+	authorization string
+	spaceKey      string
+	parentID      string
+	titleTemplate string
+}
+
+// confluencePage is the part of a Confluence page the provider reads
+type confluencePage struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// NewConfluenceOutputProvider creates a Confluence output provider. With a username the token
+// is an Atlassian API token (Confluence Cloud); without one it is a personal access token
+// (Confluence Data Center and Server).
+func NewConfluenceOutputProvider(cfg config.ConfluenceConfig) *ConfluenceOutputProvider {
+	authorization := "Bearer " + cfg.Token
+	if cfg.Username != "" {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.Token))
+	}
+	titleTemplate := cfg.TitleTemplate
+	if titleTemplate == "" {
+		titleTemplate = config.DefaultConfluenceTitleTemplate
+	}
+	return &ConfluenceOutputProvider{
+		client:        &http.Client{Timeout: 30 * time.Second},
+		baseURL:       strings.TrimRight(cfg.BaseURL, "/"),
+		authorization: authorization,
+		spaceKey:      cfg.SpaceKey,
+		parentID:      cfg.ParentID,
+		titleTemplate: titleTemplate,
+	}
+}
+
+// WithDestination returns a copy of the provider that creates pages under another parent page ID
+func (c *ConfluenceOutputProvider) WithDestination(parentID string) interfaces.OutputProvider {
+	if parentID == "" {
+		return c
+	}
+	copied := *c
+	copied.parentID = parentID
+	return &copied
+}
+
+// UploadSummary creates or updates the request's page with the summary, headed by the video's
+// link, channel and category
+func (c *ConfluenceOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	var details []string
+	if link := videoLink(videoInfo); link != "" {
+		escaped := html.EscapeString(link)
+		details = append(details, fmt.Sprintf(`<a href="%s">%s</a>`, escaped, escaped))
+	}
+	if channel := infoString(videoInfo, "channel", "uploader"); channel != "" {
+		details = append(details, html.EscapeString(channel))
+	}
+	details = append(details, "Category: "+html.EscapeString(category))
+	if user != "" {
+		details = append(details, "Requested by "+html.EscapeString(user))
+	}
+	body := "<p><em>" + strings.Join(details, " · ") + "</em></p>\n" + summaryHTML(string(summary))
+
+	page, err := c.savePage(c.pageTitle(requestID, videoInfo, category, user), c.parentID, body)
+	if err != nil {
+		return apiError(fmt.Errorf("failed to save summary page for request %s: %w", requestID, err))
+	}
+	if label := confluenceLabel(category); label != "" {
+		labels := []map[string]string{{"prefix": "global", "name": label}}
+		if err := postJSON(c.client, c.baseURL+"/rest/api/content/"+page.ID+"/label", c.authorization, labels, nil); err != nil {
+			log.Warnf("Failed to label Confluence page %s with %q: %v", page.ID, label, err)
+		}
+	}
+	log.Infof("Saved summary for request %s to Confluence page %s%s", requestID, c.baseURL, page.Links.WebUI)
+	return nil
+}
+
+// UploadTranscript creates or updates a child page of the summary page holding the transcript
+func (c *ConfluenceOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	transcript, err := os.ReadFile(transcriptPath)
+	if err != nil {
+		return fmt.Errorf("failed to read transcript: %w", err)
+	}
+	title := c.pageTitle(requestID, videoInfo, category, user)
+	parentID := c.parentID
+	summaryPage, err := c.findPage(title)
+	if err != nil {
+		return apiError(fmt.Errorf("failed to find summary page for request %s: %w", requestID, err))
+	}
+	if summaryPage != nil {
+		parentID = summaryPage.ID
+	}
+	if _, err := c.savePage(truncateTitle(title+" (transcript)"), parentID, summaryHTML(string(transcript))); err != nil {
+		return apiError(fmt.Errorf("failed to save transcript page for request %s: %w", requestID, err))
+	}
+	log.Infof("Saved transcript for request %s to Confluence", requestID)
+	return nil
+}
+
+// DeleteOutputs removes the request's transcript and summary pages
+func (c *ConfluenceOutputProvider) DeleteOutputs(requestID string, videoInfo map[string]interface{}, category string, user string) error {
+	title := c.pageTitle(requestID, videoInfo, category, user)
+	for _, pageTitle := range []string{truncateTitle(title + " (transcript)"), title} {
+		page, err := c.findPage(pageTitle)
+		if err != nil {
+			return apiError(fmt.Errorf("failed to find Confluence page %q: %w", pageTitle, err))
+		}
+		if page == nil {
+			continue
+		}
+		if err := requestJSON(c.client, http.MethodDelete, c.baseURL+"/rest/api/content/"+page.ID, c.authorization, nil, nil); err != nil {
+			return apiError(fmt.Errorf("failed to delete Confluence page %s: %w", page.ID, err))
+		}
+		log.Infof("Deleted Confluence page %s for request %s", page.ID, requestID)
+	}
+	return nil
+}
+
+// savePage updates the space's page with the title, or creates it under parentID. An
+// existing page keeps its place in the page tree.
+func (c *ConfluenceOutputProvider) savePage(title, parentID, body string) (*confluencePage, error) {
+	existing, err := c.findPage(title)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": c.spaceKey},
+		"body": map[string]interface{}{
+			"storage": map[string]string{"value": body, "representation": "storage"},
+		},
+	}
+	var page confluencePage
+	if existing != nil {
+		content["id"] = existing.ID
+		content["version"] = map[string]int{"number": existing.Version.Number + 1}
+		err = requestJSON(c.client, http.MethodPut, c.baseURL+"/rest/api/content/"+existing.ID, c.authorization, content, &page)
+	} else {
+		if parentID != "" {
+			content["ancestors"] = []map[string]string{{"id": parentID}}
+		}
+		err = postJSON(c.client, c.baseURL+"/rest/api/content", c.authorization, content, &page)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// findPage returns the space's page with the title, or nil if there is none
+func (c *ConfluenceOutputProvider) findPage(title string) (*confluencePage, error) {
+	query := url.Values{
+		"spaceKey": {c.spaceKey},
+		"title":    {title},
+		"type":     {"page"},
+		"expand":   {"version"},
+	}
+	var result struct {
+		Results []confluencePage `json:"results"`
+	}
+	if err := requestJSON(c.client, http.MethodGet, c.baseURL+"/rest/api/content?"+query.Encode(), c.authorization, nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+	return &result.Results[0], nil
+}
+
+// pageTitle fills in the title template. Titles are unique within a space, so the default
+// template includes the request ID.
+func (c *ConfluenceOutputProvider) pageTitle(requestID string, videoInfo map[string]interface{}, category, user string) string {
+	values := map[string]string{
+		"title":      infoString(videoInfo, "title"),
+		"channel":    infoString(videoInfo, "channel", "uploader"),
+		"request_id": requestID,
+		"category":   category,
+		"user":       user,
+		"date":       time.Now().Format("2006-01-02"),
+	}
+	if uploadDate := infoString(videoInfo, "upload_date"); len(uploadDate) == 8 {
+		values["upload_date"] = uploadDate[:4] + "-" + uploadDate[4:6] + "-" + uploadDate[6:]
+	}
+	title := namePlaceholder.ReplaceAllStringFunc(c.titleTemplate, func(match string) string {
+		return values[match[1:len(match)-1]]
+	})
+	title = strings.Join(strings.Fields(title), " ")
+	if strings.Trim(title, "()[]-_:| ") == "" {
+		title = requestID
+	}
+	return truncateTitle(title)
+}
+
+func truncateTitle(title string) string {
+	if runes := []rune(title); len(runes) > confluenceMaxTitleRunes {
+		return string(runes[:confluenceMaxTitleRunes-1]) + "…"
+	}
+	return title
+}
+
+// confluenceLabel turns a category into a label: lower case, with no spaces
+func confluenceLabel(category string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(category)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+const (
+	// discordMaxMessageRunes is Discord's message length limit; longer summaries are split
+	discordMaxMessageRunes = 2000
+	// discordMaxThreadNameRunes is Discord's thread name length limit
+	discordMaxThreadNameRunes = 100
+	// discordThreadExists is the error code Discord returns when a message already has a thread
+	discordThreadExists = 160004
+)
+
+// DiscordOutputProvider posts summaries to a Discord channel with a bot token. A destination
+// of "<channel ID>/<message ID>" replies in a thread started from that message, which is how
+// the discord source answers the message a video link was posted in. Transcripts are not
+// posted.
+type DiscordOutputProvider struct {
+	client   *http.Client
+	botToken string
+	channel  string
+	apiURL   string
+}
+
+// NewDiscordOutputProvider creates a Discord output provider posting to the given channel ID
+func NewDiscordOutputProvider(botToken, channel string) *DiscordOutputProvider {
+	return &DiscordOutputProvider{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		botToken: botToken,
+		channel:  channel,
+		apiURL:   "https://discord.com/api/v10",
+	}
+}
+
+// WithDestination returns a copy of the provider that posts to another channel, or to a
+// thread on a message when the destination is "<channel ID>/<message ID>"
+func (d *DiscordOutputProvider) WithDestination(destination string) interfaces.OutputProvider {
+	if destination == "" {
+		return d
+	}
+	copied := *d
+	copied.channel = destination
+	return &copied
+}
+
+// UploadSummary posts the summary, headed by the video title and category, split into as
+// many messages as Discord's length limit needs
+func (d *DiscordOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	if d.channel == "" {
+		return fmt.Errorf("no Discord channel set for request %s", requestID)
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	title, _ := videoInfo["title"].(string)
+	if title == "" {
+		title = requestID
+	}
+
+	channelID, messageID, _ := strings.Cut(d.channel, "/")
+	if messageID != "" {
+		channelID, err = d.thread(channelID, messageID, title)
+		if err != nil {
+			return fmt.Errorf("failed to start Discord thread on message %s: %w", messageID, err)
+		}
+	}
+
+	text := fmt.Sprintf("**%s**\n*Category: %s", title, category)
+	if user != "" {
+		text += fmt.Sprintf(", requested by %s", user)
+	}
+	text += "*\n\n" + string(summary)
+	for _, chunk := range splitMessage(text, discordMaxMessageRunes) {
+		if err := d.post(fmt.Sprintf("/channels/%s/messages", channelID), map[string]interface{}{
+			"content": chunk,
+			// Summaries quote the video; don't let them ping anyone
+			"allowed_mentions": map[string]interface{}{"parse": []string{}},
+		}, nil); err != nil {
+			return fmt.Errorf("failed to post summary to Discord channel %s: %w", channelID, err)
+		}
+	}
+	log.Infof("Posted summary for request %s to Discord channel %s", requestID, channelID)
+	return nil
+}
+
+// UploadTranscript is a no-op; only summaries are posted to Discord
+func (d *DiscordOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	log.Debugf("Skipping transcript upload to Discord for request %s", requestID)
+	return nil
+}
+
+// thread starts a thread on a message and returns its ID. A thread started from a message
+// shares the message's ID, so a retried request reuses the thread it started before.
+func (d *DiscordOutputProvider) thread(channelID, messageID, title string) (string, error) {
+	if runes := []rune(title); len(runes) > discordMaxThreadNameRunes {
+		title = string(runes[:discordMaxThreadNameRunes-1]) + "…"
+	}
+	var thread struct {
+		ID string `json:"id"`
+	}
+	err := d.post(fmt.Sprintf("/channels/%s/messages/%s/threads", channelID, messageID), map[string]interface{}{"name": title}, &thread)
+	if err == nil {
+		return thread.ID, nil
+	}
+	var apiErr *discordAPIError
+	if errors.As(err, &apiErr) && apiErr.Code == discordThreadExists {
+		return messageID, nil
+	}
+	return "", err
+}
+
+// discordAPIError is an error response from the Discord API
+type discordAPIError struct {
+	Status  int
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *discordAPIError) Error() string {
+	return fmt.Sprintf("discord error %d (status %d): %s", e.Code, e.Status, e.Message)
+}
+
+// post sends a JSON request to the Discord API and decodes the response into out
+func (d *DiscordOutputProvider) post(path string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, d.apiURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bot "+d.botToken)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		apiErr := &discordAPIError{Status: resp.StatusCode}
+		if json.Unmarshal(respBody, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(respBody))
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// splitMessage splits text into chunks of at most maxRunes, breaking at the last newline
+// (or failing that, space) in each chunk so paragraphs stay whole where they can
+func splitMessage(text string, maxRunes int) []string {
+	var chunks []string
+	runes := []rune(strings.TrimSpace(text))
+	for len(runes) > maxRunes {
+		cut := maxRunes
+		if i := lastIndexRune(runes[:maxRunes], '\n'); i > maxRunes/2 {
+			cut = i
+		} else if i := lastIndexRune(runes[:maxRunes], ' '); i > maxRunes/2 {
+			cut = i
+		}
+		chunks = append(chunks, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		chunks = append(chunks, string(runes))
+	}
+	return chunks
+}
+
+func lastIndexRune(runes []rune, r rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
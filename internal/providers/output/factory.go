@@ -11,6 +11,22 @@ func NewOutputProviderFromConfig(cfg *config.AppConfig) (interfaces.OutputProvid
 	switch cfg.OutputProvider {
 	case "gdrive":
 		return NewGDriveOutputProvider(cfg)
+	case "slack":
+		slack := NewSlackOutputProvider(cfg.SlackBotToken, cfg.SlackChannel)
+		slack.ShowThumbnail = cfg.UploadThumbnail
+		return slack, nil
+	case "discord":
+		return NewDiscordOutputProvider(cfg.DiscordBotToken, cfg.DiscordChannel), nil
+	case "local":
+		return NewLocalOutputProvider(cfg.LocalOutputDir, NewNaming(cfg.OutputNaming)), nil
+	case "gsheets":
+		return NewGSheetsOutputProvider(cfg)
+	case "readwise":
+		return NewReadwiseOutputProvider(cfg.Readwise.Token, cfg.Readwise.Mode), nil
+	case "raindrop":
+		return NewRaindropOutputProvider(cfg.Raindrop.Token, cfg.Raindrop.CollectionID), nil
+	case "confluence":
+		return NewConfluenceOutputProvider(cfg.Confluence), nil
 	case "":
 		return nil, fmt.Errorf("output_provider not set in config")
 	default:
@@ -3,86 +3,142 @@ package output
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
 type GDriveOutputProvider struct {
 	driveService *drive.Service
 	folderID     string
+	folders      *folderCache
+	naming       *Naming
 }
 
 func NewGDriveOutputProvider(cfg *config.AppConfig) (*GDriveOutputProvider, error) {
 	ctx := context.Background()
-
-	var service *drive.Service
-	var err error
-
-	switch cfg.GDriveAuthMethod {
-	case "oauth":
-		// Use OAuth client + user token
-		creds, err := os.ReadFile(cfg.GDriveCredentialsFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read OAuth credentials file: %w", err)
-		}
-		config, err := google.ConfigFromJSON(creds, drive.DriveFileScope)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse OAuth credentials: %w", err)
-		}
-		tok, err := tokenFromFile(cfg.GDriveTokenFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read OAuth token file: %w", err)
-		}
-		client := config.Client(ctx, tok)
-		service, err = drive.NewService(ctx, option.WithHTTPClient(client))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Google Drive service (oauth): %w", err)
-		}
-	case "service_account":
-		fallthrough
-	default:
-		// Use service account (default)
-		service, err = drive.NewService(ctx, option.WithCredentialsFile(cfg.GDriveCredentialsFile))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Google Drive service (service_account): %w", err)
-		}
+	auth, err := googleAuthOption(ctx, cfg, drive.DriveFileScope)
+	if err != nil {
+		return nil, err
+	}
+	service, err := drive.NewService(ctx, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Drive service (%s): %w", cfg.GDriveAuthMethod, err)
 	}
 
 	return &GDriveOutputProvider{
 		driveService: service,
 		folderID:     cfg.GDriveFolderID,
+		folders:      newFolderCache(),
+		naming:       NewNaming(cfg.OutputNaming),
 	}, nil
 }
 
-func (g *GDriveOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
-	title := ""
-	if t, ok := videoInfo["title"].(string); ok {
-		title = t
+// WithDestination returns a copy of the provider that uploads under another Drive folder
+func (g *GDriveOutputProvider) WithDestination(folderID string) interfaces.OutputProvider {
+	if folderID == "" {
+		return g
 	}
-	return g.uploadFileAndCleanup(requestID, title, summaryPath, "summary.txt", category, user)
+	return &GDriveOutputProvider{driveService: g.driveService, folderID: folderID, folders: g.folders, naming: g.naming}
+}
+
+// CheckDestination looks up the configured Drive folder, checking the credentials work and the
+// folder exists, without creating anything
+func (g *GDriveOutputProvider) CheckDestination(ctx context.Context) error {
+	folderID := g.folderID
+	if folderID == "" {
+		folderID = "root"
+	}
+	folder, err := g.driveService.Files.Get(folderID).Fields("id, mimeType, trashed").Context(ctx).Do()
+	if err != nil {
+		return driveError(fmt.Errorf("failed to look up Drive folder %s: %w", folderID, err))
+	}
+	if folder.Trashed {
+		return fmt.Errorf("folder %s is in the Drive trash", folderID)
+	}
+	if folder.MimeType != "" && folder.MimeType != folderMimeType {
+		return fmt.Errorf("%s is not a Drive folder", folderID)
+	}
+	return nil
+}
+
+func (g *GDriveOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	return driveError(g.uploadFileAndCleanup(requestID, videoInfo, summaryPath, "summary.txt", category, user))
 }
 
 func (g *GDriveOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
-	title := ""
-	if t, ok := videoInfo["title"].(string); ok {
-		title = t
+	return driveError(g.uploadFileAndCleanup(requestID, videoInfo, transcriptPath, "transcript.txt", category, user))
+}
+
+// UploadAttachment uploads an extra file, such as the full video info, next to the summary
+func (g *GDriveOutputProvider) UploadAttachment(requestID string, videoInfo map[string]interface{}, filePath string, suffix string, category string, user string) error {
+	return driveError(g.uploadFileAndCleanup(requestID, videoInfo, filePath, suffix, category, user))
+}
+
+// UploadMetadata uploads the request metadata as a JSON sidecar next to the summary
+func (g *GDriveOutputProvider) UploadMetadata(requestID string, videoInfo map[string]interface{}, metadata map[string]interface{}, category string, user string) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	f, err := os.CreateTemp("", "metadata-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
 	}
-	return g.uploadFileAndCleanup(requestID, title, transcriptPath, "transcript.txt", category, user)
+	return driveError(g.uploadFileAndCleanup(requestID, videoInfo, f.Name(), "metadata.json", category, user))
+}
+
+// driveError attaches the error code a Drive API or OAuth error indicates
+func driveError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		// The refresh token was revoked or expired
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadAuthExpired, err)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadFailed, err)
+	}
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.Code == 401:
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadAuthExpired, err)
+	case apiErr.Code == 429 || (apiErr.Code == 403 && strings.Contains(message, "rate limit")):
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadRateLimited, err)
+	case apiErr.Code == 403 && strings.Contains(message, "quota"):
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadQuotaExceeded, err)
+	case apiErr.Code >= 500:
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadUnavailable, err)
+	}
+	return interfaces.WithErrorCode(interfaces.ErrorCodeUploadFailed, err)
 }
 
 // uploadFileAndCleanup uploads a file to Google Drive and deletes it after upload
-func (g *GDriveOutputProvider) uploadFileAndCleanup(requestID, title, filePath, suffix, category, user string) error {
+func (g *GDriveOutputProvider) uploadFileAndCleanup(requestID string, videoInfo map[string]interface{}, filePath, suffix, category, user string) error {
 	// Normalize user (default to "admin" if empty)
 	if user == "" {
 		user = "admin"
@@ -91,26 +147,15 @@ func (g *GDriveOutputProvider) uploadFileAndCleanup(requestID, title, filePath,
 	if category == "" {
 		category = "general"
 	}
-	// Create user folder if it doesn't exist
-	userFolderID, err := g.getOrCreateUserFolder(user)
+	videoFolderID, err := g.videoFolder(requestID, videoInfo, category, user)
 	if err != nil {
-		return fmt.Errorf("failed to get/create user folder: %w", err)
-	}
-	// Create category folder under user
-	categoryFolderID, err := g.getOrCreateCategoryFolder(category, userFolderID)
-	if err != nil {
-		return fmt.Errorf("failed to get/create category folder: %w", err)
-	}
-	// Create video-specific folder under category
-	videoFolderID, err := g.getOrCreateVideoFolder(requestID, title, categoryFolderID)
-	if err != nil {
-		return fmt.Errorf("failed to get/create video folder: %w", err)
+		return err
 	}
-	filename := buildOutputFilename(title, requestID, suffix)
+	filename := g.naming.FileName(requestID, videoInfo, category, user, suffix)
 	file := &drive.File{
 		Name:     filename,
 		Parents:  []string{videoFolderID}, // Upload to video-specific folder
-		MimeType: "text/plain",
+		MimeType: uploadMimeType(suffix),
 	}
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -127,100 +172,168 @@ func (g *GDriveOutputProvider) uploadFileAndCleanup(requestID, title, filePath,
 		log.Infof("Uploaded %s for request %s in %.2fs", filename, requestID, elapsed.Seconds())
 	}
 	if err != nil {
+		g.forgetDeletedFolders(err)
 		return fmt.Errorf("failed to upload %s to Google Drive: %w", filename, err)
 	}
 	return nil
 }
 
-// getOrCreateUserFolder creates a user folder if it doesn't exist, returns existing if it does
-func (g *GDriveOutputProvider) getOrCreateUserFolder(user string) (string, error) {
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and '%s' in parents and trashed=false", user, g.folderID)
-	files, err := g.driveService.Files.List().Q(query).Do()
-	if err != nil {
-		return "", fmt.Errorf("failed to search for user folder: %w", err)
+// VerifyOutput finds the newest file uploaded for the request under suffix's name and checks
+// its size and MD5 against localPath
+func (g *GDriveOutputProvider) VerifyOutput(requestID string, videoInfo map[string]interface{}, localPath string, suffix string, category string, user string) (*interfaces.OutputFile, error) {
+	if user == "" {
+		user = "admin"
 	}
-	if len(files.Files) > 0 {
-		log.Infof("Found existing user folder: %s (ID: %s)", user, files.Files[0].Id)
-		return files.Files[0].Id, nil
+	if category == "" {
+		category = "general"
 	}
-	folder := &drive.File{
-		Name:     user,
-		MimeType: "application/vnd.google-apps.folder",
-		Parents:  []string{g.folderID},
+	videoFolderID, err := g.videoFolder(requestID, videoInfo, category, user)
+	if err != nil {
+		return nil, driveError(err)
 	}
-	createdFolder, err := g.driveService.Files.Create(folder).Do()
+	filename := g.naming.FileName(requestID, videoInfo, category, user, suffix)
+	query := fmt.Sprintf("name='%s' and '%s' in parents and trashed=false", escapeQueryValue(filename), escapeQueryValue(videoFolderID))
+	files, err := g.driveService.Files.List().Q(query).OrderBy("createdTime desc").PageSize(1).
+		Fields("files(id, name, size, md5Checksum, webViewLink)").Do()
 	if err != nil {
-		return "", fmt.Errorf("failed to create user folder: %w", err)
+		return nil, driveError(fmt.Errorf("failed to look up uploaded %s: %w", filename, err))
 	}
-	log.Infof("Created new user folder: %s (ID: %s)", user, createdFolder.Id)
-	return createdFolder.Id, nil
+	if len(files.Files) == 0 {
+		return nil, fmt.Errorf("uploaded %s not found in Google Drive", filename)
+	}
+	file := files.Files[0]
+	uploaded := &interfaces.OutputFile{Name: file.Name, ID: file.Id, URL: file.WebViewLink, Size: file.Size}
+	if err := checkUpload(uploaded, file.Md5Checksum, localPath); err != nil {
+		return nil, err
+	}
+	return uploaded, nil
 }
 
-// getOrCreateCategoryFolder creates a category folder under the user folder
-func (g *GDriveOutputProvider) getOrCreateCategoryFolder(category string, userFolderID string) (string, error) {
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and '%s' in parents and trashed=false", category, userFolderID)
-	files, err := g.driveService.Files.List().Q(query).Do()
+// videoFolder returns the ID of the request's folder, <user>/<category>/<folder>, creating
+// the folders that don't exist yet
+func (g *GDriveOutputProvider) videoFolder(requestID string, videoInfo map[string]interface{}, category, user string) (string, error) {
+	// Create user folder if it doesn't exist
+	userFolderID, err := g.getOrCreateUserFolder(user)
 	if err != nil {
-		return "", fmt.Errorf("failed to search for category folder: %w", err)
+		return "", fmt.Errorf("failed to get/create user folder: %w", err)
 	}
-	if len(files.Files) > 0 {
-		log.Infof("Found existing category folder: %s (ID: %s)", category, files.Files[0].Id)
-		return files.Files[0].Id, nil
-	}
-	folder := &drive.File{
-		Name:     category,
-		MimeType: "application/vnd.google-apps.folder",
-		Parents:  []string{userFolderID},
+	// Create category folder under user
+	categoryFolderID, err := g.getOrCreateCategoryFolder(category, userFolderID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get/create category folder: %w", err)
 	}
-	createdFolder, err := g.driveService.Files.Create(folder).Do()
+	// Create video-specific folder under category. It is used by a single request, so it is not cached.
+	videoFolderID, err := g.getOrCreateFolder(g.naming.FolderName(requestID, videoInfo, category, user), categoryFolderID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create category folder: %w", err)
+		g.forgetDeletedFolders(err)
+		return "", fmt.Errorf("failed to get/create video folder: %w", err)
 	}
-	log.Infof("Created new category folder: %s (ID: %s)", category, createdFolder.Id)
-	return createdFolder.Id, nil
+	return videoFolderID, nil
 }
 
-// getOrCreateVideoFolder creates a video-specific folder under the category folder
-func (g *GDriveOutputProvider) getOrCreateVideoFolder(requestID, title, categoryFolderID string) (string, error) {
-	// Create folder name from title and request ID
-	folderName := buildVideoFolderName(title, requestID)
+// folderMimeType is the MIME type Drive uses for folders
+const folderMimeType = "application/vnd.google-apps.folder"
 
-	// First, try to find existing video folder
-	query := fmt.Sprintf("name='%s' and mimeType='application/vnd.google-apps.folder' and '%s' in parents and trashed=false", folderName, categoryFolderID)
-	files, err := g.driveService.Files.List().Q(query).Do()
-	if err != nil {
-		return "", fmt.Errorf("failed to search for video folder: %w", err)
+// folderCache remembers the IDs of user and category folders, keyed by parent folder ID and
+// name, so each upload doesn't look them up again. It is shared by destination copies.
+type folderCache struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func newFolderCache() *folderCache {
+	return &folderCache{ids: make(map[string]string)}
+}
+
+// reset forgets every cached folder, e.g. after one was deleted in Drive
+func (c *folderCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = make(map[string]string)
+}
+
+// forgetDeletedFolders clears the folder cache when Drive reports a parent folder missing,
+// since a cached folder was probably deleted
+func (g *GDriveOutputProvider) forgetDeletedFolders(err error) {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == 404 {
+		g.folders.reset()
 	}
+}
+
+// getOrCreateUserFolder creates a user folder if it doesn't exist, returns existing if it does
+func (g *GDriveOutputProvider) getOrCreateUserFolder(user string) (string, error) {
+	return g.getOrCreateCachedFolder(user, g.folderID)
+}
+
+// getOrCreateCategoryFolder creates a category folder under the user folder
+func (g *GDriveOutputProvider) getOrCreateCategoryFolder(category string, userFolderID string) (string, error) {
+	return g.getOrCreateCachedFolder(category, userFolderID)
+}
 
-	// If folder exists, return its ID
-	if len(files.Files) > 0 {
-		log.Infof("Found existing video folder: %s (ID: %s)", folderName, files.Files[0].Id)
-		return files.Files[0].Id, nil
+// getOrCreateCachedFolder is getOrCreateFolder with the folder ID cached. The cache lock is
+// held throughout, so concurrent uploads don't create the same folder twice.
+func (g *GDriveOutputProvider) getOrCreateCachedFolder(name, parentID string) (string, error) {
+	g.folders.mu.Lock()
+	defer g.folders.mu.Unlock()
+	key := parentID + "/" + name
+	if id, ok := g.folders.ids[key]; ok {
+		return id, nil
+	}
+	id, err := g.getOrCreateFolder(name, parentID)
+	if err != nil {
+		return "", err
 	}
+	g.folders.ids[key] = id
+	return id, nil
+}
 
-	// Create new video folder
+// getOrCreateFolder returns the ID of the named folder under parentID, creating it if needed
+func (g *GDriveOutputProvider) getOrCreateFolder(name, parentID string) (string, error) {
+	id, err := g.findFolder(name, parentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for folder %q: %w", name, err)
+	}
+	if id != "" {
+		log.Infof("Found existing folder: %s (ID: %s)", name, id)
+		return id, nil
+	}
 	folder := &drive.File{
-		Name:     folderName,
-		MimeType: "application/vnd.google-apps.folder",
-		Parents:  []string{categoryFolderID},
+		Name:     name,
+		MimeType: folderMimeType,
+		Parents:  []string{parentID},
 	}
-
-	createdFolder, err := g.driveService.Files.Create(folder).Do()
+	createdFolder, err := g.driveService.Files.Create(folder).Fields("id").Do()
 	if err != nil {
-		return "", fmt.Errorf("failed to create video folder: %w", err)
+		return "", fmt.Errorf("failed to create folder %q: %w", name, err)
 	}
-
-	log.Infof("Created new video folder: %s (ID: %s)", folderName, createdFolder.Id)
+	log.Infof("Created new folder: %s (ID: %s)", name, createdFolder.Id)
 	return createdFolder.Id, nil
 }
 
-// buildVideoFolderName creates a sanitized folder name for the video
-func buildVideoFolderName(title, requestID string) string {
-	if title != "" {
-		title = sanitizeFilename(title)
-		return fmt.Sprintf("%s_%s", title, requestID)
+// findFolder returns the ID of the named folder under parentID, or "" if there is none.
+// Drive may return empty pages with a next page token, so every page is checked.
+func (g *GDriveOutputProvider) findFolder(name, parentID string) (string, error) {
+	query := fmt.Sprintf("name='%s' and mimeType='%s' and '%s' in parents and trashed=false",
+		escapeQueryValue(name), folderMimeType, escapeQueryValue(parentID))
+	pageToken := ""
+	for {
+		call := g.driveService.Files.List().Q(query).Fields("nextPageToken, files(id)")
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		files, err := call.Do()
+		if err != nil {
+			return "", err
+		}
+		if len(files.Files) > 0 {
+			return files.Files[0].Id, nil
+		}
+		if files.NextPageToken == "" {
+			return "", nil
+		}
+		pageToken = files.NextPageToken
 	}
-	return fmt.Sprintf("video_%s", requestID)
 }
 
 // getTitleForRequest is a placeholder; in real use, fetch from state store or pass as arg
@@ -229,21 +342,25 @@ func getTitleForRequest(requestID string) string {
 	return ""
 }
 
-// buildOutputFilename builds a sanitized filename
-func buildOutputFilename(title, requestID, suffix string) string {
-	if title != "" {
-		title = sanitizeFilename(title)
-		return fmt.Sprintf("%s_%s_%s", title, requestID, suffix)
+// googleAuthOption authenticates Google API clients with the gdrive_* settings: the OAuth
+// client and user token, or the service account key (the default)
+func googleAuthOption(ctx context.Context, cfg *config.AppConfig, scope string) (option.ClientOption, error) {
+	if cfg.GDriveAuthMethod != "oauth" {
+		return option.WithCredentialsFile(cfg.GDriveCredentialsFile), nil
 	}
-	return fmt.Sprintf("%s_%s", requestID, suffix)
-}
-
-// sanitizeFilename removes/escapes problematic characters
-func sanitizeFilename(name string) string {
-	// Remove non-alphanumeric, replace spaces with _
-	name = strings.ReplaceAll(name, " ", "_")
-	reg := regexp.MustCompile(`[^a-zA-Z0-9_\-]`)
-	return reg.ReplaceAllString(name, "")
+	creds, err := os.ReadFile(cfg.GDriveCredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth credentials file: %w", err)
+	}
+	config, err := google.ConfigFromJSON(creds, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OAuth credentials: %w", err)
+	}
+	tok, err := tokenFromFile(cfg.GDriveTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth token file: %w", err)
+	}
+	return option.WithHTTPClient(config.Client(ctx, tok)), nil
 }
 
 // tokenFromFile loads an OAuth2 token from a file
@@ -259,3 +376,17 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 	}
 	return &token, nil
 }
+
+// escapeQueryValue escapes a string for use inside a quoted Drive query value, since user
+// names and categories come from submissions
+func escapeQueryValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
+
+// uploadMimeType returns the MIME type of an uploaded file from its suffix, defaulting to text
+func uploadMimeType(suffix string) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(suffix)); mimeType != "" {
+		return mimeType
+	}
+	return "text/plain"
+}
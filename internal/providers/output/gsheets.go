@@ -0,0 +1,91 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/sheets/v4"
+
+	"video-summarizer-go/internal/config"
+)
+
+// GSheetsOutputProvider appends a row per summary to a Google Sheet, as a reviewable index of
+// everything processed. Transcripts are not written.
+type GSheetsOutputProvider struct {
+	service       *sheets.Service
+	spreadsheetID string
+	sheet         string
+	excerptChars  int
+}
+
+// NewGSheetsOutputProvider creates a Sheets output provider, authenticating with the gdrive_*
+// settings. OAuth tokens need the spreadsheets scope, which gdrive-auth requests.
+func NewGSheetsOutputProvider(cfg *config.AppConfig) (*GSheetsOutputProvider, error) {
+	ctx := context.Background()
+	auth, err := googleAuthOption(ctx, cfg, sheets.SpreadsheetsScope)
+	if err != nil {
+		return nil, err
+	}
+	service, err := sheets.NewService(ctx, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Sheets service (%s): %w", cfg.GDriveAuthMethod, err)
+	}
+	return &GSheetsOutputProvider{
+		service:       service,
+		spreadsheetID: cfg.GSheets.SpreadsheetID,
+		sheet:         cfg.GSheets.Sheet,
+		excerptChars:  cfg.GSheets.ExcerptChars,
+	}, nil
+}
+
+// UploadSummary appends the request's row: date, title, channel, category, the start of the
+// summary, the video's link, request ID and user
+func (g *GSheetsOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	title, _ := videoInfo["title"].(string)
+	channel, _ := videoInfo["channel"].(string)
+	link, _ := videoInfo["webpage_url"].(string)
+	if link == "" {
+		link, _ = videoInfo["url"].(string)
+	}
+	row := []interface{}{
+		time.Now().Format("2006-01-02"), title, channel, category,
+		excerpt(string(summary), g.excerptChars), link, requestID, user,
+	}
+
+	// RAW keeps titles and summaries starting with "=" from being read as formulas
+	_, err = g.service.Spreadsheets.Values.Append(g.spreadsheetID, g.sheetRange(), &sheets.ValueRange{Values: [][]interface{}{row}}).
+		ValueInputOption("RAW").InsertDataOption("INSERT_ROWS").Do()
+	if err != nil {
+		return driveError(fmt.Errorf("failed to append row to sheet %s: %w", g.sheet, err))
+	}
+	log.Infof("Appended summary of request %s to sheet %s", requestID, g.sheet)
+	return nil
+}
+
+// UploadTranscript is a no-op; rows only hold the start of the summary
+func (g *GSheetsOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	log.Debugf("Skipping transcript upload to Google Sheets for request %s", requestID)
+	return nil
+}
+
+// sheetRange is the A1 range rows are appended after, quoted since sheet names may hold spaces
+func (g *GSheetsOutputProvider) sheetRange() string {
+	return fmt.Sprintf("'%s'!A:H", strings.ReplaceAll(g.sheet, "'", "''"))
+}
+
+// excerpt returns the start of text, on one line and cut at maxChars runes; 0 keeps it all
+func excerpt(text string, maxChars int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); maxChars > 0 && len(runes) > maxChars {
+		return strings.TrimSpace(string(runes[:maxChars])) + "…"
+	}
+	return text
+}
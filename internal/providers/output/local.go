@@ -0,0 +1,206 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// LocalOutputProvider writes outputs to a local directory laid out like the Drive folders:
+// <dir>/<user>/<category>/<folder>/<file>, with folder and file names set by naming
+type LocalOutputProvider struct {
+	dir    string
+	naming *Naming
+}
+
+// NewLocalOutputProvider writes outputs under dir, creating it as needed
+func NewLocalOutputProvider(dir string, naming *Naming) *LocalOutputProvider {
+	return &LocalOutputProvider{dir: dir, naming: naming}
+}
+
+// CheckDestination checks the output directory can be written to, creating it if needed
+func (l *LocalOutputProvider) CheckDestination(ctx context.Context) error {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", l.dir, err)
+	}
+	f, err := os.CreateTemp(l.dir, ".preflight-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %w", l.dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// UploadSummary copies the summary into the output directory
+func (l *LocalOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	return l.copyFile(requestID, videoInfo, summaryPath, "summary.txt", category, user)
+}
+
+// UploadTranscript copies the transcript into the output directory
+func (l *LocalOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	return l.copyFile(requestID, videoInfo, transcriptPath, "transcript.txt", category, user)
+}
+
+// UploadAttachment copies an extra file, such as the full video info, into the output directory
+func (l *LocalOutputProvider) UploadAttachment(requestID string, videoInfo map[string]interface{}, filePath string, suffix string, category string, user string) error {
+	return l.copyFile(requestID, videoInfo, filePath, suffix, category, user)
+}
+
+// UploadMetadata writes the metadata sidecar next to the summary
+func (l *LocalOutputProvider) UploadMetadata(requestID string, videoInfo map[string]interface{}, metadata map[string]interface{}, category string, user string) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	folder, err := l.requestFolder(requestID, videoInfo, category, user)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(folder, l.naming.FileName(requestID, videoInfo, category, user, "metadata.json"))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	log.Infof("Wrote %s for request %s", path, requestID)
+	return nil
+}
+
+// copyFile copies a generated file into the request's output folder
+func (l *LocalOutputProvider) copyFile(requestID string, videoInfo map[string]interface{}, filePath, suffix, category, user string) error {
+	folder, err := l.requestFolder(requestID, videoInfo, category, user)
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	path := filepath.Join(folder, l.naming.FileName(requestID, videoInfo, category, user, suffix))
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	log.Infof("Wrote %s for request %s", path, requestID)
+	return nil
+}
+
+// VerifyOutput checks the copy of localPath in the output directory has the same size and
+// contents
+func (l *LocalOutputProvider) VerifyOutput(requestID string, videoInfo map[string]interface{}, localPath string, suffix string, category string, user string) (*interfaces.OutputFile, error) {
+	name := l.naming.FileName(requestID, videoInfo, category, user, suffix)
+	path := filepath.Join(l.folderPath(requestID, videoInfo, category, user), name)
+	size, md5sum, err := fileDigest(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read written %s: %w", name, err)
+	}
+	uploaded := &interfaces.OutputFile{Name: name, URL: path, Size: size}
+	if err := checkUpload(uploaded, md5sum, localPath); err != nil {
+		return nil, err
+	}
+	return uploaded, nil
+}
+
+// DeleteOutputs removes the request's output folder when it holds only that request's files,
+// and otherwise the files in it named after the request
+func (l *LocalOutputProvider) DeleteOutputs(requestID string, videoInfo map[string]interface{}, category string, user string) error {
+	folder := l.folderPath(requestID, videoInfo, category, user)
+	if strings.Contains(filepath.Base(folder), requestID) {
+		if err := os.RemoveAll(folder); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", folder, err)
+		}
+		log.Infof("Removed %s for request %s", folder, requestID)
+		return nil
+	}
+	entries, err := os.ReadDir(folder)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", folder, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), requestID) {
+			continue
+		}
+		path := filepath.Join(folder, entry.Name())
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		log.Infof("Removed %s for request %s", path, requestID)
+	}
+	return nil
+}
+
+// PruneOutputs removes output files ending with suffix that were last written before
+// olderThan, and any request folders left empty
+func (l *LocalOutputProvider) PruneOutputs(suffix string, olderThan time.Time) (int, error) {
+	removed := 0
+	folders := make(map[string]bool)
+	err := filepath.WalkDir(l.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), suffix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(olderThan) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed++
+		if folder := filepath.Dir(path); folder != filepath.Clean(l.dir) {
+			folders[folder] = true
+		}
+		return nil
+	})
+	for folder := range folders {
+		// Fails, as intended, while the folder still holds other files
+		os.Remove(folder)
+	}
+	return removed, err
+}
+
+// folderPath returns the folder for one request's outputs
+func (l *LocalOutputProvider) folderPath(requestID string, videoInfo map[string]interface{}, category, user string) string {
+	if user == "" {
+		user = "admin"
+	}
+	if category == "" {
+		category = "general"
+	}
+	return filepath.Join(l.dir, sanitizeFilename(user), sanitizeFilename(category), l.naming.FolderName(requestID, videoInfo, category, user))
+}
+
+// requestFolder creates and returns the folder for one request's outputs
+func (l *LocalOutputProvider) requestFolder(requestID string, videoInfo map[string]interface{}, category, user string) (string, error) {
+	folder := l.folderPath(requestID, videoInfo, category, user)
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output folder: %w", err)
+	}
+	return folder, nil
+}
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"video-summarizer-go/internal/config"
+)
+
+// namePlaceholder matches a {placeholder} in a naming template
+var namePlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// repeatedSeparators matches runs of separators left by sanitizing or empty placeholders
+var repeatedSeparators = regexp.MustCompile(`[_\-]*_[_\-]*`)
+
+// Naming builds output folder and file names from the output_naming templates
+type Naming struct {
+	folderTemplate string
+	fileTemplate   string
+	maxBytes       int
+}
+
+// NewNaming creates a naming scheme from config. Empty fields fall back to the defaults,
+// which produce <title>_<request> folders and <title>_<request>_<suffix> files.
+func NewNaming(cfg config.OutputNamingConfig) *Naming {
+	n := &Naming{
+		folderTemplate: cfg.FolderTemplate,
+		fileTemplate:   cfg.FileTemplate,
+		maxBytes:       cfg.MaxLength,
+	}
+	if n.folderTemplate == "" {
+		n.folderTemplate = config.DefaultOutputFolderTemplate
+	}
+	if n.fileTemplate == "" {
+		n.fileTemplate = config.DefaultOutputFileTemplate
+	}
+	if n.maxBytes <= 0 {
+		n.maxBytes = config.DefaultOutputNameMaxLength
+	}
+	return n
+}
+
+// nameFields are the values a template can refer to
+type nameFields struct {
+	requestID string
+	videoInfo map[string]interface{}
+	category  string
+	user      string
+}
+
+// FolderName returns the name of the folder holding a request's outputs
+func (n *Naming) FolderName(requestID string, videoInfo map[string]interface{}, category, user string) string {
+	return n.render(n.folderTemplate, nameFields{requestID, videoInfo, category, user}, "")
+}
+
+// FileName returns the name of one of a request's output files, e.g. suffix "summary.txt"
+func (n *Naming) FileName(requestID string, videoInfo map[string]interface{}, category, user, suffix string) string {
+	return n.render(n.fileTemplate, nameFields{requestID, videoInfo, category, user}, suffix)
+}
+
+// render fills in the template. When the result is longer than the limit, the title and
+// then the channel are shortened, so the request ID and suffix always survive.
+func (n *Naming) render(template string, fields nameFields, suffix string) string {
+	values := map[string]string{
+		"title":      sanitizeFilename(infoString(fields.videoInfo, "title")),
+		"channel":    sanitizeFilename(infoString(fields.videoInfo, "channel", "uploader")),
+		"request_id": fields.requestID,
+		"category":   sanitizeFilename(fields.category),
+		"user":       sanitizeFilename(fields.user),
+		"date":       time.Now().Format("2006-01-02"),
+		"suffix":     suffix,
+	}
+	if uploadDate := infoString(fields.videoInfo, "upload_date"); len(uploadDate) == 8 {
+		// yt-dlp reports YYYYMMDD
+		values["upload_date"] = uploadDate[:4] + "-" + uploadDate[4:6] + "-" + uploadDate[6:]
+	} else {
+		values["upload_date"] = ""
+	}
+
+	name := fillTemplate(template, values)
+	for _, key := range []string{"title", "channel"} {
+		excess := len(name) - n.maxBytes
+		if excess <= 0 {
+			break
+		}
+		values[key] = truncateBytes(values[key], len(values[key])-excess)
+		name = fillTemplate(template, values)
+	}
+	return name
+}
+
+// fillTemplate substitutes the placeholders and tidies the separators empty values leave
+func fillTemplate(template string, values map[string]string) string {
+	name := namePlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+		return values[match[1:len(match)-1]]
+	})
+	name = repeatedSeparators.ReplaceAllString(name, "_")
+	return strings.Trim(name, "_-")
+}
+
+// infoString returns the first non-empty string among the given video info keys
+func infoString(info map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := info[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// sanitizeFilename keeps letters and digits of any script, dashes and underscores, turns
+// whitespace into underscores and drops everything else. Runs of separators are collapsed
+// and trimmed, so a value's length doesn't change once placed in a template.
+func sanitizeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(repeatedSeparators.ReplaceAllString(b.String(), "_"), "_-")
+}
+
+// truncateBytes cuts s to at most n bytes without splitting a character
+func truncateBytes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimRight(s[:n], "_-")
+}
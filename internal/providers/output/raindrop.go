@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+const (
+	// raindropMaxNoteChars is Raindrop's limit on a bookmark's note
+	raindropMaxNoteChars = 10000
+	// raindropUnsorted is the ID of the Unsorted collection every account has
+	raindropUnsorted = -1
+)
+
+// RaindropOutputProvider bookmarks each summarized video in Raindrop.io, with the summary as
+// the bookmark's note and the category as its tag. Transcripts are not sent, and uploaded
+// documents, which have no link to bookmark, can't be sent.
+type RaindropOutputProvider struct {
+	client       *http.Client
+	token        string
+	collectionID int
+	apiURL       string
+}
+
+// NewRaindropOutputProvider creates a Raindrop output provider with a test token from
+// app.raindrop.io/settings/integrations, saving into a collection (0 or -1 is Unsorted)
+func NewRaindropOutputProvider(token string, collectionID int) *RaindropOutputProvider {
+	if collectionID == 0 {
+		collectionID = raindropUnsorted
+	}
+	return &RaindropOutputProvider{
+		client:       &http.Client{Timeout: 30 * time.Second},
+		token:        token,
+		collectionID: collectionID,
+		apiURL:       "https://api.raindrop.io/rest/v1",
+	}
+}
+
+// WithDestination returns a copy of the provider that saves into another collection ID
+func (r *RaindropOutputProvider) WithDestination(collectionID string) interfaces.OutputProvider {
+	id, err := strconv.Atoi(collectionID)
+	if err != nil {
+		log.Warnf("Ignoring Raindrop collection %q: not a collection ID", collectionID)
+		return r
+	}
+	copied := *r
+	copied.collectionID = id
+	return &copied
+}
+
+// UploadSummary bookmarks the video with the summary as its note
+func (r *RaindropOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	link := videoLink(videoInfo)
+	if link == "" {
+		return fmt.Errorf("raindrop needs the source's link, and request %s has none", requestID)
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	title, _ := videoInfo["title"].(string)
+	if title == "" {
+		title = requestID
+	}
+	note := string(summary)
+	if runes := []rune(note); len(runes) > raindropMaxNoteChars {
+		note = string(runes[:raindropMaxNoteChars-1]) + "…"
+	}
+
+	bookmark := map[string]interface{}{
+		"link":       link,
+		"title":      title,
+		"excerpt":    excerpt(string(summary), 500),
+		"note":       note,
+		"tags":       []string{category},
+		"collection": map[string]int{"$id": r.collectionID},
+	}
+	var resp struct {
+		Result bool `json:"result"`
+	}
+	if err := postJSON(r.client, r.apiURL+"/raindrop", "Bearer "+r.token, bookmark, &resp); err != nil {
+		return apiError(fmt.Errorf("failed to bookmark request %s in Raindrop: %w", requestID, err))
+	}
+	if !resp.Result {
+		return interfaces.WithErrorCode(interfaces.ErrorCodeUploadFailed, fmt.Errorf("raindrop refused the bookmark of request %s", requestID))
+	}
+	log.Infof("Bookmarked request %s in Raindrop collection %d", requestID, r.collectionID)
+	return nil
+}
+
+// UploadTranscript is a no-op; only summaries are sent to Raindrop
+func (r *RaindropOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	log.Debugf("Skipping transcript upload to Raindrop for request %s", requestID)
+	return nil
+}
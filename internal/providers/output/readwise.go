@@ -0,0 +1,166 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ReadwiseModeHighlights adds each passage of a summary as a highlight of one Readwise source
+	ReadwiseModeHighlights = "highlights"
+	// ReadwiseModeReader saves each summary as a document in Readwise Reader
+	ReadwiseModeReader = "reader"
+
+	// readwiseMaxHighlightChars is Readwise's limit on the text of a highlight
+	readwiseMaxHighlightChars = 8191
+)
+
+// listItemPattern matches the marker of a bulleted or numbered list item
+var listItemPattern = regexp.MustCompile(`^([-*+•]|\d+[.)])\s+`)
+
+// ReadwiseOutputProvider sends summaries to Readwise, either as highlights, one per passage
+// of the summary under a source named after the video, or as Reader documents. Readwise
+// merges highlights it already has, so a retried upload doesn't repeat them. Transcripts
+// are not sent.
+type ReadwiseOutputProvider struct {
+	client *http.Client
+	token  string
+	mode   string
+	apiURL string
+}
+
+// NewReadwiseOutputProvider creates a Readwise output provider with an access token from
+// readwise.io/access_token
+func NewReadwiseOutputProvider(token, mode string) *ReadwiseOutputProvider {
+	if mode == "" {
+		mode = ReadwiseModeHighlights
+	}
+	return &ReadwiseOutputProvider{
+		client: &http.Client{Timeout: 30 * time.Second},
+		token:  token,
+		mode:   mode,
+		apiURL: "https://readwise.io/api",
+	}
+}
+
+// UploadSummary sends the summary as highlights or a Reader document
+func (r *ReadwiseOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	title, _ := videoInfo["title"].(string)
+	if title == "" {
+		title = requestID
+	}
+	author, _ := videoInfo["channel"].(string)
+	link := videoLink(videoInfo)
+
+	if r.mode == ReadwiseModeReader {
+		if link == "" {
+			return fmt.Errorf("readwise reader needs the source's link, and request %s has none", requestID)
+		}
+		document := map[string]interface{}{
+			"url":               link,
+			"html":              summaryHTML(string(summary)),
+			"title":             title,
+			"summary":           excerpt(string(summary), 500),
+			"category":          "article",
+			"tags":              []string{category},
+			"saved_using":       "video-summarizer",
+			"should_clean_html": false,
+		}
+		if author != "" {
+			document["author"] = author
+		}
+		if err := postJSON(r.client, r.apiURL+"/v3/save/", "Token "+r.token, document, nil); err != nil {
+			return apiError(fmt.Errorf("failed to save summary to Readwise Reader: %w", err))
+		}
+		log.Infof("Saved summary for request %s to Readwise Reader", requestID)
+		return nil
+	}
+
+	passages := summaryPassages(string(summary), readwiseMaxHighlightChars)
+	highlights := make([]map[string]interface{}, len(passages))
+	for i, passage := range passages {
+		highlight := map[string]interface{}{
+			"text":          passage,
+			"title":         title,
+			"category":      "articles",
+			"location":      i + 1,
+			"location_type": "order",
+			// A note of ".<word>" tags the highlight
+			"note": "." + category,
+		}
+		if author != "" {
+			highlight["author"] = author
+		}
+		if link != "" {
+			highlight["source_url"] = link
+		}
+		highlights[i] = highlight
+	}
+	if err := postJSON(r.client, r.apiURL+"/v2/highlights/", "Token "+r.token, map[string]interface{}{"highlights": highlights}, nil); err != nil {
+		return apiError(fmt.Errorf("failed to add summary highlights to Readwise: %w", err))
+	}
+	log.Infof("Added %d highlights for request %s to Readwise", len(highlights), requestID)
+	return nil
+}
+
+// UploadTranscript is a no-op; only summaries are sent to Readwise
+func (r *ReadwiseOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	log.Debugf("Skipping transcript upload to Readwise for request %s", requestID)
+	return nil
+}
+
+// summaryPassages splits a summary into its paragraphs and list items, with list markers
+// removed, headings dropped and each cut at maxChars runes
+func summaryPassages(summary string, maxChars int) []string {
+	var passages []string
+	var current []string
+	flush := func() {
+		passage := strings.Join(current, " ")
+		current = nil
+		if passage == "" {
+			return
+		}
+		if runes := []rune(passage); len(runes) > maxChars {
+			passage = string(runes[:maxChars-1]) + "…"
+		}
+		passages = append(passages, passage)
+	}
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			flush()
+		case listItemPattern.MatchString(line):
+			flush()
+			current = append(current, listItemPattern.ReplaceAllString(line, ""))
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+	return passages
+}
+
+// summaryHTML renders a plain text or markdown summary as escaped HTML paragraphs
+func summaryHTML(summary string) string {
+	var b strings.Builder
+	for _, passage := range summaryPassages(summary, 1<<20) {
+		b.WriteString("<p>")
+		b.WriteString(html.EscapeString(passage))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// slackMaxMessageRunes keeps a summary under Slack's 40,000 character message limit
+const slackMaxMessageRunes = 39000
+
+// SlackOutputProvider posts summaries to a Slack channel with the chat.postMessage API.
+// Transcripts are not posted; they are too long to be useful as messages.
+type SlackOutputProvider struct {
+	client   *http.Client
+	botToken string
+	channel  string
+	apiURL   string
+	// Show the video thumbnail under the summary, linked from its original URL
+	ShowThumbnail bool
+}
+
+// NewSlackOutputProvider creates a Slack output provider posting to the given channel
+func NewSlackOutputProvider(botToken, channel string) *SlackOutputProvider {
+	return &SlackOutputProvider{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		botToken: botToken,
+		channel:  channel,
+		apiURL:   "https://slack.com/api/chat.postMessage",
+	}
+}
+
+// WithDestination returns a copy of the provider that posts to another channel
+func (s *SlackOutputProvider) WithDestination(channel string) interfaces.OutputProvider {
+	if channel == "" {
+		return s
+	}
+	copied := *s
+	copied.channel = channel
+	return &copied
+}
+
+// UploadSummary posts the summary, headed by the video title and category
+func (s *SlackOutputProvider) UploadSummary(requestID string, videoInfo map[string]interface{}, summaryPath string, category string, user string) error {
+	if s.channel == "" {
+		return fmt.Errorf("no Slack channel set for request %s", requestID)
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	title, _ := videoInfo["title"].(string)
+	if title == "" {
+		title = requestID
+	}
+
+	text := fmt.Sprintf("*%s*\n_Category: %s", title, category)
+	if user != "" {
+		text += fmt.Sprintf(", requested by %s", user)
+	}
+	text += "_\n\n" + string(summary)
+	if runes := []rune(text); len(runes) > slackMaxMessageRunes {
+		text = string(runes[:slackMaxMessageRunes]) + "\n…(truncated)"
+	}
+
+	imageURL := ""
+	if s.ShowThumbnail {
+		imageURL, _ = videoInfo["thumbnail"].(string)
+	}
+	if err := s.post(text, title, imageURL); err != nil {
+		return fmt.Errorf("failed to post summary to Slack channel %s: %w", s.channel, err)
+	}
+	log.Infof("Posted summary for request %s to Slack channel %s", requestID, s.channel)
+	return nil
+}
+
+// UploadTranscript is a no-op; only summaries are posted to Slack
+func (s *SlackOutputProvider) UploadTranscript(requestID string, videoInfo map[string]interface{}, transcriptPath string, category string, user string) error {
+	log.Debugf("Skipping transcript upload to Slack for request %s", requestID)
+	return nil
+}
+
+// post sends a message, with an image attachment when imageURL is set
+func (s *SlackOutputProvider) post(text, title, imageURL string) error {
+	message := map[string]interface{}{"channel": s.channel, "text": text}
+	if imageURL != "" {
+		message["attachments"] = []map[string]string{{"fallback": title, "image_url": imageURL}}
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.botToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	// Slack reports errors in the body with a 200 status
+	var slackResp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &slackResp); err != nil {
+		return fmt.Errorf("invalid Slack response: %w", err)
+	}
+	if !slackResp.OK {
+		return fmt.Errorf("slack error: %s", slackResp.Error)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// fileDigest returns a file's size and the hex MD5 of its contents, the checksum Drive
+// reports for uploaded files
+func fileDigest(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkUpload compares the size and MD5 of an uploaded file with the local file it was
+// uploaded from
+func checkUpload(uploaded *interfaces.OutputFile, md5sum, localPath string) error {
+	size, localMD5, err := fileDigest(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s to verify its upload: %w", localPath, err)
+	}
+	if uploaded.Size != size {
+		return fmt.Errorf("uploaded %s is %d bytes, expected %d", uploaded.Name, uploaded.Size, size)
+	}
+	if !strings.EqualFold(md5sum, localMD5) {
+		return fmt.Errorf("uploaded %s has MD5 %s, expected %s", uploaded.Name, md5sum, localMD5)
+	}
+	return nil
+}
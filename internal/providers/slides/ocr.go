@@ -0,0 +1,155 @@
+package slides
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// minFrameChars is the least text a frame needs, letters and digits only, to be kept; less
+// is usually OCR noise from faces, logos or scenery
+const minFrameChars = 20
+
+// OCRSlideProvider implements interfaces.SlideProvider by sampling frames with ffmpeg and
+// reading them with tesseract
+type OCRSlideProvider struct {
+	// Download fetches the video at url, at most maxHeight pixels high, and returns its path
+	Download      func(ctx context.Context, url string, maxHeight int) (string, error)
+	FfmpegPath    string // path to ffmpeg binary
+	TesseractPath string // path to tesseract binary
+	TmpDir        string // where to write the video, its frames and the text
+}
+
+func NewOCRSlideProvider(download func(ctx context.Context, url string, maxHeight int) (string, error), ffmpegPath, tesseractPath, tmpDir string) *OCRSlideProvider {
+	return &OCRSlideProvider{
+		Download:      download,
+		FfmpegPath:    ffmpegPath,
+		TesseractPath: tesseractPath,
+		TmpDir:        tmpDir,
+	}
+}
+
+// ExtractSlideText downloads the video, reads a frame every opts.Interval and writes the text
+// of each frame that shows something new to a slides-*.txt temp file, under the frame's
+// [M:SS] time. Frames repeating the text of an earlier one are skipped.
+func (p *OCRSlideProvider) ExtractSlideText(ctx context.Context, url string, opts interfaces.SlideOptions) (string, error) {
+	videoPath, err := p.Download(ctx, url, opts.MaxHeight)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(videoPath)
+
+	framesDir, err := os.MkdirTemp(p.TmpDir, "slides-frames-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create frames directory: %v", err)
+	}
+	defer os.RemoveAll(framesDir)
+	frames, err := p.sampleFrames(ctx, videoPath, framesDir, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for i, frame := range frames {
+		text, err := p.readFrame(ctx, frame, opts.Language)
+		if err != nil {
+			return "", err
+		}
+		interfaces.Heartbeat(ctx)
+		key := frameKey(text)
+		if len(key) < minFrameChars || seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Fprintf(&b, "[%s]\n%s\n\n", formatTimestamp(time.Duration(i)*opts.Interval), text)
+	}
+	log.WithContext(ctx).Debugf("Read %d frames of %s, %d with new text", len(frames), url, len(seen))
+	if len(seen) == 0 {
+		return "", nil
+	}
+
+	out, err := os.CreateTemp(p.TmpDir, "slides-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create slides text file: %v", err)
+	}
+	defer out.Close()
+	if _, err := out.WriteString(b.String()); err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("failed to write slides text file: %v", err)
+	}
+	return out.Name(), nil
+}
+
+// sampleFrames writes one frame every opts.Interval, up to opts.MaxFrames, as PNG files and
+// returns their paths in order
+func (p *OCRSlideProvider) sampleFrames(ctx context.Context, videoPath, framesDir string, opts interfaces.SlideOptions) ([]string, error) {
+	fps := fmt.Sprintf("fps=1/%s", strconv.FormatFloat(opts.Interval.Seconds(), 'f', -1, 64))
+	cmd := exec.CommandContext(ctx, p.FfmpegPath, "-nostdin", "-loglevel", "error", "-i", videoPath,
+		"-vf", fps, "-frames:v", strconv.Itoa(opts.MaxFrames), filepath.Join(framesDir, "frame-%05d.png"))
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg error: %v, output: %s", err, out.String())
+	}
+	frames, err := filepath.Glob(filepath.Join(framesDir, "frame-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(frames)
+	return frames, nil
+}
+
+// readFrame returns the text tesseract finds on a frame, with blank and noise-only lines
+// dropped
+func (p *OCRSlideProvider) readFrame(ctx context.Context, framePath, language string) (string, error) {
+	cmd := exec.CommandContext(ctx, p.TesseractPath, framePath, "stdout", "-l", language)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract error: %v, output: %s", err, stderr.String())
+	}
+	var lines []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if len(frameKey(line)) >= 2 {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// frameKey reduces text to its lowercase letters and digits, so frames of the same slide
+// compare equal despite small OCR differences in spacing and punctuation
+func frameKey(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// formatTimestamp formats an offset into the video as M:SS, or H:MM:SS for an hour or more
+func formatTimestamp(d time.Duration) string {
+	total := int(d.Seconds())
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,63 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// CommandSpeechProvider implements interfaces.SpeechProvider with a local TTS engine, such
+// as piper or espeak-ng, run once per text
+type CommandSpeechProvider struct {
+	// Command and arguments; the text is written to its stdin, and "{output}" is replaced
+	// with the path it writes the audio to
+	Command []string
+	Format  string // file extension of the audio, e.g. "wav"
+	TmpDir  string // where to write the audio ("" uses the system temp dir)
+}
+
+// NewCommandSpeechProvider creates a provider running command for each text
+func NewCommandSpeechProvider(command []string, format, tmpDir string) *CommandSpeechProvider {
+	return &CommandSpeechProvider{
+		Command: command,
+		Format:  format,
+		TmpDir:  tmpDir,
+	}
+}
+
+// Synthesize reads text aloud to a speech-*.<format> temp file. The voice and speed options
+// are left to the command's own arguments.
+func (p *CommandSpeechProvider) Synthesize(ctx context.Context, text string, opts interfaces.SpeechOptions) (string, error) {
+	if len(p.Command) == 0 {
+		return "", fmt.Errorf("no speech command configured")
+	}
+	out, err := os.CreateTemp(p.TmpDir, "speech-*."+p.Format)
+	if err != nil {
+		return "", fmt.Errorf("failed to create speech file: %v", err)
+	}
+	out.Close()
+
+	args := make([]string, len(p.Command)-1)
+	for i, arg := range p.Command[1:] {
+		args[i] = strings.ReplaceAll(arg, "{output}", out.Name())
+	}
+	cmd := exec.CommandContext(ctx, p.Command[0], args...)
+	cmd.Stdin = strings.NewReader(text)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("%s error: %v, output: %s", p.Command[0], err, strings.TrimSpace(output.String()))
+	}
+	if info, err := os.Stat(out.Name()); err != nil || info.Size() == 0 {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("%s wrote no audio", p.Command[0])
+	}
+	return out.Name(), nil
+}
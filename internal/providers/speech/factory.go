@@ -0,0 +1,14 @@
+package speech
+
+import (
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+)
+
+// NewSpeechProviderFromConfig returns the configured speech provider (OpenAI or a local command)
+func NewSpeechProviderFromConfig(cfg *config.AppConfig) interfaces.SpeechProvider {
+	if cfg.Speech.Provider == config.SpeechProviderLocal {
+		return NewCommandSpeechProvider(cfg.Speech.Command, cfg.Speech.Format, cfg.TmpDir)
+	}
+	return NewOpenAISpeechProvider(cfg.OpenAIKey, cfg.Speech.Model, cfg.TmpDir)
+}
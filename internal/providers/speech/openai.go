@@ -0,0 +1,74 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	openai "github.com/sashabaranov/go-openai"
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// openAISpeechMaxChars is the most text the OpenAI speech API reads in one call
+const openAISpeechMaxChars = 4096
+
+// OpenAISpeechProvider implements interfaces.SpeechProvider with the OpenAI speech API.
+// Longer text is read in parts, and the MP3 parts are joined into one file.
+type OpenAISpeechProvider struct {
+	client *openai.Client
+	Model  string // e.g. gpt-4o-mini-tts
+	TmpDir string // where to write the audio ("" uses the system temp dir)
+}
+
+// NewOpenAISpeechProvider creates a provider calling the OpenAI API with apiKey
+func NewOpenAISpeechProvider(apiKey, model, tmpDir string) *OpenAISpeechProvider {
+	return &OpenAISpeechProvider{
+		client: openai.NewClient(apiKey),
+		Model:  model,
+		TmpDir: tmpDir,
+	}
+}
+
+// Synthesize reads text aloud to a speech-*.mp3 temp file
+func (p *OpenAISpeechProvider) Synthesize(ctx context.Context, text string, opts interfaces.SpeechOptions) (string, error) {
+	parts := splitText(text, openAISpeechMaxChars)
+	if len(parts) == 0 {
+		return "", fmt.Errorf("no text to read")
+	}
+	out, err := os.CreateTemp(p.TmpDir, "speech-*.mp3")
+	if err != nil {
+		return "", fmt.Errorf("failed to create speech file: %v", err)
+	}
+	defer out.Close()
+
+	for i, part := range parts {
+		if err := p.synthesizePart(ctx, part, opts, out); err != nil {
+			os.Remove(out.Name())
+			return "", fmt.Errorf("failed to read part %d of %d aloud: %w", i+1, len(parts), err)
+		}
+		interfaces.Heartbeat(ctx)
+	}
+	log.WithContext(ctx).Debugf("Read %d characters aloud in %d parts with %s", len(text), len(parts), p.Model)
+	return out.Name(), nil
+}
+
+func (p *OpenAISpeechProvider) synthesizePart(ctx context.Context, text string, opts interfaces.SpeechOptions, out io.Writer) error {
+	resp, err := p.client.CreateSpeech(ctx, openai.CreateSpeechRequest{
+		Model:          openai.SpeechModel(p.Model),
+		Input:          text,
+		Voice:          openai.SpeechVoice(opts.Voice),
+		ResponseFormat: openai.SpeechResponseFormatMp3,
+		Speed:          opts.Speed,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	if _, err := io.Copy(out, resp); err != nil {
+		return fmt.Errorf("failed to write speech: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package speech
+
+import "strings"
+
+// splitText splits text into parts of at most maxChars bytes, at line breaks where it can
+// and otherwise at the end of a sentence or word, so each part reads naturally
+func splitText(text string, maxChars int) []string {
+	var parts []string
+	var current strings.Builder
+	flush := func() {
+		if part := strings.TrimSpace(current.String()); part != "" {
+			parts = append(parts, part)
+		}
+		current.Reset()
+	}
+	for _, piece := range pieces(text, maxChars) {
+		if current.Len() > 0 && current.Len()+1+len(piece) > maxChars {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(piece)
+	}
+	flush()
+	return parts
+}
+
+// pieces breaks text into lines, cutting lines longer than maxChars after a sentence or,
+// failing that, a word
+func pieces(text string, maxChars int) []string {
+	var result []string
+	for _, line := range strings.Split(text, "\n") {
+		for len(line) > maxChars {
+			cut := strings.LastIndex(line[:maxChars], ". ")
+			if cut > 0 {
+				cut++
+			} else if cut = strings.LastIndex(line[:maxChars], " "); cut <= 0 {
+				cut = maxChars
+				// Don't cut inside a UTF-8 sequence
+				for cut > 0 && line[cut]&0xC0 == 0x80 {
+					cut--
+				}
+			}
+			result = append(result, strings.TrimSpace(line[:cut]))
+			line = strings.TrimSpace(line[cut:])
+		}
+		result = append(result, line)
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package summarization
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// CachingSummarizationProvider wraps a SummarizationProvider and caches its responses by
+// input, so retries, reprocessing with unchanged inputs and digest re-runs don't pay for the
+// same LLM call twice
+type CachingSummarizationProvider struct {
+	provider interfaces.SummarizationProvider
+	// identity names the model and the settings that shape its responses, e.g.
+	// "openai/gpt-4o max_tokens=10000"; responses are only reused for the same identity
+	identity   string
+	tmpDir     string
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]summaryCacheEntry
+	hits    int
+	misses  int
+	saved   interfaces.TokenUsage // usage of the calls cache hits replaced
+}
+
+type summaryCacheEntry struct {
+	summary   string
+	usage     interfaces.TokenUsage // what the original call cost
+	expiresAt time.Time
+}
+
+// SummaryCacheStats reports cache effectiveness and the token usage cache hits saved
+type SummaryCacheStats struct {
+	Entries int                   `json:"entries"`
+	Hits    int                   `json:"hits"`
+	Misses  int                   `json:"misses"`
+	Saved   interfaces.TokenUsage `json:"saved"`
+	// Estimated cost of the saved tokens at the configured prices
+	SavedUSD float64 `json:"saved_usd,omitempty"`
+}
+
+// NewCachingSummarizationProvider caches responses from provider for ttl, keeping at most
+// maxEntries. Cached summaries are written back out to files in tmpDir.
+func NewCachingSummarizationProvider(provider interfaces.SummarizationProvider, identity, tmpDir string, ttl time.Duration, maxEntries int) *CachingSummarizationProvider {
+	return &CachingSummarizationProvider{
+		provider:   provider,
+		identity:   identity,
+		tmpDir:     tmpDir,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]summaryCacheEntry),
+	}
+}
+
+// SummarizeText returns the cached summary of the same text and prompt when there is one,
+// otherwise calls the provider and caches its summary. Errors are not cached, and cache
+// hits record no token usage.
+func (c *CachingSummarizationProvider) SummarizeText(ctx context.Context, text, prompt string, maxTokens int) (string, error) {
+	identity := c.identity
+	if model := interfaces.SummaryModel(ctx); model != "" {
+		identity += " model=" + model
+	}
+	key := summaryCacheKey(identity, text, prompt, maxTokens)
+	now := time.Now()
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expiresAt) {
+		c.hits++
+		c.saved.PromptTokens += entry.usage.PromptTokens
+		c.saved.CompletionTokens += entry.usage.CompletionTokens
+		c.saved.TotalTokens += entry.usage.TotalTokens
+		c.mu.Unlock()
+		log.WithContext(ctx).Debugf("Summary cache hit for %s", key[:12])
+		return c.writeSummary(entry.summary)
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Collect the call's usage to report it for saved calls, and pass it on
+	callCtx, recorder := interfaces.WithUsageRecorder(ctx)
+	summaryPath, err := c.provider.SummarizeText(callCtx, text, prompt, maxTokens)
+	usage := recorder.Usage()
+	interfaces.RecordUsage(ctx, usage)
+	if err != nil {
+		return "", err
+	}
+	summary, err := os.ReadFile(summaryPath)
+	if err != nil {
+		log.WithContext(ctx).Warnf("Not caching summary %s: %v", summaryPath, err)
+		return summaryPath, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = summaryCacheEntry{summary: string(summary), usage: usage, expiresAt: now.Add(c.ttl)}
+	return summaryPath, nil
+}
+
+// writeSummary writes a cached summary to a new file, which the caller owns
+func (c *CachingSummarizationProvider) writeSummary(summary string) (string, error) {
+	f, err := os.CreateTemp(c.tmpDir, "summary-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to write cached summary: %w", err)
+	}
+	_, err = f.WriteString(summary)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write cached summary: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// Clear drops every cached response. Entries aren't tied to requests, so this is how
+// responses derived from deleted data are removed.
+func (c *CachingSummarizationProvider) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]summaryCacheEntry)
+}
+
+// Stats returns hit and miss counts
+func (c *CachingSummarizationProvider) Stats() SummaryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return SummaryCacheStats{Entries: len(c.entries), Hits: c.hits, Misses: c.misses, Saved: c.saved}
+}
+
+// evictLocked drops expired entries, and the entry closest to expiry if the cache is still full
+func (c *CachingSummarizationProvider) evictLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// summaryCacheKey hashes everything that determines a response
+func summaryCacheKey(identity, text, prompt string, maxTokens int) string {
+	hash := sha256.New()
+	for _, part := range []string{identity, fmt.Sprint(maxTokens), hashString(prompt), hashString(text)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashString returns the hex-encoded SHA-256 of s
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,20 +1,25 @@
 package summarization
 
 import (
+	"fmt"
+
 	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/interfaces"
 )
 
-// NewConfigurableSummarizationProviderFromConfig returns the configured summarization provider (OpenAI or text)
+// NewConfigurableSummarizationProviderFromConfig returns the configured summarization provider (OpenAI, text or stub)
 func NewConfigurableSummarizationProviderFromConfig(cfg *config.AppConfig) (interfaces.SummarizationProvider, error) {
-	if cfg.SummarizerProvider == "openai" {
+	switch cfg.SummarizerProvider {
+	case "stub":
+		return NewStubSummarizationProvider(cfg.StubSummarySentences, cfg.TmpDir), nil
+	case "text":
+		return NewTextSummarizationProvider(cfg.TextSummarySentences, cfg.TmpDir), nil
+	case "openai":
 		openaiProvider, err := NewOpenAISummarizationProviderFromConfig(cfg)
 		if err != nil {
 			return nil, err
 		}
 		return openaiProvider, nil
 	}
-
-	// Default to text provider
-	return nil, nil // This line is removed as text.go has been deleted.
+	return nil, fmt.Errorf("unsupported summarizer_provider %q", cfg.SummarizerProvider)
 }
@@ -2,10 +2,14 @@ package summarization
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
 
 	"os"
 
@@ -19,6 +23,9 @@ type OpenAISummarizationProvider struct {
 	client    *openai.Client
 	model     string
 	maxTokens int
+	// streaming reports the summary text as it is written, every flushInterval
+	streaming     bool
+	flushInterval time.Duration
 }
 
 func NewOpenAISummarizationProviderFromConfig(cfg *config.AppConfig) (*OpenAISummarizationProvider, error) {
@@ -38,9 +45,11 @@ func NewOpenAISummarizationProviderFromConfig(cfg *config.AppConfig) (*OpenAISum
 	log.Infof("Initializing provider with model: %s (from config: %s)", model, cfg.OpenAIModel)
 
 	return &OpenAISummarizationProvider{
-		client:    client,
-		model:     model,
-		maxTokens: maxTokens,
+		client:        client,
+		model:         model,
+		maxTokens:     maxTokens,
+		streaming:     cfg.SummaryStreaming,
+		flushInterval: cfg.GetSummaryStreamFlushInterval(),
 	}, nil
 }
 
@@ -56,24 +65,94 @@ func (p *OpenAISummarizationProvider) SummarizeText(ctx context.Context, text, p
 			Content: text,
 		},
 	}
+	model := p.model
+	if requested := interfaces.SummaryModel(ctx); requested != "" {
+		model = requested
+	}
+	if maxTokens <= 0 {
+		maxTokens = p.maxTokens
+	}
 	req := openai.ChatCompletionRequest{
-		Model:       p.model,
+		Model:       model,
 		Messages:    messages,
-		MaxTokens:   p.maxTokens,
+		MaxTokens:   maxTokens,
 		Temperature: 0.4,
 	}
 
-	log.Debugf("Sending request with model: %s", req.Model)
+	log.WithContext(ctx).Debugf("Sending request with model: %s", req.Model)
+
+	if p.streaming {
+		summary, err := p.streamSummary(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return writeSummaryFile(summary)
+	}
 
 	resp, err := p.client.CreateChatCompletion(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("OpenAI API error: %w", err)
+		return "", interfaces.WithErrorCode(openAIErrorCode(err), fmt.Errorf("OpenAI API error: %w", err))
 	}
 
-	log.Debugf("Response received with model: %s", resp.Model)
+	log.WithContext(ctx).Debugf("Response received with model: %s", resp.Model)
+
+	interfaces.RecordUsage(ctx, interfaces.TokenUsage{
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	})
+
+	return writeSummaryFile(strings.TrimSpace(resp.Choices[0].Message.Content))
+}
+
+// streamSummary streams the completion, reporting a heartbeat for every piece of text and
+// the text written so far every flush interval. Text written before the stream fails is
+// reported before the error is returned, so it isn't lost.
+func (p *OpenAISummarizationProvider) streamSummary(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	stream, err := p.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return "", interfaces.WithErrorCode(openAIErrorCode(err), fmt.Errorf("OpenAI API error: %w", err))
+	}
+	defer stream.Close()
 
-	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+	var text strings.Builder
+	reported := 0
+	lastFlush := time.Now()
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if text.Len() > reported {
+				interfaces.ReportSummaryProgress(ctx, text.String())
+			}
+			return "", interfaces.WithErrorCode(openAIErrorCode(err), fmt.Errorf("OpenAI API error after %d bytes of summary: %w", text.Len(), err))
+		}
+		if resp.Usage != nil {
+			interfaces.RecordUsage(ctx, interfaces.TokenUsage{
+				PromptTokens:     resp.Usage.PromptTokens,
+				CompletionTokens: resp.Usage.CompletionTokens,
+				TotalTokens:      resp.Usage.TotalTokens,
+			})
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		text.WriteString(resp.Choices[0].Delta.Content)
+		interfaces.Heartbeat(ctx)
+		if time.Since(lastFlush) >= p.flushInterval {
+			interfaces.ReportSummaryProgress(ctx, text.String())
+			reported, lastFlush = text.Len(), time.Now()
+		}
+	}
+	log.WithContext(ctx).Debugf("Streamed %d bytes of summary with model: %s", text.Len(), req.Model)
+	return strings.TrimSpace(text.String()), nil
+}
 
+// writeSummaryFile writes a summary to a new temp file, which the caller owns
+func writeSummaryFile(summary string) (string, error) {
 	tmpFile, err := os.CreateTemp("", "summary-*.txt")
 	if err != nil {
 		return "", err
@@ -85,3 +164,33 @@ func (p *OpenAISummarizationProvider) SummarizeText(ctx context.Context, text, p
 	}
 	return tmpFile.Name(), nil
 }
+
+// openAIErrorCode categorizes an OpenAI API error by its status and error code
+func openAIErrorCode(err error) interfaces.ErrorCode {
+	status := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+		if code, ok := apiErr.Code.(string); ok {
+			switch code {
+			case "insufficient_quota":
+				return interfaces.ErrorCodeLLMQuotaExceeded
+			case "context_length_exceeded":
+				return interfaces.ErrorCodeLLMInputTooLong
+			}
+		}
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	}
+	switch {
+	case status == 429:
+		return interfaces.ErrorCodeLLMRateLimited
+	case status == 401 || status == 403:
+		return interfaces.ErrorCodeLLMAuthFailed
+	case status >= 500:
+		return interfaces.ErrorCodeLLMUnavailable
+	}
+	return interfaces.ErrorCodeOf(err, interfaces.ErrorCodeSummarizationFailed)
+}
@@ -0,0 +1,62 @@
+package summarization
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// StubSummarizationProvider "summarizes" text by keeping its first sentences. It calls no
+// API and spends no tokens, for dry runs and smoke tests of the full pipeline.
+type StubSummarizationProvider struct {
+	sentences int
+	tmpDir    string
+}
+
+// NewStubSummarizationProvider keeps the first sentences sentences of each text
+func NewStubSummarizationProvider(sentences int, tmpDir string) *StubSummarizationProvider {
+	if sentences <= 0 {
+		sentences = 3
+	}
+	return &StubSummarizationProvider{sentences: sentences, tmpDir: tmpDir}
+}
+
+// SummarizeText writes the first sentences of text to a summary file
+func (p *StubSummarizationProvider) SummarizeText(ctx context.Context, text, prompt string, maxTokens int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	summary := "[dry run] " + firstSentences(text, p.sentences)
+
+	tmpFile, err := os.CreateTemp(p.tmpDir, "summary-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer tmpFile.Close()
+	if _, err := tmpFile.WriteString(summary); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	return tmpFile.Name(), nil
+}
+
+// stubSummaryMaxLen caps stub summaries of text without sentence punctuation, such as raw transcripts
+const stubSummaryMaxLen = 2000
+
+// firstSentences returns up to n sentences from the start of text, on one line
+func firstSentences(text string, n int) string {
+	var b strings.Builder
+	for _, word := range strings.Fields(text) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(word)
+		if strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?") {
+			n--
+		}
+		if n == 0 || b.Len() >= stubSummaryMaxLen {
+			break
+		}
+	}
+	return b.String()
+}
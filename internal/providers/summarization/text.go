@@ -0,0 +1,179 @@
+package summarization
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TextSummarizationProvider summarizes offline by extraction: it scores each sentence by how
+// often its words occur across the text and keeps the best ones, in their original order. It
+// needs no API key and ignores the prompt.
+type TextSummarizationProvider struct {
+	sentences int
+	tmpDir    string
+}
+
+// NewTextSummarizationProvider keeps up to sentences sentences of each text
+func NewTextSummarizationProvider(sentences int, tmpDir string) *TextSummarizationProvider {
+	if sentences <= 0 {
+		sentences = 8
+	}
+	return &TextSummarizationProvider{sentences: sentences, tmpDir: tmpDir}
+}
+
+// Limits on what counts as a sentence; transcripts without punctuation are cut into pieces
+const (
+	textMinSentenceWords = 4
+	textMaxSentenceWords = 60
+	textPieceWords       = 30
+)
+
+var (
+	sentenceEndPattern = regexp.MustCompile(`([.!?])\s+`)
+	// partHeaderPattern matches the "Part N:" headers of a long transcript's chunk summaries
+	partHeaderPattern = regexp.MustCompile(`(?m)^Part \d+:\s*$`)
+)
+
+// textStopWords are left out of word counts
+var textStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "her": true, "was": true, "one": true,
+	"our": true, "out": true, "has": true, "have": true, "him": true, "his": true, "how": true,
+	"its": true, "may": true, "new": true, "now": true, "see": true, "she": true, "that": true,
+	"this": true, "with": true, "they": true, "them": true, "then": true, "than": true,
+	"there": true, "their": true, "what": true, "when": true, "where": true, "which": true,
+	"who": true, "will": true, "would": true, "could": true, "should": true, "from": true,
+	"been": true, "were": true, "into": true, "just": true, "like": true, "some": true,
+	"about": true, "also": true, "more": true, "very": true, "your": true, "yeah": true,
+	"really": true, "know": true, "going": true, "thing": true, "things": true, "think": true,
+	"here": true, "because": true, "these": true, "those": true, "over": true, "only": true,
+	"did": true, "does": true, "doing": true, "get": true, "got": true, "let": true, "okay": true,
+}
+
+// SummarizeText writes the highest-scoring sentences of text to a summary file, one bullet
+// each. maxTokens, when set, also caps the summary at about three words per four tokens.
+func (p *TextSummarizationProvider) SummarizeText(ctx context.Context, text, prompt string, maxTokens int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	maxWords := 0
+	if maxTokens > 0 {
+		maxWords = maxTokens * 3 / 4
+	}
+	var b strings.Builder
+	for _, sentence := range extractSentences(text, p.sentences, maxWords) {
+		b.WriteString("- ")
+		b.WriteString(sentence)
+		b.WriteByte('\n')
+	}
+
+	tmpFile, err := os.CreateTemp(p.tmpDir, "summary-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer tmpFile.Close()
+	if _, err := tmpFile.WriteString(b.String()); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	return tmpFile.Name(), nil
+}
+
+// extractSentences returns up to n of the text's highest-scoring sentences, in the order they
+// appear, within maxWords words when maxWords > 0
+func extractSentences(text string, n, maxWords int) []string {
+	sentences := splitSentences(partHeaderPattern.ReplaceAllString(text, ""))
+	if len(sentences) == 0 {
+		return nil
+	}
+
+	freq := make(map[string]int)
+	words := make([][]string, len(sentences))
+	for i, sentence := range sentences {
+		words[i] = contentWords(sentence)
+		for _, word := range words[i] {
+			freq[word]++
+		}
+	}
+
+	type scored struct {
+		index int
+		score float64
+	}
+	candidates := make([]scored, 0, len(sentences))
+	seen := make(map[string]bool, len(sentences))
+	for i, sentence := range sentences {
+		key := strings.ToLower(sentence)
+		if seen[key] || len(strings.Fields(sentence)) < textMinSentenceWords || len(words[i]) == 0 {
+			continue
+		}
+		seen[key] = true
+		total := 0
+		for _, word := range words[i] {
+			total += freq[word]
+		}
+		candidates = append(candidates, scored{index: i, score: float64(total) / float64(len(words[i]))})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	var picked []int
+	budget := maxWords
+	for _, c := range candidates {
+		if len(picked) == n {
+			break
+		}
+		count := len(strings.Fields(sentences[c.index]))
+		if maxWords > 0 {
+			if count > budget && len(picked) > 0 {
+				continue
+			}
+			budget -= count
+		}
+		picked = append(picked, c.index)
+	}
+	sort.Ints(picked)
+
+	summary := make([]string, 0, len(picked))
+	for _, i := range picked {
+		summary = append(summary, sentences[i])
+	}
+	return summary
+}
+
+// splitSentences splits text at sentence punctuation and line breaks, cutting overlong
+// unpunctuated runs into pieces
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(sentenceEndPattern.ReplaceAllString(text, "$1\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) <= textMaxSentenceWords {
+			if len(fields) > 0 {
+				sentences = append(sentences, strings.Join(fields, " "))
+			}
+			continue
+		}
+		for start := 0; start < len(fields); start += textPieceWords {
+			end := min(start+textPieceWords, len(fields))
+			sentences = append(sentences, strings.Join(fields[start:end], " "))
+		}
+	}
+	return sentences
+}
+
+// contentWords returns the lowercased words of a sentence, without stop words and words
+// shorter than three letters
+func contentWords(sentence string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 && !textStopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,213 @@
+package transcription
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ModelInfo describes a configured whisper model and its state on disk
+type ModelInfo struct {
+	Name      string     `json:"name"` // file name, e.g. ggml-tiny.en.bin
+	Path      string     `json:"path"`
+	Installed bool       `json:"installed"`
+	SizeBytes int64      `json:"size_bytes,omitempty"`
+	SHA256    string     `json:"sha256,omitempty"`     // expected checksum, if configured
+	Verified  bool       `json:"verified"`             // the installed file matches SHA256
+	CheckedAt *time.Time `json:"checked_at,omitempty"` // last sync that looked at the model
+	Error     string     `json:"error,omitempty"`      // why the last download or verification failed
+}
+
+// ModelManager keeps the configured whisper models installed, downloading missing models
+// from a registry and verifying them against their expected SHA-256
+type ModelManager struct {
+	registryURL string
+	download    bool
+	checksums   map[string]string
+	paths       []string
+	client      *http.Client
+
+	syncMu sync.Mutex // one sync at a time
+
+	mu     sync.RWMutex
+	status map[string]ModelInfo // keyed by path
+}
+
+// NewModelManager creates a manager for the models at paths. Missing models are only
+// downloaded when download is set; checksums are keyed by model file name.
+func NewModelManager(registryURL string, download bool, checksums map[string]string, paths []string) *ModelManager {
+	return &ModelManager{
+		registryURL: strings.TrimSuffix(registryURL, "/"),
+		download:    download,
+		checksums:   checksums,
+		paths:       paths,
+		client:      &http.Client{},
+		status:      make(map[string]ModelInfo),
+	}
+}
+
+// Sync verifies every configured model and downloads the ones that are missing or fail
+// their checksum. It returns the failures; a model that cannot be installed does not stop
+// the others.
+func (m *ModelManager) Sync(ctx context.Context) error {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	var errs []error
+	for _, path := range m.paths {
+		info := m.syncModel(ctx, path)
+		m.mu.Lock()
+		m.status[path] = info
+		m.mu.Unlock()
+		if info.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", info.Name, info.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncModel verifies one model, downloading it if needed
+func (m *ModelManager) syncModel(ctx context.Context, path string) ModelInfo {
+	name := filepath.Base(path)
+	now := time.Now()
+	info := ModelInfo{Name: name, Path: path, SHA256: strings.ToLower(m.checksums[name]), CheckedAt: &now}
+
+	if _, err := os.Stat(path); err == nil {
+		if info.SHA256 == "" {
+			info.Installed = true
+			return m.withSize(info)
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			info.Error = fmt.Sprintf("failed to verify: %v", err)
+			return m.withSize(info)
+		}
+		if sum == info.SHA256 {
+			info.Installed = true
+			info.Verified = true
+			return m.withSize(info)
+		}
+		if !m.download {
+			info.Installed = true
+			info.Error = fmt.Sprintf("checksum mismatch: got %s", sum)
+			return m.withSize(info)
+		}
+		log.Warnf("Whisper model %s fails its checksum (got %s), downloading it again", path, sum)
+	} else if !os.IsNotExist(err) {
+		info.Error = err.Error()
+		return info
+	} else if !m.download {
+		info.Error = "not installed (enable whisper_models.download to fetch it)"
+		return info
+	}
+
+	if err := m.fetch(ctx, name, path, info.SHA256); err != nil {
+		log.Errorf("Failed to download whisper model %s: %v", name, err)
+		info.Error = err.Error()
+		_, statErr := os.Stat(path)
+		info.Installed = statErr == nil
+		return m.withSize(info)
+	}
+	info.Installed = true
+	info.Verified = info.SHA256 != ""
+	return m.withSize(info)
+}
+
+// fetch downloads a model next to its destination and moves it into place once the
+// download is complete and matches the expected checksum
+func (m *ModelManager) fetch(ctx context.Context, name, path, expected string) error {
+	url := m.registryURL + "/" + name
+	log.Infof("Downloading whisper model %s from %s", name, url)
+	start := time.Now()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create model directory: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed: registry returned %s", resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+name+".*.download")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); expected != "" && sum != expected {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", expected, sum)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to install model: %w", err)
+	}
+	log.Infof("Installed whisper model %s (%d MB) in %s", path, size/(1024*1024), time.Since(start).Round(time.Second))
+	return nil
+}
+
+// withSize fills in the model file's current size
+func (m *ModelManager) withSize(info ModelInfo) ModelInfo {
+	if stat, err := os.Stat(info.Path); err == nil {
+		info.SizeBytes = stat.Size()
+	}
+	return info
+}
+
+// Models returns the configured models with the result of the last sync. Models that
+// haven't been synced yet are reported from what is on disk.
+func (m *ModelManager) Models() []ModelInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	models := make([]ModelInfo, 0, len(m.paths))
+	for _, path := range m.paths {
+		info, ok := m.status[path]
+		if !ok {
+			name := filepath.Base(path)
+			info = ModelInfo{Name: name, Path: path, SHA256: strings.ToLower(m.checksums[name])}
+			_, err := os.Stat(path)
+			info.Installed = err == nil
+		}
+		models = append(models, m.withSize(info))
+	}
+	return models
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
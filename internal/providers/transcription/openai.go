@@ -0,0 +1,131 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// OpenAIMaxAudioBytes is the largest audio file the OpenAI transcription API accepts
+const OpenAIMaxAudioBytes = 25 * 1024 * 1024
+
+// openAITranscriptionTimeout bounds one call to the transcription API
+const openAITranscriptionTimeout = 15 * time.Minute
+
+// ErrAudioTooLarge is returned for audio files above the transcription API's size limit
+var ErrAudioTooLarge = errors.New("audio file is too large for the transcription API")
+
+// openAILanguageCodes maps the language names the transcription API reports to ISO 639-1 codes
+var openAILanguageCodes = map[string]string{
+	"arabic": "ar", "bengali": "bn", "chinese": "zh", "czech": "cs", "danish": "da", "dutch": "nl",
+	"english": "en", "finnish": "fi", "french": "fr", "german": "de", "greek": "el", "hebrew": "he",
+	"hindi": "hi", "hungarian": "hu", "indonesian": "id", "italian": "it", "japanese": "ja", "korean": "ko",
+	"malay": "ms", "norwegian": "no", "persian": "fa", "polish": "pl", "portuguese": "pt", "romanian": "ro",
+	"russian": "ru", "spanish": "es", "swedish": "sv", "tamil": "ta", "telugu": "te", "thai": "th",
+	"turkish": "tr", "ukrainian": "uk", "urdu": "ur", "vietnamese": "vi",
+}
+
+// OpenAITranscriptionProvider implements interfaces.DetailedTranscriptionProvider using the
+// OpenAI audio transcription API
+type OpenAITranscriptionProvider struct {
+	client *openai.Client
+	Model  string // e.g. whisper-1
+	TmpDir string // where to write transcripts ("" uses the system temp dir)
+	// Spoken language hint such as "de"; "" or "auto" lets the API detect it
+	Language string
+}
+
+// NewOpenAITranscriptionProvider creates a provider calling the OpenAI API with apiKey
+func NewOpenAITranscriptionProvider(apiKey, model, tmpDir string) *OpenAITranscriptionProvider {
+	return &OpenAITranscriptionProvider{
+		client: openai.NewClient(apiKey),
+		Model:  model,
+		TmpDir: tmpDir,
+	}
+}
+
+// TranscribeAudio uploads the audio and returns the path to the transcript file
+func (p *OpenAITranscriptionProvider) TranscribeAudio(audioPath string) (string, error) {
+	transcription, err := p.TranscribeAudioDetailed(context.Background(), audioPath, interfaces.TranscriptionOptions{})
+	if err != nil {
+		return "", err
+	}
+	return transcription.Path, nil
+}
+
+// TranscribeAudioDetailed also returns the segments, scored by their mean token probability,
+// and the detected language. Audio above OpenAIMaxAudioBytes fails with ErrAudioTooLarge.
+func (p *OpenAITranscriptionProvider) TranscribeAudioDetailed(ctx context.Context, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio file: %w", err)
+	}
+	if info.Size() > OpenAIMaxAudioBytes {
+		return nil, interfaces.WithErrorCode(interfaces.ErrorCodeTranscriptionFailed,
+			fmt.Errorf("%w: %.1f MB (limit %d MB)", ErrAudioTooLarge, float64(info.Size())/(1024*1024), OpenAIMaxAudioBytes/(1024*1024)))
+	}
+
+	req := openai.AudioRequest{
+		Model:    p.Model,
+		FilePath: audioPath,
+		Format:   openai.AudioResponseFormatVerboseJSON,
+	}
+	if p.Language != "" && p.Language != "auto" {
+		req.Language = p.Language
+	}
+	ctx, cancel := context.WithTimeout(ctx, openAITranscriptionTimeout)
+	defer cancel()
+	log.WithContext(ctx).Infof("Transcribing %s with OpenAI %s", audioPath, p.Model)
+	resp, err := p.client.CreateTranscription(ctx, req)
+	if err != nil {
+		return nil, interfaces.WithErrorCode(interfaces.ErrorCodeTranscriptionFailed, fmt.Errorf("OpenAI transcription error: %w", err))
+	}
+
+	tmpFile, err := os.CreateTemp(p.TmpDir, "transcript-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp transcript file: %v", err)
+	}
+	defer tmpFile.Close()
+	if _, err := tmpFile.WriteString(strings.TrimSpace(resp.Text) + "\n"); err != nil {
+		os.Remove(tmpFile.Name())
+		return nil, fmt.Errorf("failed to write transcript: %v", err)
+	}
+
+	transcription := &interfaces.Transcription{Path: tmpFile.Name(), Model: p.Model}
+	language := strings.ToLower(resp.Language)
+	if code, ok := openAILanguageCodes[language]; ok {
+		language = code
+	}
+	if len(language) == 2 {
+		transcription.Language = language
+	}
+	for _, segment := range resp.Segments {
+		transcription.Segments = append(transcription.Segments, interfaces.TranscriptSegment{
+			Start:      time.Duration(segment.Start * float64(time.Second)),
+			End:        time.Duration(segment.End * float64(time.Second)),
+			Text:       strings.TrimSpace(segment.Text),
+			Confidence: math.Exp(segment.AvgLogprob),
+		})
+	}
+	return transcription, nil
+}
+
+// GetSupportedLanguages returns the languages the API is known to transcribe well
+func (p *OpenAITranscriptionProvider) GetSupportedLanguages() []string {
+	languages := make([]string, 0, len(openAILanguageCodes))
+	for _, code := range openAILanguageCodes {
+		languages = append(languages, code)
+	}
+	sort.Strings(languages)
+	return languages
+}
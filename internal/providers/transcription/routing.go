@@ -0,0 +1,90 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Route names, matching the transcription_routing config values
+const (
+	RouteLocal = "local"
+	RouteCloud = "cloud"
+)
+
+// RoutingTranscriptionProvider sends each request to a local or a cloud provider depending
+// on whether the video is longer than Threshold
+type RoutingTranscriptionProvider struct {
+	Local interfaces.TranscriptionProvider
+	Cloud interfaces.TranscriptionProvider
+	// Videos up to Threshold take ShortRoute, longer ones LongRoute, and videos of unknown
+	// duration UnknownRoute
+	Threshold    time.Duration
+	ShortRoute   string
+	LongRoute    string
+	UnknownRoute string
+}
+
+// TranscribeAudio transcribes on the route for videos of unknown duration
+func (p *RoutingTranscriptionProvider) TranscribeAudio(audioPath string) (string, error) {
+	transcription, err := p.TranscribeAudioDetailed(context.Background(), audioPath, interfaces.TranscriptionOptions{})
+	if err != nil {
+		return "", err
+	}
+	return transcription.Path, nil
+}
+
+// TranscribeAudioDetailed transcribes on the route for the video's duration and reports the
+// route taken. Audio too large for the cloud provider is transcribed locally instead.
+func (p *RoutingTranscriptionProvider) TranscribeAudioDetailed(ctx context.Context, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	route := p.route(opts.Duration)
+	transcription, err := p.transcribe(ctx, route, audioPath, opts)
+	if err != nil && route == RouteCloud && errors.Is(err, ErrAudioTooLarge) {
+		log.WithContext(ctx).Warnf("Transcribing %s locally: %v", audioPath, err)
+		route = RouteLocal
+		transcription, err = p.transcribe(ctx, route, audioPath, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	transcription.Route = route
+	return transcription, nil
+}
+
+// route picks the route for a video of the given duration (0 = unknown)
+func (p *RoutingTranscriptionProvider) route(duration time.Duration) string {
+	switch {
+	case duration <= 0:
+		return p.UnknownRoute
+	case duration <= p.Threshold:
+		return p.ShortRoute
+	default:
+		return p.LongRoute
+	}
+}
+
+// transcribe runs the provider for a route, with details when it reports them
+func (p *RoutingTranscriptionProvider) transcribe(ctx context.Context, route, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	provider := p.Local
+	if route == RouteCloud {
+		provider = p.Cloud
+	}
+	log.WithContext(ctx).Debugf("Routing transcription of %s (duration %s) to %s", audioPath, opts.Duration, route)
+	if detailed, ok := provider.(interfaces.DetailedTranscriptionProvider); ok {
+		return detailed.TranscribeAudioDetailed(ctx, audioPath, opts)
+	}
+	path, err := provider.TranscribeAudio(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	return &interfaces.Transcription{Path: path}, nil
+}
+
+// GetSupportedLanguages returns the languages of the local provider
+func (p *RoutingTranscriptionProvider) GetSupportedLanguages() []string {
+	return p.Local.GetSupportedLanguages()
+}
@@ -2,65 +2,231 @@ package transcription
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
 )
 
 // WhisperCppTranscriptionProvider implements interfaces.TranscriptionProvider using whisper.cpp CLI
 type WhisperCppTranscriptionProvider struct {
 	WhisperPath string // path to whisper.cpp binary (e.g., ./tools/whisper)
 	ModelPath   string // path to model file (e.g., ./models/ggml-base.en.bin)
+	TmpDir      string // where to write transcripts ("" uses the system temp dir)
+	// Spoken language passed to whisper.cpp: a code such as "de", or "auto" to detect it.
+	// "" keeps whisper.cpp's default of English.
+	Language string
+	// Models to pick from per request, most accurate first; ModelPath is used when none fits
+	Models []WhisperModel
+}
+
+// WhisperModel is a model a request can be transcribed with
+type WhisperModel struct {
+	Name        string
+	Path        string
+	MaxDuration time.Duration // longest video the model is picked for (0 = any length)
 }
 
-func NewWhisperCppTranscriptionProvider(whisperPath, modelPath string) *WhisperCppTranscriptionProvider {
+func NewWhisperCppTranscriptionProvider(whisperPath, modelPath, tmpDir string) *WhisperCppTranscriptionProvider {
 	return &WhisperCppTranscriptionProvider{
 		WhisperPath: whisperPath,
 		ModelPath:   modelPath,
+		TmpDir:      tmpDir,
 	}
 }
 
-// TranscribeAudio runs whisper.cpp CLI and returns the path to the transcript file
+// TranscribeAudio runs whisper.cpp CLI with the default model and returns the path to the
+// transcript file
 func (p *WhisperCppTranscriptionProvider) TranscribeAudio(audioPath string) (string, error) {
+	transcription, err := p.transcribe(context.Background(), audioPath, "", p.ModelPath, false)
+	if err != nil {
+		return "", err
+	}
+	return transcription.Path, nil
+}
+
+// TranscribeAudioDetailed also returns the segments with their mean token probability and the
+// spoken language, read from whisper.cpp's full JSON output. A transcript whose JSON can't be
+// read is still returned, without the details. The model is picked from the options.
+func (p *WhisperCppTranscriptionProvider) TranscribeAudioDetailed(ctx context.Context, audioPath string, opts interfaces.TranscriptionOptions) (*interfaces.Transcription, error) {
+	name, modelPath := p.selectModel(ctx, opts)
+	return p.transcribe(ctx, audioPath, name, modelPath, true)
+}
+
+// selectModel picks the model for a request: the model named by its quality hint, the first
+// or last model for "accurate" and "fast", else the first model whose max duration covers the
+// video. Requests of unknown duration, or longer than every model's limit, use ModelPath,
+// reported by its file name.
+func (p *WhisperCppTranscriptionProvider) selectModel(ctx context.Context, opts interfaces.TranscriptionOptions) (string, string) {
+	if len(p.Models) == 0 {
+		return "", p.ModelPath
+	}
+	switch opts.Quality {
+	case "", "auto":
+	case "accurate":
+		return p.Models[0].Name, p.Models[0].Path
+	case "fast":
+		last := p.Models[len(p.Models)-1]
+		return last.Name, last.Path
+	default:
+		for _, model := range p.Models {
+			if model.Name == opts.Quality {
+				return model.Name, model.Path
+			}
+		}
+		log.WithContext(ctx).Warnf("Unknown whisper model %q, picking one by duration", opts.Quality)
+	}
+	if opts.Duration > 0 {
+		for _, model := range p.Models {
+			if model.MaxDuration == 0 || opts.Duration <= model.MaxDuration {
+				return model.Name, model.Path
+			}
+		}
+	}
+	return filepath.Base(p.ModelPath), p.ModelPath
+}
+
+func (p *WhisperCppTranscriptionProvider) transcribe(ctx context.Context, audioPath, modelName, modelPath string, detailed bool) (*interfaces.Transcription, error) {
 	// Create a temp file for the transcript base (no .txt extension)
-	tmpFile, err := ioutil.TempFile("", "transcript-*")
+	tmpFile, err := ioutil.TempFile(p.TmpDir, "transcript-*")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp transcript file: %v", err)
+		return nil, fmt.Errorf("failed to create temp transcript file: %v", err)
 	}
 	tmpBasePath := tmpFile.Name()
 	tmpFile.Close()
 
-	cmdArgs := []string{"-m", p.ModelPath, "-f", audioPath, "-otxt", "-of", tmpBasePath}
-	log.Infof("Running command: %s %v", p.WhisperPath, cmdArgs)
-	cmd := exec.Command(p.WhisperPath, cmdArgs...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
+	cmdArgs := []string{"-m", modelPath, "-f", audioPath, "-otxt", "-of", tmpBasePath}
+	if p.Language != "" {
+		cmdArgs = append(cmdArgs, "-l", p.Language)
+	}
+	if detailed {
+		cmdArgs = append(cmdArgs, "-ojf")
+		defer os.Remove(tmpBasePath + ".json")
+	}
+	log.WithContext(ctx).Infof("Running command: %s %v", p.WhisperPath, cmdArgs)
+	cmd := exec.CommandContext(ctx, p.WhisperPath, cmdArgs...)
+	// whisper.cpp also prints the transcript itself; keep only the tail for error reporting.
+	// Each segment it prints counts as the task making progress.
+	out := &tailBuffer{limit: 8192}
+	cmd.Stdout = interfaces.HeartbeatWriter(ctx, out)
+	cmd.Stderr = out
 	if err := cmd.Run(); err != nil {
 		os.Remove(tmpBasePath + ".txt")
-		log.Errorf("%v, output: %s", err, out.String())
-		return "", fmt.Errorf("whisper.cpp error: %v, output: %s", err, out.String())
+		log.WithContext(ctx).Errorf("%v, output: %s", err, out.String())
+		if whisperModelLoadFailed(out.String()) {
+			return nil, whisperModelError(p.WhisperPath, modelPath, out.String())
+		}
+		return nil, fmt.Errorf("whisper.cpp error: %v, output: %s", err, out.String())
 	}
 
 	transcriptPath := tmpBasePath + ".txt"
 	// Check file size and log output if empty
 	info, statErr := os.Stat(transcriptPath)
 	if statErr != nil {
-		log.Errorf("could not stat transcript file: %v", statErr)
+		log.WithContext(ctx).Errorf("could not stat transcript file: %v", statErr)
 	} else {
-		log.Debugf("Transcript file size: %d bytes", info.Size())
+		log.WithContext(ctx).Debugf("Transcript file size: %d bytes", info.Size())
 		if info.Size() == 0 {
-			log.Warnf("transcript file is empty! Command output: %s", out.String())
+			log.WithContext(ctx).Warnf("transcript file is empty! Command output: %s", out.String())
 		}
 	}
 
-	return transcriptPath, nil
+	transcription := &interfaces.Transcription{Path: transcriptPath, Model: modelName}
+	if !detailed {
+		return transcription, nil
+	}
+	if err := readWhisperJSON(tmpBasePath+".json", transcription); err != nil {
+		log.WithContext(ctx).Warnf("Could not read transcript details: %v", err)
+	}
+	return transcription, nil
+}
+
+// whisperJSON is the part of whisper.cpp's full JSON output (-ojf) used for confidence and
+// the detected language
+type whisperJSON struct {
+	Result struct {
+		Language string `json:"language"`
+	} `json:"result"`
+	Transcription []struct {
+		Offsets struct {
+			From int64 `json:"from"` // milliseconds
+			To   int64 `json:"to"`
+		} `json:"offsets"`
+		Text   string `json:"text"`
+		Tokens []struct {
+			Text string  `json:"text"`
+			P    float64 `json:"p"`
+		} `json:"tokens"`
+	} `json:"transcription"`
 }
 
-// GetSupportedLanguages returns supported languages (for demo, just English)
+// readWhisperJSON reads the language and segments of a whisper.cpp JSON transcript. A
+// segment's confidence is the mean probability of its text tokens; special tokens such as
+// [_BEG_] and timestamps are skipped.
+func readWhisperJSON(path string, transcription *interfaces.Transcription) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var parsed whisperJSON
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	transcription.Language = parsed.Result.Language
+	for _, s := range parsed.Transcription {
+		var sum float64
+		var count int
+		for _, token := range s.Tokens {
+			if strings.HasPrefix(token.Text, "[_") {
+				continue
+			}
+			sum += token.P
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		transcription.Segments = append(transcription.Segments, interfaces.TranscriptSegment{
+			Start:      time.Duration(s.Offsets.From) * time.Millisecond,
+			End:        time.Duration(s.Offsets.To) * time.Millisecond,
+			Text:       strings.TrimSpace(s.Text),
+			Confidence: sum / float64(count),
+		})
+	}
+	return nil
+}
+
+// tailBuffer is an io.Writer that keeps only the last limit bytes written to it
+type tailBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	if extra := t.buf.Len() - t.limit; extra > 0 {
+		t.buf.Next(extra)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return t.buf.String()
+}
+
+// GetSupportedLanguages returns the configured language, or English when none is set
 func (p *WhisperCppTranscriptionProvider) GetSupportedLanguages() []string {
+	if p.Language != "" && p.Language != "auto" {
+		return []string{p.Language}
+	}
 	return []string{"en"}
 }
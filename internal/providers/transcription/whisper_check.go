@@ -0,0 +1,118 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// whisperModelLoadErrors are printed by whisper.cpp when it can't load a model
+var whisperModelLoadErrors = []string{
+	"failed to initialize whisper context",
+	"failed to load model",
+	"invalid model",
+	"bad magic",
+	"bad ftype",
+	"wrong shape",
+	"wrong size",
+	"unknown tensor",
+}
+
+// whisperModelLoadFailed reports whether whisper.cpp output says the model couldn't be loaded
+func whisperModelLoadFailed(output string) bool {
+	return modelLoadMessage(output) != ""
+}
+
+// modelLoadMessage returns the first line of whisper.cpp output saying why the model couldn't
+// be loaded, or "" if there is none
+func modelLoadMessage(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		lower := strings.ToLower(line)
+		for _, message := range whisperModelLoadErrors {
+			if strings.Contains(lower, message) {
+				return strings.TrimSpace(line)
+			}
+		}
+	}
+	return ""
+}
+
+// whisperModelError explains a failed whisper.cpp run that couldn't load its model
+func whisperModelError(whisperPath, modelPath, output string) error {
+	return interfaces.WithErrorCode(interfaces.ErrorCodeWhisperModelUnusable, fmt.Errorf(
+		"whisper.cpp at %s can't load model %s; the file may be corrupt, or in a format this whisper.cpp build doesn't support (update one to match the other): %s",
+		whisperPath, modelPath, modelLoadMessage(output)))
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// CheckWhisperSetup checks the whisper.cpp binary runs by running it with -h. With loadModels
+// it also checks the binary can load each model, without transcribing anything, by running it
+// with each model on an empty audio file, which whisper.cpp rejects only after loading the
+// model; this can take minutes with large models. Missing files are left to the dependency
+// checks.
+func CheckWhisperSetup(ctx context.Context, whisperPath string, modelPaths []string, tmpDir string, loadModels bool) []error {
+	if whisperPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(whisperPath); err != nil {
+		return nil
+	}
+
+	output, err := exec.CommandContext(ctx, whisperPath, "-h").CombinedOutput()
+	switch {
+	case err != nil && !isExitError(err):
+		return []error{fmt.Errorf("whisper_path: can't run %s: %v (is it built for this platform?)", whisperPath, err)}
+	case strings.Contains(strings.ToLower(string(output)), "is deprecated"):
+		return []error{fmt.Errorf("whisper_path: %s is a deprecated whisper.cpp binary; point whisper_path at whisper-cli: %s", whisperPath, lastLine(string(output)))}
+	case err != nil && strings.Contains(string(output), "error while loading shared libraries"):
+		return []error{fmt.Errorf("whisper_path: %s can't start: %s", whisperPath, lastLine(string(output)))}
+	}
+	if !loadModels {
+		return nil
+	}
+
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return []error{fmt.Errorf("failed to create tmp_dir %s to check whisper models: %w", tmpDir, err)}
+	}
+	audio, err := os.CreateTemp(tmpDir, "whisper-check-*.wav")
+	if err != nil {
+		return []error{fmt.Errorf("failed to create an audio file to check whisper models: %w", err)}
+	}
+	audio.Close()
+	defer os.Remove(audio.Name())
+
+	var errs []error
+	for _, modelPath := range modelPaths {
+		if modelPath == "" {
+			continue
+		}
+		if _, err := os.Stat(modelPath); err != nil {
+			continue
+		}
+		output, err := exec.CommandContext(ctx, whisperPath, "-m", modelPath, "-f", audio.Name()).CombinedOutput()
+		switch {
+		case whisperModelLoadFailed(string(output)):
+			errs = append(errs, whisperModelError(whisperPath, modelPath, string(output)))
+		case ctx.Err() != nil:
+			return append(errs, fmt.Errorf("checking whisper model %s: %w", modelPath, ctx.Err()))
+		case err != nil && !strings.Contains(string(output), "failed to read audio"):
+			errs = append(errs, fmt.Errorf("whisper.cpp at %s failed with model %s: %v: %s", whisperPath, modelPath, err, lastLine(string(output))))
+		}
+	}
+	return errs
+}
+
+func isExitError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
@@ -0,0 +1,145 @@
+package video
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// CachingVideoProvider wraps a VideoProvider and caches GetVideoInfo results by video ID,
+// so repeated lookups of the same video don't re-run yt-dlp --dump-json
+type CachingVideoProvider struct {
+	interfaces.VideoProvider
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]videoInfoCacheEntry
+	hits    int
+	misses  int
+}
+
+type videoInfoCacheEntry struct {
+	info      map[string]interface{}
+	expiresAt time.Time
+}
+
+// VideoInfoCacheStats reports cache effectiveness
+type VideoInfoCacheStats struct {
+	Entries int `json:"entries"`
+	Hits    int `json:"hits"`
+	Misses  int `json:"misses"`
+}
+
+// NewCachingVideoProvider caches video info from provider for ttl, keeping at most maxEntries videos
+func NewCachingVideoProvider(provider interfaces.VideoProvider, ttl time.Duration, maxEntries int) *CachingVideoProvider {
+	return &CachingVideoProvider{
+		VideoProvider: provider,
+		ttl:           ttl,
+		maxEntries:    maxEntries,
+		entries:       make(map[string]videoInfoCacheEntry),
+	}
+}
+
+// GetVideoInfo returns cached video info when available, otherwise fetches and caches it.
+// Errors are not cached.
+func (c *CachingVideoProvider) GetVideoInfo(ctx context.Context, videoURL string) (map[string]interface{}, error) {
+	key := VideoCacheKey(videoURL)
+	now := time.Now()
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expiresAt) {
+		c.hits++
+		c.mu.Unlock()
+		log.Debugf("Video info cache hit for %s", key)
+		return copyInfo(entry.info), nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	info, err := c.VideoProvider.GetVideoInfo(ctx, videoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = videoInfoCacheEntry{info: copyInfo(info), expiresAt: now.Add(c.ttl)}
+	return info, nil
+}
+
+// Invalidate drops the cached video info for a URL
+func (c *CachingVideoProvider) Invalidate(videoURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, VideoCacheKey(videoURL))
+}
+
+// Stats returns hit and miss counts
+func (c *CachingVideoProvider) Stats() VideoInfoCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return VideoInfoCacheStats{Entries: len(c.entries), Hits: c.hits, Misses: c.misses}
+}
+
+// evictLocked drops expired entries, and the entry closest to expiry if the cache is still full
+func (c *CachingVideoProvider) evictLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// VideoCacheKey returns the YouTube video ID for a URL, or the URL itself for anything else
+func VideoCacheKey(videoURL string) string {
+	u, err := url.Parse(strings.TrimSpace(videoURL))
+	if err != nil {
+		return videoURL
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	switch host {
+	case "youtu.be":
+		if id := strings.Trim(u.Path, "/"); id != "" {
+			return "youtube:" + id
+		}
+	case "youtube.com", "music.youtube.com":
+		if id := u.Query().Get("v"); id != "" {
+			return "youtube:" + id
+		}
+		for _, prefix := range []string{"/shorts/", "/embed/", "/live/"} {
+			if strings.HasPrefix(u.Path, prefix) {
+				return "youtube:" + strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+			}
+		}
+	}
+	return videoURL
+}
+
+// copyInfo returns a shallow copy so callers can't modify the cached map
+func copyInfo(info map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(info))
+	for k, v := range info {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,168 @@
+package video
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// HostLimitedVideoProvider wraps a VideoProvider and limits how many calls run against each
+// host at once and how closely they follow each other, so bursts of video info lookups and
+// audio downloads don't trip rate limits or bot detection
+type HostLimitedVideoProvider struct {
+	interfaces.VideoProvider
+
+	mu            sync.Mutex
+	freed         chan struct{} // closed and replaced when a slot frees or the limits change
+	maxConcurrent int           // calls per host at once, 0 = unlimited
+	minInterval   time.Duration // time between the starts of calls to a host
+	hosts         map[string]*hostSlots
+}
+
+type hostSlots struct {
+	active    int
+	nextStart time.Time
+}
+
+// NewHostLimitedVideoProvider limits calls to provider to maxConcurrent per host (0 = unlimited),
+// starting at least minInterval apart
+func NewHostLimitedVideoProvider(provider interfaces.VideoProvider, maxConcurrent int, minInterval time.Duration) *HostLimitedVideoProvider {
+	p := &HostLimitedVideoProvider{
+		VideoProvider: provider,
+		maxConcurrent: maxConcurrent,
+		minInterval:   minInterval,
+		hosts:         make(map[string]*hostSlots),
+		freed:         make(chan struct{}),
+	}
+	return p
+}
+
+// hostWaitHeartbeat is how often a call queued for a host slot reports a heartbeat, so the
+// time it spends queued isn't taken for a hung task
+const hostWaitHeartbeat = 10 * time.Second
+
+// SetLimits changes the limits; calls already waiting are re-checked against them
+func (p *HostLimitedVideoProvider) SetLimits(maxConcurrent int, minInterval time.Duration) {
+	p.mu.Lock()
+	p.maxConcurrent = maxConcurrent
+	p.minInterval = minInterval
+	p.wakeLocked()
+	p.mu.Unlock()
+}
+
+// wakeLocked wakes every call waiting for a slot; p.mu must be held
+func (p *HostLimitedVideoProvider) wakeLocked() {
+	close(p.freed)
+	p.freed = make(chan struct{})
+}
+
+// GetVideoInfo fetches video info once the host has a free slot
+func (p *HostLimitedVideoProvider) GetVideoInfo(ctx context.Context, videoURL string) (map[string]interface{}, error) {
+	release, err := p.acquire(ctx, videoURL)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return p.VideoProvider.GetVideoInfo(ctx, videoURL)
+}
+
+// DownloadAudio downloads audio once the host has a free slot
+func (p *HostLimitedVideoProvider) DownloadAudio(ctx context.Context, videoURL string) (string, error) {
+	release, err := p.acquire(ctx, videoURL)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return p.VideoProvider.DownloadAudio(ctx, videoURL)
+}
+
+// Do runs another call to the URL's host, such as a video download, under the same limits
+func (p *HostLimitedVideoProvider) Do(ctx context.Context, videoURL string, call func() error) error {
+	release, err := p.acquire(ctx, videoURL)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return call()
+}
+
+// acquire waits for a free slot on the URL's host and for its turn to start, and returns
+// the function that frees the slot. It reports heartbeats while it waits, and returns ctx's
+// error if ctx is done first.
+func (p *HostLimitedVideoProvider) acquire(ctx context.Context, videoURL string) (func(), error) {
+	host := videoHost(videoURL)
+	heartbeat := time.NewTicker(hostWaitHeartbeat)
+	defer heartbeat.Stop()
+
+	p.mu.Lock()
+	slots, ok := p.hosts[host]
+	if !ok {
+		slots = &hostSlots{}
+		p.hosts[host] = slots
+	}
+	for p.maxConcurrent > 0 && slots.active >= p.maxConcurrent {
+		freed := p.freed
+		p.mu.Unlock()
+		select {
+		case <-freed:
+		case <-heartbeat.C:
+			interfaces.Heartbeat(ctx)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	slots.active++
+	now := time.Now()
+	start := now
+	if slots.nextStart.After(now) {
+		start = slots.nextStart
+	}
+	slots.nextStart = start.Add(p.minInterval)
+	p.mu.Unlock()
+
+	release := func() {
+		p.mu.Lock()
+		slots.active--
+		p.wakeLocked()
+		p.mu.Unlock()
+	}
+	if wait := start.Sub(now); wait > 0 {
+		log.Debugf("Waiting %v before the next request to %s", wait.Round(time.Millisecond), host)
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		for waiting := true; waiting; {
+			select {
+			case <-timer.C:
+				waiting = false
+			case <-heartbeat.C:
+				interfaces.Heartbeat(ctx)
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return release, nil
+}
+
+// videoHost returns the host a URL is served from, with YouTube's domains folded together
+func videoHost(videoURL string) string {
+	u, err := url.Parse(strings.TrimSpace(videoURL))
+	if err != nil || u.Host == "" {
+		return videoURL
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	switch host {
+	case "youtu.be", "music.youtube.com", "youtube-nocookie.com":
+		return "youtube.com"
+	}
+	return host
+}
@@ -2,35 +2,92 @@ package video
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"video-summarizer-go/internal/interfaces"
 )
 
+// ytDlpErrorCodes maps yt-dlp error messages to the error code they indicate
+var ytDlpErrorCodes = []struct {
+	message string
+	code    interfaces.ErrorCode
+}{
+	{"Private video", interfaces.ErrorCodeSourceUnavailable},
+	{"Video unavailable", interfaces.ErrorCodeSourceUnavailable},
+	{"This video has been removed", interfaces.ErrorCodeSourceUnavailable},
+	{"HTTP Error 404", interfaces.ErrorCodeSourceUnavailable},
+	{"Sign in to confirm", interfaces.ErrorCodeDownloadBlocked},
+	{"not available in your country", interfaces.ErrorCodeDownloadBlocked},
+	{"confirm your age", interfaces.ErrorCodeDownloadBlocked},
+	{"HTTP Error 403", interfaces.ErrorCodeDownloadBlocked},
+}
+
+// ytDlpError attaches the error code the yt-dlp output indicates, if any
+func ytDlpError(err error, output string) error {
+	for _, known := range ytDlpErrorCodes {
+		if strings.Contains(output, known.message) {
+			return interfaces.WithErrorCode(known.code, err)
+		}
+	}
+	return err
+}
+
 // YtDlpVideoProvider implements interfaces.VideoProvider using yt-dlp binary
 type YtDlpVideoProvider struct {
 	YtDlpPath string // path to yt-dlp binary
 	TmpDir    string // where to save temp audio files
+	UserAgent string // sent with yt-dlp's requests; yt-dlp's own default if empty
+	// ExtraArgs are added to every yt-dlp call, e.g. "--extractor-args" settings
+	ExtraArgs []string
+
+	limitRate atomic.Int64 // download bandwidth in bytes per second, 0 = unlimited
 }
 
 func NewYtDlpVideoProvider(ytDlpPath, tmpDir string) *YtDlpVideoProvider {
 	return &YtDlpVideoProvider{
 		YtDlpPath: ytDlpPath,
 		TmpDir:    tmpDir,
+		UserAgent: DefaultUserAgent,
+	}
+}
+
+// DefaultUserAgent is the browser user agent yt-dlp presents unless configured otherwise
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// command builds a yt-dlp command with the configured user agent and extra arguments before
+// args and the URL; it is killed if ctx is cancelled
+func (p *YtDlpVideoProvider) command(ctx context.Context, url string, args ...string) *exec.Cmd {
+	var cmdArgs []string
+	if p.UserAgent != "" {
+		cmdArgs = append(cmdArgs, "--user-agent", p.UserAgent)
 	}
+	cmdArgs = append(cmdArgs, p.ExtraArgs...)
+	cmdArgs = append(cmdArgs, args...)
+	return exec.CommandContext(ctx, p.YtDlpPath, append(cmdArgs, url)...)
+}
+
+// SetLimitRate caps the bandwidth of each audio download, in bytes per second (0 = unlimited).
+// Downloads already running keep the limit they started with.
+func (p *YtDlpVideoProvider) SetLimitRate(bytesPerSecond int64) {
+	p.limitRate.Store(bytesPerSecond)
 }
 
 // GetVideoInfo fetches video info as a map using yt-dlp --dump-json
-func (p *YtDlpVideoProvider) GetVideoInfo(url string) (map[string]interface{}, error) {
-	cmd := exec.Command(p.YtDlpPath, "--simulate", "--skip-download", "--user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "--dump-json", url)
+func (p *YtDlpVideoProvider) GetVideoInfo(ctx context.Context, url string) (map[string]interface{}, error) {
+	cmd := p.command(ctx, url, "--simulate", "--skip-download", "--dump-json")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &out
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("yt-dlp error: %v, output: %s", err, out.String())
+		return nil, ytDlpError(fmt.Errorf("yt-dlp error: %v, output: %s", err, out.String()), out.String())
 	}
 	var info map[string]interface{}
 	if err := json.Unmarshal(out.Bytes(), &info); err != nil {
@@ -40,19 +97,50 @@ func (p *YtDlpVideoProvider) GetVideoInfo(url string) (map[string]interface{}, e
 }
 
 // DownloadAudio downloads audio as mp3 using yt-dlp and returns the file path
-func (p *YtDlpVideoProvider) DownloadAudio(url string) (string, error) {
+func (p *YtDlpVideoProvider) DownloadAudio(ctx context.Context, url string) (string, error) {
 	filename := fmt.Sprintf("audio-%d.mp3", time.Now().UnixNano())
 	outPath := filepath.Join(p.TmpDir, filename)
-	cmd := exec.Command(p.YtDlpPath, "--user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "-x", "--audio-format", "mp3", "-o", outPath, url)
+	args := []string{"-x", "--audio-format", "mp3", "-o", outPath}
+	if limit := p.limitRate.Load(); limit > 0 {
+		args = append(args, "--limit-rate", strconv.FormatInt(limit, 10))
+	}
+	cmd := p.command(ctx, url, args...)
 	var out bytes.Buffer
-	cmd.Stdout = &out
+	// Download progress counts as the task making progress
+	cmd.Stdout = interfaces.HeartbeatWriter(ctx, &out)
 	cmd.Stderr = &out
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("yt-dlp audio error: %v, output: %s", err, out.String())
+		return "", ytDlpError(fmt.Errorf("yt-dlp audio error: %v, output: %s", err, out.String()), out.String())
 	}
 	return outPath, nil
 }
 
+// DownloadVideo downloads the video stream at no more than maxHeight pixels high, without
+// audio, and returns the file path
+func (p *YtDlpVideoProvider) DownloadVideo(ctx context.Context, url string, maxHeight int) (string, error) {
+	base := filepath.Join(p.TmpDir, fmt.Sprintf("video-%d", time.Now().UnixNano()))
+	format := fmt.Sprintf("bv*[height<=%d]/b[height<=%d]/wv*/w", maxHeight, maxHeight)
+	args := []string{"-f", format, "-o", base + ".%(ext)s"}
+	if limit := p.limitRate.Load(); limit > 0 {
+		args = append(args, "--limit-rate", strconv.FormatInt(limit, 10))
+	}
+	cmd := p.command(ctx, url, args...)
+	var out bytes.Buffer
+	cmd.Stdout = interfaces.HeartbeatWriter(ctx, &out)
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return "", ytDlpError(fmt.Errorf("yt-dlp video error: %v, output: %s", err, out.String()), out.String())
+	}
+	// The extension depends on the format yt-dlp picked
+	matches, _ := filepath.Glob(base + ".*")
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".part") {
+			return match, nil
+		}
+	}
+	return "", fmt.Errorf("yt-dlp did not write the video file")
+}
+
 // SupportsURL returns true if yt-dlp can handle the URL
 func (p *YtDlpVideoProvider) SupportsURL(url string) bool {
 	return strings.Contains(url, "youtube.com") || strings.Contains(url, "youtu.be")
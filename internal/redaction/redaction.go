@@ -0,0 +1,162 @@
+// Package redaction scrubs personal data and profanity from transcripts and summaries.
+// Matches are replaced with a label such as [EMAIL] or [NAME], or masked in the case of
+// profanity, so the text still reads naturally.
+package redaction
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"video-summarizer-go/internal/config"
+)
+
+// Labels of the built-in redactions, also used as the keys of redaction counts
+const (
+	LabelEmail     = "EMAIL"
+	LabelPhone     = "PHONE"
+	LabelName      = "NAME"
+	LabelProfanity = "PROFANITY"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	// Phone numbers need an area code or country code, so years and amounts are left alone
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{2,4}\)[\s.\-]?|\b\d{2,4}[\s.\-])\d{3,4}[\s.\-]?\d{4}\b|\+\d{8,14}\b`)
+)
+
+// profanityWords is the built-in profanity list, matched case-insensitively as whole words
+var profanityWords = []string{
+	"fuck", "fucks", "fucked", "fucking", "fucker", "motherfucker", "shit", "shits", "shitty", "bullshit",
+	"bitch", "bitches", "asshole", "assholes", "bastard", "bastards", "dick", "dickhead", "cunt", "wanker",
+	"prick", "twat", "goddamn", "damn", "crap", "piss", "pissed",
+}
+
+// rule replaces every match of a pattern
+type rule struct {
+	label   string
+	pattern *regexp.Regexp
+	mask    bool // replace with asterisks instead of [label]
+}
+
+// Redactor applies a set of redaction rules to text
+type Redactor struct {
+	rules []rule
+}
+
+// New builds a redactor for the rules. names are the person names to scrub when rules.Names
+// is set; the caller finds them beforehand, e.g. with a named-entity recognizer.
+func New(rules config.RedactionRules, names []string) (*Redactor, error) {
+	r := &Redactor{}
+	// Configured patterns run first, in label order, so they can match text the built-in
+	// rules would otherwise split
+	labels := make([]string, 0, len(rules.Patterns))
+	for label := range rules.Patterns {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		pattern, err := regexp.Compile(rules.Patterns[label])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", label, err)
+		}
+		r.rules = append(r.rules, rule{label: strings.ToUpper(label), pattern: pattern})
+	}
+	if rules.Emails {
+		r.rules = append(r.rules, rule{label: LabelEmail, pattern: emailPattern})
+	}
+	if rules.PhoneNumbers {
+		r.rules = append(r.rules, rule{label: LabelPhone, pattern: phonePattern})
+	}
+	if rules.Names && len(names) > 0 {
+		r.rules = append(r.rules, rule{label: LabelName, pattern: wordsPattern(names, false)})
+	}
+	var words []string
+	if rules.Profanity {
+		words = append(words, profanityWords...)
+	}
+	words = append(words, rules.ProfanityWords...)
+	if len(words) > 0 {
+		r.rules = append(r.rules, rule{label: LabelProfanity, pattern: wordsPattern(words, true), mask: true})
+	}
+	return r, nil
+}
+
+// wordsPattern matches any of the words as a whole word, longest first so "Jane Doe" is
+// replaced before "Jane"
+func wordsPattern(words []string, ignoreCase bool) *regexp.Regexp {
+	sorted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			sorted = append(sorted, regexp.QuoteMeta(word))
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	flags := ""
+	if ignoreCase {
+		flags = "(?i)"
+	}
+	return regexp.MustCompile(flags + `\b(?:` + strings.Join(sorted, "|") + `)\b`)
+}
+
+// Redact returns the text with every match replaced and adds the replacements made to counts
+func (r *Redactor) Redact(text string, counts map[string]int) string {
+	for _, rule := range r.rules {
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			counts[rule.label]++
+			if rule.mask {
+				return strings.Repeat("*", len([]rune(match)))
+			}
+			return "[" + rule.label + "]"
+		})
+	}
+	return text
+}
+
+// RedactFile rewrites a file with its redacted text, line by line so large transcripts are
+// never held in memory at once, and adds the replacements made to counts
+func (r *Redactor) RedactFile(path string, counts map[string]int) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".redact-*")
+	if err != nil {
+		return fmt.Errorf("failed to create redacted file: %w", err)
+	}
+	defer os.Remove(dst.Name())
+
+	reader := bufio.NewReader(src)
+	writer := bufio.NewWriter(dst)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			if _, err := writer.WriteString(r.Redact(line, counts)); err != nil {
+				dst.Close()
+				return fmt.Errorf("failed to write redacted file: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			dst.Close()
+			return fmt.Errorf("failed to read %s: %w", path, readErr)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to write redacted file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("failed to write redacted file: %w", err)
+	}
+	return os.Rename(dst.Name(), path)
+}
@@ -0,0 +1,402 @@
+// Package schedules re-submits a URL or re-runs a YouTube search on a daily or weekly
+// recurrence, e.g. to summarize a weekly show every Monday. Unlike background sources,
+// which poll on an interval and are configured in sources.yaml, schedules are created and
+// changed at runtime through the API and persisted to a JSON file.
+package schedules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+const (
+	// pollInterval is how often schedules are checked for due runs
+	pollInterval = 30 * time.Second
+	// defaultMaxVideos is how many search results a query schedule submits by default
+	defaultMaxVideos = 5
+	// maxMaxVideos caps the search results a query schedule can submit per run
+	maxMaxVideos = 50
+)
+
+// ErrNotFound is returned for schedule IDs that don't exist
+var ErrNotFound = errors.New("schedule not found")
+
+// Schedule submits a URL, or the videos a search finds, on a recurrence
+type Schedule struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Exactly one of URL and Query is set. A URL is summarized again on every run; a query's
+	// results are deduplicated, so only videos not summarized before are submitted.
+	URL       string `json:"url,omitempty"`
+	Query     string `json:"query,omitempty"`
+	Channel   string `json:"channel,omitempty"`    // only search this YouTube channel ID
+	MaxVideos int    `json:"max_videos,omitempty"` // search results submitted per run (default 5)
+	// "daily" or "weekly", at Time ("HH:MM" local, default "07:00") on Weekday (weekly only,
+	// default monday)
+	Recurrence string `json:"recurrence"`
+	Time       string `json:"time,omitempty"`
+	Weekday    string `json:"weekday,omitempty"`
+	// Submission settings, as for /api/submit
+	Prompt   interfaces.Prompt `json:"prompt"`
+	Category string            `json:"category,omitempty"`
+	User     string            `json:"user,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Paused   bool              `json:"paused,omitempty"`
+
+	CreatedAt      time.Time  `json:"created_at"`
+	NextRun        time.Time  `json:"next_run"`
+	LastRun        *time.Time `json:"last_run,omitempty"`
+	LastRequestIDs []string   `json:"last_request_ids,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+// validate checks the schedule's definition and fills in defaults
+func (s *Schedule) validate() error {
+	s.URL = strings.TrimSpace(s.URL)
+	s.Query = strings.TrimSpace(s.Query)
+	switch {
+	case (s.URL == "") == (s.Query == ""):
+		return fmt.Errorf("exactly one of url or query is required")
+	case s.URL != "" && !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://"):
+		return fmt.Errorf("url must be an http(s) URL")
+	case s.Channel != "" && s.Query == "":
+		return fmt.Errorf("channel only applies to query schedules")
+	}
+	if s.Query != "" {
+		if s.MaxVideos == 0 {
+			s.MaxVideos = defaultMaxVideos
+		}
+		if s.MaxVideos < 0 || s.MaxVideos > maxMaxVideos {
+			return fmt.Errorf("max_videos must be between 1 and %d", maxMaxVideos)
+		}
+	}
+
+	if s.Recurrence != "daily" && s.Recurrence != "weekly" {
+		return fmt.Errorf("unsupported recurrence %q (supported: daily, weekly)", s.Recurrence)
+	}
+	if s.Time == "" {
+		s.Time = "07:00"
+	}
+	if _, err := time.Parse("15:04", s.Time); err != nil {
+		return fmt.Errorf("time must be HH:MM, got %q", s.Time)
+	}
+	if s.Recurrence == "weekly" {
+		if s.Weekday == "" {
+			s.Weekday = "monday"
+		}
+		if _, err := config.ParseWeekday(s.Weekday); err != nil {
+			return err
+		}
+	}
+
+	if s.Prompt.Prompt == "" {
+		s.Prompt = interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: "general"}
+	}
+	if s.Category == "" {
+		s.Category = "general"
+	}
+	return nil
+}
+
+// next returns the first time the schedule runs after t
+func (s *Schedule) next(t time.Time) time.Time {
+	clock, _ := time.Parse("15:04", s.Time)
+	next := time.Date(t.Year(), t.Month(), t.Day(), clock.Hour(), clock.Minute(), 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if s.Recurrence == "weekly" {
+		weekday, _ := config.ParseWeekday(s.Weekday)
+		for next.Weekday() != weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// SearchFunc finds the URLs of up to maxVideos videos matching a query, within a channel
+// when one is given
+type SearchFunc func(query, channel string, maxVideos int) ([]string, error)
+
+// Scheduler runs schedules when they are due and keeps them in an optional JSON file
+type Scheduler struct {
+	path        string
+	submissions *services.VideoSubmissionService
+	search      SearchFunc
+
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewScheduler loads schedules from path. An empty path keeps them in memory only. Runs
+// missed while the service was down are skipped.
+func NewScheduler(path string, submissions *services.VideoSubmissionService, search SearchFunc) (*Scheduler, error) {
+	s := &Scheduler{path: path, submissions: submissions, search: search, schedules: make(map[string]*Schedule)}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedules %s: %w", path, err)
+	}
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse schedules %s: %w", path, err)
+	}
+	now := time.Now()
+	for _, schedule := range schedules {
+		if schedule.NextRun.Before(now) {
+			schedule.NextRun = schedule.next(now)
+		}
+		s.schedules[schedule.ID] = schedule
+	}
+	return s, nil
+}
+
+// Start runs due schedules until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopChan != nil {
+		return
+	}
+	s.stopChan = make(chan struct{})
+	s.wg.Add(1)
+	go s.run(s.stopChan)
+	log.Infof("Submission scheduler started with %d schedule(s)", len(s.schedules))
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stopChan := s.stopChan
+	s.stopChan = nil
+	s.mu.Unlock()
+	if stopChan != nil {
+		close(stopChan)
+		s.wg.Wait()
+	}
+}
+
+func (s *Scheduler) run(stopChan chan struct{}) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			s.RunDue(time.Now())
+		}
+	}
+}
+
+// RunDue runs every unpaused schedule whose next run is at or before now
+func (s *Scheduler) RunDue(now time.Time) {
+	s.mu.Lock()
+	var due []Schedule
+	for _, schedule := range s.schedules {
+		if !schedule.Paused && !schedule.NextRun.After(now) {
+			due = append(due, *schedule)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].NextRun.Before(due[j].NextRun) })
+
+	for _, schedule := range due {
+		s.execute(schedule, now)
+	}
+}
+
+// RunNow runs a schedule immediately, without moving its next run, and returns it with the
+// outcome of the run
+func (s *Scheduler) RunNow(id string) (Schedule, error) {
+	schedule, err := s.Get(id)
+	if err != nil {
+		return Schedule{}, err
+	}
+	s.execute(schedule, time.Now())
+	return s.Get(id)
+}
+
+// execute submits a schedule's URL or search results and records the run
+func (s *Scheduler) execute(schedule Schedule, at time.Time) {
+	requestIDs, err := s.submit(schedule)
+	if err != nil {
+		log.Errorf("Schedule %s failed: %v", schedule.ID, err)
+	} else {
+		log.Infof("Schedule %s submitted %d request(s): %v", schedule.ID, len(requestIDs), requestIDs)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.schedules[schedule.ID]
+	if !ok {
+		return // deleted while running
+	}
+	current.LastRun = &at
+	current.LastRequestIDs = requestIDs
+	current.LastError = ""
+	if err != nil {
+		current.LastError = err.Error()
+	}
+	if !current.NextRun.After(at) {
+		current.NextRun = current.next(at)
+	}
+	if err := s.saveLocked(); err != nil {
+		log.Errorf("Failed to save schedules: %v", err)
+	}
+}
+
+// submit submits the schedule's URL, or the videos its query finds now
+func (s *Scheduler) submit(schedule Schedule) ([]string, error) {
+	opts := services.SubmitOptions{
+		User:   schedule.User,
+		Source: "schedule:" + schedule.ID,
+		Tags:   schedule.Tags,
+	}
+	if schedule.URL != "" {
+		opts.NoDedup = true
+		requestID, err := s.submissions.SubmitVideoWithOptions(schedule.URL, schedule.Prompt, interfaces.SourceTypeVideo, schedule.Category, services.DefaultMaxTokens, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []string{requestID}, nil
+	}
+
+	if s.search == nil {
+		return nil, fmt.Errorf("search is not available")
+	}
+	urls, err := s.search(schedule.Query, schedule.Channel, schedule.MaxVideos)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	return s.submissions.SubmitBatchWithOptions(urls, schedule.Prompt, interfaces.SourceTypeVideo, schedule.Category, services.DefaultMaxTokens, opts)
+}
+
+// List returns all schedules sorted by next run
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, *schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].NextRun.Before(schedules[j].NextRun) })
+	return schedules
+}
+
+// Get returns a schedule by ID
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	return *schedule, nil
+}
+
+// Create validates and stores a new schedule, returning it with its ID and next run
+func (s *Scheduler) Create(schedule Schedule) (Schedule, error) {
+	if err := schedule.validate(); err != nil {
+		return Schedule{}, err
+	}
+	now := time.Now()
+	schedule.ID = fmt.Sprintf("sched-%d", now.UnixNano())
+	schedule.CreatedAt = now
+	schedule.NextRun = schedule.next(now)
+	schedule.LastRun, schedule.LastRequestIDs, schedule.LastError = nil, nil, ""
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedules[schedule.ID] = &schedule
+	if err := s.saveLocked(); err != nil {
+		delete(s.schedules, schedule.ID)
+		return Schedule{}, fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return schedule, nil
+}
+
+// Update replaces a schedule's definition, keeping its ID, creation time and last run. The
+// next run is recalculated from now.
+func (s *Scheduler) Update(id string, schedule Schedule) (Schedule, error) {
+	if err := schedule.validate(); err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	previous := *current
+	schedule.ID = id
+	schedule.CreatedAt = current.CreatedAt
+	schedule.LastRun, schedule.LastRequestIDs, schedule.LastError = current.LastRun, current.LastRequestIDs, current.LastError
+	schedule.NextRun = schedule.next(time.Now())
+	*current = schedule
+	if err := s.saveLocked(); err != nil {
+		*current = previous
+		return Schedule{}, fmt.Errorf("failed to save schedules: %w", err)
+	}
+	return schedule, nil
+}
+
+// Delete removes a schedule; requests it already submitted are not affected
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.schedules, id)
+	return s.saveLocked()
+}
+
+// saveLocked writes schedules to disk. Caller must hold the lock.
+func (s *Scheduler) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	schedules := make([]*Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
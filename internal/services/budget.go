@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrBudgetExceeded is returned when the estimated LLM spend has reached the daily or
+// monthly budget
+var ErrBudgetExceeded = errors.New("spend budget exceeded")
+
+// BudgetStatus reports the estimated LLM spend against the configured budgets
+type BudgetStatus struct {
+	DailyUSD        float64 `json:"daily_usd,omitempty"` // 0 = no daily limit
+	DailySpentUSD   float64 `json:"daily_spent_usd"`
+	MonthlyUSD      float64 `json:"monthly_usd,omitempty"` // 0 = no monthly limit
+	MonthlySpentUSD float64 `json:"monthly_spent_usd"`
+	Exceeded        bool    `json:"exceeded"`
+}
+
+// GetBudgetStatus returns today's and this month's spend, or nil if no budget is configured
+func (s *VideoSubmissionService) GetBudgetStatus() *BudgetStatus {
+	cfg := s.engine.GetConfig()
+	if cfg == nil || (cfg.Budget.DailyUSD <= 0 && cfg.Budget.MonthlyUSD <= 0) {
+		return nil
+	}
+	now := time.Now()
+	store := s.engine.GetStore()
+	status := &BudgetStatus{
+		DailyUSD:        cfg.Budget.DailyUSD,
+		DailySpentUSD:   store.GetSpendSince(now),
+		MonthlyUSD:      cfg.Budget.MonthlyUSD,
+		MonthlySpentUSD: store.GetSpendSince(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())),
+	}
+	status.Exceeded = (status.DailyUSD > 0 && status.DailySpentUSD >= status.DailyUSD) ||
+		(status.MonthlyUSD > 0 && status.MonthlySpentUSD >= status.MonthlyUSD)
+	return status
+}
+
+// checkBudgetUnlessOverridden checks the budget unless the submission overrides it
+func (s *VideoSubmissionService) checkBudgetUnlessOverridden(opts SubmitOptions) error {
+	err := s.checkBudget()
+	if err != nil && opts.OverrideBudget {
+		log.Warnf("Submitting despite exceeded budget (override by %q): %v", opts.User, err)
+		return nil
+	}
+	return err
+}
+
+// checkBudget returns ErrBudgetExceeded once today's or this month's spend reaches its budget
+func (s *VideoSubmissionService) checkBudget() error {
+	status := s.GetBudgetStatus()
+	if status == nil || !status.Exceeded {
+		return nil
+	}
+	if status.DailyUSD > 0 && status.DailySpentUSD >= status.DailyUSD {
+		return fmt.Errorf("%w: spent $%.2f today (daily budget $%.2f)", ErrBudgetExceeded, status.DailySpentUSD, status.DailyUSD)
+	}
+	return fmt.Errorf("%w: spent $%.2f this month (monthly budget $%.2f)", ErrBudgetExceeded, status.MonthlySpentUSD, status.MonthlyUSD)
+}
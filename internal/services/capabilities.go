@@ -0,0 +1,115 @@
+package services
+
+import (
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/transcription"
+	"video-summarizer-go/internal/providers/video"
+)
+
+// Capabilities describes what this deployment can do, so clients can offer only the options
+// it supports
+type Capabilities struct {
+	SourceTypes   []string                  `json:"source_types"`
+	VideoProvider string                    `json:"video_provider"`
+	Transcription TranscriptionCapabilities `json:"transcription"`
+	Summarization SummarizationCapabilities `json:"summarization"`
+	Outputs       OutputCapabilities        `json:"outputs"`
+	Limits        CapabilityLimits          `json:"limits"`
+}
+
+// TranscriptionCapabilities lists the transcription routes and whisper qualities requests
+// can use
+type TranscriptionCapabilities struct {
+	Provider   string   `json:"provider"`              // "whisper.cpp", "routing" (whisper.cpp or OpenAI by length) or "custom"
+	CloudModel string   `json:"cloud_model,omitempty"` // set when transcription_routing sends long videos to OpenAI
+	Language   string   `json:"language,omitempty"`
+	Qualities  []string `json:"qualities"` // whisper_quality values a request can ask for
+}
+
+// SummarizationCapabilities lists the summary models and formats requests can use
+type SummarizationCapabilities struct {
+	Provider       string         `json:"provider"`
+	Model          string         `json:"model,omitempty"`
+	Models         []string       `json:"models,omitempty"` // models a rerun can choose
+	MaxTokens      int            `json:"max_tokens,omitempty"`
+	MaxTokensLimit int            `json:"max_tokens_limit,omitempty"` // the model's output limit, when known
+	Streaming      bool           `json:"streaming"`
+	Lengths        map[string]int `json:"lengths"` // named summary lengths and the words they aim at
+	MinWords       int            `json:"min_words"`
+	MaxWords       int            `json:"max_words"`
+	Styles         []string       `json:"styles"`
+}
+
+// OutputCapabilities lists the output providers requests can send results to
+type OutputCapabilities struct {
+	Default   string   `json:"default,omitempty"`
+	Providers []string `json:"providers"`
+}
+
+// CapabilityLimits are the limits submissions are checked against (0 = no limit)
+type CapabilityLimits struct {
+	MaxActiveRequests        int `json:"max_active_requests"`
+	MaxActiveRequestsPerUser int `json:"max_active_requests_per_user"`
+	MaxRequestsPerDayPerUser int `json:"max_requests_per_day_per_user"`
+	DocumentMaxSizeMB        int `json:"document_max_size_mb"`
+}
+
+// GetCapabilities returns the providers, source types and limits of the running configuration
+func (s *VideoSubmissionService) GetCapabilities() *Capabilities {
+	cfg := s.engine.GetConfig()
+	if cfg == nil {
+		cfg = &config.AppConfig{}
+	}
+
+	caps := &Capabilities{
+		SourceTypes:   s.engine.GetPipelineRegistry().SourceTypes(),
+		VideoProvider: "custom",
+		Transcription: TranscriptionCapabilities{
+			Provider:  "custom",
+			Language:  cfg.WhisperLanguage,
+			Qualities: []string{config.WhisperQualityAuto, config.WhisperQualityFast, config.WhisperQualityAccurate},
+		},
+		Summarization: SummarizationCapabilities{
+			Provider:       cfg.SummarizerProvider,
+			MaxTokens:      cfg.OpenAIMaxTokens,
+			MaxTokensLimit: cfg.MaxTokensLimit(),
+			Lengths:        interfaces.SummaryLengthWords,
+			MinWords:       interfaces.MinSummaryWords,
+			MaxWords:       interfaces.MaxSummaryWords,
+			Styles:         []string{interfaces.SummaryStyleBullets, interfaces.SummaryStyleProse},
+		},
+		Outputs: OutputCapabilities{
+			Default:   cfg.OutputProvider,
+			Providers: s.engine.GetOutputProviderNames(),
+		},
+		Limits: CapabilityLimits{
+			MaxActiveRequests: cfg.MaxActiveRequests,
+			DocumentMaxSizeMB: cfg.DocumentMaxSizeMB,
+		},
+	}
+	if _, ok := s.engine.GetVideoProvider().(*video.YtDlpVideoProvider); ok {
+		caps.VideoProvider = "yt-dlp"
+	}
+	switch s.engine.GetTranscriptionProvider().(type) {
+	case *transcription.WhisperCppTranscriptionProvider:
+		caps.Transcription.Provider = "whisper.cpp"
+	case *transcription.RoutingTranscriptionProvider:
+		caps.Transcription.Provider = "routing"
+		caps.Transcription.CloudModel = cfg.TranscriptionRouting.CloudModel
+	}
+	for _, model := range cfg.WhisperModels.Models {
+		caps.Transcription.Qualities = append(caps.Transcription.Qualities, model.Name)
+	}
+	if cfg.SummarizerProvider == "openai" {
+		caps.Summarization.Model = cfg.OpenAIModel
+		caps.Summarization.Models = append([]string{cfg.OpenAIModel}, cfg.OpenAIRerunModels...)
+		caps.Summarization.Streaming = cfg.SummaryStreaming
+	}
+
+	s.mu.RLock()
+	caps.Limits.MaxActiveRequestsPerUser = s.maxActivePerUser
+	caps.Limits.MaxRequestsPerDayPerUser = s.maxPerDayPerUser
+	s.mu.RUnlock()
+	return caps
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// SetSourceLimits sets how many requests of each background source may be pending or running
+// at once. A source's further submissions are held in scheduled status, and started as its
+// earlier requests finish.
+func (s *VideoSubmissionService) SetSourceLimits(limits map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sourceLimits = limits
+}
+
+// sourceAtLimit reports whether a source already has as many pending or running requests as
+// its limit allows
+func (s *VideoSubmissionService) sourceAtLimit(source string) (bool, error) {
+	s.mu.RLock()
+	limit := s.sourceLimits[source]
+	s.mu.RUnlock()
+	if source == "" || limit <= 0 {
+		return false, nil
+	}
+	active, err := s.activeBySource()
+	if err != nil {
+		return false, err
+	}
+	return active[source] >= limit, nil
+}
+
+// activeBySource counts the pending and running requests of each background source
+func (s *VideoSubmissionService) activeBySource() (map[string]int, error) {
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active requests: %w", err)
+	}
+	active := make(map[string]int)
+	for _, state := range states {
+		if state.Source != "" && (state.Status == interfaces.StatusPending || state.Status == interfaces.StatusRunning) {
+			active[state.Source]++
+		}
+	}
+	return active, nil
+}
+
+// SourceAllowance returns how many more videos a background source may submit now. It
+// returns ErrAtCapacity when the engine is at max_active_requests, ErrBudgetExceeded when
+// the spend budget is used up, and ErrSourceCapReached when the source submitted maxPerDay
+// videos in the last 24 hours. maxPerDay 0 means no
+// daily cap, reported as -1.
+func (s *VideoSubmissionService) SourceAllowance(source string, maxPerDay int) (int, error) {
+	if s.IsDraining() {
+		return 0, ErrDraining
+	}
+	if err := s.engine.CheckCapacity(1); err != nil {
+		return 0, err
+	}
+	if err := s.checkBudget(); err != nil {
+		return 0, err
+	}
+	if maxPerDay <= 0 {
+		return -1, nil
+	}
+
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count submissions: %w", err)
+	}
+	submitted := 0
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	for _, state := range states {
+		if state.Source == source && state.CreatedAt.After(dayAgo) {
+			submitted++
+		}
+	}
+	if submitted >= maxPerDay {
+		return 0, fmt.Errorf("%w: %s submitted %d videos in the last 24 hours (limit %d)", ErrSourceCapReached, source, submitted, maxPerDay)
+	}
+	return maxPerDay - submitted, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ErrInvalidCursor is returned for a since_cursor that wasn't made by CompletedSince
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// completedSettleDelay holds back requests that completed this recently, so a request that
+// completes while another is being stored can't be passed over by a cursor already past it
+const completedSettleDelay = 2 * time.Second
+
+// CompletedItem is one completion of a request, with its summary. ID is unique to the
+// completion, so a request completed again after a retry or rerun is a new item.
+type CompletedItem struct {
+	ID          string                  `json:"id"`
+	RequestID   string                  `json:"request_id"`
+	URL         string                  `json:"url"`
+	Title       string                  `json:"title,omitempty"`
+	Channel     string                  `json:"channel,omitempty"`
+	Category    string                  `json:"category"`
+	User        string                  `json:"user,omitempty"`
+	Source      string                  `json:"source,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Metadata    map[string]string       `json:"metadata,omitempty"`
+	CompletedAt time.Time               `json:"completed_at"`
+	Summary     string                  `json:"summary"`
+	OutputPath  string                  `json:"output_path,omitempty"`
+	Outputs     []interfaces.OutputFile `json:"outputs,omitempty"`
+}
+
+// CompletedPage is a page of completed requests, oldest completion first. NextCursor is
+// passed as the cursor of the next poll; it is the cursor polled with when the page is empty.
+type CompletedPage struct {
+	Items      []CompletedItem `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// CompletedFilter narrows the completed requests by category, user or source; empty fields
+// match every request
+type CompletedFilter struct {
+	Category string
+	User     string
+	Source   string
+}
+
+// completedCursor is a position in completion order: the completion time, with the request
+// ID breaking ties
+type completedCursor struct {
+	at        time.Time
+	requestID string
+}
+
+func (c completedCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.at.UnixNano(), 10) + "." + c.requestID))
+}
+
+func (c completedCursor) before(other completedCursor) bool {
+	if !c.at.Equal(other.at) {
+		return c.at.Before(other.at)
+	}
+	return c.requestID < other.requestID
+}
+
+func parseCompletedCursor(cursor string) (completedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return completedCursor{}, fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+	}
+	nanos, requestID, ok := strings.Cut(string(raw), ".")
+	at, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || err != nil || requestID == "" {
+		return completedCursor{}, fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+	}
+	return completedCursor{at: time.Unix(0, at), requestID: requestID}, nil
+}
+
+// CompletedSince returns up to limit requests with a summary that completed after the
+// cursor, or from the oldest kept when the cursor is "". Requests are only listed while
+// the store keeps them.
+func (s *VideoSubmissionService) CompletedSince(cursor string, filter CompletedFilter, limit int) (*CompletedPage, error) {
+	var since *completedCursor
+	if cursor != "" {
+		parsed, err := parseCompletedCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		since = &parsed
+	}
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	settled := time.Now().Add(-completedSettleDelay)
+	type completion struct {
+		cursor completedCursor
+		state  *interfaces.ProcessingState
+	}
+	var completions []completion
+	for _, state := range states {
+		if state.Status != interfaces.StatusCompleted || state.SummaryText == "" || state.CompletedAt == nil || state.CompletedAt.After(settled) {
+			continue
+		}
+		if (filter.Category != "" && state.Category != filter.Category) ||
+			(filter.User != "" && state.User != filter.User) ||
+			(filter.Source != "" && state.Source != filter.Source) {
+			continue
+		}
+		position := completedCursor{at: *state.CompletedAt, requestID: state.RequestID}
+		if since != nil && !since.before(position) {
+			continue
+		}
+		completions = append(completions, completion{position, state})
+	}
+	sort.Slice(completions, func(i, j int) bool { return completions[i].cursor.before(completions[j].cursor) })
+
+	page := &CompletedPage{Items: []CompletedItem{}, NextCursor: cursor}
+	if limit > 0 && len(completions) > limit {
+		completions = completions[:limit]
+		page.HasMore = true
+	}
+	for _, c := range completions {
+		state := c.state
+		channel, _ := state.VideoInfo["channel"].(string)
+		page.Items = append(page.Items, CompletedItem{
+			ID:          c.cursor.String(),
+			RequestID:   state.RequestID,
+			URL:         state.URL,
+			Title:       state.Title(),
+			Channel:     channel,
+			Category:    state.Category,
+			User:        state.User,
+			Source:      state.Source,
+			Tags:        state.Tags,
+			Metadata:    state.Metadata,
+			CompletedAt: *state.CompletedAt,
+			Summary:     state.SummaryText,
+			OutputPath:  state.OutputPath,
+			Outputs:     state.Outputs,
+		})
+		page.NextCursor = c.cursor.String()
+	}
+	return page, nil
+}
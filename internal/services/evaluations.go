@@ -0,0 +1,94 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// evaluationRecentWindow is how many of a group's latest evaluations make up its recent score
+const evaluationRecentWindow = 10
+
+// EvaluationStats aggregates the summary evaluations of one prompt and summarizer
+type EvaluationStats struct {
+	Prompt           string  `json:"prompt"` // prompt ID, or "custom" for prompt text
+	Summarizer       string  `json:"summarizer"`
+	Count            int     `json:"count"`
+	MeanScore        float64 `json:"mean_score"`
+	MeanCoverage     float64 `json:"mean_coverage"`
+	MeanFaithfulness float64 `json:"mean_faithfulness"`
+	MeanLength       float64 `json:"mean_length"`
+	// Mean score of the latest evaluations; the group has regressed when it is below min_score
+	RecentMeanScore float64   `json:"recent_mean_score"`
+	Regressed       bool      `json:"regressed"`
+	BelowThreshold  int       `json:"below_threshold"` // evaluations scoring below min_score
+	LastEvaluatedAt time.Time `json:"last_evaluated_at"`
+}
+
+// EvaluationStats groups the evaluations made since from (zero for all) by prompt and
+// summarizer, lowest recent score first
+func (s *VideoSubmissionService) EvaluationStats(from time.Time) ([]EvaluationStats, error) {
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct{ prompt, summarizer string }
+	groups := make(map[groupKey][]*interfaces.SummaryEvaluation)
+	for _, state := range states {
+		evaluation := state.Evaluation
+		if evaluation == nil || evaluation.EvaluatedAt.Before(from) {
+			continue
+		}
+		key := groupKey{prompt: "custom", summarizer: evaluation.Summarizer}
+		if state.Prompt.Type == interfaces.PromptTypeID {
+			key.prompt = state.Prompt.Prompt
+		}
+		groups[key] = append(groups[key], evaluation)
+	}
+
+	minScore := 0.0
+	if cfg := s.engine.GetConfig(); cfg != nil {
+		minScore = cfg.Evaluation.MinScore
+	}
+	stats := []EvaluationStats{}
+	for key, evaluations := range groups {
+		sort.Slice(evaluations, func(i, j int) bool {
+			return evaluations[i].EvaluatedAt.Before(evaluations[j].EvaluatedAt)
+		})
+		group := EvaluationStats{Prompt: key.prompt, Summarizer: key.summarizer, Count: len(evaluations)}
+		for i, evaluation := range evaluations {
+			group.MeanScore += evaluation.Score
+			group.MeanCoverage += evaluation.Coverage
+			group.MeanFaithfulness += evaluation.Faithfulness
+			group.MeanLength += evaluation.Length
+			if evaluation.BelowThreshold {
+				group.BelowThreshold++
+			}
+			if i >= len(evaluations)-evaluationRecentWindow {
+				group.RecentMeanScore += evaluation.Score
+			}
+		}
+		n := float64(len(evaluations))
+		group.MeanScore /= n
+		group.MeanCoverage /= n
+		group.MeanFaithfulness /= n
+		group.MeanLength /= n
+		if len(evaluations) < evaluationRecentWindow {
+			group.RecentMeanScore /= n
+		} else {
+			group.RecentMeanScore /= evaluationRecentWindow
+		}
+		group.Regressed = group.RecentMeanScore < minScore
+		group.LastEvaluatedAt = evaluations[len(evaluations)-1].EvaluatedAt
+		stats = append(stats, group)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].RecentMeanScore != stats[j].RecentMeanScore {
+			return stats[i].RecentMeanScore < stats[j].RecentMeanScore
+		}
+		return stats[i].Prompt+stats[i].Summarizer < stats[j].Prompt+stats[j].Summarizer
+	})
+	return stats, nil
+}
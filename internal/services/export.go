@@ -0,0 +1,113 @@
+package services
+
+import (
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ExportRecord is one request in an export
+type ExportRecord struct {
+	RequestID        string     `json:"request_id"`
+	URL              string     `json:"url"`
+	Title            string     `json:"title,omitempty"`
+	Channel          string     `json:"channel,omitempty"`
+	Category         string     `json:"category"`
+	SourceType       string     `json:"source_type"`
+	PromptType       string     `json:"prompt_type"`
+	Prompt           string     `json:"prompt"`
+	Status           string     `json:"status"`
+	Error            string     `json:"error,omitempty"`
+	ErrorCode        string     `json:"error_code,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	DurationSeconds  float64    `json:"duration_seconds,omitempty"`
+	OutputPath       string     `json:"output_path,omitempty"`
+	Summary          string     `json:"summary,omitempty"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens"`
+	TotalTokens      int        `json:"total_tokens"`
+	CostUSD          float64    `json:"cost_usd"`
+	// Transcript confidence score (0-1), when the transcription provider reports one
+	TranscriptScore         *float64 `json:"transcript_score,omitempty"`
+	TranscriptLowConfidence bool     `json:"transcript_low_confidence,omitempty"`
+	// Summary evaluation score (1-5) and the summarizer it was given for, when evaluated
+	EvaluationScore *float64 `json:"evaluation_score,omitempty"`
+	Summarizer      string   `json:"summarizer,omitempty"`
+}
+
+// ExportFilter selects the requests to export. Zero times leave that end of the range open;
+// an empty status exports requests in any status.
+type ExportFilter struct {
+	From   time.Time
+	To     time.Time
+	Status interfaces.ProcessingStatus
+}
+
+// ExportRequests returns the requests created within the filter's range, oldest first
+func (s *VideoSubmissionService) ExportRequests(filter ExportFilter) ([]ExportRecord, error) {
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []ExportRecord{}
+	for _, state := range states {
+		if filter.Status != "" && state.Status != filter.Status {
+			continue
+		}
+		if !filter.From.IsZero() && state.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !state.CreatedAt.Before(filter.To) {
+			continue
+		}
+		records = append(records, newExportRecord(state))
+	}
+	return records, nil
+}
+
+// newExportRecord flattens a request state into an export record
+func newExportRecord(state *interfaces.ProcessingState) ExportRecord {
+	record := ExportRecord{
+		RequestID:   state.RequestID,
+		URL:         state.URL,
+		Title:       state.Title(),
+		Category:    state.Category,
+		SourceType:  state.SourceType,
+		PromptType:  string(state.Prompt.Type),
+		Prompt:      state.Prompt.Prompt,
+		Status:      string(state.Status),
+		Error:       state.Error,
+		ErrorCode:   string(state.ErrorCode),
+		CreatedAt:   state.CreatedAt,
+		CompletedAt: state.CompletedAt,
+		OutputPath:  state.OutputPath,
+		Summary:     state.SummaryText,
+	}
+	if channel, ok := state.VideoInfo["channel"].(string); ok {
+		record.Channel = channel
+	} else if uploader, ok := state.VideoInfo["uploader"].(string); ok {
+		record.Channel = uploader
+	}
+	if duration, ok := state.VideoInfo["duration"].(float64); ok {
+		record.DurationSeconds = duration
+	}
+	if state.TokenUsage != nil {
+		record.PromptTokens = state.TokenUsage.PromptTokens
+		record.CompletionTokens = state.TokenUsage.CompletionTokens
+		record.TotalTokens = state.TokenUsage.TotalTokens
+		record.CostUSD = state.TokenUsage.CostUSD
+	}
+	if state.TranscriptQuality != nil {
+		score := state.TranscriptQuality.Score
+		record.TranscriptScore = &score
+		record.TranscriptLowConfidence = state.TranscriptQuality.LowConfidence
+	}
+	if state.Evaluation != nil {
+		score := state.Evaluation.Score
+		record.EvaluationScore = &score
+		record.Summarizer = state.Evaluation.Summarizer
+	}
+	return record
+}
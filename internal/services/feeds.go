@@ -0,0 +1,32 @@
+package services
+
+import (
+	"sort"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// RecentSummaries returns up to limit completed requests with a summary, newest first.
+// An empty category includes every category.
+func (s *VideoSubmissionService) RecentSummaries(category string, limit int) ([]*interfaces.ProcessingState, error) {
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []*interfaces.ProcessingState
+	for _, state := range states {
+		if state.Status != interfaces.StatusCompleted || state.SummaryText == "" || state.CompletedAt == nil {
+			continue
+		}
+		if category != "" && state.Category != category {
+			continue
+		}
+		summaries = append(summaries, state)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CompletedAt.After(*summaries[j].CompletedAt) })
+	if limit > 0 && len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	return summaries, nil
+}
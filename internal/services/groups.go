@@ -0,0 +1,29 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// groupPattern limits group IDs to characters that are safe in a URL path
+var groupPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,127}$`)
+
+// ValidateGroup checks a submission's group ID; "" means no group
+func (s *VideoSubmissionService) ValidateGroup(group string) error {
+	if group != "" && !groupPattern.MatchString(group) {
+		return fmt.Errorf("invalid group %q: use up to 128 letters, digits, '.', '_', ':' or '-', starting with a letter or digit", group)
+	}
+	return nil
+}
+
+// GetGroupStatus sums up the requests submitted in a group, or returns nil if it has none
+func (s *VideoSubmissionService) GetGroupStatus(group string) (*interfaces.GroupStatus, error) {
+	return s.engine.GetGroupStatus(group)
+}
+
+// CancelGroup cancels the group's requests that haven't finished, returning their IDs
+func (s *VideoSubmissionService) CancelGroup(group string) ([]string, error) {
+	return s.engine.CancelGroup(group)
+}
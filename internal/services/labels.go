@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// Limits on caller-supplied tags and metadata, to keep request state small
+const (
+	maxTags          = 20
+	maxTagLength     = 64
+	maxMetadataKeys  = 32
+	maxMetadataKey   = 64
+	maxMetadataValue = 1024
+)
+
+// normalizeTags trims, lowercases and de-duplicates tags, keeping their order
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("too many tags: %d (limit %d)", len(normalized), maxTags)
+	}
+	return normalized, nil
+}
+
+// validateMetadata checks the size of caller-supplied metadata
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("too many metadata keys: %d (limit %d)", len(metadata), maxMetadataKeys)
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "" || len(key) > maxMetadataKey {
+			return fmt.Errorf("metadata key %q must be 1 to %d characters", key, maxMetadataKey)
+		}
+		if len(metadata[key]) > maxMetadataValue {
+			return fmt.Errorf("metadata value for %q is longer than %d characters", key, maxMetadataValue)
+		}
+	}
+	return nil
+}
+
+// matchesLabels reports whether a request has every tag and metadata value given
+func matchesLabels(state *interfaces.ProcessingState, tags []string, metadata map[string]string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, have := range state.Tags {
+			if have == strings.ToLower(tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range metadata {
+		if have, ok := state.Metadata[key]; !ok || have != value {
+			return false
+		}
+	}
+	return true
+}
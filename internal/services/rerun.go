@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// RerunRequest creates a request that summarizes an earlier request's transcript again with
+// another prompt, model, summary length or style, skipping download and transcription. An
+// empty prompt keeps the original's. The rerun inherits the original's category, labels and
+// settings, and its user, output, priority, summary length and style unless opts sets them. It records the original's ID in rerun_of.
+func (s *VideoSubmissionService) RerunRequest(requestID string, prompt interfaces.Prompt, model string, opts SubmitOptions) (string, error) {
+	if s.IsDraining() {
+		return "", ErrDraining
+	}
+	original, err := s.engine.GetRequestState(requestID)
+	if err != nil {
+		return "", fmt.Errorf("request not found: %s", requestID)
+	}
+	if original.SourceType == interfaces.SourceTypeComparison {
+		return "", fmt.Errorf("request %s is a comparison; submit a new comparison instead", requestID)
+	}
+	if prompt.Prompt == "" && model == "" && opts.SummaryLength == "" && opts.SummaryStyle == "" {
+		return "", fmt.Errorf("a prompt, model, summary length or style is required")
+	}
+	if prompt.Prompt == "" {
+		prompt = original.Prompt
+	}
+	if cfg := s.engine.GetConfig(); cfg != nil {
+		if err := cfg.CheckRerunModel(model); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.User == "" {
+		opts.User = original.User
+	}
+	if opts.Output == nil {
+		opts.Output = original.Output
+	}
+	if opts.SummaryLength == "" {
+		opts.SummaryLength = original.SummaryLength
+	}
+	if opts.SummaryStyle == "" {
+		opts.SummaryStyle = original.SummaryStyle
+	}
+	if err := s.ValidateSummaryFormat(opts.SummaryLength, opts.SummaryStyle); err != nil {
+		return "", err
+	}
+	if err := s.ValidateOutputTarget(opts.Output); err != nil {
+		return "", err
+	}
+	if err := s.engine.CheckCapacity(1); err != nil {
+		return "", err
+	}
+	if err := s.checkUserQuota(opts.User, 1); err != nil {
+		return "", err
+	}
+	if err := s.checkBudgetUnlessOverridden(opts); err != nil {
+		return "", err
+	}
+	priority := original.Priority
+	if opts.Priority != "" {
+		if priority, err = opts.priority(); err != nil {
+			return "", err
+		}
+	}
+
+	transcriptPath, err := s.engine.CopyTranscript(original)
+	if err != nil {
+		return "", err
+	}
+	state := &interfaces.ProcessingState{
+		RequestID:         fmt.Sprintf("req-%d", time.Now().UnixNano()),
+		Status:            interfaces.StatusPending,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+		SourceType:        original.SourceType,
+		URL:               original.URL,
+		Prompt:            prompt,
+		MaxTokens:         original.MaxTokens,
+		Category:          original.Category,
+		User:              opts.User,
+		Source:            original.Source,
+		Output:            opts.Output,
+		Tags:              slices.Clone(original.Tags),
+		Metadata:          maps.Clone(original.Metadata),
+		Priority:          priority,
+		VideoInfo:         original.VideoInfo,
+		DocumentInfo:      original.DocumentInfo,
+		WhisperModel:      original.WhisperModel,
+		TranscriptQuality: original.TranscriptQuality,
+		Language:          original.Language,
+		LanguageMode:      original.LanguageMode,
+		Citations:         original.Citations,
+		SummaryLength:     opts.SummaryLength,
+		SummaryStyle:      opts.SummaryStyle,
+		RerunOf:           original.RequestID,
+		SummaryModel:      model,
+	}
+	if s.engine.GetPipelineRegistry().Includes(state.SourceType, interfaces.TaskTranscription) {
+		state.Transcript = transcriptPath
+	} else {
+		state.TextPath = transcriptPath
+	}
+	if err := s.engine.StartPreparedRequest(state); err != nil {
+		return "", fmt.Errorf("failed to start rerun: %w", err)
+	}
+	log.WithFields(log.Fields{"requestID": state.RequestID, "rerunOf": requestID, "model": model}).Info("RerunRequest created new request")
+	return state.RequestID, nil
+}
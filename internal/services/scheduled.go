@@ -0,0 +1,93 @@
+package services
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// scheduledPollInterval is how often scheduled requests are checked for release
+const scheduledPollInterval = 10 * time.Second
+
+// StartScheduler starts scheduled requests once their not-before time has passed, until
+// StopScheduler is called
+func (s *VideoSubmissionService) StartScheduler() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.schedulerStop != nil {
+		return
+	}
+	s.schedulerStop = make(chan struct{})
+	s.schedulerWG.Add(1)
+	go s.runScheduler(s.schedulerStop)
+}
+
+// StopScheduler stops releasing scheduled requests; they stay scheduled
+func (s *VideoSubmissionService) StopScheduler() {
+	s.mu.Lock()
+	stopChan := s.schedulerStop
+	s.schedulerStop = nil
+	s.mu.Unlock()
+	if stopChan != nil {
+		close(stopChan)
+		s.schedulerWG.Wait()
+	}
+}
+
+func (s *VideoSubmissionService) runScheduler(stopChan chan struct{}) {
+	defer s.schedulerWG.Done()
+	ticker := time.NewTicker(scheduledPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			s.ReleaseScheduled(time.Now())
+		}
+	}
+}
+
+// ReleaseScheduled starts the scheduled requests that are due at now, earliest first, and
+// returns how many it started. Nothing is released while draining, and requests that don't
+// fit under max_active_requests or their source's limit wait for the next check.
+func (s *VideoSubmissionService) ReleaseScheduled(now time.Time) int {
+	if s.IsDraining() {
+		return 0
+	}
+	due, err := s.engine.DueScheduledRequests(now)
+	if err != nil {
+		log.Errorf("Failed to check scheduled requests: %v", err)
+		return 0
+	}
+	s.mu.RLock()
+	limits := s.sourceLimits
+	s.mu.RUnlock()
+	active := make(map[string]int)
+	if len(limits) > 0 && len(due) > 0 {
+		if active, err = s.activeBySource(); err != nil {
+			log.Errorf("Failed to check scheduled requests: %v", err)
+			return 0
+		}
+	}
+	released := 0
+	for _, state := range due {
+		if limit := limits[state.Source]; state.Source != "" && limit > 0 && active[state.Source] >= limit {
+			continue
+		}
+		if err := s.engine.CheckCapacity(1); err != nil {
+			log.Infof("Scheduled requests are due but waiting: %v", err)
+			break
+		}
+		started, err := s.engine.ReleaseScheduledRequest(state.RequestID)
+		if err != nil {
+			log.Errorf("Failed to start scheduled request %s: %v", state.RequestID, err)
+			continue
+		}
+		if started {
+			released++
+			active[state.Source]++
+		}
+	}
+	return released
+}
@@ -1,21 +1,54 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"video-summarizer-go/internal/core"
 	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/providers/summarization"
+	"video-summarizer-go/internal/providers/video"
 )
 
+// ErrDraining is returned for submissions made while the service is draining for shutdown
+var ErrDraining = errors.New("service is draining and not accepting new requests")
+
+// ErrAtCapacity is returned for submissions made while the engine is at max_active_requests
+var ErrAtCapacity = core.ErrAtCapacity
+
+// ErrSourceCapReached is returned when a background source has reached its daily submission cap
+var ErrSourceCapReached = errors.New("source reached its daily submission cap")
+
+// ErrDocumentTooLarge is returned for uploaded documents above the configured size limit
+var ErrDocumentTooLarge = errors.New("document is larger than the configured limit")
+
 // VideoSubmissionService provides a unified interface for submitting videos to the processing queue
 type VideoSubmissionService struct {
 	engine    *core.ProcessingEngine
 	mu        sync.RWMutex
 	requestID string
+	draining  atomic.Bool
+
+	// Releases scheduled requests when they are due
+	schedulerStop chan struct{}
+	schedulerWG   sync.WaitGroup
+
+	// Per-user quotas, 0 = no limit
+	maxActivePerUser int
+	maxPerDayPerUser int
+
+	// Pending or running requests allowed per background source (absent = no limit)
+	sourceLimits map[string]int
 }
 
 // NewVideoSubmissionService creates a new video submission service
@@ -25,26 +58,219 @@ func NewVideoSubmissionService(engine *core.ProcessingEngine) *VideoSubmissionSe
 	}
 }
 
+// DefaultMaxTokens submits a request whose summary token limit is its prompt's max_tokens,
+// its category's under max_tokens_by_category, or openai_max_tokens
+const DefaultMaxTokens = 0
+
+// SubmitOptions carries optional per-request settings
+type SubmitOptions struct {
+	// User who submitted the request ("" for background sources and the CLI)
+	User string
+	// Source is the name of the background source that found the video
+	Source string
+	// Output overrides the configured output provider and destination
+	Output *interfaces.OutputTarget
+	// Tags and Metadata are caller-supplied labels stored with the request
+	Tags     []string
+	Metadata map[string]string
+	// Priority is "high", "normal" or "low"; "" is low for background sources and normal
+	// otherwise, so interactive submissions run ahead of bulk source traffic
+	Priority string
+	// LanguageMode overrides the configured prompt_language_mode ("" = use the configured mode)
+	LanguageMode string
+	// WhisperQuality names the whisper model to transcribe with, or is a quality hint
+	// ("fast", "accurate"); "" picks the model by video duration
+	WhisperQuality string
+	// Citations overrides the configured summary_citations (nil = use the configured default)
+	Citations *bool
+	// SummaryLength ("short", "medium", "detailed" or a word count) and SummaryStyle
+	// ("bullets" or "prose") shape the summary; "" leaves them to the prompt
+	SummaryLength string
+	SummaryStyle  string
+	// OverrideBudget submits the request even when the spend budget is exceeded. Background
+	// sources never set it, so they pause until the budget resets.
+	OverrideBudget bool
+	// NotBefore holds the request in scheduled status until this time (zero or a past time
+	// starts it now). Capacity is checked when it starts rather than on submission.
+	NotBefore time.Time
+	// NoDedup always creates a new request, even if the same video was already summarized
+	// with the same prompt, e.g. for a schedule that summarizes a URL again every week
+	NoDedup bool
+	// Group collects the request with others, e.g. one per playlist or source run, for
+	// group status and cancellation. A duplicate submission adds the existing request to it.
+	Group string
+}
+
+// priority resolves the request priority for the options
+func (opts SubmitOptions) priority() (interfaces.Priority, error) {
+	if opts.Priority == "" && opts.Source != "" {
+		return interfaces.PriorityLow, nil
+	}
+	return interfaces.ParsePriority(opts.Priority)
+}
+
+// ValidateOutputTarget checks that a requested output override can be honored
+func (s *VideoSubmissionService) ValidateOutputTarget(target *interfaces.OutputTarget) error {
+	if target == nil {
+		return nil
+	}
+	if target.Provider == "" {
+		return fmt.Errorf("output provider is required")
+	}
+	provider := s.engine.GetNamedOutputProvider(target.Provider)
+	if provider == nil {
+		return fmt.Errorf("output provider %q is not configured", target.Provider)
+	}
+	if target.Destination() != "" {
+		if _, ok := provider.(interfaces.DestinationOutputProvider); !ok {
+			return fmt.Errorf("output provider %q does not support a destination override", target.Provider)
+		}
+	}
+	return nil
+}
+
+// ValidateWhisperQuality checks that a requested whisper model or quality hint is known
+func (s *VideoSubmissionService) ValidateWhisperQuality(hint string) error {
+	cfg := s.engine.GetConfig()
+	if hint == "" || cfg == nil {
+		return nil
+	}
+	return cfg.CheckWhisperQuality(hint)
+}
+
 // SubmitVideo submits a single video for processing
 func (s *VideoSubmissionService) SubmitVideo(url string, prompt interfaces.Prompt, sourceType string, category string, maxTokens int) (string, error) {
+	return s.SubmitVideoWithOptions(url, prompt, sourceType, category, maxTokens, SubmitOptions{})
+}
+
+// ValidateSummaryFormat checks a requested summary length and style
+func (s *VideoSubmissionService) ValidateSummaryFormat(length, style string) error {
+	if _, err := interfaces.ParseSummaryLength(length); err != nil {
+		return err
+	}
+	return interfaces.CheckSummaryStyle(style)
+}
+
+// SubmitVideoWithOptions submits a single video for processing with per-request options
+func (s *VideoSubmissionService) SubmitVideoWithOptions(url string, prompt interfaces.Prompt, sourceType string, category string, maxTokens int, opts SubmitOptions) (string, error) {
+	if s.IsDraining() {
+		return "", ErrDraining
+	}
+	if err := s.ValidateOutputTarget(opts.Output); err != nil {
+		return "", err
+	}
+	if err := s.ValidateWhisperQuality(opts.WhisperQuality); err != nil {
+		return "", err
+	}
+	if err := s.ValidateGroup(opts.Group); err != nil {
+		return "", err
+	}
+	if err := s.ValidateSummaryFormat(opts.SummaryLength, opts.SummaryStyle); err != nil {
+		return "", err
+	}
+	scheduled := opts.NotBefore.After(time.Now())
+	held := false
+	if !scheduled {
+		var err error
+		if held, err = s.sourceAtLimit(opts.Source); err != nil {
+			return "", err
+		}
+	}
+	if !scheduled && !held {
+		if err := s.engine.CheckCapacity(1); err != nil {
+			return "", err
+		}
+	}
+	if err := s.checkUserQuota(opts.User, 1); err != nil {
+		return "", err
+	}
+	if err := s.checkBudgetUnlessOverridden(opts); err != nil {
+		return "", err
+	}
+	tags, err := normalizeTags(opts.Tags)
+	if err != nil {
+		return "", err
+	}
+	priority, err := opts.priority()
+	if err != nil {
+		return "", err
+	}
+	if err := validateMetadata(opts.Metadata); err != nil {
+		return "", err
+	}
+
 	model := "gpt-4o" // TODO: Make this configurable or pass as argument
 	dedupKey := core.MakeDedupKey(url, prompt.Prompt, model)
+	if opts.Output != nil {
+		// The same video sent somewhere else is a separate request
+		dedupKey += fmt.Sprintf("|%s:%s", opts.Output.Provider, opts.Output.Destination())
+	}
+	if opts.Citations != nil {
+		// and so is a summary with or without citations
+		dedupKey += fmt.Sprintf("|citations:%t", *opts.Citations)
+	}
+	if opts.SummaryLength != "" || opts.SummaryStyle != "" {
+		// and so is one of another length or style
+		dedupKey += fmt.Sprintf("|length:%s|style:%s", opts.SummaryLength, opts.SummaryStyle)
+	}
 
 	// Prepare the state for possible creation
 	requestID := fmt.Sprintf("req-%d", time.Now().UnixNano())
 	state := &interfaces.ProcessingState{
-		RequestID:  requestID,
-		Status:     interfaces.StatusPending,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-		SourceType: sourceType,
-		URL:        url,
-		Prompt:     prompt,
-		MaxTokens:  maxTokens,
-		Category:   category,
+		RequestID:      requestID,
+		Status:         interfaces.StatusPending,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		SourceType:     sourceType,
+		URL:            url,
+		Prompt:         prompt,
+		MaxTokens:      maxTokens,
+		Category:       category,
+		User:           opts.User,
+		Source:         opts.Source,
+		Output:         opts.Output,
+		Tags:           tags,
+		Metadata:       opts.Metadata,
+		Priority:       priority,
+		LanguageMode:   opts.LanguageMode,
+		WhisperQuality: opts.WhisperQuality,
+		Citations:      opts.Citations,
+		SummaryLength:  opts.SummaryLength,
+		SummaryStyle:   opts.SummaryStyle,
+	}
+	if opts.Group != "" {
+		state.Groups = []string{opts.Group}
+	}
+	if scheduled {
+		state.Status = interfaces.StatusScheduled
+		state.NotBefore = &opts.NotBefore
+	} else if held {
+		// Scheduled without a time: started once one of the source's requests finishes
+		state.Status = interfaces.StatusScheduled
+	}
+
+	// Background sources can treat the video as a duplicate whatever prompt it was summarized with
+	if cfg := s.engine.GetConfig(); opts.Source != "" && !opts.NoDedup && cfg != nil && cfg.SourceDedupAcrossPrompts {
+		existing, err := s.engine.FindVideoRequest(url)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil {
+			log.WithFields(log.Fields{
+				"url":       url,
+				"source":    opts.Source,
+				"requestID": existing.RequestID,
+				"prompt":    existing.Prompt.Prompt,
+			}).Info("Deduplication hit across prompts")
+			return existing.RequestID, s.addToGroup(existing.RequestID, opts.Group)
+		}
 	}
 
 	// Use the store's deduplication method
+	if opts.NoDedup {
+		// A key of its own makes the store create the request without matching an earlier one
+		dedupKey += "|" + requestID
+	}
 	id, alreadyExists, err := s.engine.GetStore().CreateOrGetDedupRequest(dedupKey, state)
 	if err != nil {
 		return "", fmt.Errorf("failed to create or get dedup request: %w", err)
@@ -54,11 +280,15 @@ func (s *VideoSubmissionService) SubmitVideo(url string, prompt interfaces.Promp
 			"dedupKey":  dedupKey,
 			"requestID": id,
 		}).Info("Deduplication hit")
-		return id, nil
+		return id, s.addToGroup(id, opts.Group)
 	}
 
-	// Start the request (stores state and publishes event)
-	err = s.engine.StartRequest(state.RequestID, state.URL, state.Prompt, state.SourceType, category, state.MaxTokens)
+	// Start the request (stores state and publishes event), or hold it until it's due
+	if scheduled || held {
+		err = s.engine.ScheduleRequest(state)
+	} else {
+		err = s.engine.StartPreparedRequest(state)
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to start request: %w", err)
 	}
@@ -74,15 +304,196 @@ func (s *VideoSubmissionService) SubmitVideo(url string, prompt interfaces.Promp
 	return state.RequestID, nil
 }
 
+// addToGroup adds an existing request to the submission's group, if it has one
+func (s *VideoSubmissionService) addToGroup(requestID, group string) error {
+	if group == "" {
+		return nil
+	}
+	if err := s.engine.AddToGroup(requestID, group); err != nil {
+		return fmt.Errorf("failed to add request %s to group %s: %w", requestID, group, err)
+	}
+	return nil
+}
+
+// MaxDocumentBytes returns the largest document that can be uploaded, or 0 if there is no limit
+func (s *VideoSubmissionService) MaxDocumentBytes() int64 {
+	cfg := s.engine.GetConfig()
+	if cfg == nil || cfg.DocumentMaxSizeMB <= 0 {
+		return 0
+	}
+	return int64(cfg.DocumentMaxSizeMB) * 1024 * 1024
+}
+
+// SubmitUploadedDocument saves an uploaded PDF or text file into the temp directory and
+// submits it to the document pipeline
+func (s *VideoSubmissionService) SubmitUploadedDocument(filename string, content io.Reader, prompt interfaces.Prompt, category string, maxTokens int, opts SubmitOptions) (string, error) {
+	if s.IsDraining() {
+		return "", ErrDraining
+	}
+	cfg := s.engine.GetConfig()
+	if cfg == nil {
+		return "", fmt.Errorf("document uploads are not configured")
+	}
+
+	f, err := os.CreateTemp(cfg.TmpDir, interfaces.UploadedDocumentPrefix+"*"+filepath.Ext(filename))
+	if err != nil {
+		return "", fmt.Errorf("failed to store document: %w", err)
+	}
+	maxBytes := s.MaxDocumentBytes()
+	if maxBytes > 0 {
+		// Read one byte past the limit so oversized uploads are detected
+		content = io.LimitReader(content, maxBytes+1)
+	}
+	written, err := io.Copy(f, content)
+	f.Close()
+	if err == nil && maxBytes > 0 && written > maxBytes {
+		err = ErrDocumentTooLarge
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		if errors.Is(err, ErrDocumentTooLarge) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to store document: %w", err)
+	}
+
+	requestID, err := s.SubmitVideoWithOptions(f.Name(), prompt, interfaces.SourceTypeDocument, category, maxTokens, opts)
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	log.WithFields(log.Fields{"filename": filename, "bytes": written, "requestID": requestID}).Info("Document uploaded")
+	return requestID, nil
+}
+
+// MaxComparedRequests bounds how many videos and past requests one comparison can include
+const MaxComparedRequests = 10
+
+// defaultComparisonPrompt is used when a comparison is submitted without a prompt
+const defaultComparisonPrompt = "Compare the sources below. Summarize what they agree on, where they disagree or " +
+	"contradict each other, and what only one of them covers. Refer to each source by its title."
+
+// SubmitComparison submits videos and/or selects past requests for one comparative summary.
+// New videos are summarized individually with the "general" prompt first; the comparison
+// prompt is then applied to all summaries together. It returns the comparison request ID
+// and the IDs of the requests being compared.
+func (s *VideoSubmissionService) SubmitComparison(urls []string, requestIDs []string, prompt interfaces.Prompt, category string, maxTokens int, opts SubmitOptions) (string, []string, error) {
+	if s.IsDraining() {
+		return "", nil, ErrDraining
+	}
+	total := len(urls) + len(requestIDs)
+	if total < 2 || total > MaxComparedRequests {
+		return "", nil, fmt.Errorf("a comparison needs between 2 and %d videos or requests, got %d", MaxComparedRequests, total)
+	}
+	if err := s.ValidateOutputTarget(opts.Output); err != nil {
+		return "", nil, err
+	}
+	if err := s.ValidateGroup(opts.Group); err != nil {
+		return "", nil, err
+	}
+	// The comparison counts against capacity and quota along with each new video
+	if err := s.engine.CheckCapacity(len(urls) + 1); err != nil {
+		return "", nil, err
+	}
+	if err := s.checkUserQuota(opts.User, len(urls)+1); err != nil {
+		return "", nil, err
+	}
+	if err := s.checkBudgetUnlessOverridden(opts); err != nil {
+		return "", nil, err
+	}
+	tags, err := normalizeTags(opts.Tags)
+	if err != nil {
+		return "", nil, err
+	}
+	priority, err := opts.priority()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validateMetadata(opts.Metadata); err != nil {
+		return "", nil, err
+	}
+	for _, id := range requestIDs {
+		state, err := s.engine.GetRequestState(id)
+		if err != nil {
+			return "", nil, fmt.Errorf("request not found: %s", id)
+		}
+		if state.SourceType == interfaces.SourceTypeComparison {
+			return "", nil, fmt.Errorf("request %s is itself a comparison", id)
+		}
+	}
+
+	childIDs := append([]string(nil), requestIDs...)
+	childPrompt := interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: "general"}
+	// Only the comparison itself goes to the requested output
+	childOpts := SubmitOptions{User: opts.User, Source: opts.Source, Tags: tags, Metadata: opts.Metadata, Priority: priority.String(), OverrideBudget: opts.OverrideBudget}
+	for _, url := range urls {
+		id, err := s.SubmitVideoWithOptions(url, childPrompt, interfaces.SourceTypeVideo, category, maxTokens, childOpts)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to submit %s: %w", url, err)
+		}
+		childIDs = append(childIDs, id)
+	}
+
+	if prompt.Prompt == "" {
+		prompt = interfaces.Prompt{Type: interfaces.PromptTypeText, Prompt: defaultComparisonPrompt}
+	}
+	requestID := fmt.Sprintf("req-%d", time.Now().UnixNano())
+	state := &interfaces.ProcessingState{
+		RequestID:  requestID,
+		Status:     interfaces.StatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		SourceType: interfaces.SourceTypeComparison,
+		URL:        "comparison://" + requestID,
+		Prompt:     prompt,
+		MaxTokens:  maxTokens,
+		Category:   category,
+		User:       opts.User,
+		Source:     opts.Source,
+		Output:     opts.Output,
+		Tags:       tags,
+		Metadata:   opts.Metadata,
+		Priority:   priority,
+		ChildIDs:   childIDs,
+	}
+	if opts.Group != "" {
+		state.Groups = []string{opts.Group}
+	}
+	if err := s.engine.StartPreparedRequest(state); err != nil {
+		return "", nil, fmt.Errorf("failed to start comparison: %w", err)
+	}
+	log.WithFields(log.Fields{"requestID": requestID, "compared": childIDs}).Info("SubmitComparison created new request")
+	return requestID, childIDs, nil
+}
+
+// StartPreparedRequest starts a request whose state was built by the caller, such as a digest
+func (s *VideoSubmissionService) StartPreparedRequest(state *interfaces.ProcessingState) error {
+	if s.IsDraining() {
+		return ErrDraining
+	}
+	if err := s.engine.CheckCapacity(1); err != nil {
+		return err
+	}
+	if err := s.checkBudget(); err != nil {
+		return err
+	}
+	return s.engine.StartPreparedRequest(state)
+}
+
 // SubmitBatch submits multiple videos for processing
 func (s *VideoSubmissionService) SubmitBatch(urls []string, prompt interfaces.Prompt, sourceType, category string, maxTokens int) ([]string, error) {
+	return s.SubmitBatchWithOptions(urls, prompt, sourceType, category, maxTokens, SubmitOptions{})
+}
+
+// SubmitBatchWithOptions submits multiple videos for processing with per-request options
+func (s *VideoSubmissionService) SubmitBatchWithOptions(urls []string, prompt interfaces.Prompt, sourceType, category string, maxTokens int, opts SubmitOptions) ([]string, error) {
 	log.WithField("prompt", prompt).Info("SubmitBatch called")
 	var requestIDs []string
 	var errors []error
 
 	for _, url := range urls {
 		log.WithField("url", url).WithField("prompt", prompt).Info("Submitting url")
-		requestID, err := s.SubmitVideo(url, prompt, sourceType, category, maxTokens)
+		requestID, err := s.SubmitVideoWithOptions(url, prompt, sourceType, category, maxTokens, opts)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("failed to submit %s: %w", url, err))
 			continue
@@ -102,12 +513,158 @@ func (s *VideoSubmissionService) GetRequestStatus(requestID string) (*interfaces
 	return s.engine.GetRequestState(requestID)
 }
 
+// GetRequestStatuses returns the requests among requestIDs that exist, as of one moment
+func (s *VideoSubmissionService) GetRequestStatuses(requestIDs []string) map[string]*interfaces.ProcessingState {
+	return s.engine.GetRequestStates(requestIDs)
+}
+
 // CancelRequest cancels a processing request
 func (s *VideoSubmissionService) CancelRequest(requestID string) error {
 	return s.engine.CancelRequest(requestID)
 }
 
+// RetryRequest restarts a failed, cancelled or partially completed request
+func (s *VideoSubmissionService) RetryRequest(requestID string) error {
+	if s.IsDraining() {
+		return ErrDraining
+	}
+	return s.engine.RetryRequest(requestID)
+}
+
+// GetRequestEvents returns the stored events of a request, oldest first
+func (s *VideoSubmissionService) GetRequestEvents(requestID string) ([]interfaces.Event, error) {
+	return s.engine.GetRequestEvents(requestID)
+}
+
+// PurgeVideo removes every request for a video and the data derived from them
+func (s *VideoSubmissionService) PurgeVideo(videoURL string, deleteOutputs bool) (*core.PurgeReport, error) {
+	return s.engine.PurgeVideo(videoURL, deleteOutputs)
+}
+
+// PurgeUser removes every request a user submitted and the data derived from them
+func (s *VideoSubmissionService) PurgeUser(user string, deleteOutputs bool) (*core.PurgeReport, error) {
+	return s.engine.PurgeUser(user, deleteOutputs)
+}
+
+// ReplayRequest re-publishes a stored event of a request so processing continues from there
+func (s *VideoSubmissionService) ReplayRequest(requestID, fromEventID string, force bool) (*interfaces.Event, error) {
+	if s.IsDraining() {
+		return nil, ErrDraining
+	}
+	return s.engine.ReplayRequest(requestID, fromEventID, force)
+}
+
+// RebuildRequestState reconstructs a request's state from its stored events, writing the
+// fields that disagree back to the store with apply
+func (s *VideoSubmissionService) RebuildRequestState(requestID string, apply bool) (*core.StateRebuild, error) {
+	return s.engine.RebuildRequestState(requestID, apply)
+}
+
+// RebuildAllRequestStates rebuilds every request from its events, returning those whose
+// stored state disagrees
+func (s *VideoSubmissionService) RebuildAllRequestStates(apply bool) ([]*core.StateRebuild, error) {
+	return s.engine.RebuildAllRequestStates(apply)
+}
+
+// SearchRequests finds requests by keywords in their title, channel or summary
+func (s *VideoSubmissionService) SearchRequests(query string) []*interfaces.ProcessingState {
+	return s.engine.SearchRequests(query)
+}
+
 // GetRequestCountsByStatus returns a map of status to count
 func (s *VideoSubmissionService) GetRequestCountsByStatus() map[string]int {
 	return s.engine.GetRequestCountsByStatus()
 }
+
+// GetStoreStats reports the size of the state store
+func (s *VideoSubmissionService) GetStoreStats() interfaces.StoreStats {
+	return s.engine.GetStoreStats()
+}
+
+// GetVideoInfoCacheStats reports video info cache hits and misses
+func (s *VideoSubmissionService) GetVideoInfoCacheStats() *video.VideoInfoCacheStats {
+	return s.engine.GetVideoInfoCacheStats()
+}
+
+// GetLLMCacheStats reports summarization cache hits and the spend they saved
+func (s *VideoSubmissionService) GetLLMCacheStats() *summarization.SummaryCacheStats {
+	return s.engine.GetLLMCacheStats()
+}
+
+// GetTmpDirUsage reports disk usage of the temp directory
+func (s *VideoSubmissionService) GetTmpDirUsage() *core.TmpDirUsage {
+	return s.engine.GetTmpDirUsage()
+}
+
+// GetRunningTasks returns the tasks the workers are processing
+func (s *VideoSubmissionService) GetRunningTasks() []core.RunningTask {
+	return s.engine.GetRunningTasks()
+}
+
+// GetQueueLengths returns the number of queued tasks per task type
+func (s *VideoSubmissionService) GetQueueLengths() map[interfaces.TaskType]int {
+	return s.engine.GetQueueLengths()
+}
+
+// CheckDependencies verifies the external tools and directories the engine depends on
+func (s *VideoSubmissionService) CheckDependencies() []error {
+	cfg := s.engine.GetConfig()
+	if cfg == nil {
+		return nil
+	}
+	return cfg.CheckRuntimeDependencies()
+}
+
+// GetPreflightStatus returns the results of the startup self-test, or nil if it is disabled
+func (s *VideoSubmissionService) GetPreflightStatus() *core.PreflightStatus {
+	return s.engine.GetPreflightStatus()
+}
+
+// PauseProcessing holds queued tasks until ResumeProcessing is called
+func (s *VideoSubmissionService) PauseProcessing() {
+	s.engine.Pause()
+}
+
+// ResumeProcessing restarts processing of queued tasks
+func (s *VideoSubmissionService) ResumeProcessing() {
+	s.engine.Resume()
+}
+
+// IsProcessingPaused reports whether processing is paused
+func (s *VideoSubmissionService) IsProcessingPaused() bool {
+	return s.engine.IsPaused()
+}
+
+// StartDrain stops accepting new submissions; requests already in the pipeline keep running
+func (s *VideoSubmissionService) StartDrain() {
+	if !s.draining.Swap(true) {
+		log.Info("Draining: no longer accepting new submissions")
+	}
+}
+
+// IsDraining reports whether the service has stopped accepting submissions
+func (s *VideoSubmissionService) IsDraining() bool {
+	return s.draining.Load()
+}
+
+// WaitForIdle blocks until no requests are active or ctx is done.
+// It returns the number of requests still active.
+func (s *VideoSubmissionService) WaitForIdle(ctx context.Context) int {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		active, err := s.engine.GetStore().GetAllActiveRequests()
+		// Scheduled requests haven't started, so there's nothing to wait for
+		active = slices.DeleteFunc(active, func(state *interfaces.ProcessingState) bool {
+			return state.Status == interfaces.StatusScheduled
+		})
+		if err == nil && len(active) == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return len(active)
+		case <-ticker.C:
+		}
+	}
+}
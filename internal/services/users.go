@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"video-summarizer-go/internal/interfaces"
+)
+
+// ErrQuotaExceeded is returned when a user has reached their request quota
+var ErrQuotaExceeded = errors.New("request quota exceeded")
+
+// RequestFilter selects requests to list. Empty fields match every request.
+type RequestFilter struct {
+	User     string
+	Status   interfaces.ProcessingStatus
+	Tags     []string          // requests must have all of these tags
+	Metadata map[string]string // requests must have all of these metadata values
+	Group    string
+}
+
+// SetUserQuotas limits how many requests each user may have active (pending or running)
+// and submit per 24 hours. Zero disables a limit. Requests without a user are not limited.
+func (s *VideoSubmissionService) SetUserQuotas(maxActive, maxPerDay int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxActivePerUser = maxActive
+	s.maxPerDayPerUser = maxPerDay
+}
+
+// ListRequests returns the requests matching the filter, newest first
+func (s *VideoSubmissionService) ListRequests(filter RequestFilter) ([]*interfaces.ProcessingState, error) {
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*interfaces.ProcessingState
+	for _, state := range states {
+		if filter.User != "" && state.User != filter.User {
+			continue
+		}
+		if filter.Status != "" && state.Status != filter.Status {
+			continue
+		}
+		if filter.Group != "" && !state.InGroup(filter.Group) {
+			continue
+		}
+		if !matchesLabels(state, filter.Tags, filter.Metadata) {
+			continue
+		}
+		matched = append(matched, state)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+	return matched, nil
+}
+
+// checkUserQuota returns ErrQuotaExceeded if submitting n more requests would take the
+// user over a quota
+func (s *VideoSubmissionService) checkUserQuota(user string, n int) error {
+	s.mu.RLock()
+	maxActive, maxPerDay := s.maxActivePerUser, s.maxPerDayPerUser
+	s.mu.RUnlock()
+	if user == "" || (maxActive <= 0 && maxPerDay <= 0) {
+		return nil
+	}
+
+	states, err := s.engine.GetStore().ListRequests()
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	active, today := 0, 0
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	for _, state := range states {
+		if state.User != user {
+			continue
+		}
+		if state.Status == interfaces.StatusScheduled || state.Status == interfaces.StatusPending || state.Status == interfaces.StatusRunning {
+			active++
+		}
+		if state.CreatedAt.After(dayAgo) {
+			today++
+		}
+	}
+	if maxActive > 0 && active+n > maxActive {
+		return fmt.Errorf("%w: %s already has %d active request(s) (limit %d)", ErrQuotaExceeded, user, active, maxActive)
+	}
+	if maxPerDay > 0 && today+n > maxPerDay {
+		return fmt.Errorf("%w: %s submitted %d request(s) in the last 24 hours (limit %d)", ErrQuotaExceeded, user, today, maxPerDay)
+	}
+	return nil
+}
@@ -2,6 +2,10 @@ package sources
 
 import (
 	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
 	"video-summarizer-go/internal/config"
 )
 
@@ -9,6 +13,7 @@ import (
 type ArtifactSourceManager struct {
 	sources map[string]ArtifactSource
 	configs map[string]*config.SourceConfig
+	mu      sync.RWMutex
 }
 
 // NewArtifactSourceManager creates a new artifact source manager
@@ -21,12 +26,59 @@ func NewArtifactSourceManager() *ArtifactSourceManager {
 
 // AddSource adds a video source to the manager
 func (m *ArtifactSourceManager) AddSource(name string, source ArtifactSource, config *config.SourceConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.sources[name] = source
 	m.configs[name] = config
 }
 
+// LoadSources creates sources from configuration using the factory and adds them to the manager.
+// Disabled or invalid sources are logged and skipped.
+func (m *ArtifactSourceManager) LoadSources(factory *SourceFactory, sourceConfigs []config.SourceConfig, appCfg *config.AppConfig) {
+	for i := range sourceConfigs {
+		sourceConfig := &sourceConfigs[i]
+		if !sourceConfig.Enabled {
+			log.Warnf("Skipping disabled source: %s", sourceConfig.Name)
+			continue
+		}
+
+		if _, err := sourceConfig.GetIntervalDuration(); err != nil && sourceConfig.Type != "push" {
+			log.Errorf("Invalid interval for source %s: %v", sourceConfig.Name, err)
+			continue
+		}
+
+		source, err := factory.CreateSource(sourceConfig, appCfg)
+		if err != nil {
+			log.Errorf("Failed to create source %s: %v", sourceConfig.Name, err)
+			continue
+		}
+
+		m.AddSource(sourceConfig.Name, source, sourceConfig)
+
+		log.Infof("Added source: %s (type: %s, interval: %s)", sourceConfig.Name, sourceConfig.Type, sourceConfig.Interval)
+	}
+}
+
+// ReplaceSources stops and removes all current sources, then loads and starts the given ones.
+// Submissions already made by the old sources are unaffected.
+func (m *ArtifactSourceManager) ReplaceSources(ctx context.Context, factory *SourceFactory, sourceConfigs []config.SourceConfig, appCfg *config.AppConfig) error {
+	if err := m.StopAll(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.sources = make(map[string]ArtifactSource)
+	m.configs = make(map[string]*config.SourceConfig)
+	m.mu.Unlock()
+
+	m.LoadSources(factory, sourceConfigs, appCfg)
+	return m.StartAll(ctx)
+}
+
 // StartAll starts all enabled video sources
 func (m *ArtifactSourceManager) StartAll(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	for name, source := range m.sources {
 		config := m.configs[name]
 		if config.Enabled {
@@ -40,6 +92,8 @@ func (m *ArtifactSourceManager) StartAll(ctx context.Context) error {
 
 // StopAll stops all video sources
 func (m *ArtifactSourceManager) StopAll() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	for _, source := range m.sources {
 		if source.IsRunning() {
 			if err := source.Stop(); err != nil {
@@ -52,18 +106,24 @@ func (m *ArtifactSourceManager) StopAll() error {
 
 // GetSource returns a video source by name
 func (m *ArtifactSourceManager) GetSource(name string) (ArtifactSource, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	source, exists := m.sources[name]
 	return source, exists
 }
 
 // GetConfig returns the configuration for a video source
 func (m *ArtifactSourceManager) GetConfig(name string) (*config.SourceConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	config, exists := m.configs[name]
 	return config, exists
 }
 
 // GetEnabledSourceNames returns a list of enabled source names
 func (m *ArtifactSourceManager) GetEnabledSourceNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	var enabledSources []string
 	for name, config := range m.configs {
 		if config.Enabled {
@@ -0,0 +1,80 @@
+package sources
+
+import "strings"
+
+// ChannelFilter decides which channels' videos search sources may submit. Entries match a
+// channel's ID (UC...), @handle or name, ignoring case. A denied channel is never submitted;
+// with an allow list, only the listed channels are.
+type ChannelFilter struct {
+	allow map[string]bool
+	deny  map[string]bool
+}
+
+// NewChannelFilter creates a filter from allow and deny lists; either may be empty
+func NewChannelFilter(allow, deny []string) *ChannelFilter {
+	return &ChannelFilter{allow: channelSet(allow), deny: channelSet(deny)}
+}
+
+// With returns a filter for one source: it denies both filters' channels, and allows the
+// source's allow list when it has one, otherwise this filter's
+func (f *ChannelFilter) With(allow, deny []string) *ChannelFilter {
+	combined := NewChannelFilter(allow, deny)
+	if f == nil {
+		return combined
+	}
+	for channel := range f.deny {
+		combined.deny[channel] = true
+	}
+	if len(combined.allow) == 0 {
+		for channel := range f.allow {
+			combined.allow[channel] = true
+		}
+	}
+	return combined
+}
+
+// Active reports whether the filter can reject any video
+func (f *ChannelFilter) Active() bool {
+	return f != nil && (len(f.allow) > 0 || len(f.deny) > 0)
+}
+
+// Allows reports whether a search result's channel may be submitted. A result whose channel
+// isn't known passes the deny list but not an allow list.
+func (f *ChannelFilter) Allows(result SearchResult) bool {
+	if !f.Active() {
+		return true
+	}
+	names := []string{result.ChannelID, result.Handle, result.Channel}
+	for _, name := range names {
+		if name != "" && f.deny[strings.ToLower(name)] {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && f.allow[strings.ToLower(name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Reject returns why a result's channel may not be submitted, or "" if it may be
+func (f *ChannelFilter) Reject(result SearchResult) string {
+	if f.Allows(result) {
+		return ""
+	}
+	return "channel " + result.channelLabel() + " is filtered out"
+}
+
+func channelSet(channels []string) map[string]bool {
+	set := make(map[string]bool, len(channels))
+	for _, channel := range channels {
+		if channel = strings.ToLower(strings.TrimSpace(channel)); channel != "" {
+			set[channel] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,296 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// discordLinkPattern matches the links in a message; Discord wraps a link in <> to hide its embed
+var discordLinkPattern = regexp.MustCompile(`https?://[^\s<>|]+`)
+
+// DefaultDiscordLinkHosts are the hosts whose links a discord source submits when none are configured
+var DefaultDiscordLinkHosts = []string{"youtube.com", "youtu.be"}
+
+// DiscordSource implements ArtifactSource for Discord channels: a bot polls each channel for
+// new messages and submits the video links posted in them. Each video's summary is posted
+// by the discord output provider in a thread started from the message that linked it.
+// Messages posted before the source started are not read, nor are those of bots.
+type DiscordSource struct {
+	name                 string
+	botToken             string
+	channels             []string
+	hosts                []string
+	interval             time.Duration
+	submissionService    *services.VideoSubmissionService
+	Category             string
+	PromptID             string
+	maxSubmissionsPerDay int    // 0 = no daily cap
+	languageMode         string // overrides prompt_language_mode ("" = global mode)
+	windows              ActiveWindows
+	skipShorts           bool
+	client               *http.Client
+	apiURL               string
+
+	lastSeen map[string]string // channel ID -> ID of the newest message read
+
+	running bool
+	stopCh  chan struct{}
+	mu      sync.RWMutex
+}
+
+// discordMessage is the part of a Discord message the source reads
+type discordMessage struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Author  struct {
+		Bot bool `json:"bot"`
+	} `json:"author"`
+}
+
+// NewDiscordSource creates a new Discord source watching the given channel IDs
+func NewDiscordSource(name, botToken string, channels, hosts []string, interval time.Duration, submissionService *services.VideoSubmissionService, category, promptID string) *DiscordSource {
+	if len(hosts) == 0 {
+		hosts = DefaultDiscordLinkHosts
+	}
+	return &DiscordSource{
+		name:              name,
+		botToken:          botToken,
+		channels:          channels,
+		hosts:             hosts,
+		interval:          interval,
+		submissionService: submissionService,
+		Category:          category,
+		PromptID:          promptID,
+		client:            &http.Client{Timeout: 30 * time.Second},
+		apiURL:            "https://discord.com/api/v10",
+		lastSeen:          make(map[string]string),
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start begins polling the channels
+func (s *DiscordSource) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("discord source %s is already running", s.name)
+	}
+
+	s.running = true
+	s.stopCh = make(chan struct{})
+
+	go s.run(ctx)
+
+	log.Infof("Started discord source: %s", s.name)
+	return nil
+}
+
+// Stop gracefully stops polling the channels
+func (s *DiscordSource) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return nil
+	}
+
+	close(s.stopCh)
+	s.running = false
+
+	log.Infof("Stopped discord source: %s", s.name)
+	return nil
+}
+
+// GetName returns the name of this video source
+func (s *DiscordSource) GetName() string {
+	return s.name
+}
+
+// IsRunning returns true if the source is currently running
+func (s *DiscordSource) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+// run is the main polling loop
+func (s *DiscordSource) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start, to note where each channel's new messages begin
+	s.pollChannels()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.pollChannels()
+		}
+	}
+}
+
+// pollChannels reads each channel's messages since the last poll and submits their links
+func (s *DiscordSource) pollChannels() {
+	for _, channelID := range s.channels {
+		if err := s.pollChannel(channelID); err != nil {
+			log.Errorf("Error reading Discord channel %s for source %s: %v", channelID, s.name, err)
+		}
+	}
+}
+
+// pollChannel submits the links of a channel's new messages, oldest first. A message is only
+// marked read once its links are submitted, so when the engine is at capacity, the budget
+// is used up, the daily cap is reached or the source is outside its active windows, the rest
+// are picked up on a later poll.
+func (s *DiscordSource) pollChannel(channelID string) error {
+	after, started := s.lastSeen[channelID]
+	messages, err := s.messages(channelID, after)
+	if err != nil {
+		return err
+	}
+	if !started {
+		// First poll: start after the newest message rather than answering old links. "0"
+		// reads an empty channel from its first message.
+		s.lastSeen[channelID] = "0"
+		if len(messages) > 0 {
+			s.lastSeen[channelID] = messages[len(messages)-1].ID
+		}
+		return nil
+	}
+	if !s.windows.Contains(time.Now()) {
+		// Leave the new messages for a poll in an active window
+		return nil
+	}
+
+	for _, message := range messages {
+		links := s.videoLinks(message)
+		if len(links) > 0 {
+			allowance, err := s.submissionService.SourceAllowance(s.name, s.maxSubmissionsPerDay)
+			if err != nil {
+				log.Infof("Skipping run of source %s: %v", s.name, err)
+				return nil
+			}
+			if allowance >= 0 && len(links) > allowance {
+				log.Infof("Skipping run of source %s: %d more videos can be submitted today", s.name, allowance)
+				return nil
+			}
+			s.submit(channelID, message.ID, links)
+		}
+		s.lastSeen[channelID] = message.ID
+	}
+	return nil
+}
+
+// submit submits a message's links, with the summaries replied in a thread on the message
+func (s *DiscordSource) submit(channelID, messageID string, links []string) {
+	prompt := s.PromptID
+	if prompt == "" {
+		prompt = "general"
+	}
+	promptStruct := interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: prompt}
+	requestIDs, err := s.submissionService.SubmitBatchWithOptions(links, promptStruct, "video", s.Category, services.DefaultMaxTokens, services.SubmitOptions{
+		Source:       s.name,
+		LanguageMode: s.languageMode,
+		Output:       &interfaces.OutputTarget{Provider: "discord", Channel: channelID + "/" + messageID},
+	})
+	if err != nil {
+		log.Errorf("Error submitting videos from Discord message %s: %v", messageID, err)
+	}
+	if len(requestIDs) > 0 {
+		log.Infof("Discord source %s submitted %d videos from message %s: %v", s.name, len(requestIDs), messageID, requestIDs)
+	}
+}
+
+// messages returns up to 100 of a channel's messages after the given message ID, oldest
+// first, or just the newest message when after is ""
+func (s *DiscordSource) messages(channelID, after string) ([]discordMessage, error) {
+	query := url.Values{"limit": {"100"}}
+	if after == "" {
+		query.Set("limit", "1")
+	} else {
+		query.Set("after", after)
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/channels/%s/messages?%s", s.apiURL, channelID, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bot "+s.botToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var messages []discordMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return nil, fmt.Errorf("invalid Discord response: %w", err)
+	}
+	// Message IDs are snowflakes, which sort by time when compared as numbers
+	sort.Slice(messages, func(i, j int) bool {
+		a, b := messages[i].ID, messages[j].ID
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	return messages, nil
+}
+
+// videoLinks returns the distinct links in a message whose host is one of the source's
+// hosts or a subdomain of one, leaving out Shorts when the source skips them. Bots'
+// messages, including the summaries, are ignored.
+func (s *DiscordSource) videoLinks(message discordMessage) []string {
+	if message.Author.Bot {
+		return nil
+	}
+	var links []string
+	seen := make(map[string]bool)
+	for _, link := range discordLinkPattern.FindAllString(message.Content, -1) {
+		link = strings.TrimRight(link, ".,;:!?)]*_~`'\"")
+		parsed, err := url.Parse(link)
+		if err != nil || seen[link] || !s.allowedHost(parsed.Hostname()) {
+			continue
+		}
+		seen[link] = true
+		if s.skipShorts && IsShortsURL(link) {
+			log.Infof("%s: skipping %s: YouTube Short", s.name, link)
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func (s *DiscordSource) allowedHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range s.hosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
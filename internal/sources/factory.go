@@ -2,6 +2,7 @@ package sources
 
 import (
 	"fmt"
+	"sync"
 
 	"video-summarizer-go/internal/config"
 	"video-summarizer-go/internal/services"
@@ -10,6 +11,10 @@ import (
 // SourceFactory creates video sources based on configuration
 type SourceFactory struct {
 	submissionService *services.VideoSubmissionService
+
+	mu            sync.RWMutex
+	channelFilter *ChannelFilter // the sources file's allow_channels and deny_channels
+	skipShorts    bool           // the sources file's skip_shorts
 }
 
 // NewSourceFactory creates a new source factory
@@ -19,15 +24,46 @@ func NewSourceFactory(submissionService *services.VideoSubmissionService) *Sourc
 	}
 }
 
+// SetChannelFilter sets the channel lists every search source applies, on top of its own.
+// Sources created before the call keep the lists they were created with.
+func (f *SourceFactory) SetChannelFilter(allow, deny []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.channelFilter = NewChannelFilter(allow, deny)
+}
+
+// ChannelFilter returns the channel lists every search source applies
+func (f *SourceFactory) ChannelFilter() *ChannelFilter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.channelFilter
+}
+
+// SetSkipShorts sets whether sources skip YouTube Shorts and sub-minute clips when they don't
+// set skip_shorts themselves. Sources created before the call keep their setting.
+func (f *SourceFactory) SetSkipShorts(skip bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.skipShorts = skip
+}
+
+// SkipShorts returns whether sources skip YouTube Shorts and sub-minute clips by default
+func (f *SourceFactory) SkipShorts() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.skipShorts
+}
+
 // CreateSource creates a video source based on the source configuration
 func (f *SourceFactory) CreateSource(sourceConfig *config.SourceConfig, appCfg *config.AppConfig) (ArtifactSource, error) {
 	if !sourceConfig.Enabled {
 		return nil, fmt.Errorf("source %s is disabled", sourceConfig.Name)
 	}
 
-	_, err := sourceConfig.GetIntervalDuration()
-	if err != nil {
-		return nil, fmt.Errorf("invalid interval for source %s: %w", sourceConfig.Name, err)
+	if sourceConfig.Type != "push" {
+		if _, err := sourceConfig.GetIntervalDuration(); err != nil {
+			return nil, fmt.Errorf("invalid interval for source %s: %w", sourceConfig.Name, err)
+		}
 	}
 
 	if sourceConfig.PromptID == "" {
@@ -37,6 +73,10 @@ func (f *SourceFactory) CreateSource(sourceConfig *config.SourceConfig, appCfg *
 	switch sourceConfig.Type {
 	case "youtube_search":
 		return f.createYouTubeSearchSource(sourceConfig, appCfg)
+	case "push":
+		return f.createPushSource(sourceConfig)
+	case "discord":
+		return f.createDiscordSource(sourceConfig, appCfg)
 	default:
 		return nil, fmt.Errorf("unsupported source type: %s", sourceConfig.Type)
 	}
@@ -76,7 +116,7 @@ func (f *SourceFactory) createYouTubeSearchSource(sourceConfig *config.SourceCon
 	}
 
 	interval, _ := sourceConfig.GetIntervalDuration()
-	return NewSearchQuerySource(
+	source := NewSearchQuerySource(
 		sourceConfig.Name,
 		queries,
 		channel,
@@ -87,5 +127,99 @@ func (f *SourceFactory) createYouTubeSearchSource(sourceConfig *config.SourceCon
 		f.submissionService,
 		category,
 		sourceConfig.PromptID,
-	), nil
+	)
+	source.maxSubmissionsPerDay = sourceConfig.GetMaxSubmissionsPerDay()
+	source.languageMode = sourceConfig.LanguageMode
+	windows, err := sourceConfig.GetActiveWindows()
+	if err != nil {
+		return nil, fmt.Errorf("invalid active_windows for source %s: %w", sourceConfig.Name, err)
+	}
+	source.windows = windows
+	allow, err := sourceConfig.GetAllowChannels()
+	if err != nil {
+		return nil, err
+	}
+	deny, err := sourceConfig.GetDenyChannels()
+	if err != nil {
+		return nil, err
+	}
+	source.channelFilter = f.ChannelFilter().With(allow, deny)
+	skipShorts, err := sourceConfig.GetSkipShorts(f.SkipShorts())
+	if err != nil {
+		return nil, err
+	}
+	source.shorts = ShortsFilter(skipShorts)
+	source.thresholds.MinViews = int64(sourceConfig.GetMinViews())
+	if source.thresholds.MaxAge, err = sourceConfig.GetMaxAge(); err != nil {
+		return nil, err
+	}
+	if source.thresholds.MinDuration, err = sourceConfig.GetMinDuration(); err != nil {
+		return nil, err
+	}
+	if source.thresholds.MaxDuration, err = sourceConfig.GetMaxDuration(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// createPushSource creates a source that accepts signed submissions over HTTP
+func (f *SourceFactory) createPushSource(sourceConfig *config.SourceConfig) (ArtifactSource, error) {
+	secret := sourceConfig.GetSecret()
+	if secret == "" {
+		return nil, fmt.Errorf("push source %s has no secret", sourceConfig.Name)
+	}
+	tolerance, err := sourceConfig.GetSignatureTolerance()
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature_tolerance for source %s: %w", sourceConfig.Name, err)
+	}
+
+	category := "general"
+	if sourceConfig.Category != "" {
+		category = sourceConfig.Category
+	}
+	source := NewPushSource(sourceConfig.Name, secret, tolerance, f.submissionService, category, sourceConfig.PromptID)
+	source.maxSubmissionsPerDay = sourceConfig.GetMaxSubmissionsPerDay()
+	source.languageMode = sourceConfig.LanguageMode
+	windows, err := sourceConfig.GetActiveWindows()
+	if err != nil {
+		return nil, fmt.Errorf("invalid active_windows for source %s: %w", sourceConfig.Name, err)
+	}
+	source.windows = windows
+	return source, nil
+}
+
+// createDiscordSource creates a source that submits the video links posted in Discord channels
+func (f *SourceFactory) createDiscordSource(sourceConfig *config.SourceConfig, appCfg *config.AppConfig) (ArtifactSource, error) {
+	if appCfg.DiscordBotToken == "" {
+		return nil, fmt.Errorf("discord source %s needs discord_bot_token (or VS_DISCORD_BOT_TOKEN)", sourceConfig.Name)
+	}
+	channels, err := sourceConfig.GetChannels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels for source %s: %w", sourceConfig.Name, err)
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no channels specified for source %s", sourceConfig.Name)
+	}
+	hosts, err := sourceConfig.GetLinkHosts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link_hosts for source %s: %w", sourceConfig.Name, err)
+	}
+
+	category := "general"
+	if sourceConfig.Category != "" {
+		category = sourceConfig.Category
+	}
+	interval, _ := sourceConfig.GetIntervalDuration()
+	source := NewDiscordSource(sourceConfig.Name, appCfg.DiscordBotToken, channels, hosts, interval, f.submissionService, category, sourceConfig.PromptID)
+	source.maxSubmissionsPerDay = sourceConfig.GetMaxSubmissionsPerDay()
+	source.languageMode = sourceConfig.LanguageMode
+	windows, err := sourceConfig.GetActiveWindows()
+	if err != nil {
+		return nil, fmt.Errorf("invalid active_windows for source %s: %w", sourceConfig.Name, err)
+	}
+	source.windows = windows
+	if source.skipShorts, err = sourceConfig.GetSkipShorts(f.SkipShorts()); err != nil {
+		return nil, err
+	}
+	return source, nil
 }
@@ -0,0 +1,184 @@
+package sources
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// ErrInvalidSignature is returned for pushed payloads whose signature or timestamp doesn't verify
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// ErrSourceStopped is returned for payloads pushed to a source that isn't running
+var ErrSourceStopped = errors.New("source is not running")
+
+// PushSource implements ArtifactSource for trusted systems that submit videos over HTTP.
+// Each payload is signed with the source's shared secret: the X-Signature header carries
+// "sha256=" and the hex HMAC-SHA256 of the X-Signature-Timestamp header (Unix seconds), a
+// ".", and the raw body. Timestamps outside the tolerance are rejected to stop replays, and
+// each signature is accepted only once within it.
+type PushSource struct {
+	name                 string
+	secret               []byte
+	tolerance            time.Duration
+	submissionService    *services.VideoSubmissionService
+	Category             string
+	PromptID             string
+	maxSubmissionsPerDay int    // 0 = no daily cap
+	languageMode         string // overrides prompt_language_mode ("" = global mode)
+	windows              ActiveWindows
+
+	running bool
+	mu      sync.RWMutex
+
+	seenMu sync.Mutex
+	seen   map[string]time.Time // accepted signatures, until their timestamp leaves the tolerance
+}
+
+// NewPushSource creates a new push source
+func NewPushSource(name, secret string, tolerance time.Duration, submissionService *services.VideoSubmissionService, category, promptID string) *PushSource {
+	return &PushSource{
+		name:              name,
+		secret:            []byte(secret),
+		tolerance:         tolerance,
+		submissionService: submissionService,
+		Category:          category,
+		PromptID:          promptID,
+		seen:              make(map[string]time.Time),
+	}
+}
+
+// Start begins accepting pushed payloads
+func (s *PushSource) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return fmt.Errorf("push source %s is already running", s.name)
+	}
+	s.running = true
+
+	log.Infof("Started push source: %s", s.name)
+	return nil
+}
+
+// Stop stops accepting pushed payloads
+func (s *PushSource) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return nil
+	}
+	s.running = false
+
+	log.Infof("Stopped push source: %s", s.name)
+	return nil
+}
+
+// GetName returns the name of this video source
+func (s *PushSource) GetName() string {
+	return s.name
+}
+
+// IsRunning returns true if the source is currently accepting payloads
+func (s *PushSource) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+// VerifySignature checks a payload's signature, that its timestamp is within the tolerance
+// of now, and that the same signed payload wasn't already accepted
+func (s *PushSource) VerifySignature(body []byte, timestamp, signature string, now time.Time) error {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed timestamp", ErrInvalidSignature)
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > s.tolerance || skew < -s.tolerance {
+		return fmt.Errorf("%w: timestamp is outside the %v window", ErrInvalidSignature, s.tolerance)
+	}
+	given, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(given) == 0 {
+		return fmt.Errorf("%w: missing or malformed signature", ErrInvalidSignature)
+	}
+	if !hmac.Equal(given, SignPayload(s.secret, strconv.FormatInt(seconds, 10), body)) {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	if !s.markSeen(hex.EncodeToString(given), time.Unix(seconds, 0).Add(s.tolerance), now) {
+		return fmt.Errorf("%w: payload was already delivered", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// markSeen records an accepted signature until expires, dropping those that have expired,
+// and reports whether it hadn't been seen yet
+func (s *PushSource) markSeen(signature string, expires, now time.Time) bool {
+	s.seenMu.Lock()
+	defer s.seenMu.Unlock()
+
+	for seen, until := range s.seen {
+		if now.After(until) {
+			delete(s.seen, seen)
+		}
+	}
+	if _, ok := s.seen[signature]; ok {
+		return false
+	}
+	s.seen[signature] = expires
+	return true
+}
+
+// SignPayload returns the HMAC-SHA256 a push source expects for a timestamp and body
+func SignPayload(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Submit submits pushed videos with the source's prompt and category. The whole payload is
+// refused when the engine is at capacity, the budget is used up or it would exceed the
+// source's daily cap. Outside the source's active windows the videos are scheduled to start
+// when the next window opens.
+func (s *PushSource) Submit(urls []string) ([]string, error) {
+	if !s.IsRunning() {
+		return nil, ErrSourceStopped
+	}
+	allowance, err := s.submissionService.SourceAllowance(s.name, s.maxSubmissionsPerDay)
+	if err != nil {
+		return nil, err
+	}
+	if allowance >= 0 && len(urls) > allowance {
+		return nil, fmt.Errorf("%w: %s can accept %d more videos today", services.ErrSourceCapReached, s.name, allowance)
+	}
+
+	prompt := s.PromptID
+	if prompt == "" {
+		prompt = "general"
+	}
+	promptStruct := interfaces.Prompt{Type: interfaces.PromptTypeID, Prompt: prompt}
+	requestIDs, err := s.submissionService.SubmitBatchWithOptions(urls, promptStruct, "video", s.Category, services.DefaultMaxTokens, services.SubmitOptions{
+		Source:       s.name,
+		LanguageMode: s.languageMode,
+		NotBefore:    s.windows.NextStart(time.Now()),
+	})
+	if err != nil {
+		return requestIDs, err
+	}
+	log.Infof("Push source %s submitted %d videos: %v", s.name, len(requestIDs), requestIDs)
+	return requestIDs, nil
+}
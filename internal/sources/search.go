@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	log "github.com/sirupsen/logrus"
 
@@ -26,6 +28,12 @@ type SearchQuerySource struct {
 	submissionService     *services.VideoSubmissionService
 	Category              string
 	PromptID              string
+	maxSubmissionsPerDay  int    // 0 = no daily cap
+	languageMode          string // overrides prompt_language_mode ("" = global mode)
+	windows               ActiveWindows
+	channelFilter         *ChannelFilter
+	thresholds            SearchThresholds
+	shorts                ShortsFilter
 
 	running bool
 	stopCh  chan struct{}
@@ -128,9 +136,22 @@ func (s *SearchQuerySource) run(ctx context.Context) {
 
 // processQueries processes all configured search queries
 func (s *SearchQuerySource) processQueries() {
+	if !s.windows.Contains(time.Now()) {
+		log.Debugf("Skipping run of source %s: outside its active windows", s.name)
+		return
+	}
 	log.Infof("Processing %d queries for source: %s", len(s.queries), s.name)
+	group := runGroup(s.name, time.Now())
 
 	for _, query := range s.queries {
+		// Skip the rest of the run when the engine is at capacity, the spend budget is used up
+		// or the daily cap is reached
+		allowance, err := s.submissionService.SourceAllowance(s.name, s.maxSubmissionsPerDay)
+		if err != nil {
+			log.Infof("Skipping run of source %s: %v", s.name, err)
+			return
+		}
+
 		videos, err := s.searchVideos(query)
 		if err != nil {
 			log.Errorf("Error searching for query '%s': %v", query, err)
@@ -146,6 +167,9 @@ func (s *SearchQuerySource) processQueries() {
 		if len(videos) > s.maxVideos {
 			videos = videos[:s.maxVideos]
 		}
+		if allowance >= 0 && len(videos) > allowance {
+			videos = videos[:allowance]
+		}
 
 		prompt := s.PromptID
 		if prompt == "" {
@@ -157,9 +181,9 @@ func (s *SearchQuerySource) processQueries() {
 		if category == "" {
 			category = "general"
 		}
-		maxTokens := 10000
+		maxTokens := services.DefaultMaxTokens
 		// Submit videos for processing
-		requestIDs, err := s.submissionService.SubmitBatch(videos, promptStruct, sourceType, category, maxTokens)
+		requestIDs, err := s.submissionService.SubmitBatchWithOptions(videos, promptStruct, sourceType, category, maxTokens, services.SubmitOptions{Source: s.name, LanguageMode: s.languageMode, Group: group})
 		if err != nil {
 			log.Errorf("Error submitting videos for query '%s': %v", query, err)
 			continue
@@ -169,23 +193,114 @@ func (s *SearchQuerySource) processQueries() {
 	}
 }
 
-// searchVideos uses yt-dlp to search for videos
+// runGroup is the group of the requests a source submits in one run, its name followed by
+// the run's start time, e.g. "tech-news-20250301T090000"
+func runGroup(name string, start time.Time) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._:-", r)) {
+			return r
+		}
+		return '-'
+	}, name)
+	sanitized = strings.TrimLeft(sanitized, "._:-")
+	if len(sanitized) > 100 {
+		sanitized = sanitized[:100]
+	}
+	if sanitized == "" {
+		sanitized = "source"
+	}
+	return sanitized + "-" + start.Format("20060102T150405")
+}
+
+// searchVideos uses yt-dlp to search for videos, leaving out those of filtered channels,
+// skipped Shorts and those that miss the source's thresholds
 func (s *SearchQuerySource) searchVideos(query string) ([]string, error) {
-	log.Debugf("Starting search for query: '%s' (channel: %s)", query, s.channel)
+	return FilteredSearch(s.name, []SearchFilter{s.channelFilter, s.shorts, s.thresholds}, s.ytDlpPath, query, s.channel, s.maxVideos, s.channelVideosLookback)
+}
 
-	var shellCmd string
+// FilteredSearch searches like SearchVideos, leaving out the videos any filter rejects. name
+// identifies the searching source or schedule in the log.
+func FilteredSearch(name string, filters []SearchFilter, ytDlpPath, query, channel string, maxVideos, channelVideosLookback int) ([]string, error) {
+	count := maxVideos
+	if anyActive(filters) {
+		// Look further, so skipped videos don't use up the search
+		count *= 3
+	}
+	results, err := SearchVideoResults(ytDlpPath, query, channel, count, channelVideosLookback)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, result := range filterResults(name, results, filters) {
+		if len(urls) == maxVideos {
+			break
+		}
+		urls = append(urls, result.URL)
+	}
+	return urls, nil
+}
+
+// SearchResult is a video found by a search, with the channel that posted it and the
+// metadata of the flat listing. Fields yt-dlp didn't report are empty, or -1 for Views.
+type SearchResult struct {
+	URL       string
+	ChannelID string
+	Channel   string // channel name
+	Handle    string // @handle
+	Views     int64
+	Duration  time.Duration
+	Uploaded  time.Time // approximate for search results ("3 days ago")
+	Short     bool      // listed as a YouTube Short
+}
+
+// channelLabel names the result's channel for log messages
+func (r SearchResult) channelLabel() string {
+	for _, name := range []string{r.Channel, r.Handle, r.ChannelID} {
+		if name != "" {
+			return name
+		}
+	}
+	return "(unknown)"
+}
+
+// searchFields are the fields printed for each search result, separated by tabs since
+// channel names may hold any other character
+const searchFields = "%(id)s\t%(channel_id)s\t%(channel)s\t%(uploader_id)s\t%(view_count)s\t%(duration)s\t%(timestamp)s\t%(upload_date)s\t%(url)s"
 
-	if s.channel != "" {
+// searchExtractorArgs has yt-dlp estimate upload times from the "3 days ago" text of flat
+// listings, which otherwise have none
+const searchExtractorArgs = "youtubetab:approximate_date"
+
+// SearchVideos uses yt-dlp to search YouTube, or the latest channelVideosLookback videos of a
+// channel when channel is set, and returns the URLs of up to maxVideos matching videos
+func SearchVideos(ytDlpPath, query, channel string, maxVideos, channelVideosLookback int) ([]string, error) {
+	results, err := SearchVideoResults(ytDlpPath, query, channel, maxVideos, channelVideosLookback)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.URL
+	}
+	return urls, nil
+}
+
+// SearchVideoResults searches like SearchVideos, returning each video's channel with its URL
+func SearchVideoResults(ytDlpPath, query, channel string, maxVideos, channelVideosLookback int) ([]SearchResult, error) {
+	log.Debugf("Starting search for query: '%s' (channel: %s)", query, channel)
+
+	var shellCmd string
+	if channel != "" {
 		// Use --match-title with channel videos URL when channel is provided
 		// Scan through channelVideosLookback videos, then limit to maxVideos results
-		channelURL := fmt.Sprintf("https://www.youtube.com/channel/%s/videos", s.channel)
-		shellCmd = fmt.Sprintf("%s --match-title '%s' --print '%%(id)s' --flat-playlist --simulate -I :%d %s | head -%d",
-			s.ytDlpPath, query, s.channelVideosLookback, channelURL, s.maxVideos)
-		log.Debugf("Using channel-specific search with --match-title (scanning %d videos, will return up to %d)", s.channelVideosLookback, s.maxVideos)
+		channelURL := fmt.Sprintf("https://www.youtube.com/channel/%s/videos", channel)
+		shellCmd = fmt.Sprintf("%s --match-title '%s' --print '%s' --extractor-args '%s' --flat-playlist --simulate -I :%d %s | head -%d",
+			ytDlpPath, query, searchFields, searchExtractorArgs, channelVideosLookback, channelURL, maxVideos)
+		log.Debugf("Using channel-specific search with --match-title (scanning %d videos, will return up to %d)", channelVideosLookback, maxVideos)
 	} else {
 		// Use ytsearch when no channel is specified
-		searchArg := fmt.Sprintf("ytsearch%d:%s", s.maxVideos, strings.TrimSpace(query))
-		shellCmd = fmt.Sprintf("%s '%s' --get-id --no-playlist", s.ytDlpPath, searchArg)
+		searchArg := fmt.Sprintf("ytsearch%d:%s", maxVideos, strings.TrimSpace(query))
+		shellCmd = fmt.Sprintf("%s '%s' --print '%s' --extractor-args '%s' --flat-playlist --no-playlist", ytDlpPath, searchArg, searchFields, searchExtractorArgs)
 		log.Debugf("Using general ytsearch (no channel filter)")
 	}
 
@@ -202,19 +317,50 @@ func (s *SearchQuerySource) searchVideos(query string) ([]string, error) {
 	log.Debugf("yt-dlp output for query '%s':\n%s", query, string(output))
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var videoURLs []string
+	var results []SearchResult
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if fields[0] == "" {
 			continue
 		}
-		videoURL := fmt.Sprintf("https://www.youtube.com/watch?v=%s", line)
-		videoURLs = append(videoURLs, videoURL)
-		if len(videoURLs) >= s.maxVideos {
+		// yt-dlp prints NA for fields it doesn't know
+		for len(fields) < 9 {
+			fields = append(fields, "NA")
+		}
+		for i, field := range fields {
+			if field == "NA" {
+				fields[i] = ""
+			}
+		}
+		result := SearchResult{
+			URL:       fmt.Sprintf("https://www.youtube.com/watch?v=%s", fields[0]),
+			ChannelID: fields[1],
+			Channel:   fields[2],
+			Handle:    fields[3],
+			Views:     -1,
+			Short:     IsShortsURL(fields[8]),
+		}
+		if views, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
+			result.Views = views
+		}
+		if seconds, err := strconv.ParseFloat(fields[5], 64); err == nil {
+			result.Duration = time.Duration(seconds * float64(time.Second))
+		}
+		if timestamp, err := strconv.ParseFloat(fields[6], 64); err == nil {
+			result.Uploaded = time.Unix(int64(timestamp), 0)
+		} else if date, err := time.Parse("20060102", fields[7]); err == nil {
+			result.Uploaded = date
+		}
+		if result.ChannelID == "" {
+			// Flat channel listings may leave out the channel the videos are listed from
+			result.ChannelID = channel
+		}
+		results = append(results, result)
+		if len(results) >= maxVideos {
 			break
 		}
 	}
 
-	log.Infof("Found %d video(s) for query '%s' (channel: %s)", len(videoURLs), query, s.channel)
-	return videoURLs, nil
+	log.Infof("Found %d video(s) for query '%s' (channel: %s)", len(results), query, channel)
+	return results, nil
 }
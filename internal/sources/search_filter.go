@@ -0,0 +1,123 @@
+package sources
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SearchFilter rejects search results before they are submitted
+type SearchFilter interface {
+	// Active reports whether the filter can reject any result
+	Active() bool
+	// Reject returns why a result must not be submitted, or "" if it may be
+	Reject(result SearchResult) string
+}
+
+// SearchThresholds rejects search results with too few views, uploaded too long ago, or
+// shorter or longer than a duration range. Zero fields don't filter. A result whose value
+// yt-dlp didn't report passes that threshold, since flat search listings often leave out
+// upload dates.
+type SearchThresholds struct {
+	MinViews    int64
+	MaxAge      time.Duration
+	MinDuration time.Duration
+	MaxDuration time.Duration
+}
+
+// Active reports whether any threshold is set
+func (t SearchThresholds) Active() bool {
+	return t.MinViews > 0 || t.MaxAge > 0 || t.MinDuration > 0 || t.MaxDuration > 0
+}
+
+// Reject returns which threshold the result misses, or "" if it meets them all
+func (t SearchThresholds) Reject(result SearchResult) string {
+	if t.MinViews > 0 && result.Views >= 0 && result.Views < t.MinViews {
+		return fmt.Sprintf("%d views is below %d", result.Views, t.MinViews)
+	}
+	if t.MaxAge > 0 && !result.Uploaded.IsZero() {
+		if age := time.Since(result.Uploaded); age > t.MaxAge {
+			return fmt.Sprintf("uploaded %.0f days ago, older than %s", age.Hours()/24, t.MaxAge)
+		}
+	}
+	if result.Duration > 0 {
+		if t.MinDuration > 0 && result.Duration < t.MinDuration {
+			return fmt.Sprintf("%s long, shorter than %s", result.Duration, t.MinDuration)
+		}
+		if t.MaxDuration > 0 && result.Duration > t.MaxDuration {
+			return fmt.Sprintf("%s long, longer than %s", result.Duration, t.MaxDuration)
+		}
+	}
+	return ""
+}
+
+// ShortClipDuration is the length under which ShortsFilter skips a video as a clip
+const ShortClipDuration = time.Minute
+
+// ShortsFilter, when true, rejects YouTube Shorts and clips shorter than ShortClipDuration,
+// whose summaries say little more than their titles
+type ShortsFilter bool
+
+// Active reports whether the filter skips Shorts
+func (f ShortsFilter) Active() bool {
+	return bool(f)
+}
+
+// Reject returns why a result is a Short or clip, or "" if it is neither
+func (f ShortsFilter) Reject(result SearchResult) string {
+	if !f {
+		return ""
+	}
+	if result.Short {
+		return "YouTube Short"
+	}
+	if result.Duration > 0 && result.Duration < ShortClipDuration {
+		return fmt.Sprintf("%s clip", result.Duration)
+	}
+	return ""
+}
+
+// IsShortsURL reports whether a link is to a YouTube Short
+func IsShortsURL(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host != "youtube.com" && !strings.HasSuffix(host, ".youtube.com") {
+		return false
+	}
+	return strings.HasPrefix(parsed.Path, "/shorts/")
+}
+
+// filterResults returns the results no filter rejects, logging the rest under the given name
+func filterResults(name string, results []SearchResult, filters []SearchFilter) []SearchResult {
+	var allowed []SearchResult
+	for _, result := range results {
+		reason := ""
+		for _, filter := range filters {
+			if reason = filter.Reject(result); reason != "" {
+				break
+			}
+		}
+		if reason != "" {
+			log.Infof("%s: skipping %s: %s", name, result.URL, reason)
+			continue
+		}
+		allowed = append(allowed, result)
+	}
+	return allowed
+}
+
+// anyActive reports whether any of the filters can reject a result
+func anyActive(filters []SearchFilter) bool {
+	for _, filter := range filters {
+		if filter.Active() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package sources
+
+import (
+	"time"
+
+	"video-summarizer-go/internal/config"
+)
+
+// ActiveWindows are the daily spans of local time a source submits in; with none it
+// submits at any time
+type ActiveWindows []config.TimeWindow
+
+// Contains reports whether t falls in one of the windows
+func (w ActiveWindows) Contains(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, window := range w {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStart returns t if it falls in a window, otherwise when the next window opens
+func (w ActiveWindows) NextStart(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	var next time.Time
+	for _, window := range w {
+		start := time.Date(t.Year(), t.Month(), t.Day(), window.Start/60, window.Start%60, 0, 0, t.Location())
+		if !start.After(t) {
+			start = start.AddDate(0, 0, 1)
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
@@ -0,0 +1,210 @@
+// Package summarizer is the public, embeddable API of the video summarization pipeline.
+//
+// It lets other Go programs run the same engine as the HTTP service in-process:
+//
+//	cfg, err := summarizer.LoadConfig("config.yaml")
+//	p, err := summarizer.New(cfg)
+//	defer p.Stop()
+//	id, err := p.Submit(summarizer.Request{URL: "https://www.youtube.com/watch?v=..."})
+//	state, err := p.Wait(ctx, id)
+//
+// Provider interfaces and state types are exported from this package so callers can plug in
+// their own implementations with WithVideoProvider, WithTranscriptionProvider, and so on,
+// without importing anything under internal/.
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"video-summarizer-go/internal/config"
+	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/interfaces"
+	"video-summarizer-go/internal/services"
+)
+
+// Config is the engine configuration (the contents of config.yaml)
+type Config = config.AppConfig
+
+// Provider interfaces that can be implemented outside this module
+type (
+	VideoProvider         = interfaces.VideoProvider
+	TranscriptionProvider = interfaces.TranscriptionProvider
+	SummarizationProvider = interfaces.SummarizationProvider
+	OutputProvider        = interfaces.OutputProvider
+)
+
+// Request state types
+type (
+	Prompt           = interfaces.Prompt
+	PromptType       = interfaces.PromptType
+	ProcessingState  = interfaces.ProcessingState
+	ProcessingStatus = interfaces.ProcessingStatus
+	ErrorCode        = interfaces.ErrorCode
+	// Confidence in a request's transcript, when the transcription provider reports it
+	TranscriptQuality = interfaces.TranscriptQuality
+	// Key moments of a request's video, when highlights are enabled
+	Highlights = interfaces.Highlights
+	Highlight  = interfaces.Highlight
+	// Rubric scores of a request's summary, when evaluation is enabled
+	SummaryEvaluation = interfaces.SummaryEvaluation
+	// Outcome of a post-processing hook run after upload
+	HookResult = interfaces.HookResult
+)
+
+const (
+	PromptTypeID   = interfaces.PromptTypeID
+	PromptTypeText = interfaces.PromptTypeText
+
+	StatusScheduled = interfaces.StatusScheduled
+	StatusPending   = interfaces.StatusPending
+	StatusRunning   = interfaces.StatusRunning
+	StatusCompleted = interfaces.StatusCompleted
+	StatusFailed    = interfaces.StatusFailed
+	StatusCancelled = interfaces.StatusCancelled
+	// The transcript was kept after summarization or output failed; Retry repeats only that stage
+	StatusPartiallyCompleted = interfaces.StatusPartiallyCompleted
+)
+
+// LoadConfig loads the engine configuration from a YAML file, applying environment overrides and defaults
+func LoadConfig(path string) (*Config, error) {
+	return config.LoadConfig(path)
+}
+
+// Option customizes a Pipeline
+type Option func(*core.EngineOptions)
+
+// WithVideoProvider replaces the yt-dlp video provider
+func WithVideoProvider(provider VideoProvider) Option {
+	return func(o *core.EngineOptions) { o.VideoProvider = provider }
+}
+
+// WithTranscriptionProvider replaces the whisper.cpp transcription provider
+func WithTranscriptionProvider(provider TranscriptionProvider) Option {
+	return func(o *core.EngineOptions) { o.TranscriptionProvider = provider }
+}
+
+// WithSummarizationProvider replaces the configured summarization provider
+func WithSummarizationProvider(provider SummarizationProvider) Option {
+	return func(o *core.EngineOptions) { o.SummarizationProvider = provider }
+}
+
+// WithOutputProvider replaces the configured output provider
+func WithOutputProvider(provider OutputProvider) Option {
+	return func(o *core.EngineOptions) { o.OutputProvider = provider }
+}
+
+// Request describes a video to summarize
+type Request struct {
+	URL string
+	// Prompt defaults to the "general" prompt ID
+	Prompt Prompt
+	// Category defaults to "general"
+	Category string
+	// MaxTokens defaults to the prompt's max_tokens, the category's under max_tokens_by_category,
+	// or openai_max_tokens from the config
+	MaxTokens int
+}
+
+// Pipeline is an in-process summarization engine
+type Pipeline struct {
+	engine      *core.ProcessingEngine
+	submissions *services.VideoSubmissionService
+	cfg         *Config
+}
+
+// New builds and starts a pipeline from the given configuration
+func New(cfg *Config, opts ...Option) (*Pipeline, error) {
+	var engineOpts core.EngineOptions
+	for _, opt := range opts {
+		opt(&engineOpts)
+	}
+	engine, _, _, err := core.SetupEngineWithOptions(cfg, engineOpts)
+	if err != nil {
+		return nil, err
+	}
+	engine.Start()
+	return &Pipeline{
+		engine:      engine,
+		submissions: services.NewVideoSubmissionService(engine),
+		cfg:         cfg,
+	}, nil
+}
+
+// Submit queues a video for processing and returns its request ID.
+// Submitting the same URL and prompt again returns the existing request ID.
+func (p *Pipeline) Submit(req Request) (string, error) {
+	if req.URL == "" {
+		return "", fmt.Errorf("url is required")
+	}
+	prompt := req.Prompt
+	if prompt.Prompt == "" {
+		prompt = Prompt{Type: PromptTypeID, Prompt: "general"}
+	}
+	category := req.Category
+	if category == "" {
+		category = "general"
+	}
+	return p.submissions.SubmitVideo(req.URL, prompt, "video", category, req.MaxTokens)
+}
+
+// Status returns the current state of a request
+func (p *Pipeline) Status(requestID string) (*ProcessingState, error) {
+	return p.submissions.GetRequestStatus(requestID)
+}
+
+// Cancel cancels a request that has not finished yet
+func (p *Pipeline) Cancel(requestID string) error {
+	return p.submissions.CancelRequest(requestID)
+}
+
+// Retry restarts a failed, cancelled or partially completed request, reusing checkpointed
+// artifacts when artifacts_dir is set and the artifacts a partially completed request kept
+func (p *Pipeline) Retry(requestID string) error {
+	return p.submissions.RetryRequest(requestID)
+}
+
+// Rerun summarizes a request's transcript again with another prompt and/or model, as a new
+// request, and returns its ID. Transcripts of finished requests are only available when
+// artifacts_dir is set.
+func (p *Pipeline) Rerun(requestID string, prompt Prompt, model string) (string, error) {
+	return p.submissions.RerunRequest(requestID, prompt, model, services.SubmitOptions{})
+}
+
+// Wait blocks until the request has finished (completed, failed, cancelled or partially completed) or ctx is done
+func (p *Pipeline) Wait(ctx context.Context, requestID string) (*ProcessingState, error) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		state, err := p.Status(requestID)
+		if err != nil {
+			return nil, err
+		}
+		if isFinished(state) {
+			return state, nil
+		}
+		select {
+		case <-ctx.Done():
+			return state, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops all workers. Requests still in the pipeline are abandoned.
+func (p *Pipeline) Stop() {
+	p.engine.Stop()
+}
+
+// isFinished reports whether a request reached a final state; completed requests are
+// finished once cleanup has recorded the completion time
+func isFinished(state *ProcessingState) bool {
+	switch state.Status {
+	case StatusFailed, StatusCancelled, StatusPartiallyCompleted:
+		return true
+	case StatusCompleted:
+		return state.CompletedAt != nil
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Package summarizertest runs the summarization pipeline in-process with fake providers, so
+// integrators and contributors can write end-to-end tests without yt-dlp, whisper, pdftotext
+// or API keys. Every fake is deterministic: the same URL and prompt always produce the same
+// video info, transcript and summary.
+//
+//	h, err := summarizertest.New()
+//	if err != nil { ... }
+//	defer h.Close()
+//	state, err := h.Run(ctx, summarizer.Request{URL: "https://www.youtube.com/watch?v=abc"})
+//	uploads := h.Output.UploadsFor(state.RequestID)
+//
+// Any fake can be replaced with the usual summarizer options, e.g. a summarization provider
+// that returns an error to exercise the failure path.
+package summarizertest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"video-summarizer-go/internal/core"
+	"video-summarizer-go/internal/providers/mock"
+	"video-summarizer-go/pkg/summarizer"
+)
+
+// Fake providers, usable on their own as well as through the harness
+type (
+	VideoProvider         = mock.VideoProvider
+	TranscriptionProvider = mock.TranscriptionProvider
+	SummarizationProvider = mock.SummarizationProvider
+	DocumentProvider      = mock.DocumentProvider
+	SlideProvider         = mock.SlideProvider
+	SpeechProvider        = mock.SpeechProvider
+	OutputProvider        = mock.OutputProvider
+	Upload                = mock.Upload
+)
+
+// Harness is a running pipeline wired to the fake providers
+type Harness struct {
+	*summarizer.Pipeline
+	Config *summarizer.Config
+	// Output records every upload, unless it was replaced with summarizer.WithOutputProvider
+	Output *OutputProvider
+	// Dir holds the harness's temp files and prompts
+	Dir string
+
+	ownDir bool
+}
+
+// Config returns a configuration for a harness that keeps its files under dir.
+// Adjust it and pass it to NewWithConfig, e.g. to change concurrency or limits.
+func Config(dir string) *summarizer.Config {
+	return &summarizer.Config{
+		SummarizerProvider:               "mock",
+		OutputProvider:                   "mock",
+		OpenAIMaxTokens:                  10000,
+		SummarizationChunkSize:           60000,
+		SummarizationChunkConcurrency:    1,
+		VideoInfoCacheTTL:                "0",
+		LLMCacheTTL:                      "0",
+		DocumentMaxSizeMB:                50,
+		TmpDir:                           filepath.Join(dir, "tmp"),
+		PromptsDir:                       filepath.Join(dir, "prompts"),
+		TmpSweepInterval:                 "10m",
+		TmpOrphanMinAge:                  "1h",
+		ArtifactsRetention:               "72h",
+		TranscriptLowConfidenceThreshold: 0.6,
+		PromptLanguageMode:               "variant",
+		UploadSummary:                    true,
+		UploadTranscript:                 true,
+		Concurrency: map[string]int{
+			"transcription":   1,
+			"summarization":   1,
+			"video_info":      1,
+			"output":          1,
+			"cleanup":         1,
+			"audio_download":  1,
+			"text_extraction": 1,
+			"highlights":      1,
+			"evaluation":      1,
+			"redaction":       1,
+			"hooks":           1,
+		},
+	}
+}
+
+// New starts a harness in a new temp directory, which Close removes
+func New(opts ...summarizer.Option) (*Harness, error) {
+	dir, err := os.MkdirTemp("", "summarizertest-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create harness directory: %w", err)
+	}
+	h, err := NewWithConfig(Config(dir), opts...)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	h.Dir = dir
+	h.ownDir = true
+	return h, nil
+}
+
+// NewWithConfig starts a harness with the given configuration. Options are applied after
+// the fakes are installed, so they replace them.
+func NewWithConfig(cfg *summarizer.Config, opts ...summarizer.Option) (*Harness, error) {
+	if err := os.MkdirAll(cfg.TmpDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create harness temp directory: %w", err)
+	}
+
+	recorder := mock.NewOutputProvider()
+	fakes := func(o *core.EngineOptions) {
+		o.VideoProvider = mock.NewVideoProvider(cfg.TmpDir)
+		o.TranscriptionProvider = mock.NewTranscriptionProvider(cfg.TmpDir)
+		o.SummarizationProvider = mock.NewSummarizationProvider(cfg.TmpDir)
+		o.OutputProvider = recorder
+		o.DocumentProvider = mock.NewDocumentProvider(cfg.TmpDir)
+		o.ArticleProvider = mock.NewDocumentProvider(cfg.TmpDir)
+		o.SlideProvider = mock.NewSlideProvider(cfg.TmpDir)
+		o.SpeechProvider = mock.NewSpeechProvider(cfg.TmpDir)
+	}
+	// Find out whether the caller replaced the recording output provider
+	var resolved core.EngineOptions
+	fakes(&resolved)
+	for _, opt := range opts {
+		opt(&resolved)
+	}
+	output := recorder
+	if resolved.OutputProvider != summarizer.OutputProvider(recorder) {
+		output = nil
+	}
+
+	p, err := summarizer.New(cfg, append([]summarizer.Option{fakes}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Harness{Pipeline: p, Config: cfg, Output: output, Dir: filepath.Dir(cfg.TmpDir)}, nil
+}
+
+// Run submits a request and waits for it to finish
+func (h *Harness) Run(ctx context.Context, req summarizer.Request) (*summarizer.ProcessingState, error) {
+	requestID, err := h.Submit(req)
+	if err != nil {
+		return nil, err
+	}
+	return h.Wait(ctx, requestID)
+}
+
+// Close stops the pipeline and removes the harness directory if New created it
+func (h *Harness) Close() {
+	h.Stop()
+	if h.ownDir {
+		os.RemoveAll(h.Dir)
+	}
+}